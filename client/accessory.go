@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultReconnectDelay is how long long-running commands wait before retrying a failed
+// accessory connection by default.
+const DefaultReconnectDelay = 10 * time.Second
+
+// Accessory is the connection to a paired accessory used to read, write and subscribe to
+// characteristics. It's implemented by AccessoryClient.
+type Accessory interface {
+	Accessories(ctx context.Context) ([]*RawAccessory, error)
+	Characteristics(ctx context.Context, readReq *CharacteristicsReadRequest) ([]*CharacteristicReadResponse, error)
+	SetCharacteristics(ctx context.Context, writeReq *CharacteristicsWriteRequest) ([]*CharacteristicWriteResponse, error)
+	Subscribe(ctx context.Context, characteristics []CharacteristicReadRequest, handler EventHandler) error
+}
+
+// LockedAccessory serializes the requests of an Accessory so it can be shared between
+// goroutines. AccessoryClient already serializes its requests but other implementations
+// may not. Subscriptions aren't serialized since they use a dedicated connection.
+type LockedAccessory struct {
+	Accessory
+	mu sync.Mutex
+}
+
+// NewLockedAccessory returns a LockedAccessory sending requests with acc.
+func NewLockedAccessory(acc Accessory) *LockedAccessory {
+	return &LockedAccessory{Accessory: acc}
+}
+
+// Accessories reads the attribute database of the accessory.
+func (l *LockedAccessory) Accessories(ctx context.Context) ([]*RawAccessory, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Accessory.Accessories(ctx)
+}
+
+// Characteristics reads characteristic values from the accessory.
+func (l *LockedAccessory) Characteristics(ctx context.Context, readReq *CharacteristicsReadRequest) ([]*CharacteristicReadResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Accessory.Characteristics(ctx, readReq)
+}
+
+// SetCharacteristics writes characteristic values to the accessory.
+func (l *LockedAccessory) SetCharacteristics(ctx context.Context, writeReq *CharacteristicsWriteRequest) ([]*CharacteristicWriteResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Accessory.SetCharacteristics(ctx, writeReq)
+}
+
+// SubscribeWithRetry keeps an event subscription to the accessory open until ctx is cancelled.
+// When the subscription ends it's logged, prefixed by name if set, and retried after delay.
+func SubscribeWithRetry(ctx context.Context, acc Accessory, ids []CharacteristicReadRequest, handler EventHandler, delay time.Duration, logger *log.Logger, name string) {
+	prefix := ""
+	if name != "" {
+		prefix = name + ": "
+	}
+
+	for {
+		err := acc.Subscribe(ctx, ids, handler)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Printf("%sevent subscription ended: %v", prefix, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
@@ -2,16 +2,26 @@
 
 package characteristic
 
+import (
+	"fmt"
+
+	"github.com/mctofu/homekit/client/units"
+)
+
 type AccessoryFlags struct {
 	ID    uint64
 	Value uint32
 }
 
-func ReadAccessoryFlags(ch *RawCharacteristic) *AccessoryFlags {
+func ReadAccessoryFlags(ch *RawCharacteristic) (*AccessoryFlags, error) {
+	v, err := ch.Value.Uint32()
+	if err != nil {
+		return nil, fmt.Errorf("read AccessoryFlags: %v", err)
+	}
 	return &AccessoryFlags{
 		ID:    ch.ID,
-		Value: ch.Value.MustUint32(),
-	}
+		Value: v,
+	}, nil
 }
 
 type Active struct {
@@ -19,11 +29,15 @@ type Active struct {
 	Value byte
 }
 
-func ReadActive(ch *RawCharacteristic) *Active {
+func ReadActive(ch *RawCharacteristic) (*Active, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read Active: %v", err)
+	}
 	return &Active{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ActiveIdentifier struct {
@@ -31,11 +45,15 @@ type ActiveIdentifier struct {
 	Value uint32
 }
 
-func ReadActiveIdentifier(ch *RawCharacteristic) *ActiveIdentifier {
+func ReadActiveIdentifier(ch *RawCharacteristic) (*ActiveIdentifier, error) {
+	v, err := ch.Value.Uint32()
+	if err != nil {
+		return nil, fmt.Errorf("read ActiveIdentifier: %v", err)
+	}
 	return &ActiveIdentifier{
 		ID:    ch.ID,
-		Value: ch.Value.MustUint32(),
-	}
+		Value: v,
+	}, nil
 }
 
 type AdministratorOnlyAccess struct {
@@ -43,11 +61,15 @@ type AdministratorOnlyAccess struct {
 	Value bool
 }
 
-func ReadAdministratorOnlyAccess(ch *RawCharacteristic) *AdministratorOnlyAccess {
+func ReadAdministratorOnlyAccess(ch *RawCharacteristic) (*AdministratorOnlyAccess, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read AdministratorOnlyAccess: %v", err)
+	}
 	return &AdministratorOnlyAccess{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type AudioFeedback struct {
@@ -55,23 +77,33 @@ type AudioFeedback struct {
 	Value bool
 }
 
-func ReadAudioFeedback(ch *RawCharacteristic) *AudioFeedback {
+func ReadAudioFeedback(ch *RawCharacteristic) (*AudioFeedback, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read AudioFeedback: %v", err)
+	}
 	return &AudioFeedback{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type AirParticulateDensity struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadAirParticulateDensity(ch *RawCharacteristic) *AirParticulateDensity {
+func ReadAirParticulateDensity(ch *RawCharacteristic) (*AirParticulateDensity, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read AirParticulateDensity: %v", err)
+	}
 	return &AirParticulateDensity{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "micrograms/m^3"),
+	}, nil
 }
 
 type AirParticulateSize struct {
@@ -79,11 +111,15 @@ type AirParticulateSize struct {
 	Value byte
 }
 
-func ReadAirParticulateSize(ch *RawCharacteristic) *AirParticulateSize {
+func ReadAirParticulateSize(ch *RawCharacteristic) (*AirParticulateSize, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read AirParticulateSize: %v", err)
+	}
 	return &AirParticulateSize{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type AirQuality struct {
@@ -91,35 +127,51 @@ type AirQuality struct {
 	Value byte
 }
 
-func ReadAirQuality(ch *RawCharacteristic) *AirQuality {
+func ReadAirQuality(ch *RawCharacteristic) (*AirQuality, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read AirQuality: %v", err)
+	}
 	return &AirQuality{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type BatteryLevel struct {
 	ID    uint64
 	Value byte
+	Unit  units.Unit
 }
 
-func ReadBatteryLevel(ch *RawCharacteristic) *BatteryLevel {
+func ReadBatteryLevel(ch *RawCharacteristic) (*BatteryLevel, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read BatteryLevel: %v", err)
+	}
 	return &BatteryLevel{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type Brightness struct {
 	ID    uint64
 	Value int32
+	Unit  units.Unit
 }
 
-func ReadBrightness(ch *RawCharacteristic) *Brightness {
+func ReadBrightness(ch *RawCharacteristic) (*Brightness, error) {
+	v, err := ch.Value.Int32()
+	if err != nil {
+		return nil, fmt.Errorf("read Brightness: %v", err)
+	}
 	return &Brightness{
 		ID:    ch.ID,
-		Value: ch.Value.MustInt32(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type ButtonEvent struct {
@@ -127,35 +179,51 @@ type ButtonEvent struct {
 	Value []byte
 }
 
-func ReadButtonEvent(ch *RawCharacteristic) *ButtonEvent {
+func ReadButtonEvent(ch *RawCharacteristic) (*ButtonEvent, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read ButtonEvent: %v", err)
+	}
 	return &ButtonEvent{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CarbonMonoxideLevel struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadCarbonMonoxideLevel(ch *RawCharacteristic) *CarbonMonoxideLevel {
+func ReadCarbonMonoxideLevel(ch *RawCharacteristic) (*CarbonMonoxideLevel, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read CarbonMonoxideLevel: %v", err)
+	}
 	return &CarbonMonoxideLevel{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "ppm"),
+	}, nil
 }
 
 type CarbonMonoxidePeakLevel struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadCarbonMonoxidePeakLevel(ch *RawCharacteristic) *CarbonMonoxidePeakLevel {
+func ReadCarbonMonoxidePeakLevel(ch *RawCharacteristic) (*CarbonMonoxidePeakLevel, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read CarbonMonoxidePeakLevel: %v", err)
+	}
 	return &CarbonMonoxidePeakLevel{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "ppm"),
+	}, nil
 }
 
 type CarbonDioxideDetected struct {
@@ -163,35 +231,51 @@ type CarbonDioxideDetected struct {
 	Value byte
 }
 
-func ReadCarbonDioxideDetected(ch *RawCharacteristic) *CarbonDioxideDetected {
+func ReadCarbonDioxideDetected(ch *RawCharacteristic) (*CarbonDioxideDetected, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CarbonDioxideDetected: %v", err)
+	}
 	return &CarbonDioxideDetected{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CarbonDioxideLevel struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadCarbonDioxideLevel(ch *RawCharacteristic) *CarbonDioxideLevel {
+func ReadCarbonDioxideLevel(ch *RawCharacteristic) (*CarbonDioxideLevel, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read CarbonDioxideLevel: %v", err)
+	}
 	return &CarbonDioxideLevel{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "ppm"),
+	}, nil
 }
 
 type CarbonDioxidePeakLevel struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadCarbonDioxidePeakLevel(ch *RawCharacteristic) *CarbonDioxidePeakLevel {
+func ReadCarbonDioxidePeakLevel(ch *RawCharacteristic) (*CarbonDioxidePeakLevel, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read CarbonDioxidePeakLevel: %v", err)
+	}
 	return &CarbonDioxidePeakLevel{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "ppm"),
+	}, nil
 }
 
 type CarbonMonoxideDetected struct {
@@ -199,11 +283,15 @@ type CarbonMonoxideDetected struct {
 	Value byte
 }
 
-func ReadCarbonMonoxideDetected(ch *RawCharacteristic) *CarbonMonoxideDetected {
+func ReadCarbonMonoxideDetected(ch *RawCharacteristic) (*CarbonMonoxideDetected, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CarbonMonoxideDetected: %v", err)
+	}
 	return &CarbonMonoxideDetected{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ChargingState struct {
@@ -211,23 +299,38 @@ type ChargingState struct {
 	Value byte
 }
 
-func ReadChargingState(ch *RawCharacteristic) *ChargingState {
+func ReadChargingState(ch *RawCharacteristic) (*ChargingState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read ChargingState: %v", err)
+	}
 	return &ChargingState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CoolingThresholdTemperature struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadCoolingThresholdTemperature(ch *RawCharacteristic) *CoolingThresholdTemperature {
+func ReadCoolingThresholdTemperature(ch *RawCharacteristic) (*CoolingThresholdTemperature, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read CoolingThresholdTemperature: %v", err)
+	}
 	return &CoolingThresholdTemperature{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "celsius"),
+	}, nil
+}
+
+// Fahrenheit returns the temperature in degrees Fahrenheit
+func (c *CoolingThresholdTemperature) Fahrenheit() float64 {
+	return units.CelsiusToFahrenheit(c.Value)
 }
 
 type ColorTemperature struct {
@@ -235,11 +338,15 @@ type ColorTemperature struct {
 	Value uint32
 }
 
-func ReadColorTemperature(ch *RawCharacteristic) *ColorTemperature {
+func ReadColorTemperature(ch *RawCharacteristic) (*ColorTemperature, error) {
+	v, err := ch.Value.Uint32()
+	if err != nil {
+		return nil, fmt.Errorf("read ColorTemperature: %v", err)
+	}
 	return &ColorTemperature{
 		ID:    ch.ID,
-		Value: ch.Value.MustUint32(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ContactSensorState struct {
@@ -247,35 +354,51 @@ type ContactSensorState struct {
 	Value byte
 }
 
-func ReadContactSensorState(ch *RawCharacteristic) *ContactSensorState {
+func ReadContactSensorState(ch *RawCharacteristic) (*ContactSensorState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read ContactSensorState: %v", err)
+	}
 	return &ContactSensorState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CurrentAmbientLightLevel struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadCurrentAmbientLightLevel(ch *RawCharacteristic) *CurrentAmbientLightLevel {
+func ReadCurrentAmbientLightLevel(ch *RawCharacteristic) (*CurrentAmbientLightLevel, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentAmbientLightLevel: %v", err)
+	}
 	return &CurrentAmbientLightLevel{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "lux"),
+	}, nil
 }
 
 type CurrentHorizontalTiltAngle struct {
 	ID    uint64
 	Value int32
+	Unit  units.Unit
 }
 
-func ReadCurrentHorizontalTiltAngle(ch *RawCharacteristic) *CurrentHorizontalTiltAngle {
+func ReadCurrentHorizontalTiltAngle(ch *RawCharacteristic) (*CurrentHorizontalTiltAngle, error) {
+	v, err := ch.Value.Int32()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentHorizontalTiltAngle: %v", err)
+	}
 	return &CurrentHorizontalTiltAngle{
 		ID:    ch.ID,
-		Value: ch.Value.MustInt32(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "arcdegrees"),
+	}, nil
 }
 
 type CurrentAirPurifierState struct {
@@ -283,11 +406,15 @@ type CurrentAirPurifierState struct {
 	Value byte
 }
 
-func ReadCurrentAirPurifierState(ch *RawCharacteristic) *CurrentAirPurifierState {
+func ReadCurrentAirPurifierState(ch *RawCharacteristic) (*CurrentAirPurifierState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentAirPurifierState: %v", err)
+	}
 	return &CurrentAirPurifierState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CurrentSlatState struct {
@@ -295,35 +422,51 @@ type CurrentSlatState struct {
 	Value byte
 }
 
-func ReadCurrentSlatState(ch *RawCharacteristic) *CurrentSlatState {
+func ReadCurrentSlatState(ch *RawCharacteristic) (*CurrentSlatState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentSlatState: %v", err)
+	}
 	return &CurrentSlatState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CurrentPosition struct {
 	ID    uint64
 	Value byte
+	Unit  units.Unit
 }
 
-func ReadCurrentPosition(ch *RawCharacteristic) *CurrentPosition {
+func ReadCurrentPosition(ch *RawCharacteristic) (*CurrentPosition, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentPosition: %v", err)
+	}
 	return &CurrentPosition{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type CurrentVerticalTiltAngle struct {
 	ID    uint64
 	Value int32
+	Unit  units.Unit
 }
 
-func ReadCurrentVerticalTiltAngle(ch *RawCharacteristic) *CurrentVerticalTiltAngle {
+func ReadCurrentVerticalTiltAngle(ch *RawCharacteristic) (*CurrentVerticalTiltAngle, error) {
+	v, err := ch.Value.Int32()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentVerticalTiltAngle: %v", err)
+	}
 	return &CurrentVerticalTiltAngle{
 		ID:    ch.ID,
-		Value: ch.Value.MustInt32(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "arcdegrees"),
+	}, nil
 }
 
 type CurrentHumidifierDehumidifierState struct {
@@ -331,11 +474,15 @@ type CurrentHumidifierDehumidifierState struct {
 	Value byte
 }
 
-func ReadCurrentHumidifierDehumidifierState(ch *RawCharacteristic) *CurrentHumidifierDehumidifierState {
+func ReadCurrentHumidifierDehumidifierState(ch *RawCharacteristic) (*CurrentHumidifierDehumidifierState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentHumidifierDehumidifierState: %v", err)
+	}
 	return &CurrentHumidifierDehumidifierState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CurrentDoorState struct {
@@ -343,11 +490,15 @@ type CurrentDoorState struct {
 	Value byte
 }
 
-func ReadCurrentDoorState(ch *RawCharacteristic) *CurrentDoorState {
+func ReadCurrentDoorState(ch *RawCharacteristic) (*CurrentDoorState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentDoorState: %v", err)
+	}
 	return &CurrentDoorState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CurrentFanState struct {
@@ -355,11 +506,15 @@ type CurrentFanState struct {
 	Value byte
 }
 
-func ReadCurrentFanState(ch *RawCharacteristic) *CurrentFanState {
+func ReadCurrentFanState(ch *RawCharacteristic) (*CurrentFanState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentFanState: %v", err)
+	}
 	return &CurrentFanState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CurrentHeatingCoolingState struct {
@@ -367,11 +522,15 @@ type CurrentHeatingCoolingState struct {
 	Value byte
 }
 
-func ReadCurrentHeatingCoolingState(ch *RawCharacteristic) *CurrentHeatingCoolingState {
+func ReadCurrentHeatingCoolingState(ch *RawCharacteristic) (*CurrentHeatingCoolingState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentHeatingCoolingState: %v", err)
+	}
 	return &CurrentHeatingCoolingState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CurrentHeaterCoolerState struct {
@@ -379,47 +538,74 @@ type CurrentHeaterCoolerState struct {
 	Value byte
 }
 
-func ReadCurrentHeaterCoolerState(ch *RawCharacteristic) *CurrentHeaterCoolerState {
+func ReadCurrentHeaterCoolerState(ch *RawCharacteristic) (*CurrentHeaterCoolerState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentHeaterCoolerState: %v", err)
+	}
 	return &CurrentHeaterCoolerState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type CurrentRelativeHumidity struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadCurrentRelativeHumidity(ch *RawCharacteristic) *CurrentRelativeHumidity {
+func ReadCurrentRelativeHumidity(ch *RawCharacteristic) (*CurrentRelativeHumidity, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentRelativeHumidity: %v", err)
+	}
 	return &CurrentRelativeHumidity{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type CurrentTemperature struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadCurrentTemperature(ch *RawCharacteristic) *CurrentTemperature {
+func ReadCurrentTemperature(ch *RawCharacteristic) (*CurrentTemperature, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentTemperature: %v", err)
+	}
 	return &CurrentTemperature{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "celsius"),
+	}, nil
+}
+
+// Fahrenheit returns the temperature in degrees Fahrenheit
+func (c *CurrentTemperature) Fahrenheit() float64 {
+	return units.CelsiusToFahrenheit(c.Value)
 }
 
 type CurrentTiltAngle struct {
 	ID    uint64
 	Value int32
+	Unit  units.Unit
 }
 
-func ReadCurrentTiltAngle(ch *RawCharacteristic) *CurrentTiltAngle {
+func ReadCurrentTiltAngle(ch *RawCharacteristic) (*CurrentTiltAngle, error) {
+	v, err := ch.Value.Int32()
+	if err != nil {
+		return nil, fmt.Errorf("read CurrentTiltAngle: %v", err)
+	}
 	return &CurrentTiltAngle{
 		ID:    ch.ID,
-		Value: ch.Value.MustInt32(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "arcdegrees"),
+	}, nil
 }
 
 type DigitalZoom struct {
@@ -427,11 +613,15 @@ type DigitalZoom struct {
 	Value float64
 }
 
-func ReadDigitalZoom(ch *RawCharacteristic) *DigitalZoom {
+func ReadDigitalZoom(ch *RawCharacteristic) (*DigitalZoom, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read DigitalZoom: %v", err)
+	}
 	return &DigitalZoom{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+	}, nil
 }
 
 type FilterLifeLevel struct {
@@ -439,11 +629,15 @@ type FilterLifeLevel struct {
 	Value float64
 }
 
-func ReadFilterLifeLevel(ch *RawCharacteristic) *FilterLifeLevel {
+func ReadFilterLifeLevel(ch *RawCharacteristic) (*FilterLifeLevel, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read FilterLifeLevel: %v", err)
+	}
 	return &FilterLifeLevel{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+	}, nil
 }
 
 type FilterChangeIndication struct {
@@ -451,11 +645,15 @@ type FilterChangeIndication struct {
 	Value byte
 }
 
-func ReadFilterChangeIndication(ch *RawCharacteristic) *FilterChangeIndication {
+func ReadFilterChangeIndication(ch *RawCharacteristic) (*FilterChangeIndication, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read FilterChangeIndication: %v", err)
+	}
 	return &FilterChangeIndication{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type FirmwareRevision struct {
@@ -463,11 +661,15 @@ type FirmwareRevision struct {
 	Value string
 }
 
-func ReadFirmwareRevision(ch *RawCharacteristic) *FirmwareRevision {
+func ReadFirmwareRevision(ch *RawCharacteristic) (*FirmwareRevision, error) {
+	v, err := ch.Value.String()
+	if err != nil {
+		return nil, fmt.Errorf("read FirmwareRevision: %v", err)
+	}
 	return &FirmwareRevision{
 		ID:    ch.ID,
-		Value: ch.Value.MustString(),
-	}
+		Value: v,
+	}, nil
 }
 
 type HardwareRevision struct {
@@ -475,23 +677,38 @@ type HardwareRevision struct {
 	Value string
 }
 
-func ReadHardwareRevision(ch *RawCharacteristic) *HardwareRevision {
+func ReadHardwareRevision(ch *RawCharacteristic) (*HardwareRevision, error) {
+	v, err := ch.Value.String()
+	if err != nil {
+		return nil, fmt.Errorf("read HardwareRevision: %v", err)
+	}
 	return &HardwareRevision{
 		ID:    ch.ID,
-		Value: ch.Value.MustString(),
-	}
+		Value: v,
+	}, nil
 }
 
 type HeatingThresholdTemperature struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadHeatingThresholdTemperature(ch *RawCharacteristic) *HeatingThresholdTemperature {
+func ReadHeatingThresholdTemperature(ch *RawCharacteristic) (*HeatingThresholdTemperature, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read HeatingThresholdTemperature: %v", err)
+	}
 	return &HeatingThresholdTemperature{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "celsius"),
+	}, nil
+}
+
+// Fahrenheit returns the temperature in degrees Fahrenheit
+func (c *HeatingThresholdTemperature) Fahrenheit() float64 {
+	return units.CelsiusToFahrenheit(c.Value)
 }
 
 type HoldPosition struct {
@@ -499,23 +716,33 @@ type HoldPosition struct {
 	Value bool
 }
 
-func ReadHoldPosition(ch *RawCharacteristic) *HoldPosition {
+func ReadHoldPosition(ch *RawCharacteristic) (*HoldPosition, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read HoldPosition: %v", err)
+	}
 	return &HoldPosition{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type Hue struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadHue(ch *RawCharacteristic) *Hue {
+func ReadHue(ch *RawCharacteristic) (*Hue, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read Hue: %v", err)
+	}
 	return &Hue{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "arcdegrees"),
+	}, nil
 }
 
 type Identify struct {
@@ -523,11 +750,15 @@ type Identify struct {
 	Value bool
 }
 
-func ReadIdentify(ch *RawCharacteristic) *Identify {
+func ReadIdentify(ch *RawCharacteristic) (*Identify, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read Identify: %v", err)
+	}
 	return &Identify{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ImageRotation struct {
@@ -535,23 +766,33 @@ type ImageRotation struct {
 	Value float64
 }
 
-func ReadImageRotation(ch *RawCharacteristic) *ImageRotation {
+func ReadImageRotation(ch *RawCharacteristic) (*ImageRotation, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read ImageRotation: %v", err)
+	}
 	return &ImageRotation{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ImageMirroring struct {
 	ID    uint64
 	Value bool
+	Unit  units.Unit
 }
 
-func ReadImageMirroring(ch *RawCharacteristic) *ImageMirroring {
+func ReadImageMirroring(ch *RawCharacteristic) (*ImageMirroring, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read ImageMirroring: %v", err)
+	}
 	return &ImageMirroring{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "arcdegrees"),
+	}, nil
 }
 
 type InUse struct {
@@ -559,11 +800,15 @@ type InUse struct {
 	Value byte
 }
 
-func ReadInUse(ch *RawCharacteristic) *InUse {
+func ReadInUse(ch *RawCharacteristic) (*InUse, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read InUse: %v", err)
+	}
 	return &InUse{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type IsConfigured struct {
@@ -571,11 +816,15 @@ type IsConfigured struct {
 	Value byte
 }
 
-func ReadIsConfigured(ch *RawCharacteristic) *IsConfigured {
+func ReadIsConfigured(ch *RawCharacteristic) (*IsConfigured, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read IsConfigured: %v", err)
+	}
 	return &IsConfigured{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type LeakDetected struct {
@@ -583,11 +832,15 @@ type LeakDetected struct {
 	Value byte
 }
 
-func ReadLeakDetected(ch *RawCharacteristic) *LeakDetected {
+func ReadLeakDetected(ch *RawCharacteristic) (*LeakDetected, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read LeakDetected: %v", err)
+	}
 	return &LeakDetected{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type LockControlPoint struct {
@@ -595,11 +848,15 @@ type LockControlPoint struct {
 	Value []byte
 }
 
-func ReadLockControlPoint(ch *RawCharacteristic) *LockControlPoint {
+func ReadLockControlPoint(ch *RawCharacteristic) (*LockControlPoint, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read LockControlPoint: %v", err)
+	}
 	return &LockControlPoint{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type LockCurrentState struct {
@@ -607,11 +864,15 @@ type LockCurrentState struct {
 	Value byte
 }
 
-func ReadLockCurrentState(ch *RawCharacteristic) *LockCurrentState {
+func ReadLockCurrentState(ch *RawCharacteristic) (*LockCurrentState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read LockCurrentState: %v", err)
+	}
 	return &LockCurrentState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type LockLastKnownAction struct {
@@ -619,23 +880,33 @@ type LockLastKnownAction struct {
 	Value byte
 }
 
-func ReadLockLastKnownAction(ch *RawCharacteristic) *LockLastKnownAction {
+func ReadLockLastKnownAction(ch *RawCharacteristic) (*LockLastKnownAction, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read LockLastKnownAction: %v", err)
+	}
 	return &LockLastKnownAction{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type LockManagementAutoSecurityTimeout struct {
 	ID    uint64
 	Value uint32
+	Unit  units.Unit
 }
 
-func ReadLockManagementAutoSecurityTimeout(ch *RawCharacteristic) *LockManagementAutoSecurityTimeout {
+func ReadLockManagementAutoSecurityTimeout(ch *RawCharacteristic) (*LockManagementAutoSecurityTimeout, error) {
+	v, err := ch.Value.Uint32()
+	if err != nil {
+		return nil, fmt.Errorf("read LockManagementAutoSecurityTimeout: %v", err)
+	}
 	return &LockManagementAutoSecurityTimeout{
 		ID:    ch.ID,
-		Value: ch.Value.MustUint32(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "seconds"),
+	}, nil
 }
 
 type LockPhysicalControls struct {
@@ -643,11 +914,15 @@ type LockPhysicalControls struct {
 	Value byte
 }
 
-func ReadLockPhysicalControls(ch *RawCharacteristic) *LockPhysicalControls {
+func ReadLockPhysicalControls(ch *RawCharacteristic) (*LockPhysicalControls, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read LockPhysicalControls: %v", err)
+	}
 	return &LockPhysicalControls{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type LockTargetState struct {
@@ -655,11 +930,15 @@ type LockTargetState struct {
 	Value byte
 }
 
-func ReadLockTargetState(ch *RawCharacteristic) *LockTargetState {
+func ReadLockTargetState(ch *RawCharacteristic) (*LockTargetState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read LockTargetState: %v", err)
+	}
 	return &LockTargetState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type Logs struct {
@@ -667,11 +946,15 @@ type Logs struct {
 	Value []byte
 }
 
-func ReadLogs(ch *RawCharacteristic) *Logs {
+func ReadLogs(ch *RawCharacteristic) (*Logs, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read Logs: %v", err)
+	}
 	return &Logs{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type Manufacturer struct {
@@ -679,11 +962,15 @@ type Manufacturer struct {
 	Value string
 }
 
-func ReadManufacturer(ch *RawCharacteristic) *Manufacturer {
+func ReadManufacturer(ch *RawCharacteristic) (*Manufacturer, error) {
+	v, err := ch.Value.String()
+	if err != nil {
+		return nil, fmt.Errorf("read Manufacturer: %v", err)
+	}
 	return &Manufacturer{
 		ID:    ch.ID,
-		Value: ch.Value.MustString(),
-	}
+		Value: v,
+	}, nil
 }
 
 type Model struct {
@@ -691,11 +978,15 @@ type Model struct {
 	Value string
 }
 
-func ReadModel(ch *RawCharacteristic) *Model {
+func ReadModel(ch *RawCharacteristic) (*Model, error) {
+	v, err := ch.Value.String()
+	if err != nil {
+		return nil, fmt.Errorf("read Model: %v", err)
+	}
 	return &Model{
 		ID:    ch.ID,
-		Value: ch.Value.MustString(),
-	}
+		Value: v,
+	}, nil
 }
 
 type MotionDetected struct {
@@ -703,11 +994,15 @@ type MotionDetected struct {
 	Value bool
 }
 
-func ReadMotionDetected(ch *RawCharacteristic) *MotionDetected {
+func ReadMotionDetected(ch *RawCharacteristic) (*MotionDetected, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read MotionDetected: %v", err)
+	}
 	return &MotionDetected{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type Mute struct {
@@ -715,11 +1010,15 @@ type Mute struct {
 	Value bool
 }
 
-func ReadMute(ch *RawCharacteristic) *Mute {
+func ReadMute(ch *RawCharacteristic) (*Mute, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read Mute: %v", err)
+	}
 	return &Mute{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type Name struct {
@@ -727,11 +1026,15 @@ type Name struct {
 	Value string
 }
 
-func ReadName(ch *RawCharacteristic) *Name {
+func ReadName(ch *RawCharacteristic) (*Name, error) {
+	v, err := ch.Value.String()
+	if err != nil {
+		return nil, fmt.Errorf("read Name: %v", err)
+	}
 	return &Name{
 		ID:    ch.ID,
-		Value: ch.Value.MustString(),
-	}
+		Value: v,
+	}, nil
 }
 
 type NightVision struct {
@@ -739,23 +1042,33 @@ type NightVision struct {
 	Value bool
 }
 
-func ReadNightVision(ch *RawCharacteristic) *NightVision {
+func ReadNightVision(ch *RawCharacteristic) (*NightVision, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read NightVision: %v", err)
+	}
 	return &NightVision{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type NitrogenDioxideDensity struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadNitrogenDioxideDensity(ch *RawCharacteristic) *NitrogenDioxideDensity {
+func ReadNitrogenDioxideDensity(ch *RawCharacteristic) (*NitrogenDioxideDensity, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read NitrogenDioxideDensity: %v", err)
+	}
 	return &NitrogenDioxideDensity{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "micrograms/m^3"),
+	}, nil
 }
 
 type ObstructionDetected struct {
@@ -763,23 +1076,33 @@ type ObstructionDetected struct {
 	Value bool
 }
 
-func ReadObstructionDetected(ch *RawCharacteristic) *ObstructionDetected {
+func ReadObstructionDetected(ch *RawCharacteristic) (*ObstructionDetected, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read ObstructionDetected: %v", err)
+	}
 	return &ObstructionDetected{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type PM25Density struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadPM25Density(ch *RawCharacteristic) *PM25Density {
+func ReadPM25Density(ch *RawCharacteristic) (*PM25Density, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read PM2.5Density: %v", err)
+	}
 	return &PM25Density{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "micrograms/m^3"),
+	}, nil
 }
 
 type OccupancyDetected struct {
@@ -787,11 +1110,15 @@ type OccupancyDetected struct {
 	Value byte
 }
 
-func ReadOccupancyDetected(ch *RawCharacteristic) *OccupancyDetected {
+func ReadOccupancyDetected(ch *RawCharacteristic) (*OccupancyDetected, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read OccupancyDetected: %v", err)
+	}
 	return &OccupancyDetected{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type OpticalZoom struct {
@@ -799,11 +1126,15 @@ type OpticalZoom struct {
 	Value float64
 }
 
-func ReadOpticalZoom(ch *RawCharacteristic) *OpticalZoom {
+func ReadOpticalZoom(ch *RawCharacteristic) (*OpticalZoom, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read OpticalZoom: %v", err)
+	}
 	return &OpticalZoom{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+	}, nil
 }
 
 type OutletInUse struct {
@@ -811,11 +1142,15 @@ type OutletInUse struct {
 	Value bool
 }
 
-func ReadOutletInUse(ch *RawCharacteristic) *OutletInUse {
+func ReadOutletInUse(ch *RawCharacteristic) (*OutletInUse, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read OutletInUse: %v", err)
+	}
 	return &OutletInUse{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type On struct {
@@ -823,35 +1158,51 @@ type On struct {
 	Value bool
 }
 
-func ReadOn(ch *RawCharacteristic) *On {
+func ReadOn(ch *RawCharacteristic) (*On, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read On: %v", err)
+	}
 	return &On{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type OzoneDensity struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadOzoneDensity(ch *RawCharacteristic) *OzoneDensity {
+func ReadOzoneDensity(ch *RawCharacteristic) (*OzoneDensity, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read OzoneDensity: %v", err)
+	}
 	return &OzoneDensity{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "micrograms/m^3"),
+	}, nil
 }
 
 type PM10Density struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadPM10Density(ch *RawCharacteristic) *PM10Density {
+func ReadPM10Density(ch *RawCharacteristic) (*PM10Density, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read PM10Density: %v", err)
+	}
 	return &PM10Density{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "micrograms/m^3"),
+	}, nil
 }
 
 type PositionState struct {
@@ -859,11 +1210,15 @@ type PositionState struct {
 	Value byte
 }
 
-func ReadPositionState(ch *RawCharacteristic) *PositionState {
+func ReadPositionState(ch *RawCharacteristic) (*PositionState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read PositionState: %v", err)
+	}
 	return &PositionState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ProgramMode struct {
@@ -871,11 +1226,15 @@ type ProgramMode struct {
 	Value byte
 }
 
-func ReadProgramMode(ch *RawCharacteristic) *ProgramMode {
+func ReadProgramMode(ch *RawCharacteristic) (*ProgramMode, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read ProgramMode: %v", err)
+	}
 	return &ProgramMode{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ProgrammableSwitchEvent struct {
@@ -883,35 +1242,51 @@ type ProgrammableSwitchEvent struct {
 	Value byte
 }
 
-func ReadProgrammableSwitchEvent(ch *RawCharacteristic) *ProgrammableSwitchEvent {
+func ReadProgrammableSwitchEvent(ch *RawCharacteristic) (*ProgrammableSwitchEvent, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read ProgrammableSwitchEvent: %v", err)
+	}
 	return &ProgrammableSwitchEvent{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type RelativeHumidityDehumidifierThreshold struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadRelativeHumidityDehumidifierThreshold(ch *RawCharacteristic) *RelativeHumidityDehumidifierThreshold {
+func ReadRelativeHumidityDehumidifierThreshold(ch *RawCharacteristic) (*RelativeHumidityDehumidifierThreshold, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read RelativeHumidityDehumidifierThreshold: %v", err)
+	}
 	return &RelativeHumidityDehumidifierThreshold{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type RelativeHumidityHumidifierThreshold struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadRelativeHumidityHumidifierThreshold(ch *RawCharacteristic) *RelativeHumidityHumidifierThreshold {
+func ReadRelativeHumidityHumidifierThreshold(ch *RawCharacteristic) (*RelativeHumidityHumidifierThreshold, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read RelativeHumidityHumidifierThreshold: %v", err)
+	}
 	return &RelativeHumidityHumidifierThreshold{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type RemainingDuration struct {
@@ -919,11 +1294,15 @@ type RemainingDuration struct {
 	Value uint32
 }
 
-func ReadRemainingDuration(ch *RawCharacteristic) *RemainingDuration {
+func ReadRemainingDuration(ch *RawCharacteristic) (*RemainingDuration, error) {
+	v, err := ch.Value.Uint32()
+	if err != nil {
+		return nil, fmt.Errorf("read RemainingDuration: %v", err)
+	}
 	return &RemainingDuration{
 		ID:    ch.ID,
-		Value: ch.Value.MustUint32(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ResetFilterIndication struct {
@@ -931,11 +1310,15 @@ type ResetFilterIndication struct {
 	Value byte
 }
 
-func ReadResetFilterIndication(ch *RawCharacteristic) *ResetFilterIndication {
+func ReadResetFilterIndication(ch *RawCharacteristic) (*ResetFilterIndication, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read ResetFilterIndication: %v", err)
+	}
 	return &ResetFilterIndication{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type RotationDirection struct {
@@ -943,35 +1326,51 @@ type RotationDirection struct {
 	Value int32
 }
 
-func ReadRotationDirection(ch *RawCharacteristic) *RotationDirection {
+func ReadRotationDirection(ch *RawCharacteristic) (*RotationDirection, error) {
+	v, err := ch.Value.Int32()
+	if err != nil {
+		return nil, fmt.Errorf("read RotationDirection: %v", err)
+	}
 	return &RotationDirection{
 		ID:    ch.ID,
-		Value: ch.Value.MustInt32(),
-	}
+		Value: v,
+	}, nil
 }
 
 type RotationSpeed struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadRotationSpeed(ch *RawCharacteristic) *RotationSpeed {
+func ReadRotationSpeed(ch *RawCharacteristic) (*RotationSpeed, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read RotationSpeed: %v", err)
+	}
 	return &RotationSpeed{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type Saturation struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadSaturation(ch *RawCharacteristic) *Saturation {
+func ReadSaturation(ch *RawCharacteristic) (*Saturation, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read Saturation: %v", err)
+	}
 	return &Saturation{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type SecuritySystemAlarmType struct {
@@ -979,11 +1378,15 @@ type SecuritySystemAlarmType struct {
 	Value byte
 }
 
-func ReadSecuritySystemAlarmType(ch *RawCharacteristic) *SecuritySystemAlarmType {
+func ReadSecuritySystemAlarmType(ch *RawCharacteristic) (*SecuritySystemAlarmType, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read SecuritySystemAlarmType: %v", err)
+	}
 	return &SecuritySystemAlarmType{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SecuritySystemCurrentState struct {
@@ -991,11 +1394,15 @@ type SecuritySystemCurrentState struct {
 	Value byte
 }
 
-func ReadSecuritySystemCurrentState(ch *RawCharacteristic) *SecuritySystemCurrentState {
+func ReadSecuritySystemCurrentState(ch *RawCharacteristic) (*SecuritySystemCurrentState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read SecuritySystemCurrentState: %v", err)
+	}
 	return &SecuritySystemCurrentState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SecuritySystemTargetState struct {
@@ -1003,11 +1410,15 @@ type SecuritySystemTargetState struct {
 	Value byte
 }
 
-func ReadSecuritySystemTargetState(ch *RawCharacteristic) *SecuritySystemTargetState {
+func ReadSecuritySystemTargetState(ch *RawCharacteristic) (*SecuritySystemTargetState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read SecuritySystemTargetState: %v", err)
+	}
 	return &SecuritySystemTargetState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SelectedAudioStreamConfiguration struct {
@@ -1015,11 +1426,15 @@ type SelectedAudioStreamConfiguration struct {
 	Value []byte
 }
 
-func ReadSelectedAudioStreamConfiguration(ch *RawCharacteristic) *SelectedAudioStreamConfiguration {
+func ReadSelectedAudioStreamConfiguration(ch *RawCharacteristic) (*SelectedAudioStreamConfiguration, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read SelectedAudioStreamConfiguration: %v", err)
+	}
 	return &SelectedAudioStreamConfiguration{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SerialNumber struct {
@@ -1027,11 +1442,15 @@ type SerialNumber struct {
 	Value string
 }
 
-func ReadSerialNumber(ch *RawCharacteristic) *SerialNumber {
+func ReadSerialNumber(ch *RawCharacteristic) (*SerialNumber, error) {
+	v, err := ch.Value.String()
+	if err != nil {
+		return nil, fmt.Errorf("read SerialNumber: %v", err)
+	}
 	return &SerialNumber{
 		ID:    ch.ID,
-		Value: ch.Value.MustString(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ServiceLabelIndex struct {
@@ -1039,11 +1458,15 @@ type ServiceLabelIndex struct {
 	Value byte
 }
 
-func ReadServiceLabelIndex(ch *RawCharacteristic) *ServiceLabelIndex {
+func ReadServiceLabelIndex(ch *RawCharacteristic) (*ServiceLabelIndex, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read ServiceLabelIndex: %v", err)
+	}
 	return &ServiceLabelIndex{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type ServiceLabelNamespace struct {
@@ -1051,11 +1474,15 @@ type ServiceLabelNamespace struct {
 	Value byte
 }
 
-func ReadServiceLabelNamespace(ch *RawCharacteristic) *ServiceLabelNamespace {
+func ReadServiceLabelNamespace(ch *RawCharacteristic) (*ServiceLabelNamespace, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read ServiceLabelNamespace: %v", err)
+	}
 	return &ServiceLabelNamespace{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SetupDataStreamTransport struct {
@@ -1063,11 +1490,15 @@ type SetupDataStreamTransport struct {
 	Value []byte
 }
 
-func ReadSetupDataStreamTransport(ch *RawCharacteristic) *SetupDataStreamTransport {
+func ReadSetupDataStreamTransport(ch *RawCharacteristic) (*SetupDataStreamTransport, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read SetupDataStreamTransport: %v", err)
+	}
 	return &SetupDataStreamTransport{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SelectedRTPStreamConfiguration struct {
@@ -1075,11 +1506,15 @@ type SelectedRTPStreamConfiguration struct {
 	Value []byte
 }
 
-func ReadSelectedRTPStreamConfiguration(ch *RawCharacteristic) *SelectedRTPStreamConfiguration {
+func ReadSelectedRTPStreamConfiguration(ch *RawCharacteristic) (*SelectedRTPStreamConfiguration, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read SelectedRTPStreamConfiguration: %v", err)
+	}
 	return &SelectedRTPStreamConfiguration{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SetupEndpoints struct {
@@ -1087,11 +1522,15 @@ type SetupEndpoints struct {
 	Value []byte
 }
 
-func ReadSetupEndpoints(ch *RawCharacteristic) *SetupEndpoints {
+func ReadSetupEndpoints(ch *RawCharacteristic) (*SetupEndpoints, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read SetupEndpoints: %v", err)
+	}
 	return &SetupEndpoints{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SiriInputType struct {
@@ -1099,11 +1538,15 @@ type SiriInputType struct {
 	Value byte
 }
 
-func ReadSiriInputType(ch *RawCharacteristic) *SiriInputType {
+func ReadSiriInputType(ch *RawCharacteristic) (*SiriInputType, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read SiriInputType: %v", err)
+	}
 	return &SiriInputType{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SlatType struct {
@@ -1111,11 +1554,15 @@ type SlatType struct {
 	Value byte
 }
 
-func ReadSlatType(ch *RawCharacteristic) *SlatType {
+func ReadSlatType(ch *RawCharacteristic) (*SlatType, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read SlatType: %v", err)
+	}
 	return &SlatType{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SmokeDetected struct {
@@ -1123,11 +1570,15 @@ type SmokeDetected struct {
 	Value byte
 }
 
-func ReadSmokeDetected(ch *RawCharacteristic) *SmokeDetected {
+func ReadSmokeDetected(ch *RawCharacteristic) (*SmokeDetected, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read SmokeDetected: %v", err)
+	}
 	return &SmokeDetected{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type StatusActive struct {
@@ -1135,11 +1586,15 @@ type StatusActive struct {
 	Value bool
 }
 
-func ReadStatusActive(ch *RawCharacteristic) *StatusActive {
+func ReadStatusActive(ch *RawCharacteristic) (*StatusActive, error) {
+	v, err := ch.Value.Bool()
+	if err != nil {
+		return nil, fmt.Errorf("read StatusActive: %v", err)
+	}
 	return &StatusActive{
 		ID:    ch.ID,
-		Value: ch.Value.MustBool(),
-	}
+		Value: v,
+	}, nil
 }
 
 type StatusFault struct {
@@ -1147,11 +1602,15 @@ type StatusFault struct {
 	Value byte
 }
 
-func ReadStatusFault(ch *RawCharacteristic) *StatusFault {
+func ReadStatusFault(ch *RawCharacteristic) (*StatusFault, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read StatusFault: %v", err)
+	}
 	return &StatusFault{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type StatusJammed struct {
@@ -1159,11 +1618,15 @@ type StatusJammed struct {
 	Value byte
 }
 
-func ReadStatusJammed(ch *RawCharacteristic) *StatusJammed {
+func ReadStatusJammed(ch *RawCharacteristic) (*StatusJammed, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read StatusJammed: %v", err)
+	}
 	return &StatusJammed{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type StatusLowBattery struct {
@@ -1171,11 +1634,15 @@ type StatusLowBattery struct {
 	Value byte
 }
 
-func ReadStatusLowBattery(ch *RawCharacteristic) *StatusLowBattery {
+func ReadStatusLowBattery(ch *RawCharacteristic) (*StatusLowBattery, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read StatusLowBattery: %v", err)
+	}
 	return &StatusLowBattery{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type StatusTampered struct {
@@ -1183,11 +1650,15 @@ type StatusTampered struct {
 	Value byte
 }
 
-func ReadStatusTampered(ch *RawCharacteristic) *StatusTampered {
+func ReadStatusTampered(ch *RawCharacteristic) (*StatusTampered, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read StatusTampered: %v", err)
+	}
 	return &StatusTampered{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type StreamingStatus struct {
@@ -1195,11 +1666,15 @@ type StreamingStatus struct {
 	Value []byte
 }
 
-func ReadStreamingStatus(ch *RawCharacteristic) *StreamingStatus {
+func ReadStreamingStatus(ch *RawCharacteristic) (*StreamingStatus, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read StreamingStatus: %v", err)
+	}
 	return &StreamingStatus{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SupportedAudioStreamConfiguration struct {
@@ -1207,11 +1682,15 @@ type SupportedAudioStreamConfiguration struct {
 	Value []byte
 }
 
-func ReadSupportedAudioStreamConfiguration(ch *RawCharacteristic) *SupportedAudioStreamConfiguration {
+func ReadSupportedAudioStreamConfiguration(ch *RawCharacteristic) (*SupportedAudioStreamConfiguration, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read SupportedAudioStreamConfiguration: %v", err)
+	}
 	return &SupportedAudioStreamConfiguration{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SupportedDataStreamTransportConfiguration struct {
@@ -1219,11 +1698,15 @@ type SupportedDataStreamTransportConfiguration struct {
 	Value []byte
 }
 
-func ReadSupportedDataStreamTransportConfiguration(ch *RawCharacteristic) *SupportedDataStreamTransportConfiguration {
+func ReadSupportedDataStreamTransportConfiguration(ch *RawCharacteristic) (*SupportedDataStreamTransportConfiguration, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read SupportedDataStreamTransportConfiguration: %v", err)
+	}
 	return &SupportedDataStreamTransportConfiguration{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SupportedRTPConfiguration struct {
@@ -1231,11 +1714,15 @@ type SupportedRTPConfiguration struct {
 	Value []byte
 }
 
-func ReadSupportedRTPConfiguration(ch *RawCharacteristic) *SupportedRTPConfiguration {
+func ReadSupportedRTPConfiguration(ch *RawCharacteristic) (*SupportedRTPConfiguration, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read SupportedRTPConfiguration: %v", err)
+	}
 	return &SupportedRTPConfiguration{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SupportedVideoStreamConfiguration struct {
@@ -1243,23 +1730,33 @@ type SupportedVideoStreamConfiguration struct {
 	Value []byte
 }
 
-func ReadSupportedVideoStreamConfiguration(ch *RawCharacteristic) *SupportedVideoStreamConfiguration {
+func ReadSupportedVideoStreamConfiguration(ch *RawCharacteristic) (*SupportedVideoStreamConfiguration, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read SupportedVideoStreamConfiguration: %v", err)
+	}
 	return &SupportedVideoStreamConfiguration{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type SulphurDioxideDensity struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadSulphurDioxideDensity(ch *RawCharacteristic) *SulphurDioxideDensity {
+func ReadSulphurDioxideDensity(ch *RawCharacteristic) (*SulphurDioxideDensity, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read SulphurDioxideDensity: %v", err)
+	}
 	return &SulphurDioxideDensity{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "micrograms/m^3"),
+	}, nil
 }
 
 type SwingMode struct {
@@ -1267,11 +1764,15 @@ type SwingMode struct {
 	Value byte
 }
 
-func ReadSwingMode(ch *RawCharacteristic) *SwingMode {
+func ReadSwingMode(ch *RawCharacteristic) (*SwingMode, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read SwingMode: %v", err)
+	}
 	return &SwingMode{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetAirPurifierState struct {
@@ -1279,11 +1780,15 @@ type TargetAirPurifierState struct {
 	Value byte
 }
 
-func ReadTargetAirPurifierState(ch *RawCharacteristic) *TargetAirPurifierState {
+func ReadTargetAirPurifierState(ch *RawCharacteristic) (*TargetAirPurifierState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetAirPurifierState: %v", err)
+	}
 	return &TargetAirPurifierState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetFanState struct {
@@ -1291,23 +1796,33 @@ type TargetFanState struct {
 	Value byte
 }
 
-func ReadTargetFanState(ch *RawCharacteristic) *TargetFanState {
+func ReadTargetFanState(ch *RawCharacteristic) (*TargetFanState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetFanState: %v", err)
+	}
 	return &TargetFanState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetTiltAngle struct {
 	ID    uint64
 	Value int32
+	Unit  units.Unit
 }
 
-func ReadTargetTiltAngle(ch *RawCharacteristic) *TargetTiltAngle {
+func ReadTargetTiltAngle(ch *RawCharacteristic) (*TargetTiltAngle, error) {
+	v, err := ch.Value.Int32()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetTiltAngle: %v", err)
+	}
 	return &TargetTiltAngle{
 		ID:    ch.ID,
-		Value: ch.Value.MustInt32(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "arcdegrees"),
+	}, nil
 }
 
 type SetDuration struct {
@@ -1315,11 +1830,15 @@ type SetDuration struct {
 	Value uint32
 }
 
-func ReadSetDuration(ch *RawCharacteristic) *SetDuration {
+func ReadSetDuration(ch *RawCharacteristic) (*SetDuration, error) {
+	v, err := ch.Value.Uint32()
+	if err != nil {
+		return nil, fmt.Errorf("read SetDuration: %v", err)
+	}
 	return &SetDuration{
 		ID:    ch.ID,
-		Value: ch.Value.MustUint32(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetControlSupportedConfiguration struct {
@@ -1327,11 +1846,15 @@ type TargetControlSupportedConfiguration struct {
 	Value []byte
 }
 
-func ReadTargetControlSupportedConfiguration(ch *RawCharacteristic) *TargetControlSupportedConfiguration {
+func ReadTargetControlSupportedConfiguration(ch *RawCharacteristic) (*TargetControlSupportedConfiguration, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetControlSupportedConfiguration: %v", err)
+	}
 	return &TargetControlSupportedConfiguration{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetControlList struct {
@@ -1339,23 +1862,33 @@ type TargetControlList struct {
 	Value []byte
 }
 
-func ReadTargetControlList(ch *RawCharacteristic) *TargetControlList {
+func ReadTargetControlList(ch *RawCharacteristic) (*TargetControlList, error) {
+	v, err := ch.Value.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetControlList: %v", err)
+	}
 	return &TargetControlList{
 		ID:    ch.ID,
-		Value: ch.Value.MustBytes(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetHorizontalTiltAngle struct {
 	ID    uint64
 	Value int32
+	Unit  units.Unit
 }
 
-func ReadTargetHorizontalTiltAngle(ch *RawCharacteristic) *TargetHorizontalTiltAngle {
+func ReadTargetHorizontalTiltAngle(ch *RawCharacteristic) (*TargetHorizontalTiltAngle, error) {
+	v, err := ch.Value.Int32()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetHorizontalTiltAngle: %v", err)
+	}
 	return &TargetHorizontalTiltAngle{
 		ID:    ch.ID,
-		Value: ch.Value.MustInt32(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "arcdegrees"),
+	}, nil
 }
 
 type TargetHeaterCoolerState struct {
@@ -1363,11 +1896,15 @@ type TargetHeaterCoolerState struct {
 	Value byte
 }
 
-func ReadTargetHeaterCoolerState(ch *RawCharacteristic) *TargetHeaterCoolerState {
+func ReadTargetHeaterCoolerState(ch *RawCharacteristic) (*TargetHeaterCoolerState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetHeaterCoolerState: %v", err)
+	}
 	return &TargetHeaterCoolerState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetHumidifierDehumidifierState struct {
@@ -1375,23 +1912,33 @@ type TargetHumidifierDehumidifierState struct {
 	Value byte
 }
 
-func ReadTargetHumidifierDehumidifierState(ch *RawCharacteristic) *TargetHumidifierDehumidifierState {
+func ReadTargetHumidifierDehumidifierState(ch *RawCharacteristic) (*TargetHumidifierDehumidifierState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetHumidifierDehumidifierState: %v", err)
+	}
 	return &TargetHumidifierDehumidifierState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetPosition struct {
 	ID    uint64
 	Value byte
+	Unit  units.Unit
 }
 
-func ReadTargetPosition(ch *RawCharacteristic) *TargetPosition {
+func ReadTargetPosition(ch *RawCharacteristic) (*TargetPosition, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetPosition: %v", err)
+	}
 	return &TargetPosition{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type TargetDoorState struct {
@@ -1399,11 +1946,15 @@ type TargetDoorState struct {
 	Value byte
 }
 
-func ReadTargetDoorState(ch *RawCharacteristic) *TargetDoorState {
+func ReadTargetDoorState(ch *RawCharacteristic) (*TargetDoorState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetDoorState: %v", err)
+	}
 	return &TargetDoorState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetHeatingCoolingState struct {
@@ -1411,35 +1962,56 @@ type TargetHeatingCoolingState struct {
 	Value byte
 }
 
-func ReadTargetHeatingCoolingState(ch *RawCharacteristic) *TargetHeatingCoolingState {
+func ReadTargetHeatingCoolingState(ch *RawCharacteristic) (*TargetHeatingCoolingState, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetHeatingCoolingState: %v", err)
+	}
 	return &TargetHeatingCoolingState{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetRelativeHumidity struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadTargetRelativeHumidity(ch *RawCharacteristic) *TargetRelativeHumidity {
+func ReadTargetRelativeHumidity(ch *RawCharacteristic) (*TargetRelativeHumidity, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetRelativeHumidity: %v", err)
+	}
 	return &TargetRelativeHumidity{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type TargetTemperature struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadTargetTemperature(ch *RawCharacteristic) *TargetTemperature {
+func ReadTargetTemperature(ch *RawCharacteristic) (*TargetTemperature, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetTemperature: %v", err)
+	}
 	return &TargetTemperature{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "celsius"),
+	}, nil
+}
+
+// Fahrenheit returns the temperature in degrees Fahrenheit
+func (c *TargetTemperature) Fahrenheit() float64 {
+	return units.CelsiusToFahrenheit(c.Value)
 }
 
 type TemperatureDisplayUnits struct {
@@ -1447,23 +2019,33 @@ type TemperatureDisplayUnits struct {
 	Value byte
 }
 
-func ReadTemperatureDisplayUnits(ch *RawCharacteristic) *TemperatureDisplayUnits {
+func ReadTemperatureDisplayUnits(ch *RawCharacteristic) (*TemperatureDisplayUnits, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read TemperatureDisplayUnits: %v", err)
+	}
 	return &TemperatureDisplayUnits{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type TargetVerticalTiltAngle struct {
 	ID    uint64
 	Value int32
+	Unit  units.Unit
 }
 
-func ReadTargetVerticalTiltAngle(ch *RawCharacteristic) *TargetVerticalTiltAngle {
+func ReadTargetVerticalTiltAngle(ch *RawCharacteristic) (*TargetVerticalTiltAngle, error) {
+	v, err := ch.Value.Int32()
+	if err != nil {
+		return nil, fmt.Errorf("read TargetVerticalTiltAngle: %v", err)
+	}
 	return &TargetVerticalTiltAngle{
 		ID:    ch.ID,
-		Value: ch.Value.MustInt32(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "arcdegrees"),
+	}, nil
 }
 
 type ValveType struct {
@@ -1471,11 +2053,15 @@ type ValveType struct {
 	Value byte
 }
 
-func ReadValveType(ch *RawCharacteristic) *ValveType {
+func ReadValveType(ch *RawCharacteristic) (*ValveType, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read ValveType: %v", err)
+	}
 	return &ValveType{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+	}, nil
 }
 
 type Version struct {
@@ -1483,45 +2069,67 @@ type Version struct {
 	Value string
 }
 
-func ReadVersion(ch *RawCharacteristic) *Version {
+func ReadVersion(ch *RawCharacteristic) (*Version, error) {
+	v, err := ch.Value.String()
+	if err != nil {
+		return nil, fmt.Errorf("read Version: %v", err)
+	}
 	return &Version{
 		ID:    ch.ID,
-		Value: ch.Value.MustString(),
-	}
+		Value: v,
+	}, nil
 }
 
 type VOCDensity struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadVOCDensity(ch *RawCharacteristic) *VOCDensity {
+func ReadVOCDensity(ch *RawCharacteristic) (*VOCDensity, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read VOCDensity: %v", err)
+	}
 	return &VOCDensity{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "micrograms/m^3"),
+	}, nil
 }
 
 type Volume struct {
 	ID    uint64
 	Value byte
+	Unit  units.Unit
 }
 
-func ReadVolume(ch *RawCharacteristic) *Volume {
+func ReadVolume(ch *RawCharacteristic) (*Volume, error) {
+	v, err := ch.Value.Byte()
+	if err != nil {
+		return nil, fmt.Errorf("read Volume: %v", err)
+	}
 	return &Volume{
 		ID:    ch.ID,
-		Value: ch.Value.MustByte(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
 
 type WaterLevel struct {
 	ID    uint64
 	Value float64
+	Unit  units.Unit
 }
 
-func ReadWaterLevel(ch *RawCharacteristic) *WaterLevel {
+func ReadWaterLevel(ch *RawCharacteristic) (*WaterLevel, error) {
+	v, err := ch.Value.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("read WaterLevel: %v", err)
+	}
 	return &WaterLevel{
 		ID:    ch.ID,
-		Value: ch.Value.MustFloat64(),
-	}
+		Value: v,
+		Unit:  readUnit(ch, "percentage"),
+	}, nil
 }
@@ -3,261 +3,1555 @@
 package characteristic
 
 const (
-	TypeAccessoryFlags = "A6"
-	TypeActive = "B0"
-	TypeActiveIdentifier = "E7"
-	TypeAdministratorOnlyAccess = "1"
-	TypeAudioFeedback = "5"
-	TypeAirParticulateDensity = "64"
-	TypeAirParticulateSize = "65"
-	TypeAirQuality = "95"
-	TypeBatteryLevel = "68"
-	TypeBrightness = "8"
-	TypeButtonEvent = "126"
-	TypeCarbonMonoxideLevel = "90"
-	TypeCarbonMonoxidePeakLevel = "91"
-	TypeCarbonDioxideDetected = "92"
-	TypeCarbonDioxideLevel = "93"
-	TypeCarbonDioxidePeakLevel = "94"
-	TypeCarbonMonoxideDetected = "69"
-	TypeChargingState = "8F"
-	TypeCoolingThresholdTemperature = "D"
-	TypeColorTemperature = "CE"
-	TypeContactSensorState = "6A"
-	TypeCurrentAmbientLightLevel = "6B"
-	TypeCurrentHorizontalTiltAngle = "6C"
-	TypeCurrentAirPurifierState = "A9"
-	TypeCurrentSlatState = "AA"
-	TypeCurrentPosition = "6D"
-	TypeCurrentVerticalTiltAngle = "6E"
-	TypeCurrentHumidifierDehumidifierState = "B3"
-	TypeCurrentDoorState = "E"
-	TypeCurrentFanState = "AF"
-	TypeCurrentHeatingCoolingState = "F"
-	TypeCurrentHeaterCoolerState = "B1"
-	TypeCurrentRelativeHumidity = "10"
-	TypeCurrentTemperature = "11"
-	TypeCurrentTiltAngle = "C1"
-	TypeDigitalZoom = "11D"
-	TypeFilterLifeLevel = "AB"
-	TypeFilterChangeIndication = "AC"
-	TypeFirmwareRevision = "52"
-	TypeHardwareRevision = "53"
-	TypeHeatingThresholdTemperature = "12"
-	TypeHoldPosition = "6F"
-	TypeHue = "13"
-	TypeIdentify = "14"
-	TypeImageRotation = "11E"
-	TypeImageMirroring = "11F"
-	TypeInUse = "D2"
-	TypeIsConfigured = "D6"
-	TypeLeakDetected = "70"
-	TypeLockControlPoint = "19"
-	TypeLockCurrentState = "1D"
-	TypeLockLastKnownAction = "1C"
-	TypeLockManagementAutoSecurityTimeout = "1A"
-	TypeLockPhysicalControls = "A7"
-	TypeLockTargetState = "1E"
-	TypeLogs = "1F"
-	TypeManufacturer = "20"
-	TypeModel = "21"
-	TypeMotionDetected = "22"
-	TypeMute = "11A"
-	TypeName = "23"
-	TypeNightVision = "11B"
-	TypeNitrogenDioxideDensity = "C4"
-	TypeObstructionDetected = "24"
-	TypePM25Density = "C6"
-	TypeOccupancyDetected = "71"
-	TypeOpticalZoom = "11C"
-	TypeOutletInUse = "26"
-	TypeOn = "25"
-	TypeOzoneDensity = "C3"
-	TypePM10Density = "C7"
-	TypePositionState = "72"
-	TypeProgramMode = "D1"
-	TypeProgrammableSwitchEvent = "73"
-	TypeRelativeHumidityDehumidifierThreshold = "C9"
-	TypeRelativeHumidityHumidifierThreshold = "CA"
-	TypeRemainingDuration = "D4"
-	TypeResetFilterIndication = "AD"
-	TypeRotationDirection = "28"
-	TypeRotationSpeed = "29"
-	TypeSaturation = "2F"
-	TypeSecuritySystemAlarmType = "8E"
-	TypeSecuritySystemCurrentState = "66"
-	TypeSecuritySystemTargetState = "67"
-	TypeSelectedAudioStreamConfiguration = "128"
-	TypeSerialNumber = "30"
-	TypeServiceLabelIndex = "CB"
-	TypeServiceLabelNamespace = "CD"
-	TypeSetupDataStreamTransport = "131"
-	TypeSelectedRTPStreamConfiguration = "117"
-	TypeSetupEndpoints = "118"
-	TypeSiriInputType = "132"
-	TypeSlatType = "C0"
-	TypeSmokeDetected = "76"
-	TypeStatusActive = "75"
-	TypeStatusFault = "77"
-	TypeStatusJammed = "78"
-	TypeStatusLowBattery = "79"
-	TypeStatusTampered = "7A"
-	TypeStreamingStatus = "120"
-	TypeSupportedAudioStreamConfiguration = "115"
+	TypeAccessoryFlags                            = "A6"
+	TypeActive                                    = "B0"
+	TypeActiveIdentifier                          = "E7"
+	TypeAdministratorOnlyAccess                   = "1"
+	TypeAudioFeedback                             = "5"
+	TypeAirParticulateDensity                     = "64"
+	TypeAirParticulateSize                        = "65"
+	TypeAirQuality                                = "95"
+	TypeBatteryLevel                              = "68"
+	TypeBrightness                                = "8"
+	TypeButtonEvent                               = "126"
+	TypeCarbonMonoxideLevel                       = "90"
+	TypeCarbonMonoxidePeakLevel                   = "91"
+	TypeCarbonDioxideDetected                     = "92"
+	TypeCarbonDioxideLevel                        = "93"
+	TypeCarbonDioxidePeakLevel                    = "94"
+	TypeCarbonMonoxideDetected                    = "69"
+	TypeChargingState                             = "8F"
+	TypeCoolingThresholdTemperature               = "D"
+	TypeColorTemperature                          = "CE"
+	TypeContactSensorState                        = "6A"
+	TypeCurrentAmbientLightLevel                  = "6B"
+	TypeCurrentHorizontalTiltAngle                = "6C"
+	TypeCurrentAirPurifierState                   = "A9"
+	TypeCurrentSlatState                          = "AA"
+	TypeCurrentPosition                           = "6D"
+	TypeCurrentVerticalTiltAngle                  = "6E"
+	TypeCurrentHumidifierDehumidifierState        = "B3"
+	TypeCurrentDoorState                          = "E"
+	TypeCurrentFanState                           = "AF"
+	TypeCurrentHeatingCoolingState                = "F"
+	TypeCurrentHeaterCoolerState                  = "B1"
+	TypeCurrentRelativeHumidity                   = "10"
+	TypeCurrentTemperature                        = "11"
+	TypeCurrentTiltAngle                          = "C1"
+	TypeDigitalZoom                               = "11D"
+	TypeFilterLifeLevel                           = "AB"
+	TypeFilterChangeIndication                    = "AC"
+	TypeFirmwareRevision                          = "52"
+	TypeHardwareRevision                          = "53"
+	TypeHeatingThresholdTemperature               = "12"
+	TypeHoldPosition                              = "6F"
+	TypeHue                                       = "13"
+	TypeIdentify                                  = "14"
+	TypeImageRotation                             = "11E"
+	TypeImageMirroring                            = "11F"
+	TypeInUse                                     = "D2"
+	TypeIsConfigured                              = "D6"
+	TypeLeakDetected                              = "70"
+	TypeLockControlPoint                          = "19"
+	TypeLockCurrentState                          = "1D"
+	TypeLockLastKnownAction                       = "1C"
+	TypeLockManagementAutoSecurityTimeout         = "1A"
+	TypeLockPhysicalControls                      = "A7"
+	TypeLockTargetState                           = "1E"
+	TypeLogs                                      = "1F"
+	TypeManufacturer                              = "20"
+	TypeModel                                     = "21"
+	TypeMotionDetected                            = "22"
+	TypeMute                                      = "11A"
+	TypeName                                      = "23"
+	TypeNightVision                               = "11B"
+	TypeNitrogenDioxideDensity                    = "C4"
+	TypeObstructionDetected                       = "24"
+	TypePM25Density                               = "C6"
+	TypeOccupancyDetected                         = "71"
+	TypeOpticalZoom                               = "11C"
+	TypeOutletInUse                               = "26"
+	TypeOn                                        = "25"
+	TypeOzoneDensity                              = "C3"
+	TypePM10Density                               = "C7"
+	TypePositionState                             = "72"
+	TypeProgramMode                               = "D1"
+	TypeProgrammableSwitchEvent                   = "73"
+	TypeRelativeHumidityDehumidifierThreshold     = "C9"
+	TypeRelativeHumidityHumidifierThreshold       = "CA"
+	TypeRemainingDuration                         = "D4"
+	TypeResetFilterIndication                     = "AD"
+	TypeRotationDirection                         = "28"
+	TypeRotationSpeed                             = "29"
+	TypeSaturation                                = "2F"
+	TypeSecuritySystemAlarmType                   = "8E"
+	TypeSecuritySystemCurrentState                = "66"
+	TypeSecuritySystemTargetState                 = "67"
+	TypeSelectedAudioStreamConfiguration          = "128"
+	TypeSerialNumber                              = "30"
+	TypeServiceLabelIndex                         = "CB"
+	TypeServiceLabelNamespace                     = "CD"
+	TypeSetupDataStreamTransport                  = "131"
+	TypeSelectedRTPStreamConfiguration            = "117"
+	TypeSetupEndpoints                            = "118"
+	TypeSiriInputType                             = "132"
+	TypeSlatType                                  = "C0"
+	TypeSmokeDetected                             = "76"
+	TypeStatusActive                              = "75"
+	TypeStatusFault                               = "77"
+	TypeStatusJammed                              = "78"
+	TypeStatusLowBattery                          = "79"
+	TypeStatusTampered                            = "7A"
+	TypeStreamingStatus                           = "120"
+	TypeSupportedAudioStreamConfiguration         = "115"
 	TypeSupportedDataStreamTransportConfiguration = "130"
-	TypeSupportedRTPConfiguration = "116"
-	TypeSupportedVideoStreamConfiguration = "114"
-	TypeSulphurDioxideDensity = "C5"
-	TypeSwingMode = "B6"
-	TypeTargetAirPurifierState = "A8"
-	TypeTargetFanState = "BF"
-	TypeTargetTiltAngle = "C2"
-	TypeSetDuration = "D3"
-	TypeTargetControlSupportedConfiguration = "123"
-	TypeTargetControlList = "124"
-	TypeTargetHorizontalTiltAngle = "7B"
-	TypeTargetHeaterCoolerState = "B2"
-	TypeTargetHumidifierDehumidifierState = "B4"
-	TypeTargetPosition = "7C"
-	TypeTargetDoorState = "32"
-	TypeTargetHeatingCoolingState = "33"
-	TypeTargetRelativeHumidity = "34"
-	TypeTargetTemperature = "35"
-	TypeTemperatureDisplayUnits = "36"
-	TypeTargetVerticalTiltAngle = "7D"
-	TypeValveType = "D5"
-	TypeVersion = "37"
-	TypeVOCDensity = "C8"
-	TypeVolume = "119"
-	TypeWaterLevel = "B5"
+	TypeSupportedRTPConfiguration                 = "116"
+	TypeSupportedVideoStreamConfiguration         = "114"
+	TypeSulphurDioxideDensity                     = "C5"
+	TypeSwingMode                                 = "B6"
+	TypeTargetAirPurifierState                    = "A8"
+	TypeTargetFanState                            = "BF"
+	TypeTargetTiltAngle                           = "C2"
+	TypeSetDuration                               = "D3"
+	TypeTargetControlSupportedConfiguration       = "123"
+	TypeTargetControlList                         = "124"
+	TypeTargetHorizontalTiltAngle                 = "7B"
+	TypeTargetHeaterCoolerState                   = "B2"
+	TypeTargetHumidifierDehumidifierState         = "B4"
+	TypeTargetPosition                            = "7C"
+	TypeTargetDoorState                           = "32"
+	TypeTargetHeatingCoolingState                 = "33"
+	TypeTargetRelativeHumidity                    = "34"
+	TypeTargetTemperature                         = "35"
+	TypeTemperatureDisplayUnits                   = "36"
+	TypeTargetVerticalTiltAngle                   = "7D"
+	TypeValveType                                 = "D5"
+	TypeVersion                                   = "37"
+	TypeVOCDensity                                = "C8"
+	TypeVolume                                    = "119"
+	TypeWaterLevel                                = "B5"
 )
 
 var typeMetadatas = []*TypeMetadata{
-	{TypeAccessoryFlags, "AccessoryFlags", "uint32"},
-	{TypeActive, "Active", "uint8"},
-	{TypeActiveIdentifier, "ActiveIdentifier", "uint32"},
-	{TypeAdministratorOnlyAccess, "AdministratorOnlyAccess", "bool"},
-	{TypeAudioFeedback, "AudioFeedback", "bool"},
-	{TypeAirParticulateDensity, "AirParticulateDensity", "float"},
-	{TypeAirParticulateSize, "AirParticulateSize", "uint8"},
-	{TypeAirQuality, "AirQuality", "uint8"},
-	{TypeBatteryLevel, "BatteryLevel", "uint8"},
-	{TypeBrightness, "Brightness", "int"},
-	{TypeButtonEvent, "ButtonEvent", "tlv8"},
-	{TypeCarbonMonoxideLevel, "CarbonMonoxideLevel", "float"},
-	{TypeCarbonMonoxidePeakLevel, "CarbonMonoxidePeakLevel", "float"},
-	{TypeCarbonDioxideDetected, "CarbonDioxideDetected", "uint8"},
-	{TypeCarbonDioxideLevel, "CarbonDioxideLevel", "float"},
-	{TypeCarbonDioxidePeakLevel, "CarbonDioxidePeakLevel", "float"},
-	{TypeCarbonMonoxideDetected, "CarbonMonoxideDetected", "uint8"},
-	{TypeChargingState, "ChargingState", "uint8"},
-	{TypeCoolingThresholdTemperature, "CoolingThresholdTemperature", "float"},
-	{TypeColorTemperature, "ColorTemperature", "uint32"},
-	{TypeContactSensorState, "ContactSensorState", "uint8"},
-	{TypeCurrentAmbientLightLevel, "CurrentAmbientLightLevel", "float"},
-	{TypeCurrentHorizontalTiltAngle, "CurrentHorizontalTiltAngle", "int"},
-	{TypeCurrentAirPurifierState, "CurrentAirPurifierState", "uint8"},
-	{TypeCurrentSlatState, "CurrentSlatState", "uint8"},
-	{TypeCurrentPosition, "CurrentPosition", "uint8"},
-	{TypeCurrentVerticalTiltAngle, "CurrentVerticalTiltAngle", "int"},
-	{TypeCurrentHumidifierDehumidifierState, "CurrentHumidifierDehumidifierState", "uint8"},
-	{TypeCurrentDoorState, "CurrentDoorState", "uint8"},
-	{TypeCurrentFanState, "CurrentFanState", "uint8"},
-	{TypeCurrentHeatingCoolingState, "CurrentHeatingCoolingState", "uint8"},
-	{TypeCurrentHeaterCoolerState, "CurrentHeaterCoolerState", "uint8"},
-	{TypeCurrentRelativeHumidity, "CurrentRelativeHumidity", "float"},
-	{TypeCurrentTemperature, "CurrentTemperature", "float"},
-	{TypeCurrentTiltAngle, "CurrentTiltAngle", "int"},
-	{TypeDigitalZoom, "DigitalZoom", "float"},
-	{TypeFilterLifeLevel, "FilterLifeLevel", "float"},
-	{TypeFilterChangeIndication, "FilterChangeIndication", "uint8"},
-	{TypeFirmwareRevision, "FirmwareRevision", "string"},
-	{TypeHardwareRevision, "HardwareRevision", "string"},
-	{TypeHeatingThresholdTemperature, "HeatingThresholdTemperature", "float"},
-	{TypeHoldPosition, "HoldPosition", "bool"},
-	{TypeHue, "Hue", "float"},
-	{TypeIdentify, "Identify", "bool"},
-	{TypeImageRotation, "ImageRotation", "float"},
-	{TypeImageMirroring, "ImageMirroring", "bool"},
-	{TypeInUse, "InUse", "uint8"},
-	{TypeIsConfigured, "IsConfigured", "uint8"},
-	{TypeLeakDetected, "LeakDetected", "uint8"},
-	{TypeLockControlPoint, "LockControlPoint", "tlv8"},
-	{TypeLockCurrentState, "LockCurrentState", "uint8"},
-	{TypeLockLastKnownAction, "LockLastKnownAction", "uint8"},
-	{TypeLockManagementAutoSecurityTimeout, "LockManagementAutoSecurityTimeout", "uint32"},
-	{TypeLockPhysicalControls, "LockPhysicalControls", "uint8"},
-	{TypeLockTargetState, "LockTargetState", "uint8"},
-	{TypeLogs, "Logs", "tlv8"},
-	{TypeManufacturer, "Manufacturer", "string"},
-	{TypeModel, "Model", "string"},
-	{TypeMotionDetected, "MotionDetected", "bool"},
-	{TypeMute, "Mute", "bool"},
-	{TypeName, "Name", "string"},
-	{TypeNightVision, "NightVision", "bool"},
-	{TypeNitrogenDioxideDensity, "NitrogenDioxideDensity", "float"},
-	{TypeObstructionDetected, "ObstructionDetected", "bool"},
-	{TypePM25Density, "PM2.5Density", "float"},
-	{TypeOccupancyDetected, "OccupancyDetected", "uint8"},
-	{TypeOpticalZoom, "OpticalZoom", "float"},
-	{TypeOutletInUse, "OutletInUse", "bool"},
-	{TypeOn, "On", "bool"},
-	{TypeOzoneDensity, "OzoneDensity", "float"},
-	{TypePM10Density, "PM10Density", "float"},
-	{TypePositionState, "PositionState", "uint8"},
-	{TypeProgramMode, "ProgramMode", "uint8"},
-	{TypeProgrammableSwitchEvent, "ProgrammableSwitchEvent", "uint8"},
-	{TypeRelativeHumidityDehumidifierThreshold, "RelativeHumidityDehumidifierThreshold", "float"},
-	{TypeRelativeHumidityHumidifierThreshold, "RelativeHumidityHumidifierThreshold", "float"},
-	{TypeRemainingDuration, "RemainingDuration", "uint32"},
-	{TypeResetFilterIndication, "ResetFilterIndication", "uint8"},
-	{TypeRotationDirection, "RotationDirection", "int"},
-	{TypeRotationSpeed, "RotationSpeed", "float"},
-	{TypeSaturation, "Saturation", "float"},
-	{TypeSecuritySystemAlarmType, "SecuritySystemAlarmType", "uint8"},
-	{TypeSecuritySystemCurrentState, "SecuritySystemCurrentState", "uint8"},
-	{TypeSecuritySystemTargetState, "SecuritySystemTargetState", "uint8"},
-	{TypeSelectedAudioStreamConfiguration, "SelectedAudioStreamConfiguration", "tlv8"},
-	{TypeSerialNumber, "SerialNumber", "string"},
-	{TypeServiceLabelIndex, "ServiceLabelIndex", "uint8"},
-	{TypeServiceLabelNamespace, "ServiceLabelNamespace", "uint8"},
-	{TypeSetupDataStreamTransport, "SetupDataStreamTransport", "tlv8"},
-	{TypeSelectedRTPStreamConfiguration, "SelectedRTPStreamConfiguration", "tlv8"},
-	{TypeSetupEndpoints, "SetupEndpoints", "tlv8"},
-	{TypeSiriInputType, "SiriInputType", "uint8"},
-	{TypeSlatType, "SlatType", "uint8"},
-	{TypeSmokeDetected, "SmokeDetected", "uint8"},
-	{TypeStatusActive, "StatusActive", "bool"},
-	{TypeStatusFault, "StatusFault", "uint8"},
-	{TypeStatusJammed, "StatusJammed", "uint8"},
-	{TypeStatusLowBattery, "StatusLowBattery", "uint8"},
-	{TypeStatusTampered, "StatusTampered", "uint8"},
-	{TypeStreamingStatus, "StreamingStatus", "tlv8"},
-	{TypeSupportedAudioStreamConfiguration, "SupportedAudioStreamConfiguration", "tlv8"},
-	{TypeSupportedDataStreamTransportConfiguration, "SupportedDataStreamTransportConfiguration", "tlv8"},
-	{TypeSupportedRTPConfiguration, "SupportedRTPConfiguration", "tlv8"},
-	{TypeSupportedVideoStreamConfiguration, "SupportedVideoStreamConfiguration", "tlv8"},
-	{TypeSulphurDioxideDensity, "SulphurDioxideDensity", "float"},
-	{TypeSwingMode, "SwingMode", "uint8"},
-	{TypeTargetAirPurifierState, "TargetAirPurifierState", "uint8"},
-	{TypeTargetFanState, "TargetFanState", "uint8"},
-	{TypeTargetTiltAngle, "TargetTiltAngle", "int"},
-	{TypeSetDuration, "SetDuration", "uint32"},
-	{TypeTargetControlSupportedConfiguration, "TargetControlSupportedConfiguration", "tlv8"},
-	{TypeTargetControlList, "TargetControlList", "tlv8"},
-	{TypeTargetHorizontalTiltAngle, "TargetHorizontalTiltAngle", "int"},
-	{TypeTargetHeaterCoolerState, "TargetHeaterCoolerState", "uint8"},
-	{TypeTargetHumidifierDehumidifierState, "TargetHumidifierDehumidifierState", "uint8"},
-	{TypeTargetPosition, "TargetPosition", "uint8"},
-	{TypeTargetDoorState, "TargetDoorState", "uint8"},
-	{TypeTargetHeatingCoolingState, "TargetHeatingCoolingState", "uint8"},
-	{TypeTargetRelativeHumidity, "TargetRelativeHumidity", "float"},
-	{TypeTargetTemperature, "TargetTemperature", "float"},
-	{TypeTemperatureDisplayUnits, "TemperatureDisplayUnits", "uint8"},
-	{TypeTargetVerticalTiltAngle, "TargetVerticalTiltAngle", "int"},
-	{TypeValveType, "ValveType", "uint8"},
-	{TypeVersion, "Version", "string"},
-	{TypeVOCDensity, "VOCDensity", "float"},
-	{TypeVolume, "Volume", "uint8"},
-	{TypeWaterLevel, "WaterLevel", "float"},
+	{
+		Type:        TypeAccessoryFlags,
+		Name:        "AccessoryFlags",
+		Description: "When set indicates accessory requires additional setup.",
+		Format:      "uint32",
+		Permissions: []string{"pr", "ev"},
+		ValidValues: map[string]string{
+			"1": "Requires additional setup",
+		},
+	},
+	{
+		Type:        TypeActive,
+		Name:        "Active",
+		Description: "The Active characteristic indicates whether the service is currently active.",
+		Format:      "uint8",
+		Permissions: []string{"pw", "pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Inactive",
+			"1": "Active",
+		},
+	},
+	{
+		Type:        TypeActiveIdentifier,
+		Name:        "ActiveIdentifier",
+		Description: "This HAP characteristic allows the accessory to indicate the target that is currently selected in the UI of the accessory.",
+		Format:      "uint32",
+		Permissions: []string{"pr", "ev"},
+	},
+	{
+		Type:        TypeAdministratorOnlyAccess,
+		Name:        "AdministratorOnlyAccess",
+		Description: "When this mode is enabled, the accessory only accepts administrator access.",
+		Format:      "bool",
+		Permissions: []string{"pr", "pw", "ev"},
+	},
+	{
+		Type:        TypeAudioFeedback,
+		Name:        "AudioFeedback",
+		Description: "This characteristic describes whether audio feedback (e.g. a beep, or other external sound mechanism) is enabled.",
+		Format:      "bool",
+		Permissions: []string{"pr", "pw", "ev"},
+	},
+	{
+		Type:        TypeAirParticulateDensity,
+		Name:        "AirParticulateDensity",
+		Description: "This characteristic indicates the current air particulate matter density in micrograms/m 3.",
+		Format:      "float",
+		Unit:        "micrograms/m^3",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1000),
+	},
+	{
+		Type:        TypeAirParticulateSize,
+		Name:        "AirParticulateSize",
+		Description: "This characteristic indicates the size of air particulate matter in micrometers.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "2.5 Micrometers",
+			"1": "10 Micrometers",
+		},
+	},
+	{
+		Type:        TypeAirQuality,
+		Name:        "AirQuality",
+		Description: "This characteristic describes the subject assessment of air quality by an accessory.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(5),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Unknown",
+			"1": "Excellent",
+			"2": "Good",
+			"3": "Fair",
+			"4": "Inferior",
+			"5": "Poor",
+		},
+	},
+	{
+		Type:        TypeBatteryLevel,
+		Name:        "BatteryLevel",
+		Description: "This characteristic describes the current level of the battery.",
+		Format:      "uint8",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeBrightness,
+		Name:        "Brightness",
+		Description: "This characteristic describes a perceived level of brightness, e.g. for lighting, and can be used for backlights or color.",
+		Format:      "int",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeButtonEvent,
+		Name:        "ButtonEvent",
+		Description: "Notifications on this characteristic can only be enabled by Admin controllers.",
+		Format:      "tlv8",
+		Permissions: []string{"pr", "ev"},
+	},
+	{
+		Type:        TypeCarbonMonoxideLevel,
+		Name:        "CarbonMonoxideLevel",
+		Description: "This characteristic indicates the Carbon Monoxide levels detected in parts per million (ppm).",
+		Format:      "float",
+		Unit:        "ppm",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+	},
+	{
+		Type:        TypeCarbonMonoxidePeakLevel,
+		Name:        "CarbonMonoxidePeakLevel",
+		Description: "This characteristic indicates the highest detected level (ppm) of Carbon Monoxide detected by a sensor.",
+		Format:      "float",
+		Unit:        "ppm",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+	},
+	{
+		Type:        TypeCarbonDioxideDetected,
+		Name:        "CarbonDioxideDetected",
+		Description: "This characteristic indicates if a sensor detects abnormal levels of Carbon Dioxide.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Carbon Dioxide levels are normal",
+			"1": "Carbon Dioxide levels are abnormal",
+		},
+	},
+	{
+		Type:        TypeCarbonDioxideLevel,
+		Name:        "CarbonDioxideLevel",
+		Description: "This characteristic indicates the detected level of Carbon Dioxide in parts per million (ppm).",
+		Format:      "float",
+		Unit:        "ppm",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100000),
+	},
+	{
+		Type:        TypeCarbonDioxidePeakLevel,
+		Name:        "CarbonDioxidePeakLevel",
+		Description: "This characteristic indicates the highest detected level (ppm) of carbon dioxide detected by a sensor.",
+		Format:      "float",
+		Unit:        "ppm",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100000),
+	},
+	{
+		Type:        TypeCarbonMonoxideDetected,
+		Name:        "CarbonMonoxideDetected",
+		Description: "This characteristic indicates if a sensor detects abnormal levels of Carbon Monoxide.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Carbon Monoxide levels are normal",
+			"1": "Carbon Monoxide levels are abnormal",
+		},
+	},
+	{
+		Type:        TypeChargingState,
+		Name:        "ChargingState",
+		Description: "This characteristic describes the charging state of a battery or an accessory.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Not Charging",
+			"1": "Charging",
+			"2": "Not Chargeable",
+		},
+	},
+	{
+		Type:        TypeCoolingThresholdTemperature,
+		Name:        "CoolingThresholdTemperature",
+		Description: "This characteristic describes the cooling threshold in Celsius for accessories that support simultaneous heating and cooling. The value of this characteristic represents the maximum temperature that must be reached before cooling is turned on.",
+		Format:      "float",
+		Unit:        "celsius",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(10),
+		MaxValue:    floatPtr(35),
+		StepValue:   floatPtr(0.1),
+	},
+	{
+		Type:        TypeColorTemperature,
+		Name:        "ColorTemperature",
+		Description: "This characteristic describes color temperature which is represented in reciprocal megaKelvin (MK -1 ) or mirek scale.",
+		Format:      "uint32",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(50),
+		MaxValue:    floatPtr(400),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeContactSensorState,
+		Name:        "ContactSensorState",
+		Description: "This characteristic describes the state of a door/window contact sensor.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Contact is detected",
+			"1": "Contact is not detected",
+		},
+	},
+	{
+		Type:        TypeCurrentAmbientLightLevel,
+		Name:        "CurrentAmbientLightLevel",
+		Description: "This characteristic indicates the current light level. The value is expressed in Lux units (lumens/m 2 )",
+		Format:      "float",
+		Unit:        "lux",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0.0001),
+		MaxValue:    floatPtr(100000),
+	},
+	{
+		Type:        TypeCurrentHorizontalTiltAngle,
+		Name:        "CurrentHorizontalTiltAngle",
+		Description: "This characteristic describes the current angle of horizontal slats for accessories such as windows, fans, portable heater/coolers etc. This characteristic takes values between -90 and 90. A value of 0 indicates that the slats are rotated to a fully open position. A value of -90 indicates that the slats are rotated all the way in a direction where the user-facing edge is higher than the window-facing edge.",
+		Format:      "int",
+		Unit:        "arcdegrees",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(-90),
+		MaxValue:    floatPtr(90),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeCurrentAirPurifierState,
+		Name:        "CurrentAirPurifierState",
+		Description: "This characteristic describes the current state of the air purifier.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Inactive",
+			"1": "Idle",
+			"2": "Purifying Air",
+		},
+	},
+	{
+		Type:        TypeCurrentSlatState,
+		Name:        "CurrentSlatState",
+		Description: "This characteristic describes the current state of the slats.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Fixed",
+			"1": "Jammed",
+			"2": "Swinging",
+		},
+	},
+	{
+		Type:        TypeCurrentPosition,
+		Name:        "CurrentPosition",
+		Description: "This characteristic describes the current position of accessories.",
+		Format:      "uint8",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeCurrentVerticalTiltAngle,
+		Name:        "CurrentVerticalTiltAngle",
+		Description: "This characteristic describes the current angle of vertical slats for accessories such as windows, fans, portable heater/coolers etc. This characteristic takes values between -90 and 90. A value of 0 indicates that the slats are rotated to be fully open. A value of -90 indicates that the slats are rotated all the way in a direction where the user-facing edge is to the left of the window-facing edge.",
+		Format:      "int",
+		Unit:        "arcdegrees",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(-90),
+		MaxValue:    floatPtr(90),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeCurrentHumidifierDehumidifierState,
+		Name:        "CurrentHumidifierDehumidifierState",
+		Description: "This characteristic describes the current state of a humidifier or/and a dehumidifier.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(3),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Inactive",
+			"1": "Idle",
+			"2": "Humidifying",
+			"3": "Dehumidifying",
+		},
+	},
+	{
+		Type:        TypeCurrentDoorState,
+		Name:        "CurrentDoorState",
+		Description: "This characteristic describes the current state of a door.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(4),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Open. The door is fully open.",
+			"1": "Closed. The door is fully closed.",
+			"2": "Opening. The door is actively opening.",
+			"3": "Closing. The door is actively closing.",
+			"4": "Stopped. The door is not moving, and it is not fully open nor fully closed.",
+		},
+	},
+	{
+		Type:        TypeCurrentFanState,
+		Name:        "CurrentFanState",
+		Description: "This characteristic describes the current state of the fan.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Inactive",
+			"1": "Idle",
+			"2": "Blowing Air",
+		},
+	},
+	{
+		Type:        TypeCurrentHeatingCoolingState,
+		Name:        "CurrentHeatingCoolingState",
+		Description: "This characteristic describes the current mode of an accessory that supports cooling or heating its environment, e.g. a thermostat is \"heating\" a room to 75 degrees Fahrenheit.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Off.",
+			"1": "Heat. The Heater is currently on.",
+			"2": "Cool. Cooler is currently on.",
+		},
+	},
+	{
+		Type:        TypeCurrentHeaterCoolerState,
+		Name:        "CurrentHeaterCoolerState",
+		Description: "This characteristic describes the current state of a heater cooler.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(3),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Inactive",
+			"1": "Idle",
+			"2": "Heating",
+			"3": "Cooling",
+		},
+	},
+	{
+		Type:        TypeCurrentRelativeHumidity,
+		Name:        "CurrentRelativeHumidity",
+		Description: "This characteristic describes the current relative humidity of the accessoryʼs environment. The value is expressed as a percentage (%).",
+		Format:      "float",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeCurrentTemperature,
+		Name:        "CurrentTemperature",
+		Description: "This characteristic describes the current temperature of the environment in Celsius.",
+		Format:      "float",
+		Unit:        "celsius",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(0.1),
+	},
+	{
+		Type:        TypeCurrentTiltAngle,
+		Name:        "CurrentTiltAngle",
+		Description: "This characteristic describes the current angle of slats for accessories such as windows, fans, portable heater/coolers etc.",
+		Format:      "int",
+		Unit:        "arcdegrees",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(-90),
+		MaxValue:    floatPtr(90),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeDigitalZoom,
+		Name:        "DigitalZoom",
+		Description: "A Digital Zoom characteristic allows the control of digital zoom of a video RTP service.",
+		Format:      "float",
+		Permissions: []string{"pw", "pr", "ev"},
+	},
+	{
+		Type:        TypeFilterLifeLevel,
+		Name:        "FilterLifeLevel",
+		Description: "This characteristic describes the current filter life level.",
+		Format:      "float",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeFilterChangeIndication,
+		Name:        "FilterChangeIndication",
+		Description: "This characteristic describes if a filter needs to be changed.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Filter does not need to be changed",
+			"1": "Filter needs to be changed",
+		},
+	},
+	{
+		Type:        TypeFirmwareRevision,
+		Name:        "FirmwareRevision",
+		Description: "This characteristic describes a firmware revision string x[.y[.z]] (e.g. \"100.1.1\")",
+		Format:      "string",
+		Permissions: []string{"pr"},
+	},
+	{
+		Type:        TypeHardwareRevision,
+		Name:        "HardwareRevision",
+		Description: "This characteristic describes a hardware revision string x[.y[.z]] (e.g. \"100.1.1\") and tracked when the board or components of the same accessory is changed.",
+		Format:      "string",
+		Permissions: []string{"pr"},
+	},
+	{
+		Type:        TypeHeatingThresholdTemperature,
+		Name:        "HeatingThresholdTemperature",
+		Description: "This characteristic describes the heating threshold in Celsius for accessories that support simultaneous heating and cooling. The value of this characteristic represents the minimum temperature that must be reached before heating is turned on.",
+		Format:      "float",
+		Unit:        "celsius",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(25),
+		StepValue:   floatPtr(0.1),
+	},
+	{
+		Type:        TypeHoldPosition,
+		Name:        "HoldPosition",
+		Description: "This characteristic causes the service such as door or window covering to stop at its current position. A value of 1 must hold the state of the accessory. For e.g, the window must stop moving when this characteristic is written a value of 1. A value of 0 should be ignored. A write to \"Target Position\" will release the hold.",
+		Format:      "bool",
+		Permissions: []string{"pw"},
+	},
+	{
+		Type:        TypeHue,
+		Name:        "Hue",
+		Description: "This characteristic describes hue or color.",
+		Format:      "float",
+		Unit:        "arcdegrees",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(360),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeIdentify,
+		Name:        "Identify",
+		Description: "This characteristic enables accessory to run its identify routine.",
+		Format:      "bool",
+		Permissions: []string{"pw"},
+	},
+	{
+		Type:        TypeImageRotation,
+		Name:        "ImageRotation",
+		Description: "An Image Rotation characteristic allows the control of rotation of the image of a video RTP service.",
+		Format:      "float",
+		Permissions: []string{"pw", "pr", "ev"},
+		ValidValues: map[string]string{
+			"0":   "No rotation",
+			"90":  "Rotated 90 degrees to the right",
+			"180": "Rotated 180 degrees to the right (flipped vertically)",
+			"270": "Rotated 270 degrees to the right",
+		},
+	},
+	{
+		Type:        TypeImageMirroring,
+		Name:        "ImageMirroring",
+		Description: "An Image Mirroring characteristic allows the control of mirroring state of the image of a video RTP service.",
+		Format:      "bool",
+		Unit:        "arcdegrees",
+		Permissions: []string{"pw", "pr", "ev"},
+		ValidValues: map[string]string{
+			"0": "Image is not mirrored",
+			"1": "Image is mirrored",
+		},
+	},
+	{
+		Type:        TypeInUse,
+		Name:        "InUse",
+		Description: "This characteristic describes if the service is in use. The service must be Active before the value of this characteristic can be set to in use.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Not in use",
+			"1": "In use",
+		},
+	},
+	{
+		Type:        TypeIsConfigured,
+		Name:        "IsConfigured",
+		Description: "This characteristic describes if the service is configured for use. For example, all of the valves in an irrigation system may not be configured depending on physical wire connection.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Not Configured",
+			"1": "Configured",
+		},
+	},
+	{
+		Type:        TypeLeakDetected,
+		Name:        "LeakDetected",
+		Description: "This characteristic indicates if a sensor detected a leak (e.g. water leak, gas leak). A value of 1 indicates that a leak is detected. Value should return to 0 when leak stops.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Leak is not detected",
+			"1": "Leak is detected",
+		},
+	},
+	{
+		Type:        TypeLockControlPoint,
+		Name:        "LockControlPoint",
+		Description: "The accessory accepts writes to this characteristic to perform vendor-specific actions as well as those defined by the \"8.25 Lock Management\" (page 148) of the \"10.2 Lock\" (page 236) . For example, user management related functions should be defined and performed using this characteristic.",
+		Format:      "tlv8",
+		Permissions: []string{"pw"},
+	},
+	{
+		Type:        TypeLockCurrentState,
+		Name:        "LockCurrentState",
+		Description: "The current state of the physical security mechanism (e.g. deadbolt).",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(3),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Unsecured",
+			"1": "Secured",
+			"2": "Jammed",
+			"3": "Unknown",
+		},
+	},
+	{
+		Type:        TypeLockLastKnownAction,
+		Name:        "LockLastKnownAction",
+		Description: "The last known action of the lock mechanism (e.g. deadbolt).",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(8),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Secured using physical movement, interior",
+			"1": "Unsecured using physical movement, interior",
+			"2": "Secured using physical movement, exterior",
+			"3": "Unsecured using physical movement, exterior",
+			"4": "Secured with keypad",
+			"5": "Unsecured with keypad",
+			"6": "Secured remotely",
+			"7": "Unsecured remotely",
+			"8": "Secured with Automatic Secure timeout",
+		},
+	},
+	{
+		Type:        TypeLockManagementAutoSecurityTimeout,
+		Name:        "LockManagementAutoSecurityTimeout",
+		Description: "A value greater than 0 indicates if the lock mechanism enters the unsecured state, it will automatically attempt to enter the secured state after n seconds, where n is the value provided in the write. A value of 0 indicates this feature is disabled.",
+		Format:      "uint32",
+		Unit:        "seconds",
+		Permissions: []string{"pr", "pw", "ev"},
+	},
+	{
+		Type:        TypeLockPhysicalControls,
+		Name:        "LockPhysicalControls",
+		Description: "This characteristic describes a way to lock a set of physical controls on an accessory (eg. child lock).",
+		Format:      "uint8",
+		Permissions: []string{"pw", "pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Control lock disabled",
+			"1": "Control lock enabled",
+		},
+	},
+	{
+		Type:        TypeLockTargetState,
+		Name:        "LockTargetState",
+		Description: "The target state of the physical security mechanism (e.g. deadbolt).",
+		Format:      "uint8",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Unsecured",
+			"1": "Secured",
+		},
+	},
+	{
+		Type:        TypeLogs,
+		Name:        "Logs",
+		Description: "Read from this characteristic to get timestamped logs from the accessory.",
+		Format:      "tlv8",
+		Permissions: []string{"pr", "ev"},
+	},
+	{
+		Type:        TypeManufacturer,
+		Name:        "Manufacturer",
+		Description: "This characteristic contains the name of the company whose brand will appear on the accessory, e.g., \"Acme\".",
+		Format:      "string",
+		Permissions: []string{"pr"},
+		MaxLen:      intPtr(64),
+	},
+	{
+		Type:        TypeModel,
+		Name:        "Model",
+		Description: "This characteristic contains the manufacturer-specific model of the accessory, e.g. \"A1234\". The minimum length of this characteristic must be 1.",
+		Format:      "string",
+		Permissions: []string{"pr"},
+		MaxLen:      intPtr(64),
+	},
+	{
+		Type:        TypeMotionDetected,
+		Name:        "MotionDetected",
+		Description: "This characteristic indicates if motion (e.g. a person moving) was detected.",
+		Format:      "bool",
+		Permissions: []string{"pr", "ev"},
+	},
+	{
+		Type:        TypeMute,
+		Name:        "Mute",
+		Description: "A Mute characteristic allows the control of audio input or output accessory respectively.",
+		Format:      "bool",
+		Permissions: []string{"pw", "pr", "ev"},
+		ValidValues: map[string]string{
+			"0": "Mute is Off / Audio is On",
+			"1": "Mute is On / There is no Audio",
+		},
+	},
+	{
+		Type:        TypeName,
+		Name:        "Name",
+		Description: "This characteristic describes a name and must not be a null value.",
+		Format:      "string",
+		Permissions: []string{"pr"},
+		MaxLen:      intPtr(64),
+	},
+	{
+		Type:        TypeNightVision,
+		Name:        "NightVision",
+		Description: "A Night Vision characteristic allows the control of night vision mode on a video RTP service.",
+		Format:      "bool",
+		Permissions: []string{"pw", "pr", "ev"},
+		ValidValues: map[string]string{
+			"0": "Disable night-vision mode",
+			"1": "Enable night-vision mode",
+		},
+	},
+	{
+		Type:        TypeNitrogenDioxideDensity,
+		Name:        "NitrogenDioxideDensity",
+		Description: "This characteristic indicates the current NO2 density in micrograms/m 3.",
+		Format:      "float",
+		Unit:        "micrograms/m^3",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1000),
+	},
+	{
+		Type:        TypeObstructionDetected,
+		Name:        "ObstructionDetected",
+		Description: "This characteristic describes the current state of an obstruction sensor, such as one that is used in a garage door. If the state is true then there is an obstruction detected.",
+		Format:      "bool",
+		Permissions: []string{"pr", "ev"},
+	},
+	{
+		Type:        TypePM25Density,
+		Name:        "PM2.5Density",
+		Description: "This characteristic indicates the current PM2.5 micrometer particulate density in micrograms/m 3.",
+		Format:      "float",
+		Unit:        "micrograms/m^3",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1000),
+	},
+	{
+		Type:        TypeOccupancyDetected,
+		Name:        "OccupancyDetected",
+		Description: "This characteristic indicates if occupancy was detected (e.g. a person present). A value of 1 indicates occupancy is detected. Value should return to 0 when occupancy is not detected.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Occupancy is not detected",
+			"1": "Occupancy is detected",
+		},
+	},
+	{
+		Type:        TypeOpticalZoom,
+		Name:        "OpticalZoom",
+		Description: "A Digital Zoom characteristic allows the control of digital zoom of a video RTP service.",
+		Format:      "float",
+		Permissions: []string{"pw", "pr", "ev"},
+	},
+	{
+		Type:        TypeOutletInUse,
+		Name:        "OutletInUse",
+		Description: "This characteristic describes if the power outlet has an appliance e.g., a floor lamp, physically plugged in. This characteristic is set to True even if the plugged-in appliance is off.",
+		Format:      "bool",
+		Permissions: []string{"pr", "ev"},
+	},
+	{
+		Type:        TypeOn,
+		Name:        "On",
+		Description: "This characteristic represents the states for “on\" and “off\".",
+		Format:      "bool",
+		Permissions: []string{"pr", "pw", "ev"},
+	},
+	{
+		Type:        TypeOzoneDensity,
+		Name:        "OzoneDensity",
+		Description: "This characteristic indicates the current ozone density in micrograms/m 3.",
+		Format:      "float",
+		Unit:        "micrograms/m^3",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1000),
+	},
+	{
+		Type:        TypePM10Density,
+		Name:        "PM10Density",
+		Description: "This characteristic indicates the current PM10 micrometer particulate density in micrograms/m 3.",
+		Format:      "float",
+		Unit:        "micrograms/m^3",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1000),
+	},
+	{
+		Type:        TypePositionState,
+		Name:        "PositionState",
+		Description: "This characteristic describes the state of the position of accessories.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Going to the minimum value specified in metadata",
+			"1": "Going to the maximum value specified in metadata",
+			"2": "Stopped",
+		},
+	},
+	{
+		Type:        TypeProgramMode,
+		Name:        "ProgramMode",
+		Description: "This characteristic describes if there are programs scheduled on the accessory. If there are Programs scheduled on the accessory and the accessory is used for manual operation, the value of this characteristic must be Program Scheduled, currently overridden to manual mode.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "No Programs Scheduled",
+			"1": "Program Scheduled",
+			"2": "Program Scheduled, currently overriden to manual mode",
+		},
+	},
+	{
+		Type:        TypeProgrammableSwitchEvent,
+		Name:        "ProgrammableSwitchEvent",
+		Description: "This characteristic describes an event generated by a programmable switch.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Single Press",
+			"1": "Double Press",
+			"2": "Long Press",
+		},
+	},
+	{
+		Type:        TypeRelativeHumidityDehumidifierThreshold,
+		Name:        "RelativeHumidityDehumidifierThreshold",
+		Description: "This characteristic describes the relative humidity dehumidifier threshold. The value of this characteristic represents the maximum relative humidity that must be reached before dehumidifier is turned on.",
+		Format:      "float",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeRelativeHumidityHumidifierThreshold,
+		Name:        "RelativeHumidityHumidifierThreshold",
+		Description: "This characteristic describes the relative humidity humidifier threshold. The value of this characteristic represents the minimum relative humidity that must be reached before humidifier is turned on.",
+		Format:      "float",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeRemainingDuration,
+		Name:        "RemainingDuration",
+		Description: "This characteristic describes the remaining duration on the accessory in seconds.",
+		Format:      "uint32",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(3600),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeResetFilterIndication,
+		Name:        "ResetFilterIndication",
+		Description: "This characteristic allows a user to reset the filter indication. When the value of 1 is written to this characteristic by the user, the accessory should reset it to 0 once the relevant action to reset the filter indication is executed. If the accessory supports Filter Change Indication, the value of that characteristic should also reset back to 0.",
+		Format:      "uint8",
+		Permissions: []string{"pw"},
+		MinValue:    floatPtr(1),
+		MaxValue:    floatPtr(1),
+	},
+	{
+		Type:        TypeRotationDirection,
+		Name:        "RotationDirection",
+		Description: "This characteristic describes the direction of rotation of a fan.",
+		Format:      "int",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Clockwise",
+			"1": "Counter-clockwise",
+		},
+	},
+	{
+		Type:        TypeRotationSpeed,
+		Name:        "RotationSpeed",
+		Description: "This characteristic describes the rotation speed of a fan.",
+		Format:      "float",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeSaturation,
+		Name:        "Saturation",
+		Description: "This characteristic describes color saturation.",
+		Format:      "float",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeSecuritySystemAlarmType,
+		Name:        "SecuritySystemAlarmType",
+		Description: "This characteristic describes the type of alarm triggered by a security system. A value of 1 indicates an ʼunknownʼ cause. Value should revert to 0 when the alarm conditions are cleared.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeSecuritySystemCurrentState,
+		Name:        "SecuritySystemCurrentState",
+		Description: "This characteristic describes the state of a security system",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(4),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Stay Arm. The home is occupied and the residents are active. e.g. morning or evenings",
+			"1": "Away Arm. The home is unoccupied",
+			"2": "Night Arm. The home is occupied and the residents are sleeping",
+			"3": "Disarmed",
+			"4": "Alarm Triggered",
+		},
+	},
+	{
+		Type:        TypeSecuritySystemTargetState,
+		Name:        "SecuritySystemTargetState",
+		Description: "This characteristic describes the target state of the security system.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(3),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Stay Arm. The home is occupied and the residents are active. e.g. morning or evenings",
+			"1": "Away Arm. The home is unoccupied",
+			"2": "Night Arm. The home is occupied and the residents are sleeping",
+			"3": "Disarm",
+		},
+	},
+	{
+		Type:        TypeSelectedAudioStreamConfiguration,
+		Name:        "SelectedAudioStreamConfiguration",
+		Description: "This is a control point characteristic that allows a controller to specify the selected audio attributes to be used forstreaming audio from the accessory.",
+		Format:      "tlv8",
+		Permissions: []string{"pr", "pw"},
+	},
+	{
+		Type:        TypeSerialNumber,
+		Name:        "SerialNumber",
+		Description: "This characteristic contains the manufacturer-specific serial number of the accessory, e.g. \"1A2B3C4D5E6F\". The length must be greater than 1.",
+		Format:      "string",
+		Permissions: []string{"pr"},
+		MaxLen:      intPtr(64),
+	},
+	{
+		Type:        TypeServiceLabelIndex,
+		Name:        "ServiceLabelIndex",
+		Description: "This characteristic should be used identify the index of the label from the Service Label Namespace.",
+		Format:      "uint8",
+		Permissions: []string{"pr"},
+		MinValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeServiceLabelNamespace,
+		Name:        "ServiceLabelNamespace",
+		Description: "This characteristic describes the naming schema for an accessory. For example, this characteristic can be used to describe the type of labels used to identify individual services of an accessory.",
+		Format:      "uint8",
+		Permissions: []string{"pr"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Dots. For e.g . .. ...",
+			"1": "Arabic numerals. For e.g. 0,1,2,3",
+		},
+	},
+	{
+		Type:        TypeSetupDataStreamTransport,
+		Name:        "SetupDataStreamTransport",
+		Description: "This is a control point characteristic which allows the controller to set up the data stream.",
+		Format:      "tlv8",
+		Permissions: []string{"pr", "pw", "wr"},
+	},
+	{
+		Type:        TypeSelectedRTPStreamConfiguration,
+		Name:        "SelectedRTPStreamConfiguration",
+		Description: "The Selected RTP Stream Configuration characteristic is a control point characteristic that allows a controller to specify the selected video and audio attributes to be used for streaming audio and video from an IP camera accessory.",
+		Format:      "tlv8",
+		Permissions: []string{"pr", "pw"},
+	},
+	{
+		Type:        TypeSetupEndpoints,
+		Name:        "SetupEndpoints",
+		Description: "The Setup Endpoints characteristic allows a controller to exchange IP address and port information with the IP camera.",
+		Format:      "tlv8",
+		Permissions: []string{"pr", "pw"},
+	},
+	{
+		Type:        TypeSiriInputType,
+		Name:        "SiriInputType",
+		Description: "This characteristic describes the type of Siri input used by the accessory.",
+		Format:      "uint8",
+		Permissions: []string{"pr"},
+		ValidValues: map[string]string{
+			"0": "Push button triggered Apple TV",
+		},
+	},
+	{
+		Type:        TypeSlatType,
+		Name:        "SlatType",
+		Description: "This characteristic describes the type of the slats.",
+		Format:      "uint8",
+		Permissions: []string{"pr"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Horizontal",
+			"1": "Vertical",
+		},
+	},
+	{
+		Type:        TypeSmokeDetected,
+		Name:        "SmokeDetected",
+		Description: "This characteristic indicates if a sensor detects abnormal levels of smoke.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Smoke is not detected",
+			"1": "Smoke is detected",
+		},
+	},
+	{
+		Type:        TypeStatusActive,
+		Name:        "StatusActive",
+		Description: "This characteristic describes an accessoryʼs current working status. A value of true indicates that the accessory is active and is functioning without any errors.",
+		Format:      "bool",
+		Permissions: []string{"pr", "ev"},
+	},
+	{
+		Type:        TypeStatusFault,
+		Name:        "StatusFault",
+		Description: "This characteristic describes an accessory which has a fault. A non-zero value indicates that the accessory has experienced a fault that may be interfering with its intended functionality. A value of 0 indicates that there is no fault.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "No Fault",
+			"1": "General Fault",
+		},
+	},
+	{
+		Type:        TypeStatusJammed,
+		Name:        "StatusJammed",
+		Description: "This characteristic describes an accessory which is in a jammed state. A status of 1 indicates that an accessoryʼs mechanisms are jammed prevents it from functionality normally. Value should return to 0 when conditions that jam the accessory are rectified.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Not Jammed",
+			"1": "Jammed",
+		},
+	},
+	{
+		Type:        TypeStatusLowBattery,
+		Name:        "StatusLowBattery",
+		Description: "This characteristic describes an accessoryʼs battery status. A status of 1 indicates that the battery level of the accessory is low. Value should return to 0 when the battery charges to a level thats above the low threshold.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Battery level is normal",
+			"1": "Battery level is low",
+		},
+	},
+	{
+		Type:        TypeStatusTampered,
+		Name:        "StatusTampered",
+		Description: "This characteristic describes an accessory which has been tampered with. A status of 1 indicates that the accessory has been tampered with. Value should return to 0 when the accessory has been reset to a non-tampered state.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Accessory is not tampered",
+			"1": "Accessory is tampered with",
+		},
+	},
+	{
+		Type:        TypeStreamingStatus,
+		Name:        "StreamingStatus",
+		Description: "A Streaming Status characteristic allows an IP Camera accessory to describe the status of the RTP Stream Management service.",
+		Format:      "tlv8",
+		Permissions: []string{"pr", "ev"},
+	},
+	{
+		Type:        TypeSupportedAudioStreamConfiguration,
+		Name:        "SupportedAudioStreamConfiguration",
+		Description: "A Supported Audio Stream Configuration characteristic allows an accessory to indicate the parameters supported for streaming audio (from a microphone and/or to a speaker) over an RTP session.",
+		Format:      "tlv8",
+		Permissions: []string{"pr"},
+	},
+	{
+		Type:        TypeSupportedDataStreamTransportConfiguration,
+		Name:        "SupportedDataStreamTransportConfiguration",
+		Description: "This characteristics describes the data stream transport supported by the accessory.",
+		Format:      "tlv8",
+		Permissions: []string{"pr"},
+	},
+	{
+		Type:        TypeSupportedRTPConfiguration,
+		Name:        "SupportedRTPConfiguration",
+		Description: "The Supported RTP Configuration characteristic allows an accessory to describe the supported configuration parameters for the RTP video service used for streaming and other operations.",
+		Format:      "tlv8",
+		Permissions: []string{"pr"},
+	},
+	{
+		Type:        TypeSupportedVideoStreamConfiguration,
+		Name:        "SupportedVideoStreamConfiguration",
+		Description: "A Supported Video Stream Configuration characteristic allows an IP Camera accessory to describe the parameters supported for streaming video over an RTP session.",
+		Format:      "tlv8",
+		Permissions: []string{"pr"},
+	},
+	{
+		Type:        TypeSulphurDioxideDensity,
+		Name:        "SulphurDioxideDensity",
+		Description: "This characteristic indicates the current SO2 density in micrograms/m 3.",
+		Format:      "float",
+		Unit:        "micrograms/m^3",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1000),
+	},
+	{
+		Type:        TypeSwingMode,
+		Name:        "SwingMode",
+		Description: "This characteristic describes if swing mode is enabled.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev", "pw"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Swing disabled",
+			"1": "Swing enabled",
+		},
+	},
+	{
+		Type:        TypeTargetAirPurifierState,
+		Name:        "TargetAirPurifierState",
+		Description: "This characteristic describes the target state of the air purifier.",
+		Format:      "uint8",
+		Permissions: []string{"pw", "pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Manual",
+			"1": "Auto",
+		},
+	},
+	{
+		Type:        TypeTargetFanState,
+		Name:        "TargetFanState",
+		Description: "This characteristic describes the target state of the fan.",
+		Format:      "uint8",
+		Permissions: []string{"pw", "pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Manual",
+			"1": "Auto",
+		},
+	},
+	{
+		Type:        TypeTargetTiltAngle,
+		Name:        "TargetTiltAngle",
+		Description: "This characteristic describes the target angle of slats for accessories such as windows, fans, portable heater/coolers etc.",
+		Format:      "int",
+		Unit:        "arcdegrees",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(-90),
+		MaxValue:    floatPtr(90),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeSetDuration,
+		Name:        "SetDuration",
+		Description: "This characteristic describes the set duration in seconds.",
+		Format:      "uint32",
+		Permissions: []string{"pw", "pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(3600),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeTargetControlSupportedConfiguration,
+		Name:        "TargetControlSupportedConfiguration",
+		Description: "This characteristic allows the accessory to indicate the configuration it supports and is encoded as a list of TLV8 tuples.",
+		Format:      "tlv8",
+		Permissions: []string{"pr"},
+	},
+	{
+		Type:        TypeTargetControlList,
+		Name:        "TargetControlList",
+		Description: "This HAP characteristic allows a controller to manage the association of targets with the accessory.",
+		Format:      "tlv8",
+		Permissions: []string{"pw", "pr", "wr"},
+	},
+	{
+		Type:        TypeTargetHorizontalTiltAngle,
+		Name:        "TargetHorizontalTiltAngle",
+		Description: "This characteristic describes the target angle of horizontal slats for accessories such as windows, fans, portable heater/coolers etc.",
+		Format:      "int",
+		Unit:        "arcdegrees",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(-90),
+		MaxValue:    floatPtr(90),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeTargetHeaterCoolerState,
+		Name:        "TargetHeaterCoolerState",
+		Description: "This characteristic describes the target state of heater cooler.",
+		Format:      "uint8",
+		Permissions: []string{"pw", "pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Heat or Cool",
+			"1": "Heat",
+			"2": "Cool",
+		},
+	},
+	{
+		Type:        TypeTargetHumidifierDehumidifierState,
+		Name:        "TargetHumidifierDehumidifierState",
+		Description: "This characteristic describes the target state of a humidifier or/and a dehumidifier.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev", "pw"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(2),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Humidifier or Dehumidifier",
+			"1": "Humidifier",
+			"2": "Dehumidifier",
+		},
+	},
+	{
+		Type:        TypeTargetPosition,
+		Name:        "TargetPosition",
+		Description: "This characteristic describes the target position of accessories.",
+		Format:      "uint8",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeTargetDoorState,
+		Name:        "TargetDoorState",
+		Description: "This characteristic describes the target state of a door.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Open",
+			"1": "Closed",
+		},
+	},
+	{
+		Type:        TypeTargetHeatingCoolingState,
+		Name:        "TargetHeatingCoolingState",
+		Description: "This characteristic describes the target mode of an accessory that supports heating/cooling, e.g. a thermostat.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(3),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Off",
+			"1": "Heat. If the current temperature is below the target temperature then turn on heating.",
+			"2": "Cool. If the current temperature is above the target temperature then turn on cooling.",
+			"3": "Auto. Turn on heating or cooling to maintain temperature within the heating and cooling threshold of the target temperature.",
+		},
+	},
+	{
+		Type:        TypeTargetRelativeHumidity,
+		Name:        "TargetRelativeHumidity",
+		Description: "This characteristic describes the target relative humidity that the accessory is actively attempting to reach. The value is expressed as a percentage (%).",
+		Format:      "float",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeTargetTemperature,
+		Name:        "TargetTemperature",
+		Description: "This characteristic describes the target temperature in Celsius that the accessory is actively attempting to reach.",
+		Format:      "float",
+		Unit:        "celsius",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(10),
+		MaxValue:    floatPtr(38),
+		StepValue:   floatPtr(0.1),
+	},
+	{
+		Type:        TypeTemperatureDisplayUnits,
+		Name:        "TemperatureDisplayUnits",
+		Description: "This characteristic describes units of temperature used for presentation purposes (e.g. the units of temperature displayed on the screen).",
+		Format:      "uint8",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Celsius",
+			"1": "Fahrenheit",
+		},
+	},
+	{
+		Type:        TypeTargetVerticalTiltAngle,
+		Name:        "TargetVerticalTiltAngle",
+		Description: "This characteristic describes the target angle of vertical slats for accessories such as windows, fans, portable heater/coolers etc.",
+		Format:      "int",
+		Unit:        "arcdegrees",
+		Permissions: []string{"pr", "pw", "ev"},
+		MinValue:    floatPtr(-90),
+		MaxValue:    floatPtr(90),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeValveType,
+		Name:        "ValveType",
+		Description: "This characteristic describes the type of valve.",
+		Format:      "uint8",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(3),
+		StepValue:   floatPtr(1),
+		ValidValues: map[string]string{
+			"0": "Generic valve",
+			"1": "Irrigation",
+			"2": "Shower head",
+			"3": "Water faucet",
+		},
+	},
+	{
+		Type:        TypeVersion,
+		Name:        "Version",
+		Description: "This characteristic contains a version string.",
+		Format:      "string",
+		Permissions: []string{"pr"},
+		MaxLen:      intPtr(64),
+	},
+	{
+		Type:        TypeVOCDensity,
+		Name:        "VOCDensity",
+		Description: "This characteristic indicates the current volatile organic compound density in micrograms/m 3 .",
+		Format:      "float",
+		Unit:        "micrograms/m^3",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(1000),
+	},
+	{
+		Type:        TypeVolume,
+		Name:        "Volume",
+		Description: "A Volume characteristic allows the control of input or output volume of an audio input or output accessory respectively.",
+		Format:      "uint8",
+		Unit:        "percentage",
+		Permissions: []string{"pw", "pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
+	{
+		Type:        TypeWaterLevel,
+		Name:        "WaterLevel",
+		Description: "This characteristic describes the current water level.",
+		Format:      "float",
+		Unit:        "percentage",
+		Permissions: []string{"pr", "ev"},
+		MinValue:    floatPtr(0),
+		MaxValue:    floatPtr(100),
+		StepValue:   floatPtr(1),
+	},
 }
@@ -0,0 +1,42 @@
+package characteristic
+
+import (
+	"io"
+
+	"github.com/mctofu/homekit/client/definitions"
+)
+
+// TypeForUUID returns the type reported by accessories for a characteristic or service UUID.
+// UUIDs of types defined by the HAP specification are shortened, like
+// 0000006D-0000-1000-8000-0026BB765291 to 6D.
+func TypeForUUID(uuid string) string {
+	return definitions.TypeForUUID(uuid)
+}
+
+// ReadTypes reads characteristic types defined in the schema of characteristics.yaml. This
+// allows vendor types to be registered with RegisterType without writing code.
+func ReadTypes(r io.Reader) ([]*TypeMetadata, error) {
+	defs, err := definitions.ReadCharacteristics(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tms := make([]*TypeMetadata, 0, len(defs))
+	for _, def := range defs {
+		tms = append(tms, &TypeMetadata{
+			Type:        def.Type,
+			Name:        def.Name,
+			Description: def.Description,
+			Format:      def.Format,
+			Unit:        def.Unit,
+			Permissions: def.Permissions,
+			MinValue:    def.MinValue,
+			MaxValue:    def.MaxValue,
+			StepValue:   def.StepValue,
+			MaxLen:      def.MaxLength,
+			ValidValues: def.ValidValues,
+		})
+	}
+
+	return tms, nil
+}
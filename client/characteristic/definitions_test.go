@@ -0,0 +1,58 @@
+package characteristic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mctofu/homekit/client/units"
+	"github.com/stretchr/testify/require"
+)
+
+const eveConsumption = "E863F10D-079E-48FF-8F27-9C2605A29F52"
+
+func TestReadTypes(t *testing.T) {
+	tms, err := ReadTypes(strings.NewReader(`
+- name: Eve Consumption
+  uuid: e863f10d-079e-48ff-8f27-9c2605a29f52
+  permissions:
+    - Paired Read
+    - ev
+  format: float
+  unit: W
+  minValue: 0
+  maxValue: 65535
+- name: Lock Physical Controls
+  uuid: 000000A7-0000-1000-8000-0026BB765291
+  format: uint8
+  validValues:
+    "0": Control lock disabled
+    "1": >
+      Control lock
+      enabled
+`))
+	require.NoError(t, err)
+	require.Len(t, tms, 2)
+
+	maxValue := 65535.0
+	require.Equal(t, eveConsumption, tms[0].Type)
+	require.Equal(t, "EveConsumption", tms[0].Name)
+	require.Equal(t, units.Unit("W"), tms[0].Unit)
+	require.Equal(t, &maxValue, tms[0].MaxValue)
+	require.Equal(t, []string{PermissionPairedRead, PermissionEvents}, tms[0].Permissions)
+	require.Equal(t, "A7", tms[1].Type)
+	require.Equal(t, "Control lock enabled", tms[1].ValidValues["1"])
+
+	require.Equal(t, "<Unknown>", NameForType(eveConsumption))
+	RegisterType(*tms[0])
+	require.Equal(t, "EveConsumption", NameForType(eveConsumption))
+	v, err := ValueForType(eveConsumption, Value("12.5"))
+	require.NoError(t, err)
+	require.Equal(t, 12.5, v)
+
+	_, err = ReadTypes(strings.NewReader("- name: Broken\n  uuid: 1234\n  format: double\n"))
+	require.EqualError(t, err, `characteristic type Broken has unknown format "double"`)
+	_, err = ReadTypes(strings.NewReader("- name: Broken\n  format: float\n"))
+	require.EqualError(t, err, "characteristic type Broken requires a uuid")
+	_, err = ReadTypes(strings.NewReader("- name: Broken\n  uuid: 1234\n  format: float\n  permissions: [Paired Read, Secret]\n"))
+	require.EqualError(t, err, `characteristic type Broken has unknown permission "Secret"`)
+}
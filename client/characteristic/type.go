@@ -6,6 +6,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strconv"
+
+	"github.com/mctofu/homekit/client/units"
 )
 
 // RawCharacteristic captures information related to a particular characteristic
@@ -27,6 +29,64 @@ type RawCharacteristic struct {
 	StepValue Value `json:"minStep,omitempty"`
 }
 
+// Permissions a characteristic can advertise.
+const (
+	PermissionPairedRead              = "pr"
+	PermissionPairedWrite             = "pw"
+	PermissionEvents                  = "ev"
+	PermissionAdditionalAuthorization = "aa"
+	PermissionTimedWrite              = "tw"
+	PermissionHidden                  = "hd"
+	PermissionWriteResponse           = "wr"
+)
+
+// HasPermission returns true if the characteristic advertises the permission.
+func (r *RawCharacteristic) HasPermission(p string) bool {
+	for _, perm := range r.Permissions {
+		if perm == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValueFormat returns the format of the characteristic or the known format of its type if the
+// accessory omits it.
+func (r *RawCharacteristic) ValueFormat() string {
+	if r.Format != "" {
+		return r.Format
+	}
+	return FormatForType(r.Type)
+}
+
+// IsNumericFormat returns true if values of the format are integers or floats.
+func IsNumericFormat(format string) bool {
+	switch format {
+	case "uint8", "uint16", "uint32", "uint64", "int", "float":
+		return true
+	default:
+		return false
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// readUnit returns the unit reported by the characteristic or defaultUnit from the
+// specification if it's not reported.
+func readUnit(ch *RawCharacteristic, defaultUnit units.Unit) units.Unit {
+	if ch.Unit != "" {
+		return units.Unit(ch.Unit)
+	}
+	return defaultUnit
+}
+
 // UndefinedValue represents a value with an unknown format
 type UndefinedValue struct{}
 
@@ -44,9 +104,32 @@ func (u UnknownType) String() string {
 // TypeMetadata captures standard known metadata that applies to all
 // characteristics with the given type.
 type TypeMetadata struct {
-	Type   string
-	Name   string
-	Format string
+	Type        string
+	Name        string
+	Description string
+	Format      string
+	Unit        units.Unit
+	Permissions []string
+
+	// MinValue, MaxValue and StepValue are the default limits of numeric values.
+	MinValue  *float64
+	MaxValue  *float64
+	StepValue *float64
+	// MaxLen is the default maximum length of string values.
+	MaxLen *int
+	// ValidValues describe the meaning of values that are limited to a set.
+	ValidValues map[string]string
+}
+
+// HasPermission returns true if characteristics of the type have the permission.
+func (tm *TypeMetadata) HasPermission(p string) bool {
+	for _, perm := range tm.Permissions {
+		if perm == p {
+			return true
+		}
+	}
+
+	return false
 }
 
 var typeMetadataByType map[string]*TypeMetadata
@@ -63,6 +146,12 @@ func RegisterType(tm TypeMetadata) {
 	typeMetadataByType[tm.Type] = &tm
 }
 
+// MetadataForType returns the metadata registered for the type or nil if the type is
+// not registered. The metadata is shared and must not be modified.
+func MetadataForType(t string) *TypeMetadata {
+	return typeMetadataByType[t]
+}
+
 // NameForType returns a human friendly name for the type or "<Unknown>"
 // if the type is not registered.
 func NameForType(t string) string {
@@ -73,40 +162,60 @@ func NameForType(t string) string {
 	return tm.Name
 }
 
-// ValueForFormat will parse v to the type matching the format. The value
-// must match the format or this method will panic. If the format is unknown
+// FormatForType returns the format registered in the type's metadata or an empty
+// string if the type is not registered.
+func FormatForType(t string) string {
+	tm := typeMetadataByType[t]
+	if tm == nil {
+		return ""
+	}
+	return tm.Format
+}
+
+// UnitForType returns the unit registered in the type's metadata or an empty
+// string if the type is not registered or its values don't have a unit.
+func UnitForType(t string) units.Unit {
+	tm := typeMetadataByType[t]
+	if tm == nil {
+		return ""
+	}
+	return tm.Unit
+}
+
+// ValueForFormat will parse v to the type matching the format. An error is
+// returned if the value doesn't match the format. If the format is unknown
 // then an instance of UndefinedValue is returned.
-func ValueForFormat(f string, v Value) interface{} {
+func ValueForFormat(f string, v Value) (interface{}, error) {
 	switch f {
 	case "bool":
-		return v.MustBool()
+		return v.Bool()
 	case "uint8":
-		return v.MustByte()
+		return v.Byte()
 	case "uint16":
-		return v.MustUint16()
+		return v.Uint16()
 	case "uint32":
-		return v.MustUint32()
+		return v.Uint32()
 	case "uint64":
-		return v.MustUint64()
+		return v.Uint64()
 	case "int":
-		return v.MustInt32()
+		return v.Int32()
 	case "float":
-		return v.MustFloat64()
+		return v.Float64()
 	case "string":
-		return v.MustString()
+		return v.String()
 	case "tlv8", "data": // TODO: look at tlv8/data examples
-		return v.MustBytes()
+		return v.Bytes()
 	default:
-		return UndefinedValue{}
+		return UndefinedValue{}, nil
 	}
 }
 
 // ValueForType will parse v based on the format registered in the type's
 // metadata.
-func ValueForType(t string, v Value) interface{} {
+func ValueForType(t string, v Value) (interface{}, error) {
 	tm := typeMetadataByType[t]
 	if tm == nil {
-		return UnknownType{}
+		return UnknownType{}, nil
 	}
 	return ValueForFormat(tm.Format, v)
 }
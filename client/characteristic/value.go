@@ -3,6 +3,9 @@ package characteristic
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
 )
 
 // Value is a clone of json.RawMessage that lets us defer unmarshalling
@@ -49,17 +52,61 @@ func (v Value) MustString() string {
 	return s
 }
 
+// number unmarshals v to the text of a JSON number. It's empty if v is null.
+func (v Value) number() (string, error) {
+	var n json.Number
+	if err := json.Unmarshal(v, &n); err != nil {
+		return "", err
+	}
+	return string(n), nil
+}
+
+// unsigned unmarshals v to an unsigned integer of bitSize. Integral numbers with a fraction
+// or exponent such as 1.0 are accepted.
+func (v Value) unsigned(bitSize int) (uint64, error) {
+	n, err := v.number()
+	if err != nil || n == "" {
+		return 0, err
+	}
+	if u, err := strconv.ParseUint(n, 10, bitSize); err == nil {
+		return u, nil
+	}
+	f, err := strconv.ParseFloat(n, 64)
+	if err != nil || f != math.Trunc(f) || f < 0 || f >= math.Ldexp(1, bitSize) {
+		return 0, fmt.Errorf("invalid uint%d value %s", bitSize, v)
+	}
+	return uint64(f), nil
+}
+
+// signed unmarshals v to a signed integer of bitSize. Integral numbers with a fraction or
+// exponent such as 1.0 are accepted.
+func (v Value) signed(bitSize int) (int64, error) {
+	n, err := v.number()
+	if err != nil || n == "" {
+		return 0, err
+	}
+	if i, err := strconv.ParseInt(n, 10, bitSize); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(n, 64)
+	limit := math.Ldexp(1, bitSize-1)
+	if err != nil || f != math.Trunc(f) || f < -limit || f >= limit {
+		return 0, fmt.Errorf("invalid int%d value %s", bitSize, v)
+	}
+	return int64(f), nil
+}
+
 // Byte attempts to unmarshal v to a byte
 func (v Value) Byte() (byte, error) {
 	if v == nil {
 		return 0, nil
 	}
 
-	var b byte
-	if err := json.Unmarshal(v, &b); err != nil {
+	u, err := v.unsigned(8)
+	if err != nil {
 		return 0, err
 	}
-	return b, nil
+	return byte(u), nil
 }
 
 // MustByte attempts to unmarshal v to a byte and panics if unsuccessful
@@ -77,11 +124,11 @@ func (v Value) Uint16() (uint16, error) {
 		return 0, nil
 	}
 
-	var u uint16
-	if err := json.Unmarshal(v, &u); err != nil {
+	u, err := v.unsigned(16)
+	if err != nil {
 		return 0, err
 	}
-	return u, nil
+	return uint16(u), nil
 }
 
 // MustUint16 attempts to unmarshal v to a uint16 and panics if unsuccessful
@@ -99,11 +146,11 @@ func (v Value) Uint32() (uint32, error) {
 		return 0, nil
 	}
 
-	var u uint32
-	if err := json.Unmarshal(v, &u); err != nil {
+	u, err := v.unsigned(32)
+	if err != nil {
 		return 0, err
 	}
-	return u, nil
+	return uint32(u), nil
 }
 
 // MustUint32 attempts to unmarshal v to a uint32 and panics if unsuccessful
@@ -121,11 +168,11 @@ func (v Value) Uint64() (uint64, error) {
 		return 0, nil
 	}
 
-	var u uint64
-	if err := json.Unmarshal(v, &u); err != nil {
+	u, err := v.unsigned(64)
+	if err != nil {
 		return 0, err
 	}
-	return u, nil
+	return uint64(u), nil
 }
 
 // MustUint64 attempts to unmarshal v to a uint64 and panics if unsuccessful
@@ -143,11 +190,11 @@ func (v Value) Int32() (int32, error) {
 		return 0, nil
 	}
 
-	var i int32
-	if err := json.Unmarshal(v, &i); err != nil {
+	i, err := v.signed(32)
+	if err != nil {
 		return 0, err
 	}
-	return i, nil
+	return int32(i), nil
 }
 
 // MustInt32 attempts to unmarshal v to a int32 and panics if unsuccessful
@@ -181,17 +228,27 @@ func (v Value) MustFloat64() float64 {
 	return f
 }
 
-// Bool attempts to unmarshal v to a bool
+// Bool attempts to unmarshal v to a bool. The numbers 0 and 1 are accepted as HAP permits.
 func (v Value) Bool() (bool, error) {
 	if v == nil {
 		return false, nil
 	}
 
-	var b bool
+	var b interface{}
 	if err := json.Unmarshal(v, &b); err != nil {
 		return false, err
 	}
-	return b, nil
+	switch b := b.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return b, nil
+	case float64:
+		if b == 0 || b == 1 {
+			return b == 1, nil
+		}
+	}
+	return false, fmt.Errorf("invalid bool value %s", v)
 }
 
 // MustBool attempts to unmarshal v to a bool and panics if unsuccessful
@@ -0,0 +1,76 @@
+package characteristic
+
+import (
+	"testing"
+
+	"github.com/mctofu/homekit/client/units"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueForFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		want   interface{}
+		err    string
+	}{
+		{"uint8", "40", uint8(40), ""},
+		{"uint8", "40.0", uint8(40), ""},
+		{"uint8", "4e1", uint8(40), ""},
+		{"uint8", "null", uint8(0), ""},
+		{"uint8", "40.5", nil, "invalid uint8 value 40.5"},
+		{"uint8", "256", nil, "invalid uint8 value 256"},
+		{"uint8", "-1", nil, "invalid uint8 value -1"},
+		{"uint64", "18446744073709551615", uint64(18446744073709551615), ""},
+		{"int", "-5.0", int32(-5), ""},
+		{"int", "2147483648", nil, "invalid int32 value 2147483648"},
+		{"bool", "true", true, ""},
+		{"bool", "1", true, ""},
+		{"bool", "0", false, ""},
+		{"bool", "2", nil, "invalid bool value 2"},
+		{"float", "21", 21.0, ""},
+		{"string", "5", nil, "json: cannot unmarshal number into Go value of type string"},
+		{"unknown", "5", UndefinedValue{}, ""},
+	}
+	for _, test := range tests {
+		got, err := ValueForFormat(test.format, Value(test.value))
+		if test.err != "" {
+			require.EqualError(t, err, test.err, "%s %s", test.format, test.value)
+			continue
+		}
+		require.NoError(t, err, "%s %s", test.format, test.value)
+		require.Equal(t, test.want, got, "%s %s", test.format, test.value)
+	}
+}
+
+func TestReadCharacteristic(t *testing.T) {
+	position, err := ReadCurrentPosition(&RawCharacteristic{ID: 9, Value: Value("40.0")})
+	require.NoError(t, err)
+	require.Equal(t, &CurrentPosition{ID: 9, Value: 40, Unit: units.Percentage}, position)
+
+	temperature, err := ReadTargetTemperature(&RawCharacteristic{ID: 10, Value: Value("25"), Unit: "celsius"})
+	require.NoError(t, err)
+	require.Equal(t, units.Celsius, temperature.Unit)
+	require.Equal(t, 77.0, temperature.Fahrenheit())
+
+	_, err = ReadCurrentPosition(&RawCharacteristic{ID: 9, Value: Value(`"open"`)})
+	require.Error(t, err)
+}
+
+func TestMetadataForType(t *testing.T) {
+	tm := MetadataForType(TypeTargetPosition)
+	require.NotNil(t, tm)
+	require.Equal(t, "TargetPosition", tm.Name)
+	require.Equal(t, units.Percentage, tm.Unit)
+	require.True(t, tm.HasPermission(PermissionPairedWrite))
+	require.Equal(t, 100.0, *tm.MaxValue)
+	require.Equal(t, 1.0, *tm.StepValue)
+
+	tm = MetadataForType(TypeTargetHeatingCoolingState)
+	require.False(t, MetadataForType(TypeCurrentPosition).HasPermission(PermissionPairedWrite))
+	require.Equal(t, "Off", tm.ValidValues["0"])
+	require.Contains(t, tm.Description, "target mode")
+
+	require.Equal(t, 64, *MetadataForType(TypeName).MaxLen)
+	require.Nil(t, MetadataForType("unknown"))
+}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/brutella/hc/hap"
 )
@@ -16,6 +19,9 @@ type AccessoryConnectionConfig struct {
 	DeviceID         string
 	PublicKey        []byte
 	IPConnectionInfo IPConnectionInfo
+	// Model is the model advertised in the md Bonjour record if known. It's used to find the
+	// accessory's Quirks before its accessory information is read.
+	Model string
 }
 
 // ControllerIdentity captures required identifying details for a controller.
@@ -30,6 +36,20 @@ type AccessoryClient struct {
 	transport        IPTransport
 	ipConnectionInfo IPConnectionInfo
 	closeFn          func() error
+	secureDialer     *HomeKitSecureDialer
+	trace            TraceHandler
+
+	quirksMux   sync.Mutex
+	quirkDefs   []*Quirks
+	quirks      *Quirks
+	lastRequest time.Time
+	// requests are sent one at a time as the secure dialer shares a single verified connection
+	requestLimiter chan struct{}
+
+	// used to open dedicated connections for event subscriptions
+	dialer     IPDialer
+	controller *ControllerIdentity
+	accessory  *AccessoryConnectionConfig
 }
 
 // NewAccessoryClient returns a new AccessoryClient using IP transport. The client uses the
@@ -37,7 +57,9 @@ type AccessoryClient struct {
 //
 // Before using AccessoryClient you should first pair with the accessory using SetupClient.
 //
-// This client is not thread safe.
+// The client is safe for concurrent use. Requests share a single verified connection so
+// they're sent one at a time while event subscriptions use their own connections. SetTrace
+// should be called before the client is used.
 func NewAccessoryClient(dialer IPDialer, c *ControllerIdentity, a *AccessoryConnectionConfig) *AccessoryClient {
 	homekitDialer := NewHomeKitSecureDialer(dialer, c, a)
 
@@ -45,17 +67,54 @@ func NewAccessoryClient(dialer IPDialer, c *ControllerIdentity, a *AccessoryConn
 		Transport: &http.Transport{
 			DisableCompression: true,
 			DialContext:        homekitDialer.Dial,
+			// the dialer returns the same verified connection for every dial
+			MaxConnsPerHost: 1,
 		},
 	}
 
-	return &AccessoryClient{
+	client := &AccessoryClient{
 		transport:        httpClient,
 		ipConnectionInfo: a.IPConnectionInfo,
 		closeFn: func() error {
 			httpClient.CloseIdleConnections()
 			return homekitDialer.Close()
 		},
+		secureDialer:   homekitDialer,
+		requestLimiter: make(chan struct{}, 1),
+		dialer:         dialer,
+		controller:     c,
+		accessory:      a,
+	}
+	client.SetQuirks(DefaultQuirks)
+
+	return client
+}
+
+// NewAccessoryClientWithTransport returns a new AccessoryClient sending requests for the
+// accessory at ipConnectionInfo with transport. The transport is responsible for securing
+// the connection. The client doesn't support event subscriptions.
+//
+// It's useful to serve recorded exchanges with a ReplayTransport.
+func NewAccessoryClientWithTransport(transport IPTransport, ipConnectionInfo IPConnectionInfo) *AccessoryClient {
+	client := &AccessoryClient{
+		transport:        transport,
+		ipConnectionInfo: ipConnectionInfo,
+		requestLimiter:   make(chan struct{}, 1),
 	}
+	client.SetQuirks(DefaultQuirks)
+
+	return client
+}
+
+// Transport returns the transport the client sends requests with.
+func (a *AccessoryClient) Transport() IPTransport {
+	return a.transport
+}
+
+// SetTransport replaces the transport the client sends requests with. It's useful to wrap
+// the transport with a RecordingTransport. Event subscriptions don't use the transport.
+func (a *AccessoryClient) SetTransport(transport IPTransport) {
+	a.transport = transport
 }
 
 func (a *AccessoryClient) endpoint(name string) string {
@@ -69,7 +128,7 @@ func (a *AccessoryClient) sendTLV8(ctx context.Context, endpoint string, body []
 	}
 	req.Header.Set("Content-Type", hap.HTTPContentTypePairingTLV8)
 
-	resp, err := a.transport.Do(req)
+	resp, err := a.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +145,133 @@ func (a *AccessoryClient) sendTLV8(ctx context.Context, endpoint string, body []
 	return respBody, nil
 }
 
+// SetQuirks sets the quirk definitions searched for the accessory's quirks. The quirks matching
+// the model in the AccessoryConnectionConfig apply until the accessory information is read by
+// Accessories. Then the quirks matching its manufacturer and model apply.
+func (a *AccessoryClient) SetQuirks(quirks []*Quirks) {
+	a.quirksMux.Lock()
+	a.quirkDefs = quirks
+	a.quirksMux.Unlock()
+
+	model := ""
+	if a.accessory != nil {
+		model = a.accessory.Model
+	}
+	a.applyQuirks(FindQuirks(quirks, "", model))
+}
+
+// Quirks returns the quirks applied to the accessory or nil if there aren't any.
+func (a *AccessoryClient) Quirks() *Quirks {
+	a.quirksMux.Lock()
+	defer a.quirksMux.Unlock()
+
+	return a.quirks
+}
+
+// identifyQuirks applies the quirks matching the information of the primary accessory.
+func (a *AccessoryClient) identifyQuirks(accessories []*RawAccessory) {
+	var primary *RawAccessory
+	for _, acc := range accessories {
+		if primary == nil || acc.ID == 1 {
+			primary = acc
+		}
+	}
+	if primary == nil {
+		return
+	}
+	info, err := primary.Info()
+	if err != nil || info == nil {
+		return
+	}
+
+	a.quirksMux.Lock()
+	quirks := FindQuirks(a.quirkDefs, info.Manufacturer.Value, info.Model.Value)
+	a.quirksMux.Unlock()
+	if quirks != nil {
+		a.applyQuirks(quirks)
+	}
+}
+
+func (a *AccessoryClient) applyQuirks(quirks *Quirks) {
+	a.quirksMux.Lock()
+	defer a.quirksMux.Unlock()
+
+	if quirks == a.quirks {
+		return
+	}
+	a.quirks = quirks
+}
+
+// do sends the request with the client's transport applying the accessory's quirks. Requests
+// are sent one at a time and the next request waits until the response body is closed.
+func (a *AccessoryClient) do(req *http.Request) (*http.Response, error) {
+	select {
+	case a.requestLimiter <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := func() { <-a.requestLimiter }
+
+	a.quirksMux.Lock()
+	quirks := a.quirks
+	// reconnect rather than use a connection the accessory may have dropped
+	idle := quirks != nil && quirks.IdleTimeout > 0 && !a.lastRequest.IsZero() &&
+		time.Since(a.lastRequest) > quirks.IdleTimeout
+	a.lastRequest = time.Now()
+	a.quirksMux.Unlock()
+
+	if idle && a.closeFn != nil {
+		_ = a.closeFn()
+	}
+
+	resp, err := a.transport.Do(req)
+
+	a.quirksMux.Lock()
+	a.lastRequest = time.Now()
+	a.quirksMux.Unlock()
+
+	if err != nil {
+		release()
+		return nil, err
+	}
+	quirks.mapStatus(resp)
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// releasingBody calls release once the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// SetTrace enables tracing of the decrypted messages exchanged with the accessory, including
+// events received by Subscribe. Connections that are already established aren't traced.
+// Tracing is disabled if handler is nil.
+func (a *AccessoryClient) SetTrace(handler TraceHandler) {
+	a.trace = handler
+	if a.secureDialer != nil {
+		a.secureDialer.SetTrace(handler)
+	}
+}
+
+// ConnectionStats returns statistics of the secure connections the client has established to
+// the accessory. Connections opened for event subscriptions aren't included.
+func (a *AccessoryClient) ConnectionStats() ConnectionStats {
+	if a.secureDialer == nil {
+		return ConnectionStats{}
+	}
+	return a.secureDialer.Stats()
+}
+
 // Close releases any resources used by the client.
 func (a *AccessoryClient) Close() error {
 	if a.closeFn != nil {
@@ -19,11 +19,12 @@ type RawAccessory struct {
 
 // Info returns the characteristic data from the accessory's info service.Info
 // This service is required by the spec to be present for all accessories but
-// if it's not found then nil is returned.
-func (r *RawAccessory) Info() *service.AccessoryInfo {
+// if it's not found then nil is returned. An error is returned if a value of the
+// service doesn't match its format.
+func (r *RawAccessory) Info() (*service.AccessoryInfo, error) {
 	infoSvc := r.ServiceByType(service.TypeAccessoryInformation)
 	if infoSvc == nil {
-		return nil
+		return nil, nil
 	}
 
 	return service.ReadAccessoryInfo(infoSvc.Characteristics)
@@ -49,7 +50,7 @@ func (a *AccessoryClient) Accessories(ctx context.Context) ([]*RawAccessory, err
 		return nil, err
 	}
 
-	resp, err := a.transport.Do(req)
+	resp, err := a.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +69,7 @@ func (a *AccessoryClient) Accessories(ctx context.Context) ([]*RawAccessory, err
 	if err := json.Unmarshal(body, &respData); err != nil {
 		return nil, fmt.Errorf("unmarshal: %v\n%s", err, string(body))
 	}
+	a.identifyQuirks(respData.Accessories)
 
 	return respData.Accessories, nil
 }
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/mctofu/homekit/client/characteristic"
 )
@@ -60,11 +61,11 @@ func (a *AccessoryClient) Characteristics(
 		return nil, err
 	}
 
-	// velux homekit accessory doesn't seem to like url encoded commas so
-	// we need to use raw commas.
-	// TODO: confirm if other devices behave similarly
+	// ids are separated with raw commas unless the accessory's quirks require them to be
+	// url encoded
+	quirks := a.Quirks()
 	var query strings.Builder
-	query.WriteString(fmt.Sprintf("id=%s", encodeIDs(readReq.Characteristics)))
+	query.WriteString(fmt.Sprintf("id=%s", quirks.encodeQuery(encodeIDs(readReq.Characteristics))))
 	if readReq.Metadata {
 		query.WriteString("&meta=1")
 	}
@@ -79,7 +80,7 @@ func (a *AccessoryClient) Characteristics(
 	}
 	req.URL.RawQuery = query.String()
 
-	resp, err := a.transport.Do(req)
+	resp, err := a.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +100,9 @@ func (a *AccessoryClient) Characteristics(
 	if err := json.Unmarshal(body, &respData); err != nil {
 		return nil, fmt.Errorf("unmarshal: %v", err)
 	}
+	if readReq.Metadata {
+		quirks.fillMetadata(respData.Characteristics)
+	}
 
 	return respData.Characteristics, nil
 }
@@ -155,7 +159,7 @@ func (a *AccessoryClient) SetCharacteristics(ctx context.Context, writeReq *Char
 	}
 	req.Header.Set("Content-Type", "application/hap+json")
 
-	resp, err := a.transport.Do(req)
+	resp, err := a.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("transport.Do: %v", err)
 	}
@@ -182,6 +186,53 @@ func (a *AccessoryClient) SetCharacteristics(ctx context.Context, writeReq *Char
 	}
 }
 
+// Prepare prepares a timed write with the accessory. A CharacteristicsWriteRequest with the same
+// PrepareID must be sent before ttl elapses for the write to be accepted.
+func (a *AccessoryClient) Prepare(ctx context.Context, pid uint64, ttl time.Duration) error {
+	reqBody, err := json.Marshal(struct {
+		TTL uint64 `json:"ttl"`
+		PID uint64 `json:"pid"`
+	}{
+		TTL: uint64(ttl / time.Millisecond),
+		PID: pid,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, a.endpoint("prepare"), bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/hap+json")
+
+	resp, err := a.do(req)
+	if err != nil {
+		return fmt.Errorf("transport.Do: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	respData := struct {
+		Status int `json:"status"`
+	}{}
+	if err := json.Unmarshal(body, &respData); err != nil {
+		return fmt.Errorf("unmarshal: %v", err)
+	}
+	if respData.Status != 0 {
+		return fmt.Errorf("prepare status: %d", respData.Status)
+	}
+
+	return nil
+}
+
 func encodeIDs(ids []CharacteristicReadRequest) string {
 	stringIDs := make([]string, 0, len(ids))
 	for _, id := range ids {
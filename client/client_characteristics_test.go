@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccessories = `{"accessories": [
+  {"aid": 1, "services": [
+    {"iid": 1, "type": "3E", "characteristics": [
+      {"iid": 2, "type": "23", "format": "string", "value": "Window", "perms": ["pr"]},
+      {"iid": 3, "type": "14", "format": "bool", "perms": ["pw"]}
+    ]},
+    {"iid": 8, "type": "8B", "characteristics": [
+      {"iid": 9, "type": "6D", "format": "uint8", "unit": "percentage", "value": 0, "perms": ["pr", "ev"],
+       "minValue": 0, "maxValue": 100, "minStep": 1},
+      {"iid": 10, "type": "7C", "format": "uint8", "unit": "percentage", "value": 0, "perms": ["pr", "pw", "ev"],
+       "minValue": 0, "maxValue": 100, "minStep": 1},
+      {"iid": 11, "type": "11", "format": "float", "unit": "celsius", "value": 21.5, "perms": ["pr", "pw", "tw"]},
+      {"iid": 12, "type": "24", "format": "tlv8", "value": "AQE=", "perms": ["pr", "pw", "wr"]}
+    ]}
+  ]}
+]}`
+
+func fakeAccessoryClient(t *testing.T, ctx context.Context) (*hapfake.Server, *AccessoryClient) {
+	accessories, err := hapfake.ReadAccessories(strings.NewReader(testAccessories))
+	require.NoError(t, err)
+
+	testServer, err := hapfake.NewServer(hapfake.Config{Accessories: accessories})
+	require.NoError(t, err)
+	t.Cleanup(func() { testServer.Close() })
+
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
+
+	connectionConfig, err := setupDeviceServer(ctx, testServer, controller)
+	require.NoError(t, err, "pair")
+
+	accClient := NewAccessoryClient(NewIPDialer(), controller, connectionConfig)
+	t.Cleanup(func() { accClient.Close() })
+
+	return testServer, accClient
+}
+
+func TestAccessories(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, accClient := fakeAccessoryClient(t, ctx)
+
+	accessories, err := accClient.Accessories(ctx)
+	require.NoError(t, err)
+	require.Len(t, accessories, 1)
+	info, err := accessories[0].Info()
+	require.NoError(t, err)
+	require.Equal(t, "Window", info.Name.Value)
+
+	window := accessories[0].ServiceByType("8B")
+	require.NotNil(t, window)
+	target := window.CharacteristicByType(characteristic.TypeTargetPosition)
+	require.NotNil(t, target)
+	require.Equal(t, "uint8", target.Format)
+	require.Equal(t, "100", string(target.MaxValue))
+}
+
+func TestCharacteristics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, accClient := fakeAccessoryClient(t, ctx)
+	require.NoError(t, testServer.SetValue(1, 9, 40))
+
+	resp, err := accClient.Characteristics(ctx, &CharacteristicsReadRequest{
+		Characteristics: []CharacteristicReadRequest{
+			{AccessoryID: 1, CharacteristicID: 9},
+			{AccessoryID: 1, CharacteristicID: 11},
+		},
+		Metadata: true,
+		Type:     true,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	require.Equal(t, uint8(40), resp[0].Value.MustByte())
+	require.Equal(t, "6D", *resp[0].Type)
+	require.Equal(t, "percentage", *resp[0].Unit)
+	require.Nil(t, resp[0].Status)
+	require.Equal(t, 21.5, resp[1].Value.MustFloat64())
+
+	resp, err = accClient.Characteristics(ctx, &CharacteristicsReadRequest{
+		Characteristics: []CharacteristicReadRequest{
+			{AccessoryID: 1, CharacteristicID: 2},
+			{AccessoryID: 1, CharacteristicID: 3},
+			{AccessoryID: 2, CharacteristicID: 2},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp, 3)
+	require.Equal(t, 0, *resp[0].Status)
+	require.Equal(t, "Window", resp[0].Value.MustString())
+	require.Equal(t, hapfake.StatusWriteOnly, *resp[1].Status)
+	require.Equal(t, hapfake.StatusResourceDoesNotExist, *resp[2].Status)
+}
+
+func TestSetCharacteristics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, accClient := fakeAccessoryClient(t, ctx)
+
+	resp, err := accClient.SetCharacteristics(ctx, &CharacteristicsWriteRequest{
+		Characteristics: []CharacteristicWriteRequest{
+			{AccessoryID: 1, CharacteristicID: 10, Value: 60},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+	require.Nil(t, resp[0].Status)
+	require.Equal(t, "60", string(testServer.Value(1, 10)))
+
+	resp, err = accClient.SetCharacteristics(ctx, &CharacteristicsWriteRequest{
+		Characteristics: []CharacteristicWriteRequest{
+			{AccessoryID: 1, CharacteristicID: 10, Value: 101},
+			{AccessoryID: 1, CharacteristicID: 9, Value: 50},
+			{AccessoryID: 1, CharacteristicID: 12, Value: "AgE=", Response: true},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp, 3)
+	require.Equal(t, hapfake.StatusInvalidValue, *resp[0].Status)
+	require.Equal(t, hapfake.StatusReadOnly, *resp[1].Status)
+	require.Equal(t, 0, *resp[2].Status)
+	require.Equal(t, `"AgE="`, string(resp[2].Value))
+
+	require.Equal(t,
+		[]hapfake.Write{
+			{AccessoryID: 1, CharacteristicID: 10, Value: characteristic.Value("60")},
+			{AccessoryID: 1, CharacteristicID: 12, Value: characteristic.Value(`"AgE="`)},
+		},
+		testServer.Writes(),
+	)
+}
+
+func TestPrepare(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, accClient := fakeAccessoryClient(t, ctx)
+
+	writeReq := &CharacteristicsWriteRequest{
+		Characteristics: []CharacteristicWriteRequest{
+			{AccessoryID: 1, CharacteristicID: 11, Value: 19.5},
+		},
+	}
+
+	// the characteristic requires a timed write
+	resp, err := accClient.SetCharacteristics(ctx, writeReq)
+	require.NoError(t, err)
+	require.Equal(t, hapfake.StatusInvalidValue, *resp[0].Status)
+
+	pid := uint64(42)
+	require.NoError(t, accClient.Prepare(ctx, pid, 5*time.Second))
+	writeReq.PrepareID = &pid
+	resp, err = accClient.SetCharacteristics(ctx, writeReq)
+	require.NoError(t, err)
+	require.Nil(t, resp[0].Status)
+
+	require.Equal(t,
+		[]hapfake.Write{
+			{AccessoryID: 1, CharacteristicID: 11, Value: characteristic.Value("19.5"), Timed: true},
+		},
+		testServer.Writes(),
+	)
+
+	// a prepared write can't be reused
+	resp, err = accClient.SetCharacteristics(ctx, writeReq)
+	require.NoError(t, err)
+	require.Equal(t, hapfake.StatusInvalidValue, *resp[0].Status)
+}
+
+func TestCharacteristicsFailures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, accClient := fakeAccessoryClient(t, ctx)
+	readReq := &CharacteristicsReadRequest{
+		Characteristics: []CharacteristicReadRequest{{AccessoryID: 1, CharacteristicID: 9}},
+	}
+
+	testServer.Fail(hapfake.Failure{Path: "/characteristics", Status: hapfake.StatusResourceBusy, Count: 1})
+	resp, err := accClient.Characteristics(ctx, readReq)
+	require.NoError(t, err)
+	require.Equal(t, hapfake.StatusResourceBusy, *resp[0].Status)
+
+	testServer.Fail(hapfake.Failure{Method: "GET", StatusCode: 503, Count: 1})
+	_, err = accClient.Characteristics(ctx, readReq)
+	require.EqualError(t, err, "unexpected response status: 503 Service Unavailable")
+
+	// the transport retries a GET once on a new connection
+	testServer.Fail(hapfake.Failure{Path: "/characteristics", Disconnect: true, Count: 2})
+	_, err = accClient.Characteristics(ctx, readReq)
+	require.Error(t, err)
+
+	// the client reconnects after the failures are used up
+	resp, err = accClient.Characteristics(ctx, readReq)
+	require.NoError(t, err)
+	require.Nil(t, resp[0].Status)
+	require.Greater(t, accClient.ConnectionStats().Connections, uint64(1))
+}
+
+func TestSetCharacteristicsFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, accClient := fakeAccessoryClient(t, ctx)
+
+	// a write scripted to fail isn't applied
+	before := testServer.Value(1, 10)
+	testServer.Fail(hapfake.Failure{Path: "/characteristics", Status: hapfake.StatusResourceBusy, Count: 1})
+	resp, err := accClient.SetCharacteristics(ctx, &CharacteristicsWriteRequest{
+		Characteristics: []CharacteristicWriteRequest{{AccessoryID: 1, CharacteristicID: 10, Value: 50}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, hapfake.StatusResourceBusy, *resp[0].Status)
+	require.Equal(t, before, testServer.Value(1, 10))
+	require.Empty(t, testServer.Writes())
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/mctofu/homekit/client/characteristic"
+)
+
+// CharacteristicEvent is a notification sent by an accessory when the value of a characteristic
+// with events enabled changes.
+type CharacteristicEvent struct {
+	AccessoryID      uint64               `json:"aid"`
+	CharacteristicID uint64               `json:"iid"`
+	Value            characteristic.Value `json:"value"`
+}
+
+// EventHandler is called with each event received by Subscribe.
+type EventHandler func(event *CharacteristicEvent)
+
+// Subscribe enables events for the characteristics and calls handler with each event
+// received. Events are received on a dedicated connection to the accessory so the client
+// remains usable for other requests.
+//
+// Subscribe blocks until ctx is cancelled or the connection fails and returns the
+// error that ended the subscription.
+func (a *AccessoryClient) Subscribe(
+	ctx context.Context,
+	characteristics []CharacteristicReadRequest,
+	handler EventHandler,
+) (rErr error) {
+	if a.dialer == nil {
+		return errors.New("client does not support event subscriptions")
+	}
+
+	dialer := NewHomeKitSecureDialer(a.dialer, a.controller, a.accessory)
+	dialer.SetTrace(a.trace)
+	addr := net.JoinHostPort(a.ipConnectionInfo.IPAddress, strconv.Itoa(a.ipConnectionInfo.Port))
+	conn, err := dialer.Dial(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := dialer.Close(); cErr != nil && rErr == nil && ctx.Err() == nil {
+			rErr = cErr
+		}
+	}()
+
+	// interrupt blocked reads when the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = dialer.Close()
+		case <-done:
+		}
+	}()
+
+	writeReq := &CharacteristicsWriteRequest{
+		Characteristics: make([]CharacteristicWriteRequest, 0, len(characteristics)),
+	}
+	for _, c := range characteristics {
+		writeReq.Characteristics = append(writeReq.Characteristics, CharacteristicWriteRequest{
+			AccessoryID:      c.AccessoryID,
+			CharacteristicID: c.CharacteristicID,
+			Events:           true,
+		})
+	}
+
+	reqBody, err := json.Marshal(writeReq)
+	if err != nil {
+		return fmt.Errorf("marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, a.endpointCharacteristics(), bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/hap+json")
+
+	// write the request with a single call so it's sent in a single encrypted frame
+	var reqBuf bytes.Buffer
+	if err := req.Write(&reqBuf); err != nil {
+		return fmt.Errorf("encode request: %v", err)
+	}
+	if _, err := conn.Write(reqBuf.Bytes()); err != nil {
+		return fmt.Errorf("write request: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	subscribed := false
+	for {
+		msg, err := readHAPMessage(reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("read: %v", err)
+		}
+
+		if msg.event {
+			events := struct {
+				Characteristics []*CharacteristicEvent `json:"characteristics"`
+			}{}
+			if err := json.Unmarshal(msg.body, &events); err != nil {
+				return fmt.Errorf("unmarshal event: %v", err)
+			}
+			for _, e := range events.Characteristics {
+				handler(e)
+			}
+			continue
+		}
+
+		if subscribed {
+			return fmt.Errorf("unexpected response: %d", msg.statusCode)
+		}
+		if err := checkSubscribeResponse(msg); err != nil {
+			return err
+		}
+		subscribed = true
+	}
+}
+
+func checkSubscribeResponse(msg *hapMessage) error {
+	switch msg.statusCode {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusOK, http.StatusMultiStatus:
+		respData := struct {
+			Characteristics []*CharacteristicWriteResponse `json:"characteristics"`
+		}{}
+		if err := json.Unmarshal(msg.body, &respData); err != nil {
+			return fmt.Errorf("unmarshal: %v", err)
+		}
+		for _, c := range respData.Characteristics {
+			if c.Status != nil && *c.Status != 0 {
+				return fmt.Errorf("enable events for %d.%d failed with status %d", c.AccessoryID, c.CharacteristicID, *c.Status)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected response status: %d", msg.statusCode)
+	}
+}
+
+// hapMessage is a response or event read from an accessory connection.
+type hapMessage struct {
+	event      bool
+	statusCode int
+	body       []byte
+}
+
+// readHAPMessage reads a single HTTP response or EVENT message. Events use the "EVENT/1.0"
+// protocol which the net/http response parser rejects so messages are parsed here.
+func readHAPMessage(r *bufio.Reader) (*hapMessage, error) {
+	tp := textproto.NewReader(r)
+	line, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(line, " ", 3)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("malformed status line: %s", line)
+	}
+
+	var msg hapMessage
+	switch {
+	case strings.HasPrefix(parts[0], "EVENT/"):
+		msg.event = true
+	case strings.HasPrefix(parts[0], "HTTP/"):
+	default:
+		return nil, fmt.Errorf("unknown protocol: %s", parts[0])
+	}
+
+	msg.statusCode, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed status code: %s", parts[1])
+	}
+
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	msg.body, err = readMessageBody(r, tp, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// readMessageBody reads the body of a message with the header from r. The body is chunked or
+// has a Content-Length. Messages without either have no body.
+func readMessageBody(r *bufio.Reader, tp *textproto.Reader, header textproto.MIMEHeader) ([]byte, error) {
+	if strings.EqualFold(header.Get("Transfer-Encoding"), "chunked") {
+		body, err := ioutil.ReadAll(httputil.NewChunkedReader(r))
+		if err != nil {
+			return nil, err
+		}
+		// consume the trailer
+		if _, err := tp.ReadMIMEHeader(); err != nil {
+			return nil, err
+		}
+		return body, nil
+	}
+
+	if cl := header.Get("Content-Length"); cl != "" {
+		length, err := strconv.Atoi(cl)
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("malformed content length: %s", cl)
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil, err
+		}
+		return body, nil
+	}
+
+	return nil, nil
+}
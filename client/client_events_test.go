@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
+
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	connectionConfig, err := setupDeviceServer(ctx, testServer, controller)
+	require.NoError(t, err, "pair")
+
+	accClient := NewAccessoryClient(NewIPDialer(), controller, connectionConfig)
+	defer accClient.Close()
+
+	accessories, err := accClient.Accessories(ctx)
+	require.NoError(t, err)
+	stats := accClient.ConnectionStats()
+	require.Equal(t, uint64(1), stats.Connections)
+	require.NotZero(t, stats.VerifyDuration)
+	on := accessories[0].ServiceByType(service.TypeSwitch).CharacteristicByType(characteristic.TypeOn)
+	require.NotNil(t, on)
+	onID := CharacteristicReadRequest{AccessoryID: accessories[0].ID, CharacteristicID: on.ID}
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	events := make(chan *CharacteristicEvent, 1)
+	subErr := make(chan error, 1)
+	go func() {
+		subErr <- accClient.Subscribe(subCtx, []CharacteristicReadRequest{onID}, func(e *CharacteristicEvent) {
+			events <- e
+		})
+	}()
+
+	// keep writing until the subscription is active and the event is delivered
+	var event *CharacteristicEvent
+	for value := true; event == nil; value = !value {
+		_, err := accClient.SetCharacteristics(ctx, &CharacteristicsWriteRequest{
+			Characteristics: []CharacteristicWriteRequest{
+				{AccessoryID: onID.AccessoryID, CharacteristicID: onID.CharacteristicID, Value: value},
+			},
+		})
+		require.NoError(t, err)
+
+		select {
+		case event = <-events:
+		case err := <-subErr:
+			require.NoError(t, err, "subscribe ended")
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			require.NoError(t, ctx.Err(), "waiting for event")
+		}
+	}
+
+	require.Equal(t, onID.AccessoryID, event.AccessoryID)
+	require.Equal(t, onID.CharacteristicID, event.CharacteristicID)
+	_, err = event.Value.Bool()
+	require.NoError(t, err)
+
+	subCancel()
+	require.Equal(t, context.Canceled, <-subErr)
+}
+
+func TestSubscribeEventBurst(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, accClient := fakeAccessoryClient(t, ctx)
+	onID := CharacteristicReadRequest{AccessoryID: 1, CharacteristicID: 9}
+
+	events := make(chan *CharacteristicEvent, 10)
+	subErr := make(chan error, 1)
+	go func() {
+		subErr <- accClient.Subscribe(ctx, []CharacteristicReadRequest{onID}, func(e *CharacteristicEvent) {
+			events <- e
+		})
+	}()
+	require.Eventually(t, func() bool { return testServer.Subscriptions(1, 9) > 0 }, time.Second, 5*time.Millisecond)
+
+	// events sent back to back may be received in a single read
+	values := []string{"10", "20", "30", "40", "50"}
+	for _, v := range values {
+		require.NoError(t, testServer.SetValue(1, 9, characteristic.Value(v)))
+	}
+
+	for _, v := range values {
+		select {
+		case e := <-events:
+			require.Equal(t, characteristic.Value(v), e.Value)
+		case err := <-subErr:
+			require.NoError(t, err, "subscribe ended")
+		case <-ctx.Done():
+			require.NoError(t, ctx.Err(), "waiting for event")
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+)
+
+// Identify asks the accessory with the id to identify itself, typically by blinking a light or
+// moving briefly. The Identify characteristic of the accessory's info service is written so
+// the attribute database is fetched to find it.
+func (a *AccessoryClient) Identify(ctx context.Context, accessoryID uint64) error {
+	accessories, err := a.Accessories(ctx)
+	if err != nil {
+		return fmt.Errorf("read accessories: %v", err)
+	}
+
+	identifyID, err := identifyCharacteristicID(accessories, accessoryID)
+	if err != nil {
+		return err
+	}
+
+	resps, err := a.SetCharacteristics(ctx, &CharacteristicsWriteRequest{
+		Characteristics: []CharacteristicWriteRequest{
+			{AccessoryID: accessoryID, CharacteristicID: identifyID, Value: true},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, resp := range resps {
+		if resp.Status != nil && *resp.Status != 0 {
+			return fmt.Errorf("identify failed with status %d", *resp.Status)
+		}
+	}
+
+	return nil
+}
+
+// identifyCharacteristicID returns the instance id of the Identify characteristic in the info
+// service of the accessory.
+func identifyCharacteristicID(accessories []*RawAccessory, accessoryID uint64) (uint64, error) {
+	for _, acc := range accessories {
+		if acc.ID != accessoryID {
+			continue
+		}
+
+		infoSvc := acc.ServiceByType(service.TypeAccessoryInformation)
+		if infoSvc == nil {
+			return 0, fmt.Errorf("accessory %d has no info service", accessoryID)
+		}
+		identify := infoSvc.CharacteristicByType(characteristic.TypeIdentify)
+		if identify == nil {
+			return 0, fmt.Errorf("accessory %d has no identify characteristic", accessoryID)
+		}
+
+		return identify.ID, nil
+	}
+
+	return 0, fmt.Errorf("accessory %d not found", accessoryID)
+}
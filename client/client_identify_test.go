@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentify(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
+
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	connectionConfig, err := setupDeviceServer(ctx, testServer, controller)
+	require.NoError(t, err, "pair")
+
+	accClient := NewAccessoryClient(NewIPDialer(), controller, connectionConfig)
+	defer accClient.Close()
+
+	require.NoError(t, accClient.Identify(ctx, 1))
+	require.Equal(t, 1, testServer.Identified())
+
+	require.EqualError(t, accClient.Identify(ctx, 9), "accessory 9 not found")
+}
+
+func TestSetupClientIdentify(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setupClient := NewSetupClient(&http.Client{})
+	require.NoError(t, setupClient.Identify(ctx, fakeConnectionInfo(testServer)))
+	require.Equal(t, 1, testServer.Identified())
+}
+
+func TestSetupClientIdentifyPaired(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
+
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err = setupDeviceServer(ctx, testServer, controller)
+	require.NoError(t, err, "pair")
+
+	setupClient := NewSetupClient(&http.Client{})
+	err = setupClient.Identify(ctx, fakeConnectionInfo(testServer))
+	require.Equal(t, ErrAccessoryPaired, err)
+	require.Zero(t, testServer.Identified())
+}
@@ -60,7 +60,7 @@ func (a *AccessoryClient) ListPairings(ctx context.Context) ([]*ListPairingRespo
 		result = append(result, &ListPairingResponse{
 			ControllerID: in.GetString(pair.TagUsername),
 			PublicKey:    in.GetBytes(pair.TagPublicKey),
-			Admin:        in.GetByte(pair.TagPermission) == 1,
+			Admin:        in.GetByte(pair.TagPermission) == PermissionAdmin,
 		})
 	}
 
@@ -84,17 +84,18 @@ func splitTLV8(v []byte) ([][]byte, error) {
 			return nil, err
 		}
 
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return nil, err
+		}
+
 		if tag == tagSeparator {
+			// the separator is an empty item so it ends 2 bytes after it starts
 			end := len(v) - r.Len()
-			result = append(result, v[start:end-1])
+			result = append(result, v[start:end-2])
 			start = end
 			continue
 		}
 
-		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
-			return nil, err
-		}
-
 		if _, err := r.Seek(int64(length), io.SeekCurrent); err != nil {
 			return nil, err
 		}
@@ -105,6 +106,12 @@ func splitTLV8(v []byte) ([][]byte, error) {
 	return result, nil
 }
 
+// Permissions that can be granted to a controller with AddPairing.
+const (
+	PermissionUser  byte = 0
+	PermissionAdmin byte = 1
+)
+
 // AddPairingRequest specifies an additional controller that should be added to an
 // accessory's pairings.
 type AddPairingRequest struct {
@@ -113,7 +120,8 @@ type AddPairingRequest struct {
 	Permissions byte
 }
 
-// AddPairing adds access by an additional controller to the accessory.
+// AddPairing adds access by an additional controller to the accessory. If the controller
+// is already paired then its permissions are updated instead.
 func (a *AccessoryClient) AddPairing(ctx context.Context, req *AddPairingRequest) error {
 	out := util.NewTLV8Container()
 	out.SetByte(pair.TagSequence, 1)
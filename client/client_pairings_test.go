@@ -38,7 +38,9 @@ func TestAddRemovePairing(t *testing.T) {
 		defer addAccClient.Close()
 		accessories, err := addAccClient.Accessories(ctx)
 		require.NoError(t, err, "additional controller connection")
-		require.Equal(t, "Test", accessories[0].Info().Name.Value)
+		info, err := accessories[0].Info()
+		require.NoError(t, err)
+		require.Equal(t, "Fake", info.Name.Value)
 	}()
 
 	require.NoError(t, accClient.RemovePairing(
@@ -84,3 +86,111 @@ func TestRemoveSetupPairing(t *testing.T) {
 		require.Nil(t, accessories)
 	}()
 }
+
+func TestSplitTLV8(t *testing.T) {
+	v := []byte{
+		1, 2, 'a', 'b',
+		tagSeparator, 0,
+		1, 1, 'c',
+		tagSeparator, 0,
+		1, 0,
+	}
+
+	items, err := splitTLV8(v)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{
+		{1, 2, 'a', 'b'},
+		{1, 1, 'c'},
+		{1, 0},
+	}, items)
+
+	_, err = splitTLV8([]byte{1, 2, 'a', 'b', tagSeparator})
+	require.Error(t, err, "truncated separator")
+}
+
+func TestListPairings(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
+
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
+
+	ctx := context.Background()
+	connectionConfig, err := setupDeviceServer(ctx, testServer, controller)
+	require.NoError(t, err, "pair")
+
+	userController, err := NewRandomControllerConfig()
+	require.NoError(t, err, "userController setup")
+
+	accClient := NewAccessoryClient(NewIPDialer(), controller, connectionConfig)
+	defer accClient.Close()
+	require.NoError(t, accClient.AddPairing(
+		ctx,
+		&AddPairingRequest{
+			DeviceID:    userController.DeviceID,
+			PublicKey:   userController.PublicKey,
+			Permissions: PermissionUser,
+		},
+	))
+
+	pairings, err := accClient.ListPairings(ctx)
+	require.NoError(t, err)
+
+	byID := make(map[string]*ListPairingResponse)
+	for _, p := range pairings {
+		byID[p.ControllerID] = p
+	}
+	require.Len(t, byID, 2)
+	require.True(t, byID[controller.DeviceID].Admin)
+	require.Equal(t, controller.PublicKey, byID[controller.DeviceID].PublicKey)
+	require.False(t, byID[userController.DeviceID].Admin)
+	require.Equal(t, userController.PublicKey, byID[userController.DeviceID].PublicKey)
+}
+
+func TestPairingsRequireAdmin(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
+
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
+
+	ctx := context.Background()
+	connectionConfig, err := setupDeviceServer(ctx, testServer, controller)
+	require.NoError(t, err, "pair")
+
+	userController, err := NewRandomControllerConfig()
+	require.NoError(t, err, "userController setup")
+
+	accClient := NewAccessoryClient(NewIPDialer(), controller, connectionConfig)
+	defer accClient.Close()
+	require.NoError(t, accClient.AddPairing(
+		ctx,
+		&AddPairingRequest{
+			DeviceID:    userController.DeviceID,
+			PublicKey:   userController.PublicKey,
+			Permissions: PermissionUser,
+		},
+	))
+
+	userClient := NewAccessoryClient(NewIPDialer(), userController, connectionConfig)
+	defer userClient.Close()
+
+	// controllers without admin permission are rejected with kTLVError_Authentication
+	_, err = userClient.ListPairings(ctx)
+	require.EqualError(t, err, "error code: 2")
+	require.EqualError(t, userClient.AddPairing(
+		ctx,
+		&AddPairingRequest{
+			DeviceID:    userController.DeviceID,
+			PublicKey:   userController.PublicKey,
+			Permissions: PermissionAdmin,
+		},
+	), "error code: 2")
+	require.EqualError(t, userClient.RemovePairing(ctx, controller.DeviceID), "error code: 2")
+
+	pairings, err := accClient.ListPairings(ctx)
+	require.NoError(t, err)
+	require.Len(t, pairings, 2)
+}
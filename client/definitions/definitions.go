@@ -0,0 +1,185 @@
+// Package definitions reads the characteristic and service types defined in the schema of
+// characteristics.yaml and services.yaml. It doesn't depend on the code generated from those
+// files so the generator can always be built to regenerate it.
+package definitions
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mctofu/homekit/client/units"
+	"gopkg.in/yaml.v3"
+)
+
+// appleBaseUUID is the suffix of UUIDs of types defined by the HAP specification.
+const appleBaseUUID = "-0000-1000-8000-0026BB765291"
+
+// specPermissions maps the permissions of the specification to the permissions reported by
+// accessories.
+var specPermissions = map[string]string{
+	"Paired Read":              "pr",
+	"Paired Write":             "pw",
+	"Notify":                   "ev",
+	"Additional Authorization": "aa",
+	"Timed Write":              "tw",
+	"Hidden":                   "hd",
+	"Write Response":           "wr",
+}
+
+// Formats of characteristic values.
+var formats = []string{"bool", "uint8", "uint16", "uint32", "uint64", "int", "float", "string", "tlv8", "data"}
+
+// TypeForUUID returns the type reported by accessories for a characteristic or service UUID.
+// UUIDs of types defined by the HAP specification are shortened, like
+// 0000006D-0000-1000-8000-0026BB765291 to 6D.
+func TypeForUUID(uuid string) string {
+	uuid = strings.ToUpper(uuid)
+	if strings.HasSuffix(uuid, appleBaseUUID) {
+		return strings.TrimLeft(strings.TrimSuffix(uuid, appleBaseUUID), "0")
+	}
+	return uuid
+}
+
+// Characteristic is a characteristic type in the schema of characteristics.yaml.
+type Characteristic struct {
+	Name        string            `yaml:"name"`
+	UUID        string            `yaml:"uuid"`
+	Description string            `yaml:"description"`
+	Permissions []string          `yaml:"permissions"`
+	Format      string            `yaml:"format"`
+	Unit        units.Unit        `yaml:"unit"`
+	MinValue    *float64          `yaml:"minValue"`
+	MaxValue    *float64          `yaml:"maxValue"`
+	StepValue   *float64          `yaml:"stepValue"`
+	MaxLength   *int              `yaml:"maxLength"`
+	ValidValues map[string]string `yaml:"validValues"`
+
+	// Type is the type reported by accessories for the UUID.
+	Type string `yaml:"-"`
+}
+
+// ReadCharacteristics reads characteristic types defined in the schema of
+// characteristics.yaml. Names are pascal cased, permissions are converted to the permissions
+// reported by accessories and multi-line text is joined.
+func ReadCharacteristics(r io.Reader) ([]*Characteristic, error) {
+	var defs []*Characteristic
+	if err := yaml.NewDecoder(r).Decode(&defs); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("decode characteristic types: %v", err)
+	}
+
+	for _, def := range defs {
+		if def.Name == "" {
+			return nil, fmt.Errorf("characteristic type %s requires a name", def.UUID)
+		}
+		if def.UUID == "" {
+			return nil, fmt.Errorf("characteristic type %s requires a uuid", def.Name)
+		}
+		if !validFormat(def.Format) {
+			return nil, fmt.Errorf("characteristic type %s has unknown format %q", def.Name, def.Format)
+		}
+		perms, err := readPermissions(def.Permissions)
+		if err != nil {
+			return nil, fmt.Errorf("characteristic type %s has %v", def.Name, err)
+		}
+		def.Permissions = perms
+		def.Name = pascalCase(def.Name)
+		def.Type = TypeForUUID(def.UUID)
+		def.Description = normalizeText(def.Description)
+		for v, desc := range def.ValidValues {
+			def.ValidValues[v] = normalizeText(desc)
+		}
+	}
+
+	return defs, nil
+}
+
+// Service is a service type in the schema of services.yaml.
+type Service struct {
+	Name                    string   `yaml:"name"`
+	UUID                    string   `yaml:"uuid"`
+	RequiredCharacteristics []string `yaml:"requiredCharacteristics"`
+	OptionalCharacteristics []string `yaml:"optionalCharacteristics"`
+
+	// Type is the type reported by accessories for the UUID.
+	Type string `yaml:"-"`
+}
+
+// ReadServices reads service types defined in the schema of services.yaml. Names are pascal
+// cased and the UUIDs of characteristics are converted to the types reported by accessories.
+func ReadServices(r io.Reader) ([]*Service, error) {
+	var defs []*Service
+	if err := yaml.NewDecoder(r).Decode(&defs); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("decode service types: %v", err)
+	}
+
+	for _, def := range defs {
+		if def.Name == "" {
+			return nil, fmt.Errorf("service type %s requires a name", def.UUID)
+		}
+		if def.UUID == "" {
+			return nil, fmt.Errorf("service type %s requires a uuid", def.Name)
+		}
+		def.Name = pascalCase(def.Name)
+		def.Type = TypeForUUID(def.UUID)
+		def.RequiredCharacteristics = typesForUUIDs(def.RequiredCharacteristics)
+		def.OptionalCharacteristics = typesForUUIDs(def.OptionalCharacteristics)
+	}
+
+	return defs, nil
+}
+
+func typesForUUIDs(uuids []string) []string {
+	var types []string
+	for _, uuid := range uuids {
+		types = append(types, TypeForUUID(uuid))
+	}
+	return types
+}
+
+// readPermissions returns the permissions reported by accessories for the permissions of a
+// definition. Permissions of the specification are accepted along with the reported ones and
+// some are listed together.
+func readPermissions(defPerms []string) ([]string, error) {
+	var perms []string
+	for _, p := range defPerms {
+		for _, name := range strings.Split(p, ", ") {
+			perm, ok := specPermissions[name]
+			if !ok && !reportedPermission(name) {
+				return nil, fmt.Errorf("unknown permission %q", name)
+			}
+			if !ok {
+				perm = name
+			}
+			perms = append(perms, perm)
+		}
+	}
+	return perms, nil
+}
+
+func reportedPermission(p string) bool {
+	for _, perm := range specPermissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+func pascalCase(name string) string {
+	return strings.ReplaceAll(name, " ", "")
+}
+
+// normalizeText collapses the line breaks and indentation of multi-line yaml text.
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func validFormat(f string) bool {
+	for _, format := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -67,6 +67,11 @@ func (s StatusFlags) String() string {
 	return "Accessory " + strings.Join(notes, "/")
 }
 
+// Paired returns true if the accessory reports that it's paired with a controller.
+func (s StatusFlags) Paired() bool {
+	return s&1 == 0
+}
+
 // AccessoryDevice is information about a discovered HomeKit accessory.
 type AccessoryDevice struct {
 	Name         string
@@ -94,16 +99,7 @@ func Discover(ctx context.Context, onDevice func(context.Context, *AccessoryDevi
 	devicesCh := make(chan *zeroconf.ServiceEntry)
 	go func() {
 		for dev := range devicesCh {
-			txt := parseTXT(dev.Text)
-			onDevice(ctx, &AccessoryDevice{
-				Name:         dev.Instance,
-				ID:           txt["id"],
-				Model:        txt["md"],
-				IPs:          append(dev.AddrIPv4, dev.AddrIPv6...),
-				Port:         dev.Port,
-				FeatureFlags: FeatureFlags(parseFlag(txt["ff"])),
-				StatusFlags:  StatusFlags(parseFlag(txt["sf"])),
-			})
+			onDevice(ctx, newAccessoryDevice(dev))
 		}
 		devicesWG.Done()
 	}()
@@ -137,16 +133,34 @@ func parseTXT(txts []string) map[string]string {
 	return mapped
 }
 
-func parseFlag(v string) byte {
+// newAccessoryDevice describes the device advertised by a Bonjour service entry. Malformed
+// feature or status flags are treated as unset so the device is still reported.
+func newAccessoryDevice(dev *zeroconf.ServiceEntry) *AccessoryDevice {
+	txt := parseTXT(dev.Text)
+	featureFlags, _ := parseFlag(txt["ff"])
+	statusFlags, _ := parseFlag(txt["sf"])
+
+	return &AccessoryDevice{
+		Name:         dev.Instance,
+		ID:           txt["id"],
+		Model:        txt["md"],
+		IPs:          append(dev.AddrIPv4, dev.AddrIPv6...),
+		Port:         dev.Port,
+		FeatureFlags: FeatureFlags(featureFlags),
+		StatusFlags:  StatusFlags(statusFlags),
+	}
+}
+
+func parseFlag(v string) (byte, error) {
 	if v == "" {
-		return 0
+		return 0, nil
 	}
 	f, err := strconv.ParseUint(v, 10, 8)
 	if err != nil {
-		panic(err)
+		return 0, fmt.Errorf("invalid flags %q", v)
 	}
 
-	return byte(f)
+	return byte(f), nil
 }
 
 // DeviceByID searches for a device with the provided deviceID and returns it if found.
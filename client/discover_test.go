@@ -0,0 +1,23 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/grandcat/zeroconf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccessoryDeviceMalformedFlags(t *testing.T) {
+	entry := zeroconf.NewServiceEntry("Sensor", homekitService, homekitDomain)
+	entry.Text = []string{"id=AA:BB:CC:DD:EE:FF", "md=Sensor", "ff=bad", "sf=1"}
+
+	dev := newAccessoryDevice(entry)
+	require.Equal(t, "AA:BB:CC:DD:EE:FF", dev.ID)
+	require.Equal(t, FeatureFlags(0), dev.FeatureFlags)
+	require.Equal(t, StatusFlags(1), dev.StatusFlags)
+
+	entry.Text = []string{"id=AA:BB:CC:DD:EE:FF", "ff=2", "sf=300"}
+	dev = newAccessoryDevice(entry)
+	require.Equal(t, FeatureFlags(2), dev.FeatureFlags)
+	require.Equal(t, StatusFlags(0), dev.StatusFlags)
+}
@@ -0,0 +1,126 @@
+package hapfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+)
+
+// Accessory is an accessory served by the fake. It has the same form as the accessories
+// returned by the /accessories endpoint of a real accessory.
+type Accessory struct {
+	ID       uint64                `json:"aid"`
+	Services []*service.RawService `json:"services"`
+}
+
+// ReadAccessories parses an attribute database captured from the /accessories endpoint of an
+// accessory. Both the endpoint's {"accessories": [...]} response and a bare list of accessories
+// are accepted.
+func ReadAccessories(r io.Reader) ([]*Accessory, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var accessories []*Accessory
+	if err := json.Unmarshal(data, &accessories); err == nil {
+		return accessories, nil
+	}
+
+	var db struct {
+		Accessories []*Accessory `json:"accessories"`
+	}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("parse accessories: %v", err)
+	}
+
+	return db.Accessories, nil
+}
+
+// LoadAccessories reads an attribute database from a file. See ReadAccessories.
+func LoadAccessories(filePath string) ([]*Accessory, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadAccessories(f)
+}
+
+// DefaultAccessories returns the attribute database served when none is configured. It has a
+// single accessory with an info service and a switch.
+func DefaultAccessories() []*Accessory {
+	return []*Accessory{
+		{
+			ID: 1,
+			Services: []*service.RawService{
+				{
+					ID:   1,
+					Type: service.TypeAccessoryInformation,
+					Characteristics: []*characteristic.RawCharacteristic{
+						stringCharacteristic(2, characteristic.TypeName, "Fake"),
+						stringCharacteristic(3, characteristic.TypeManufacturer, "hapfake"),
+						stringCharacteristic(4, characteristic.TypeModel, "Switch"),
+						stringCharacteristic(5, characteristic.TypeSerialNumber, "0001"),
+						stringCharacteristic(6, characteristic.TypeFirmwareRevision, "1.0.0"),
+						{
+							ID:          7,
+							Type:        characteristic.TypeIdentify,
+							Format:      "bool",
+							Permissions: []string{characteristic.PermissionPairedWrite},
+						},
+					},
+				},
+				{
+					ID:   8,
+					Type: service.TypeSwitch,
+					Characteristics: []*characteristic.RawCharacteristic{
+						{
+							ID:     9,
+							Type:   characteristic.TypeOn,
+							Format: "bool",
+							Value:  characteristic.Value("false"),
+							Permissions: []string{
+								characteristic.PermissionPairedRead,
+								characteristic.PermissionPairedWrite,
+								characteristic.PermissionEvents,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func stringCharacteristic(id uint64, typ, value string) *characteristic.RawCharacteristic {
+	encoded, _ := json.Marshal(value)
+	return &characteristic.RawCharacteristic{
+		ID:          id,
+		Type:        typ,
+		Format:      "string",
+		Value:       characteristic.Value(encoded),
+		Permissions: []string{characteristic.PermissionPairedRead},
+	}
+}
+
+// copyAccessories returns a deep copy so the server can update values without modifying the
+// caller's accessories.
+func copyAccessories(accessories []*Accessory) ([]*Accessory, error) {
+	data, err := json.Marshal(accessories)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied []*Accessory
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
@@ -0,0 +1,94 @@
+package hapfake
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAccessories(t *testing.T) {
+	const captured = `{"aid": 1, "services": [{"iid": 1, "type": "3E", "characteristics": [
+		{"iid": 2, "type": "23", "format": "string", "value": "Lamp", "perms": ["pr"]}]}]}`
+
+	for name, data := range map[string]string{
+		"endpoint response": `{"accessories": [` + captured + `]}`,
+		"list":              `[` + captured + `]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			accessories, err := ReadAccessories(strings.NewReader(data))
+			require.NoError(t, err)
+			require.Len(t, accessories, 1)
+			require.Equal(t, uint64(1), accessories[0].ID)
+			require.Equal(t, `"Lamp"`, string(accessories[0].Services[0].Characteristics[0].Value))
+		})
+	}
+
+	_, err := ReadAccessories(strings.NewReader(`{"accessories": 1}`))
+	require.Error(t, err)
+}
+
+func TestServerValues(t *testing.T) {
+	s, err := NewServer(Config{})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.Equal(t, DefaultPIN, s.PIN())
+	require.Equal(t, characteristic.Value("false"), s.Value(1, 9))
+
+	require.NoError(t, s.SetValue(1, 9, true))
+	require.Equal(t, characteristic.Value("true"), s.Value(1, 9))
+	require.Error(t, s.SetValue(1, 9, "on"))
+	require.Error(t, s.SetValue(1, 99, true))
+	require.Nil(t, s.Value(1, 99))
+
+	// the configured accessories aren't modified
+	require.Equal(t, characteristic.Value("false"), DefaultAccessories()[0].Services[1].Characteristics[0].Value)
+}
+
+func TestValidateValue(t *testing.T) {
+	maxLen := 4
+	tests := []struct {
+		name  string
+		ch    *characteristic.RawCharacteristic
+		value string
+		want  string
+	}{
+		{"bool", &characteristic.RawCharacteristic{Format: "bool"}, "true", "true"},
+		{"bool number", &characteristic.RawCharacteristic{Format: "bool"}, "1", "true"},
+		{"bool invalid", &characteristic.RawCharacteristic{Format: "bool"}, "2", ""},
+		{"uint8", &characteristic.RawCharacteristic{Format: "uint8"}, "255", "255"},
+		{"uint8 overflow", &characteristic.RawCharacteristic{Format: "uint8"}, "256", ""},
+		{"uint8 fraction", &characteristic.RawCharacteristic{Format: "uint8"}, "1.5", ""},
+		{"int negative", &characteristic.RawCharacteristic{Format: "int"}, "-5", "-5"},
+		{"uint64", &characteristic.RawCharacteristic{Format: "uint64"}, "18446744073709551615", "18446744073709551615"},
+		{"float", &characteristic.RawCharacteristic{Format: "float"}, "21.5", "21.5"},
+		{
+			"float above max",
+			&characteristic.RawCharacteristic{Format: "float", MaxValue: characteristic.Value("38")},
+			"38.5", "",
+		},
+		{
+			"uint8 below min",
+			&characteristic.RawCharacteristic{Format: "uint8", MinValue: characteristic.Value("10")},
+			"9", "",
+		},
+		{"string", &characteristic.RawCharacteristic{Format: "string", MaxLen: &maxLen}, `"abcd"`, `"abcd"`},
+		{"string too long", &characteristic.RawCharacteristic{Format: "string", MaxLen: &maxLen}, `"abcde"`, ""},
+		{"tlv8", &characteristic.RawCharacteristic{Format: "tlv8"}, `"AQE="`, `"AQE="`},
+		{"tlv8 invalid", &characteristic.RawCharacteristic{Format: "tlv8"}, `1`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := validateValue(tt.ch, []byte(tt.value))
+			if tt.want == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(v))
+		})
+	}
+}
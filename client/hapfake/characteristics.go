@@ -0,0 +1,502 @@
+package hapfake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mctofu/homekit/client/characteristic"
+)
+
+// HAP status codes returned for characteristics and requests.
+const (
+	StatusSuccess                     = 0
+	StatusInsufficientPrivileges      = -70401
+	StatusServiceCommunicationFailure = -70402
+	StatusResourceBusy                = -70403
+	StatusReadOnly                    = -70404
+	StatusWriteOnly                   = -70405
+	StatusNotificationNotSupported    = -70406
+	StatusOutOfResources              = -70407
+	StatusOperationTimedOut           = -70408
+	StatusResourceDoesNotExist        = -70409
+	StatusInvalidValue                = -70410
+	StatusInsufficientAuthorization   = -70411
+)
+
+const (
+	statusConnectionAuthorizationRequired = 470
+
+	permissionTimedWrite    = "tw"
+	permissionWriteResponse = "wr"
+
+	defaultMaxLen = 64
+)
+
+// prepared is a timed write prepared with /prepare.
+type prepared struct {
+	id      uint64
+	expires time.Time
+}
+
+// characteristic returns the characteristic or nil if it doesn't exist. s.mu must be held.
+func (s *Server) characteristic(aid, iid uint64) *characteristic.RawCharacteristic {
+	for _, acc := range s.accessories {
+		if acc.ID != aid {
+			continue
+		}
+		for _, svc := range acc.Services {
+			for _, c := range svc.Characteristics {
+				if c.ID == iid {
+					return c
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleAccessories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"accessories": s.accessories})
+}
+
+func (s *Server) handleCharacteristics(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.readCharacteristics(w, r)
+	case http.MethodPut:
+		s.writeCharacteristics(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+type readResponse struct {
+	AccessoryID      uint64               `json:"aid"`
+	CharacteristicID uint64               `json:"iid"`
+	Value            characteristic.Value `json:"value,omitempty"`
+	Status           *int                 `json:"status,omitempty"`
+
+	Type        string   `json:"type,omitempty"`
+	Events      *bool    `json:"ev,omitempty"`
+	Permissions []string `json:"perms,omitempty"`
+
+	Format    string               `json:"format,omitempty"`
+	Unit      string               `json:"unit,omitempty"`
+	MaxLen    *int                 `json:"maxLen,omitempty"`
+	MaxValue  characteristic.Value `json:"maxValue,omitempty"`
+	MinValue  characteristic.Value `json:"minValue,omitempty"`
+	StepValue characteristic.Value `json:"minStep,omitempty"`
+}
+
+func (s *Server) readCharacteristics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	ids, err := parseIDs(query.Get("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]int{"status": StatusInvalidValue})
+		return
+	}
+
+	meta := query.Get("meta") == "1"
+	perms := query.Get("perms") == "1"
+	typ := query.Get("type") == "1"
+	ev := query.Get("ev") == "1"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := connForRequest(r)
+	failStatus := failureStatus(r)
+
+	var failed bool
+	responses := make([]*readResponse, 0, len(ids))
+	for _, id := range ids {
+		resp := &readResponse{AccessoryID: id.aid, CharacteristicID: id.iid}
+		responses = append(responses, resp)
+
+		ch := s.characteristic(id.aid, id.iid)
+		status := StatusSuccess
+		switch {
+		case failStatus != StatusSuccess:
+			status = failStatus
+		case ch == nil:
+			status = StatusResourceDoesNotExist
+		case !ch.HasPermission(characteristic.PermissionPairedRead):
+			status = StatusWriteOnly
+		}
+		if status != StatusSuccess {
+			failed = true
+			resp.Status = &status
+			continue
+		}
+
+		resp.Value = valueOrNull(ch.Value)
+		if typ {
+			resp.Type = ch.Type
+		}
+		if perms {
+			resp.Permissions = ch.Permissions
+		}
+		if ev {
+			subscribed := c != nil && c.subscriptions[id]
+			resp.Events = &subscribed
+		}
+		if meta {
+			resp.Format = ch.Format
+			resp.Unit = ch.Unit
+			resp.MaxLen = ch.MaxLen
+			resp.MaxValue = ch.MaxValue
+			resp.MinValue = ch.MinValue
+			resp.StepValue = ch.StepValue
+		}
+	}
+
+	if !failed {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"characteristics": responses})
+		return
+	}
+
+	// a multi-status response includes a status for every characteristic
+	for _, resp := range responses {
+		if resp.Status == nil {
+			success := StatusSuccess
+			resp.Status = &success
+		}
+	}
+	writeJSON(w, http.StatusMultiStatus, map[string]interface{}{"characteristics": responses})
+}
+
+type writeRequest struct {
+	Characteristics []struct {
+		AccessoryID      uint64          `json:"aid"`
+		CharacteristicID uint64          `json:"iid"`
+		Value            json.RawMessage `json:"value"`
+		Events           *bool           `json:"ev"`
+		Response         bool            `json:"r"`
+	} `json:"characteristics"`
+	PrepareID *uint64 `json:"pid"`
+}
+
+type writeResponse struct {
+	AccessoryID      uint64               `json:"aid"`
+	CharacteristicID uint64               `json:"iid"`
+	Status           int                  `json:"status"`
+	Value            characteristic.Value `json:"value,omitempty"`
+}
+
+func (s *Server) writeCharacteristics(w http.ResponseWriter, r *http.Request) {
+	var req writeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]int{"status": StatusInvalidValue})
+		return
+	}
+
+	s.mu.Lock()
+
+	c := connForRequest(r)
+	failStatus := failureStatus(r)
+	firstWrite := len(s.writes)
+
+	timed := false
+	if req.PrepareID != nil {
+		timed = c != nil && c.prepared != nil && c.prepared.id == *req.PrepareID &&
+			time.Now().Before(c.prepared.expires)
+		if c != nil {
+			// a prepared write can only be used once
+			c.prepared = nil
+		}
+	}
+
+	var multiStatus bool
+	responses := make([]*writeResponse, 0, len(req.Characteristics))
+	for _, cw := range req.Characteristics {
+		resp := &writeResponse{AccessoryID: cw.AccessoryID, CharacteristicID: cw.CharacteristicID}
+		responses = append(responses, resp)
+
+		// scripted failures aren't applied
+		if failStatus != StatusSuccess {
+			resp.Status = failStatus
+			multiStatus = true
+			continue
+		}
+
+		id := characteristicID{cw.AccessoryID, cw.CharacteristicID}
+		resp.Status = s.writeCharacteristic(c, id, cw.Value, cw.Events, req.PrepareID != nil, timed)
+		if resp.Status != StatusSuccess {
+			multiStatus = true
+			continue
+		}
+
+		if cw.Response {
+			ch := s.characteristic(id.aid, id.iid)
+			if ch.HasPermission(permissionWriteResponse) {
+				resp.Value = valueOrNull(ch.Value)
+				multiStatus = true
+			}
+		}
+	}
+
+	applied := append([]Write(nil), s.writes[firstWrite:]...)
+	s.mu.Unlock()
+
+	if s.onWrite != nil {
+		for _, write := range applied {
+			s.onWrite(write)
+		}
+	}
+
+	if !multiStatus {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, http.StatusMultiStatus, map[string]interface{}{"characteristics": responses})
+}
+
+// writeCharacteristic applies a single write and returns its status. s.mu must be held.
+func (s *Server) writeCharacteristic(c *conn, id characteristicID, value json.RawMessage, events *bool, prepared, timed bool) int {
+	ch := s.characteristic(id.aid, id.iid)
+	if ch == nil {
+		return StatusResourceDoesNotExist
+	}
+
+	if events != nil {
+		if !ch.HasPermission(characteristic.PermissionEvents) {
+			return StatusNotificationNotSupported
+		}
+		if c != nil {
+			if *events {
+				c.subscriptions[id] = true
+			} else {
+				delete(c.subscriptions, id)
+			}
+		}
+	}
+
+	if value == nil {
+		return StatusSuccess
+	}
+
+	if !ch.HasPermission(characteristic.PermissionPairedWrite) {
+		return StatusReadOnly
+	}
+	if prepared && !timed {
+		return StatusInvalidValue
+	}
+	if ch.HasPermission(permissionTimedWrite) && !timed {
+		return StatusInvalidValue
+	}
+
+	normalized, err := validateValue(ch, value)
+	if err != nil {
+		return StatusInvalidValue
+	}
+
+	if ch.Type == characteristic.TypeIdentify {
+		s.identified++
+	} else {
+		ch.Value = normalized
+	}
+	s.writes = append(s.writes, Write{
+		AccessoryID:      id.aid,
+		CharacteristicID: id.iid,
+		Value:            normalized,
+		Timed:            timed,
+	})
+	if ch.Type != characteristic.TypeIdentify {
+		s.notify(id.aid, id.iid, c)
+	}
+
+	return StatusSuccess
+}
+
+func (s *Server) handlePrepare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		TTL uint64 `json:"ttl"`
+		PID uint64 `json:"pid"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]int{"status": StatusInvalidValue})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c := connForRequest(r); c != nil {
+		c.prepared = &prepared{
+			id:      req.PID,
+			expires: time.Now().Add(time.Duration(req.TTL) * time.Millisecond),
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{"status": StatusSuccess})
+}
+
+// setValue validates and updates the value of a characteristic.
+func setValue(ch *characteristic.RawCharacteristic, v interface{}) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	normalized, err := validateValue(ch, encoded)
+	if err != nil {
+		return err
+	}
+	ch.Value = normalized
+
+	return nil
+}
+
+// validateValue checks that the JSON encoded value is valid for the characteristic's format
+// and limits. Bool values written as 0 or 1 are returned as false or true.
+func validateValue(ch *characteristic.RawCharacteristic, value json.RawMessage) (characteristic.Value, error) {
+	switch ch.Format {
+	case "bool":
+		switch strings.TrimSpace(string(value)) {
+		case "true", "1":
+			return characteristic.Value("true"), nil
+		case "false", "0":
+			return characteristic.Value("false"), nil
+		}
+		return nil, fmt.Errorf("invalid bool %s", value)
+	case "uint8", "uint16", "uint32", "uint64", "int":
+		var n json.Number
+		if err := json.Unmarshal(value, &n); err != nil {
+			return nil, fmt.Errorf("invalid %s %s", ch.Format, value)
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil {
+			if ch.Format != "uint64" {
+				return nil, fmt.Errorf("invalid %s %s", ch.Format, value)
+			}
+			if _, err := strconv.ParseUint(n.String(), 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid %s %s", ch.Format, value)
+			}
+			return characteristic.Value(n.String()), nil
+		}
+		min, max := formatRange(ch.Format)
+		if float64(i) < min || float64(i) > max {
+			return nil, fmt.Errorf("%s %d out of range", ch.Format, i)
+		}
+		if err := checkLimits(ch, float64(i)); err != nil {
+			return nil, err
+		}
+		return characteristic.Value(n.String()), nil
+	case "float":
+		var f float64
+		if err := json.Unmarshal(value, &f); err != nil {
+			return nil, fmt.Errorf("invalid float %s", value)
+		}
+		if err := checkLimits(ch, f); err != nil {
+			return nil, err
+		}
+		return characteristic.Value(value), nil
+	case "string":
+		var str string
+		if err := json.Unmarshal(value, &str); err != nil {
+			return nil, fmt.Errorf("invalid string %s", value)
+		}
+		maxLen := defaultMaxLen
+		if ch.MaxLen != nil {
+			maxLen = *ch.MaxLen
+		}
+		if len(str) > maxLen {
+			return nil, fmt.Errorf("string longer than %d", maxLen)
+		}
+		return characteristic.Value(value), nil
+	case "tlv8", "data":
+		var str string
+		if err := json.Unmarshal(value, &str); err != nil {
+			return nil, fmt.Errorf("invalid %s %s", ch.Format, value)
+		}
+		return characteristic.Value(value), nil
+	default:
+		if !json.Valid(value) {
+			return nil, errors.New("invalid json")
+		}
+		return characteristic.Value(value), nil
+	}
+}
+
+func formatRange(format string) (float64, float64) {
+	switch format {
+	case "uint8":
+		return 0, math.MaxUint8
+	case "uint16":
+		return 0, math.MaxUint16
+	case "uint32":
+		return 0, math.MaxUint32
+	case "int":
+		return math.MinInt32, math.MaxInt32
+	default:
+		return 0, math.MaxInt64
+	}
+}
+
+// checkLimits checks v against the characteristic's minValue and maxValue.
+func checkLimits(ch *characteristic.RawCharacteristic, v float64) error {
+	if ch.MinValue != nil {
+		if min, err := ch.MinValue.Float64(); err == nil && v < min {
+			return fmt.Errorf("%v less than minimum %v", v, min)
+		}
+	}
+	if ch.MaxValue != nil {
+		if max, err := ch.MaxValue.Float64(); err == nil && v > max {
+			return fmt.Errorf("%v greater than maximum %v", v, max)
+		}
+	}
+	return nil
+}
+
+// parseIDs parses a comma separated list of aid.iid pairs.
+func parseIDs(v string) ([]characteristicID, error) {
+	if v == "" {
+		return nil, errors.New("no ids")
+	}
+
+	var ids []characteristicID
+	for _, pair := range strings.Split(v, ",") {
+		parts := strings.SplitN(pair, ".", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid id %s", pair)
+		}
+		aid, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %s", pair)
+		}
+		iid, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %s", pair)
+		}
+		ids = append(ids, characteristicID{aid, iid})
+	}
+
+	return ids, nil
+}
+
+func valueOrNull(v characteristic.Value) characteristic.Value {
+	if v == nil {
+		return characteristic.Value("null")
+	}
+	return v
+}
@@ -0,0 +1,193 @@
+package hapfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/brutella/hc/crypto"
+	"github.com/brutella/hc/hap"
+)
+
+type characteristicID struct {
+	aid, iid uint64
+}
+
+type connKey struct{}
+
+// listener wraps accepted connections so they're encrypted once pair-verify completes.
+type listener struct {
+	net.Listener
+	server *Server
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{
+		Connection:    hap.NewConnection(c, l.server.hapCtx),
+		subscriptions: make(map[characteristicID]bool),
+	}, nil
+}
+
+// conn is a connection from a controller. Fields are guarded by Server.mu.
+type conn struct {
+	*hap.Connection
+
+	// idle is true between requests when events can be sent without interleaving them with a
+	// response
+	idle          bool
+	pending       []characteristicID
+	subscriptions map[characteristicID]bool
+	prepared      *prepared
+	// controllerID is the controller that verified the connection
+	controllerID string
+}
+
+func connForRequest(r *http.Request) *conn {
+	c, _ := r.Context().Value(connKey{}).(*conn)
+	return c
+}
+
+func (s *Server) connState(nc net.Conn, state http.ConnState) {
+	c, ok := nc.(*conn)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch state {
+	case http.StateNew:
+		s.conns[c] = true
+	case http.StateActive:
+		c.idle = false
+	case http.StateIdle:
+		c.idle = true
+		s.flushEvents(c)
+	case http.StateHijacked, http.StateClosed:
+		delete(s.conns, c)
+	}
+}
+
+// notify sends an event for the characteristic to subscribed connections other than except.
+// Events for connections in the middle of a request are sent once the response is written.
+// s.mu must be held.
+func (s *Server) notify(aid, iid uint64, except *conn) {
+	id := characteristicID{aid, iid}
+	for c := range s.conns {
+		if c == except || !c.subscriptions[id] {
+			continue
+		}
+		c.pending = append(c.pending, id)
+		if c.idle {
+			s.flushEvents(c)
+		}
+	}
+}
+
+// flushEvents writes the pending events of an idle connection. s.mu must be held.
+func (s *Server) flushEvents(c *conn) {
+	if len(c.pending) == 0 {
+		return
+	}
+
+	type eventValue struct {
+		AccessoryID      uint64          `json:"aid"`
+		CharacteristicID uint64          `json:"iid"`
+		Value            json.RawMessage `json:"value"`
+	}
+
+	var events []eventValue
+	seen := make(map[characteristicID]bool)
+	for _, id := range c.pending {
+		ch := s.characteristic(id.aid, id.iid)
+		if seen[id] || ch == nil {
+			continue
+		}
+		seen[id] = true
+		events = append(events, eventValue{id.aid, id.iid, json.RawMessage(valueOrNull(ch.Value))})
+	}
+	c.pending = nil
+
+	body, err := json.Marshal(map[string]interface{}{"characteristics": events})
+	if err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "EVENT/1.0 200 OK\r\nContent-Type: %s\r\nContent-Length: %d\r\n\r\n", hap.HTTPContentTypeHAPJson, len(body))
+	buf.Write(body)
+
+	// write the event with a single call so it's sent in a single encrypted frame
+	if _, err := c.Write(buf.Bytes()); err != nil {
+		_ = c.Close()
+	}
+}
+
+// verified rejects requests on connections that haven't completed pair-verify.
+func (s *Server) verified(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, _ := s.hapCtx.Get(s.hapCtx.GetConnectionKey(r)).(hap.Session)
+		if session == nil || session.Encrypter() == nil {
+			writeJSON(w, statusConnectionAuthorizationRequired, map[string]int{"status": StatusInsufficientPrivileges})
+			return
+		}
+		h(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", hap.HTTPContentTypeHAPJson)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
+
+// syncContext wraps sessions so their cryptographer can be accessed from the goroutine
+// writing events while requests are read.
+type syncContext struct {
+	hap.Context
+}
+
+func (c *syncContext) SetSessionForConnection(s hap.Session, conn net.Conn) {
+	c.Context.SetSessionForConnection(&syncSession{Session: s}, conn)
+}
+
+type syncSession struct {
+	hap.Session
+	mu sync.Mutex
+}
+
+func (s *syncSession) Decrypter() crypto.Decrypter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Session.Decrypter()
+}
+
+func (s *syncSession) Encrypter() crypto.Encrypter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Session.Encrypter()
+}
+
+func (s *syncSession) SetCryptographer(c crypto.Cryptographer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Session.SetCryptographer(c)
+}
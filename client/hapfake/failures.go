@@ -0,0 +1,113 @@
+package hapfake
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Failure scripts how the server responds to matching requests instead of handling them
+// normally.
+type Failure struct {
+	// Method matches the request method. Any method matches if empty.
+	Method string
+	// Path matches the request path such as /characteristics. Any path matches if empty.
+	Path string
+
+	// Delay waits before responding or failing.
+	Delay time.Duration
+	// Disconnect closes the connection without responding.
+	Disconnect bool
+	// StatusCode responds with the http status code and a body containing Status.
+	StatusCode int
+	// Status is the HAP status of the failure. Without a StatusCode it's returned for every
+	// characteristic of a /characteristics request in a multi-status response.
+	Status int
+
+	// Count is the number of requests to fail. Requests fail until ClearFailures is called
+	// if it's 0.
+	Count int
+}
+
+type failureStatusKey struct{}
+
+// Fail makes matching requests fail. Failures are matched in the order they were added.
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, &f)
+}
+
+// ClearFailures removes all scripted failures.
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = nil
+}
+
+// takeFailure returns the first failure matching the request and counts it against the
+// failure's Count.
+func (s *Server) takeFailure(r *http.Request) *Failure {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.failures {
+		if (f.Method != "" && f.Method != r.Method) || (f.Path != "" && f.Path != r.URL.Path) {
+			continue
+		}
+
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.failures = append(s.failures[:i:i], s.failures[i+1:]...)
+			}
+		}
+
+		matched := *f
+		return &matched
+	}
+
+	return nil
+}
+
+func (s *Server) injectFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := s.takeFailure(r)
+		if f == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if f.Delay > 0 {
+			select {
+			case <-time.After(f.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		switch {
+		case f.Disconnect:
+			if c := connForRequest(r); c != nil {
+				_ = c.Close()
+			}
+			// stop the server from writing a response on the closed connection
+			panic(http.ErrAbortHandler)
+		case f.StatusCode != 0:
+			writeJSON(w, f.StatusCode, map[string]int{"status": f.Status})
+		case f.Status != StatusSuccess:
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), failureStatusKey{}, f.Status)))
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// failureStatus returns the status a scripted failure returns for each characteristic of the
+// request.
+func failureStatus(r *http.Request) int {
+	status, _ := r.Context().Value(failureStatusKey{}).(int)
+	return status
+}
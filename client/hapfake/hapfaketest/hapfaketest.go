@@ -0,0 +1,76 @@
+// Package hapfaketest pairs controllers with fake accessories served by hapfake in tests.
+package hapfaketest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/hapfake"
+)
+
+// NewServer starts a fake accessory serving the attribute database in accessoriesJSON. The
+// default accessories are served if it's empty. The server is closed when the test finishes.
+func NewServer(t testing.TB, accessoriesJSON string, cfg hapfake.Config) *hapfake.Server {
+	t.Helper()
+
+	if accessoriesJSON != "" {
+		accessories, err := hapfake.ReadAccessories(strings.NewReader(accessoriesJSON))
+		if err != nil {
+			t.Fatalf("read accessories: %v", err)
+		}
+		cfg.Accessories = accessories
+	}
+
+	server, err := hapfake.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("start fake accessory: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	return server
+}
+
+// Pair pairs a new controller with the fake accessory and returns its identity and the
+// connection config of the accessory.
+func Pair(t testing.TB, server *hapfake.Server) (*client.ControllerIdentity, *client.AccessoryConnectionConfig) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	controller, err := client.NewRandomControllerConfig()
+	if err != nil {
+		t.Fatalf("new controller: %v", err)
+	}
+
+	connCfg, err := client.NewSetupClient(&http.Client{}).Pair(ctx,
+		&client.AccessoryPairingConfig{
+			IPConnectionInfo: client.IPConnectionInfo{IPAddress: server.Host(), Port: server.Port()},
+			PIN:              server.PIN(),
+			DeviceID:         server.DeviceID(),
+		},
+		controller,
+	)
+	if err != nil {
+		t.Fatalf("pair: %v", err)
+	}
+
+	return controller, connCfg
+}
+
+// NewClient starts a fake accessory like NewServer and returns it with a client paired with
+// it. The client is closed when the test finishes.
+func NewClient(t testing.TB, accessoriesJSON string) (*hapfake.Server, *client.AccessoryClient) {
+	t.Helper()
+
+	server := NewServer(t, accessoriesJSON, hapfake.Config{})
+	controller, connCfg := Pair(t, server)
+	accClient := client.NewAccessoryClient(client.NewIPDialer(), controller, connCfg)
+	t.Cleanup(func() { accClient.Close() })
+
+	return server, accClient
+}
@@ -0,0 +1,293 @@
+package hapfake
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/event"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/hc/hap/endpoint"
+	"github.com/brutella/hc/hap/pair"
+	"github.com/brutella/hc/util"
+)
+
+const (
+	pairingMethodList = 5
+	tagSeparator      = 0xFF
+	permissionAdmin   = 1
+)
+
+var _ db.Database = (*entityDB)(nil)
+
+// entityDB stores the device's keys and the controllers paired with it.
+type entityDB struct {
+	mu       sync.Mutex
+	entities map[string]db.Entity
+}
+
+func (d *entityDB) EntityWithName(name string) (db.Entity, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entity, ok := d.entities[name]
+	if !ok {
+		return db.Entity{}, errors.New("not found")
+	}
+	return entity, nil
+}
+
+func (d *entityDB) SaveEntity(entity db.Entity) error {
+	if entity.Name == "" {
+		return errors.New("entity missing name")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.entities == nil {
+		d.entities = make(map[string]db.Entity)
+	}
+	d.entities[entity.Name] = entity
+
+	return nil
+}
+
+func (d *entityDB) DeleteEntity(entity db.Entity) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.entities, entity.Name)
+}
+
+func (d *entityDB) Entities() ([]db.Entity, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result := make([]db.Entity, 0, len(d.entities))
+	for _, e := range d.entities {
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
+
+// pairings returns the paired controllers. Controllers paired with pair-setup are admins.
+// s.mu must be held.
+func (s *Server) pairings() []Pairing {
+	entities, _ := s.controllers.Entities()
+
+	result := make([]Pairing, 0, len(entities))
+	for _, e := range entities {
+		if e.Name == s.deviceID {
+			// the accessory's own keys
+			continue
+		}
+		result = append(result, Pairing{
+			ControllerID: e.Name,
+			PublicKey:    e.PublicKey,
+			Admin:        !s.users[e.Name],
+		})
+	}
+
+	return result
+}
+
+// verifyDB notes the controller looked up by pair-verify on a connection.
+type verifyDB struct {
+	db.Database
+	server *Server
+	conn   *conn
+}
+
+func (d *verifyDB) EntityWithName(name string) (db.Entity, error) {
+	entity, err := d.Database.EntityWithName(name)
+	if err == nil && d.conn != nil {
+		d.server.mu.Lock()
+		d.conn.controllerID = name
+		d.server.mu.Unlock()
+	}
+	return entity, err
+}
+
+// handlePairVerify handles pair-verify noting which controller verified the connection.
+// The verify controller is created with the database of the first request of a connection.
+func (s *Server) handlePairVerify(w http.ResponseWriter, r *http.Request) {
+	verifyDB := &verifyDB{Database: s.controllers, server: s, conn: connForRequest(r)}
+	endpoint.NewPairVerify(s.hapCtx, verifyDB).ServeHTTP(w, r)
+}
+
+// isAdmin returns true if the controller is paired with admin permission. s.mu must be held.
+func (s *Server) isAdmin(controllerID string) bool {
+	for _, p := range s.pairings() {
+		if p.ControllerID == controllerID {
+			return p.Admin
+		}
+	}
+	return false
+}
+
+func (s *Server) handlePairings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	in, err := util.NewTLV8ContainerFromReader(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	var body []byte
+	changed := false
+	c := connForRequest(r)
+	switch method := in.GetByte(pair.TagPairingMethod); {
+	case c == nil || !s.isAdmin(c.controllerID):
+		// only admins can manage pairings
+		body = pairingResponse(pair.ErrCodeAuthenticationFailed.Byte())
+	case method == pair.PairingMethodAdd.Byte():
+		body = s.addPairing(in)
+		changed = true
+	case method == pair.PairingMethodDelete.Byte():
+		body = s.removePairing(in)
+		changed = true
+	case method == pairingMethodList:
+		body = s.listPairings()
+	default:
+		body = pairingResponse(pair.ErrCodeUnknown.Byte())
+	}
+	s.mu.Unlock()
+
+	if changed {
+		s.pairingChanged()
+	}
+
+	w.Header().Set("Content-Type", hap.HTTPContentTypePairingTLV8)
+	_, _ = w.Write(body)
+}
+
+func (s *Server) pairingChanged() {
+	if s.onPairing != nil {
+		s.onPairing()
+	}
+}
+
+// pairingListener is called when a controller completes pair-setup.
+type pairingListener func()
+
+func (l pairingListener) Handle(ev interface{}) {
+	if _, ok := ev.(event.DevicePaired); ok {
+		l()
+	}
+}
+
+// addPairing adds a controller or updates the permissions of a paired controller.
+// s.mu must be held.
+func (s *Server) addPairing(in util.Container) []byte {
+	name := in.GetString(pair.TagUsername)
+	publicKey := in.GetBytes(pair.TagPublicKey)
+	if name == "" || len(publicKey) == 0 {
+		return pairingResponse(pair.ErrCodeUnknown.Byte())
+	}
+
+	if existing, err := s.controllers.EntityWithName(name); err == nil && !bytes.Equal(existing.PublicKey, publicKey) {
+		return pairingResponse(pair.ErrCodeUnknown.Byte())
+	}
+
+	if err := s.controllers.SaveEntity(db.NewEntity(name, publicKey, nil)); err != nil {
+		return pairingResponse(pair.ErrCodeUnknown.Byte())
+	}
+
+	if in.GetByte(pair.TagPermission) == permissionAdmin {
+		delete(s.users, name)
+	} else {
+		s.users[name] = true
+	}
+
+	return pairingResponse(pair.ErrCodeNo.Byte())
+}
+
+// removePairing removes a controller. Removing the last admin removes every pairing like an
+// accessory does when it's reset. s.mu must be held.
+func (s *Server) removePairing(in util.Container) []byte {
+	name := in.GetString(pair.TagUsername)
+	s.controllers.DeleteEntity(db.Entity{Name: name})
+	delete(s.users, name)
+
+	admins := 0
+	for _, p := range s.pairings() {
+		if p.Admin {
+			admins++
+		}
+	}
+	if admins == 0 {
+		for _, p := range s.pairings() {
+			s.controllers.DeleteEntity(db.Entity{Name: p.ControllerID})
+		}
+		s.users = make(map[string]bool)
+	}
+
+	return pairingResponse(pair.ErrCodeNo.Byte())
+}
+
+// listPairings returns each pairing separated by a separator item. s.mu must be held.
+func (s *Server) listPairings() []byte {
+	var buf bytes.Buffer
+	for i, p := range s.pairings() {
+		out := util.NewTLV8Container()
+		if i == 0 {
+			out.SetByte(pair.TagSequence, 2)
+		} else {
+			buf.Write([]byte{tagSeparator, 0})
+		}
+		out.SetString(pair.TagUsername, p.ControllerID)
+		out.SetBytes(pair.TagPublicKey, p.PublicKey)
+		permission := byte(0)
+		if p.Admin {
+			permission = permissionAdmin
+		}
+		out.SetByte(pair.TagPermission, permission)
+		buf.Write(out.BytesBuffer().Bytes())
+	}
+
+	if buf.Len() == 0 {
+		return pairingResponse(pair.ErrCodeNo.Byte())
+	}
+
+	return buf.Bytes()
+}
+
+func pairingResponse(errCode byte) []byte {
+	out := util.NewTLV8Container()
+	out.SetByte(pair.TagSequence, 2)
+	if errCode != pair.ErrCodeNo.Byte() {
+		out.SetByte(pair.TagErrCode, errCode)
+	}
+	return out.BytesBuffer().Bytes()
+}
+
+// handleIdentify handles identify requests from unpaired controllers. Paired accessories
+// reject them and must be identified with the Identify characteristic.
+func (s *Server) handleIdentify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pairings()) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]int{"status": StatusInsufficientPrivileges})
+		return
+	}
+
+	s.identified++
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,275 @@
+// Package hapfake runs a fake HomeKit accessory in-process for testing controllers.
+//
+// The fake serves an attribute database over the HAP IP protocol. It supports pair-setup,
+// pair-verify, reading and writing characteristics, events, timed writes, identify and pairing
+// management. Failures can be scripted to test how a controller handles misbehaving accessories.
+package hapfake
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/event"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/hc/hap/endpoint"
+	"github.com/mctofu/homekit/client/characteristic"
+)
+
+// Defaults used when the Config doesn't specify them.
+const (
+	DefaultDeviceID = "5F-7A-CA-6A-83-92"
+	DefaultPIN      = "031-45-154"
+)
+
+// Config describes the accessory served by a Server.
+type Config struct {
+	// DeviceID is the accessory's pairing identifier.
+	DeviceID string
+	// PIN is the setup code used for pair-setup.
+	PIN string
+	// Accessories is the attribute database to serve. DefaultAccessories is served if empty.
+	Accessories []*Accessory
+	// Addr is the address to listen on. A random loopback port is used if empty.
+	Addr string
+	// Database stores the accessory's keys and the controllers paired with it. They're kept in
+	// memory if nil.
+	Database db.Database
+
+	// OnWrite is called after a controller writes a characteristic value.
+	OnWrite func(w Write)
+	// OnPairingChange is called after a controller pairs with the accessory or a pairing is
+	// removed.
+	OnPairingChange func()
+}
+
+// Write records a characteristic value written by a controller.
+type Write struct {
+	AccessoryID      uint64
+	CharacteristicID uint64
+	Value            characteristic.Value
+	// Timed is true if the value was written with a prepared timed write.
+	Timed bool
+}
+
+// Server is a fake accessory listening for connections from controllers.
+type Server struct {
+	deviceID string
+	pin      string
+
+	listener    net.Listener
+	httpServer  *http.Server
+	hapCtx      hap.Context
+	controllers db.Database
+	onWrite     func(w Write)
+	onPairing   func()
+
+	mu          sync.Mutex
+	accessories []*Accessory
+	conns       map[*conn]bool
+	writes      []Write
+	identified  int
+	failures    []*Failure
+	// users holds controllers added without admin permission
+	users map[string]bool
+}
+
+// NewServer starts serving a fake accessory.
+func NewServer(cfg Config) (*Server, error) {
+	if cfg.DeviceID == "" {
+		cfg.DeviceID = DefaultDeviceID
+	}
+	if cfg.PIN == "" {
+		cfg.PIN = DefaultPIN
+	}
+	if len(cfg.Accessories) == 0 {
+		cfg.Accessories = DefaultAccessories()
+	}
+
+	accessories, err := copyAccessories(cfg.Accessories)
+	if err != nil {
+		return nil, fmt.Errorf("copy accessories: %v", err)
+	}
+
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:0"
+	}
+	if cfg.Database == nil {
+		cfg.Database = &entityDB{}
+	}
+
+	device, err := hap.NewSecuredDevice(cfg.DeviceID, cfg.PIN, cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("NewSecuredDevice: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		deviceID:    cfg.DeviceID,
+		pin:         cfg.PIN,
+		listener:    ln,
+		hapCtx:      &syncContext{hap.NewContextForSecuredDevice(device)},
+		controllers: cfg.Database,
+		accessories: accessories,
+		onWrite:     cfg.OnWrite,
+		onPairing:   cfg.OnPairingChange,
+		conns:       make(map[*conn]bool),
+		users:       make(map[string]bool),
+	}
+
+	emitter := event.NewEmitter()
+	emitter.AddListener(pairingListener(s.pairingChanged))
+
+	mux := http.NewServeMux()
+	mux.Handle("/pair-setup", endpoint.NewPairSetup(s.hapCtx, device, s.controllers, emitter))
+	mux.HandleFunc("/pair-verify", s.handlePairVerify)
+	mux.HandleFunc("/accessories", s.verified(s.handleAccessories))
+	mux.HandleFunc("/characteristics", s.verified(s.handleCharacteristics))
+	mux.HandleFunc("/prepare", s.verified(s.handlePrepare))
+	mux.HandleFunc("/pairings", s.verified(s.handlePairings))
+	mux.HandleFunc("/identify", s.handleIdentify)
+
+	s.httpServer = &http.Server{
+		Handler: s.injectFailures(mux),
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, connKey{}, c)
+		},
+		ConnState: s.connState,
+	}
+
+	go func() {
+		_ = s.httpServer.Serve(&listener{Listener: ln, server: s})
+	}()
+
+	return s, nil
+}
+
+// Close stops the server and closes all connections.
+func (s *Server) Close() error {
+	return s.httpServer.Close()
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Host returns the ip address the server is listening on.
+func (s *Server) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port the server is listening on.
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// DeviceID returns the accessory's pairing identifier.
+func (s *Server) DeviceID() string {
+	return s.deviceID
+}
+
+// PIN returns the setup code used for pair-setup.
+func (s *Server) PIN() string {
+	return s.pin
+}
+
+// Accessories returns a copy of the attribute database including the current values.
+func (s *Server) Accessories() []*Accessory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accessories, _ := copyAccessories(s.accessories)
+	return accessories
+}
+
+// Value returns the current JSON encoded value of a characteristic or nil if it doesn't exist.
+func (s *Server) Value(aid, iid uint64) characteristic.Value {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.characteristic(aid, iid)
+	if c == nil {
+		return nil
+	}
+
+	return append(characteristic.Value(nil), c.Value...)
+}
+
+// SetValue updates the value of a characteristic as if it changed on the accessory and sends
+// events to subscribed controllers.
+func (s *Server) SetValue(aid, iid uint64, v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.characteristic(aid, iid)
+	if c == nil {
+		return fmt.Errorf("characteristic %d.%d not found", aid, iid)
+	}
+
+	if err := setValue(c, v); err != nil {
+		return err
+	}
+	s.notify(aid, iid, nil)
+
+	return nil
+}
+
+// Writes returns the values written by controllers in the order they were written.
+func (s *Server) Writes() []Write {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Write(nil), s.writes...)
+}
+
+// Identified returns the number of times the accessory was asked to identify itself.
+func (s *Server) Identified() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.identified
+}
+
+// Paired returns true if a controller has paired with the accessory.
+func (s *Server) Paired() bool {
+	return len(s.Pairings()) > 0
+}
+
+// Pairing describes a controller paired with the accessory.
+type Pairing struct {
+	ControllerID string
+	PublicKey    []byte
+	Admin        bool
+}
+
+// Pairings returns the controllers paired with the accessory.
+func (s *Server) Pairings() []Pairing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pairings()
+}
+
+// Subscriptions returns the number of connections subscribed to events from a characteristic.
+func (s *Server) Subscriptions(aid, iid uint64) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := characteristicID{aid, iid}
+	count := 0
+	for c := range s.conns {
+		if c.subscriptions[id] {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,320 @@
+package pairing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/brutella/hc/crypto"
+	"github.com/brutella/hc/crypto/chacha20poly1305"
+	"github.com/brutella/hc/crypto/hkdf"
+	"github.com/brutella/hc/db"
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/hc/hap/pair"
+	"github.com/brutella/hc/util"
+)
+
+const (
+	srpGroupSize = 384
+	srpSaltSize  = 16
+
+	tagRetryDelay = 0x08
+)
+
+// SetupStep identifies a message of the pair-setup exchange. Odd steps are sent
+// by the controller and even steps are sent by the accessory.
+type SetupStep byte
+
+// Messages of the pair-setup exchange.
+const (
+	SetupStepM1 SetupStep = iota + 1
+	SetupStepM2
+	SetupStepM3
+	SetupStepM4
+	SetupStepM5
+	SetupStepM6
+)
+
+func (s SetupStep) String() string {
+	switch s {
+	case SetupStepM1:
+		return "M1 (SRP Start Request)"
+	case SetupStepM2:
+		return "M2 (SRP Start Response)"
+	case SetupStepM3:
+		return "M3 (SRP Verify Request)"
+	case SetupStepM4:
+		return "M4 (SRP Verify Response)"
+	case SetupStepM5:
+		return "M5 (Exchange Request)"
+	case SetupStepM6:
+		return "M6 (Exchange Response)"
+	default:
+		return fmt.Sprintf("M%d (Unknown)", byte(s))
+	}
+}
+
+// Byte returns the value used for the step in the sequence TLV.
+func (s SetupStep) Byte() byte {
+	return byte(s)
+}
+
+// SetupStepHook is called with each pair-setup message after it is built and before
+// it is sent (M1, M3, M5) or after it is received and before it is processed (M2, M4, M6).
+// Returning an error aborts the pairing.
+type SetupStepHook func(step SetupStep, msg util.Container) error
+
+// TLVError is an error code reported by an accessory in a pairing response.
+type TLVError struct {
+	Code byte
+	// RetryDelay is the number of seconds the accessory asks the controller
+	// to wait before retrying. Only set for backoff errors.
+	RetryDelay int
+}
+
+// Pairing error codes defined by HAP.
+const (
+	TLVErrorUnknown        = 0x01
+	TLVErrorAuthentication = 0x02
+	TLVErrorBackoff        = 0x03
+	TLVErrorMaxPeers       = 0x04
+	TLVErrorMaxTries       = 0x05
+	TLVErrorUnavailable    = 0x06
+	TLVErrorBusy           = 0x07
+)
+
+func (t *TLVError) Error() string {
+	switch t.Code {
+	case TLVErrorUnknown:
+		return "accessory reported an unknown error"
+	case TLVErrorAuthentication:
+		return "authentication failed (is the PIN correct?)"
+	case TLVErrorBackoff:
+		return fmt.Sprintf("accessory requested a retry after %d seconds", t.RetryDelay)
+	case TLVErrorMaxPeers:
+		return "accessory cannot accept any more pairings"
+	case TLVErrorMaxTries:
+		return "accessory reached its maximum number of authentication attempts"
+	case TLVErrorUnavailable:
+		return "accessory is unavailable for pairing (is it already paired?)"
+	case TLVErrorBusy:
+		return "accessory is busy with another pairing"
+	default:
+		return fmt.Sprintf("accessory reported error code %d", t.Code)
+	}
+}
+
+// tlvError returns a *TLVError if the container includes a non-zero error code.
+func tlvError(in util.Container) error {
+	code := in.GetByte(pair.TagErrCode)
+	if code == 0 {
+		return nil
+	}
+
+	return &TLVError{
+		Code:       code,
+		RetryDelay: int(in.GetByte(tagRetryDelay)),
+	}
+}
+
+// SetupClientController performs the controller side of the pair-setup procedure using
+// SRP-6a with the 3072-bit group. After a successful exchange the accessory's long term
+// public key is saved to the database.
+type SetupClientController struct {
+	client   hap.Device
+	database db.Database
+	pin      string
+
+	srp           *srpClient
+	encryptionKey [32]byte
+}
+
+// NewSetupClientController returns a new setup client controller for pairing with an
+// accessory using the provided setup code.
+func NewSetupClientController(pin string, client hap.Device, database db.Database) *SetupClientController {
+	return &SetupClientController{
+		client:   client,
+		database: database,
+		pin:      pin,
+	}
+}
+
+// InitialPairingRequest returns the M1 message the controller sends to start pairing with the
+// specified pairing method.
+func (s *SetupClientController) InitialPairingRequest(method byte) util.Container {
+	out := util.NewTLV8Container()
+	out.SetByte(pair.TagPairingMethod, method)
+	out.SetByte(pair.TagSequence, SetupStepM1.Byte())
+
+	return out
+}
+
+// Handle processes a response from the accessory and returns the next request to send. A nil
+// container is returned once the exchange is complete.
+func (s *SetupClientController) Handle(in util.Container) (util.Container, error) {
+	if err := tlvError(in); err != nil {
+		return nil, err
+	}
+
+	step := SetupStep(in.GetByte(pair.TagSequence))
+	switch step {
+	case SetupStepM2:
+		return s.handleStartResponse(in)
+	case SetupStepM4:
+		return s.handleVerifyResponse(in)
+	case SetupStepM6:
+		return s.handleExchangeResponse(in)
+	default:
+		return nil, fmt.Errorf("unexpected setup step: %v", step)
+	}
+}
+
+// Accessory -> Controller (M2)
+// - s: salt
+// - B: accessory SRP public key
+//
+// Controller -> Accessory (M3)
+// - A: controller SRP public key
+// - M1: controller proof
+func (s *SetupClientController) handleStartResponse(in util.Container) (util.Container, error) {
+	salt := in.GetBytes(pair.TagSalt)
+	if len(salt) != srpSaltSize {
+		return nil, fmt.Errorf("invalid salt size %d", len(salt))
+	}
+
+	// accessories may strip leading zero bytes from the key
+	accessoryPublicKey := in.GetBytes(pair.TagPublicKey)
+	if len(accessoryPublicKey) == 0 || len(accessoryPublicKey) > srpGroupSize {
+		return nil, fmt.Errorf("invalid accessory SRP public key size %d", len(accessoryPublicKey))
+	}
+
+	srp, err := newSRPClient("Pair-Setup", s.pin)
+	if err != nil {
+		return nil, err
+	}
+	if err := srp.computeKey(salt, accessoryPublicKey); err != nil {
+		return nil, fmt.Errorf("srp compute key: %v", err)
+	}
+	s.srp = srp
+
+	out := util.NewTLV8Container()
+	out.SetByte(pair.TagSequence, SetupStepM3.Byte())
+	out.SetBytes(pair.TagPublicKey, srp.publicKey())
+	out.SetBytes(pair.TagProof, srp.proof)
+
+	return out, nil
+}
+
+// Accessory -> Controller (M4)
+// - M2: accessory proof
+//
+// Controller -> Accessory (M5)
+// - encrypted message
+//   - controller pairing id
+//   - controller long term public key
+//   - signature: from derived key, pairing id, long term public key
+func (s *SetupClientController) handleVerifyResponse(in util.Container) (util.Container, error) {
+	if s.srp == nil {
+		return nil, errors.New("unexpected verify response before start response")
+	}
+	if !s.srp.verifyAccessoryProof(in.GetBytes(pair.TagProof)) {
+		return nil, errors.New("accessory SRP proof is invalid")
+	}
+
+	encryptionKey, err := hkdf.Sha512(s.srp.key, []byte("Pair-Setup-Encrypt-Salt"), []byte("Pair-Setup-Encrypt-Info"))
+	if err != nil {
+		return nil, fmt.Errorf("derive encryption key: %v", err)
+	}
+	s.encryptionKey = encryptionKey
+
+	controllerX, err := hkdf.Sha512(s.srp.key, []byte("Pair-Setup-Controller-Sign-Salt"), []byte("Pair-Setup-Controller-Sign-Info"))
+	if err != nil {
+		return nil, fmt.Errorf("derive controller signing key: %v", err)
+	}
+
+	var material []byte
+	material = append(material, controllerX[:]...)
+	material = append(material, s.client.Name()...)
+	material = append(material, s.client.PublicKey()...)
+
+	signature, err := crypto.ED25519Signature(s.client.PrivateKey(), material)
+	if err != nil {
+		return nil, fmt.Errorf("sign controller info: %v", err)
+	}
+
+	encryptedOut := util.NewTLV8Container()
+	encryptedOut.SetString(pair.TagUsername, s.client.Name())
+	encryptedOut.SetBytes(pair.TagPublicKey, s.client.PublicKey())
+	encryptedOut.SetBytes(pair.TagSignature, signature)
+
+	encryptedBytes, mac, err := chacha20poly1305.EncryptAndSeal(s.encryptionKey[:], []byte("PS-Msg05"), encryptedOut.BytesBuffer().Bytes(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("chacha20poly1305.EncryptAndSeal: %v", err)
+	}
+
+	out := util.NewTLV8Container()
+	out.SetByte(pair.TagSequence, SetupStepM5.Byte())
+	out.SetBytes(pair.TagEncryptedData, append(encryptedBytes, mac[:]...))
+
+	return out, nil
+}
+
+// Accessory -> Controller (M6)
+// - encrypted message
+//   - accessory pairing id
+//   - accessory long term public key
+//   - signature: from derived key, pairing id, long term public key
+func (s *SetupClientController) handleExchangeResponse(in util.Container) (util.Container, error) {
+	if s.srp == nil {
+		return nil, errors.New("unexpected exchange response before start response")
+	}
+
+	data := in.GetBytes(pair.TagEncryptedData)
+	if len(data) < 16 {
+		return nil, fmt.Errorf("invalid encrypted data size %d", len(data))
+	}
+	message := data[:(len(data) - 16)]
+	var mac [16]byte
+	copy(mac[:], data[len(message):]) // 16 byte (MAC)
+
+	decryptedBytes, err := chacha20poly1305.DecryptAndVerify(s.encryptionKey[:], []byte("PS-Msg06"), message, mac, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt accessory info: %v", err)
+	}
+
+	decryptedIn, err := util.NewTLV8ContainerFromReader(bytes.NewBuffer(decryptedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("parse accessory info: %v", err)
+	}
+
+	accessoryID := decryptedIn.GetString(pair.TagUsername)
+	accessoryLTPK := decryptedIn.GetBytes(pair.TagPublicKey)
+	signature := decryptedIn.GetBytes(pair.TagSignature)
+
+	if accessoryID == "" {
+		return nil, errors.New("accessory pairing id missing")
+	}
+	if len(accessoryLTPK) != 32 {
+		return nil, fmt.Errorf("invalid accessory public key size %d", len(accessoryLTPK))
+	}
+
+	accessoryX, err := hkdf.Sha512(s.srp.key, []byte("Pair-Setup-Accessory-Sign-Salt"), []byte("Pair-Setup-Accessory-Sign-Info"))
+	if err != nil {
+		return nil, fmt.Errorf("derive accessory signing key: %v", err)
+	}
+
+	var material []byte
+	material = append(material, accessoryX[:]...)
+	material = append(material, accessoryID...)
+	material = append(material, accessoryLTPK...)
+
+	if !crypto.ValidateED25519Signature(accessoryLTPK, material, signature) {
+		return nil, errors.New("could not validate accessory signature")
+	}
+
+	if err := s.database.SaveEntity(db.NewEntity(accessoryID, accessoryLTPK, nil)); err != nil {
+		return nil, fmt.Errorf("save accessory: %v", err)
+	}
+
+	return nil, nil
+}
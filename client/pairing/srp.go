@@ -0,0 +1,137 @@
+package pairing
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// srpPrime is the 3072-bit prime of the SRP group from RFC 5054 used by HAP.
+var srpPrime, _ = new(big.Int).SetString(
+	"FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74"+
+		"020BBEA63B139B22514A08798E3404DDEF9519B3CD3A431B302B0A6DF25F1437"+
+		"4FE1356D6D51C245E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7ED"+
+		"EE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3DC2007CB8A163BF05"+
+		"98DA48361C55D39A69163FA8FD24CF5F83655D23DCA3AD961C62F356208552BB"+
+		"9ED529077096966D670C354E4ABC9804F1746C08CA18217C32905E462E36CE3B"+
+		"E39E772C180E86039B2783A2EC07A28FB5C55DF06F4C52C9DE2BCBF695581718"+
+		"3995497CEA956AE515D2261898FA051015728E5A8AAAC42DAD33170D04507A33"+
+		"A85521ABDF1CBA64ECFB850458DBEF0A8AEA71575D060C7DB3970F85A6E1E4C7"+
+		"ABF5AE8CDB0933D71E8C94E04A25619DCEE3D2261AD2EE6BF12FFA06D98A0864"+
+		"D87602733EC86A64521F2B18177B200CBBE117577A615D6C770988C0BAD946E2"+
+		"08E24FA074E5AB3143DB5BFCE0FD108E4B82D120A93AD2CAFFFFFFFFFFFFFFFF",
+	16,
+)
+
+// srpGenerator is the generator of the SRP group.
+var srpGenerator = big.NewInt(5)
+
+// srpPrivateKeySize is the size in bytes of the random private value a.
+const srpPrivateKeySize = 32
+
+// srpClient is the controller side of SRP-6a as used by pair-setup. Hashes are SHA-512, the
+// username is "Pair-Setup" and the password is the setup code.
+type srpClient struct {
+	username []byte
+	password []byte
+
+	a *big.Int
+	A *big.Int
+
+	// key is the session key K = H(S) once computeKey succeeds.
+	key []byte
+	// proof is the controller proof M1 sent to the accessory.
+	proof []byte
+}
+
+// newSRPClient returns an srpClient with a new random private value.
+func newSRPClient(username, password string) (*srpClient, error) {
+	privateKey := make([]byte, srpPrivateKeySize)
+	if _, err := rand.Read(privateKey); err != nil {
+		return nil, fmt.Errorf("generate srp private key: %v", err)
+	}
+
+	a := new(big.Int).SetBytes(privateKey)
+	return &srpClient{
+		username: []byte(username),
+		password: []byte(password),
+		a:        a,
+		A:        new(big.Int).Exp(srpGenerator, a, srpPrime),
+	}, nil
+}
+
+// publicKey returns the controller public key A.
+func (c *srpClient) publicKey() []byte {
+	return c.A.Bytes()
+}
+
+// computeKey computes the session key and controller proof from the salt and public key B
+// sent by the accessory.
+func (c *srpClient) computeKey(salt, accessoryPublicKey []byte) error {
+	B := new(big.Int).SetBytes(accessoryPublicKey)
+	if B.Cmp(srpPrime) >= 0 || new(big.Int).Mod(B, srpPrime).Sign() == 0 {
+		return errors.New("invalid accessory public key")
+	}
+
+	// u = H(PAD(A) | PAD(B))
+	u := new(big.Int).SetBytes(srpHash(srpPad(c.A), srpPad(B)))
+	if u.Sign() == 0 {
+		return errors.New("invalid scrambling parameter")
+	}
+
+	// k = H(N | PAD(g))
+	k := new(big.Int).SetBytes(srpHash(srpPrime.Bytes(), srpPad(srpGenerator)))
+
+	// x = H(s | H(I | ":" | P))
+	x := new(big.Int).SetBytes(srpHash(salt, srpHash(c.username, []byte(":"), c.password)))
+
+	// S = (B - k * g^x) ^ (a + u * x) % N
+	base := new(big.Int).Exp(srpGenerator, x, srpPrime)
+	base.Mul(k, base)
+	base.Sub(B, base)
+	base.Mod(base, srpPrime)
+	exp := new(big.Int).Mul(u, x)
+	exp.Add(c.a, exp)
+	S := new(big.Int).Exp(base, exp, srpPrime)
+
+	c.key = srpHash(S.Bytes())
+
+	// M1 = H(H(N) xor H(g) | H(I) | s | A | B | K)
+	hN := new(big.Int).SetBytes(srpHash(srpPrime.Bytes()))
+	hg := new(big.Int).SetBytes(srpHash(srpGenerator.Bytes()))
+	c.proof = srpHash(
+		hN.Xor(hN, hg).Bytes(),
+		srpHash(c.username),
+		salt,
+		c.A.Bytes(),
+		B.Bytes(),
+		c.key,
+	)
+
+	return nil
+}
+
+// verifyAccessoryProof returns true if proof is the accessory proof M2 = H(A | M1 | K).
+func (c *srpClient) verifyAccessoryProof(proof []byte) bool {
+	if c.key == nil {
+		return false
+	}
+	expected := srpHash(c.A.Bytes(), c.proof, c.key)
+	return subtle.ConstantTimeCompare(expected, proof) == 1
+}
+
+// srpPad left pads v with zeros to the size of the prime.
+func srpPad(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, srpGroupSize))
+}
+
+func srpHash(values ...[]byte) []byte {
+	h := sha512.New()
+	for _, v := range values {
+		h.Write(v)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,108 @@
+package pairing
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/brutella/hc/hap/pair"
+	"github.com/stretchr/testify/require"
+	"github.com/tadglines/go-pkgs/crypto/srp"
+)
+
+func TestSRPClient(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		client, err := newSRPClient("Pair-Setup", "031-45-154")
+		require.NoError(t, err)
+		testSRPExchange(t, client)
+	}
+}
+
+func TestSRPClientShortPublicKey(t *testing.T) {
+	// A = g^a has a leading zero byte so it's shorter than the prime
+	client := testSRPClient("a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a120")
+	require.Less(t, len(client.publicKey()), srpGroupSize)
+
+	testSRPExchange(t, client)
+}
+
+func TestSRPClientShortSharedSecret(t *testing.T) {
+	salt := bytes.Repeat([]byte{0x5a}, srpSaltSize)
+	client := testSRPClient("a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a1b5")
+
+	// the accessory side of the exchange with a fixed private value b:
+	// v = g^x, B = k*v + g^b and S = (A * v^u)^b
+	x := new(big.Int).SetBytes(srpHash(salt, srpHash([]byte("Pair-Setup:031-45-154"))))
+	v := new(big.Int).Exp(srpGenerator, x, srpPrime)
+	k := new(big.Int).SetBytes(srpHash(srpPrime.Bytes(), srpPad(srpGenerator)))
+	b := new(big.Int).SetBytes(bytes.Repeat([]byte{0xb0}, 32))
+	B := new(big.Int).Exp(srpGenerator, b, srpPrime)
+	B.Add(B, k.Mul(k, v)).Mod(B, srpPrime)
+	u := new(big.Int).SetBytes(srpHash(srpPad(client.A), srpPad(B)))
+	S := new(big.Int).Exp(v, u, srpPrime)
+	S.Mul(S, client.A).Mod(S, srpPrime).Exp(S, b, srpPrime)
+
+	// S has a leading zero byte. HAP hashes it without padding: K = H(S)
+	require.Less(t, len(S.Bytes()), srpGroupSize)
+	require.NoError(t, client.computeKey(salt, B.Bytes()))
+	require.Equal(t, srpHash(S.Bytes()), client.key)
+	require.NotEqual(t, srpHash(srpPad(S)), client.key)
+}
+
+// testSRPClient returns an srpClient with the hex encoded private value a.
+func testSRPClient(a string) *srpClient {
+	privateKey, _ := new(big.Int).SetString(a, 16)
+	return &srpClient{
+		username: []byte("Pair-Setup"),
+		password: []byte("031-45-154"),
+		a:        privateKey,
+		A:        new(big.Int).Exp(srpGenerator, privateKey, srpPrime),
+	}
+}
+
+// testSRPExchange completes an exchange between the client and an accessory and checks
+// they agree on the key and accept each other's proofs.
+func testSRPExchange(t *testing.T, client *srpClient) {
+	t.Helper()
+
+	accessorySRP, err := srp.NewSRP(pair.SRPGroup, sha512.New, pair.KeyDerivativeFuncRFC2945(sha512.New, []byte("Pair-Setup")))
+	require.NoError(t, err)
+	accessorySRP.SaltLength = srpSaltSize
+	salt, verifier, err := accessorySRP.ComputeVerifier([]byte("031-45-154"))
+	require.NoError(t, err)
+	accessory := accessorySRP.NewServerSession([]byte("Pair-Setup"), salt, verifier)
+
+	require.NoError(t, client.computeKey(salt, accessory.GetB()))
+
+	key, err := accessory.ComputeKey(client.publicKey())
+	require.NoError(t, err)
+	require.Equal(t, key, client.key)
+	require.True(t, accessory.VerifyClientAuthenticator(client.proof))
+	require.True(t, client.verifyAccessoryProof(accessory.ComputeAuthenticator(client.proof)))
+}
+
+func TestSRPClientWrongPassword(t *testing.T) {
+	accessorySRP, err := srp.NewSRP(pair.SRPGroup, sha512.New, pair.KeyDerivativeFuncRFC2945(sha512.New, []byte("Pair-Setup")))
+	require.NoError(t, err)
+	salt, verifier, err := accessorySRP.ComputeVerifier([]byte("031-45-154"))
+	require.NoError(t, err)
+	accessory := accessorySRP.NewServerSession([]byte("Pair-Setup"), salt, verifier)
+
+	client, err := newSRPClient("Pair-Setup", "111-11-111")
+	require.NoError(t, err)
+	require.NoError(t, client.computeKey(salt, accessory.GetB()))
+
+	_, err = accessory.ComputeKey(client.publicKey())
+	require.NoError(t, err)
+	require.False(t, accessory.VerifyClientAuthenticator(client.proof))
+	require.False(t, client.verifyAccessoryProof(make([]byte, sha512.Size)))
+}
+
+func TestSRPClientInvalidPublicKey(t *testing.T) {
+	client, err := newSRPClient("Pair-Setup", "031-45-154")
+	require.NoError(t, err)
+	require.EqualError(t, client.computeKey(make([]byte, srpSaltSize), []byte{0}), "invalid accessory public key")
+	require.EqualError(t, client.computeKey(make([]byte, srpSaltSize), srpPrime.Bytes()), "invalid accessory public key")
+	require.False(t, client.verifyAccessoryProof(nil))
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mctofu/homekit/client/characteristic"
+)
+
+// QueryEncoding is how the ids of a /characteristics request are encoded in the query.
+type QueryEncoding string
+
+// Query encodings accepted by accessories.
+const (
+	// QueryEncodingRaw separates ids with raw commas as shown in the HAP specification.
+	QueryEncodingRaw QueryEncoding = "raw"
+	// QueryEncodingEscaped separates ids with url encoded commas.
+	QueryEncodingEscaped QueryEncoding = "escaped"
+)
+
+// Quirks declare workarounds for accessories that deviate from the HAP specification. They
+// apply to accessories with a matching manufacturer and model.
+//
+// There's no quirk limiting concurrent requests since AccessoryClient always sends requests
+// one at a time over its single verified connection.
+type Quirks struct {
+	// Name describes the quirks.
+	Name string `json:"name" yaml:"name"`
+	// Manufacturer matches the Manufacturer of the accessory information. Any manufacturer
+	// matches if empty.
+	Manufacturer string `json:"manufacturer,omitempty" yaml:"manufacturer,omitempty"`
+	// Model matches the Model of the accessory information or the md Bonjour record. Any model
+	// matches if empty.
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+
+	// QueryEncoding is how characteristic ids are encoded when reading characteristics.
+	// Defaults to QueryEncodingRaw.
+	QueryEncoding QueryEncoding `json:"queryEncoding,omitempty" yaml:"queryEncoding,omitempty"`
+	// FillMissingMetadata sets the format of characteristics read with metadata from the known
+	// format of their type when the accessory omits it.
+	FillMissingMetadata bool `json:"fillMissingMetadata,omitempty" yaml:"fillMissingMetadata,omitempty"`
+	// IdleTimeout closes the connection after it's been idle for the duration for accessories
+	// that silently drop idle connections.
+	IdleTimeout time.Duration `json:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty"`
+	// StatusCodes maps http status codes returned by the accessory to the status codes the
+	// specification requires. For example, 400 to 207 for an accessory reporting failed writes
+	// with the wrong status.
+	StatusCodes map[int]int `json:"statusCodes,omitempty" yaml:"statusCodes,omitempty"`
+}
+
+// DefaultQuirks are the known quirks of accessories.
+var DefaultQuirks = []*Quirks{
+	{
+		Name:         "Velux",
+		Manufacturer: "VELUX",
+		// url encoded commas aren't understood
+		QueryEncoding: QueryEncodingRaw,
+		// format is omitted from the metadata of characteristics
+		FillMissingMetadata: true,
+	},
+}
+
+// Matches returns true if the quirks apply to an accessory with the manufacturer and model.
+// Empty values are unknown and only match quirks that don't require them. Quirks that match
+// any accessory never match.
+func (q *Quirks) Matches(manufacturer, model string) bool {
+	if q.Manufacturer == "" && q.Model == "" {
+		return false
+	}
+	if q.Manufacturer != "" && !strings.EqualFold(q.Manufacturer, manufacturer) {
+		return false
+	}
+	if q.Model != "" && !strings.EqualFold(q.Model, model) {
+		return false
+	}
+	return true
+}
+
+// FindQuirks returns the first quirks matching the manufacturer and model or nil if none
+// match.
+func FindQuirks(quirks []*Quirks, manufacturer, model string) *Quirks {
+	for _, q := range quirks {
+		if q.Matches(manufacturer, model) {
+			return q
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the quirks can't be applied.
+func (q *Quirks) Validate() error {
+	if q.Manufacturer == "" && q.Model == "" {
+		return fmt.Errorf("quirks %s must match a manufacturer or model", q.Name)
+	}
+	switch q.QueryEncoding {
+	case "", QueryEncodingRaw, QueryEncodingEscaped:
+	default:
+		return fmt.Errorf("quirks %s has unknown query encoding %s", q.Name, q.QueryEncoding)
+	}
+	for from, to := range q.StatusCodes {
+		if http.StatusText(from) == "" || http.StatusText(to) == "" {
+			return fmt.Errorf("quirks %s maps unknown status code %d to %d", q.Name, from, to)
+		}
+	}
+	return nil
+}
+
+// encodeQuery encodes the ids of a /characteristics request.
+func (q *Quirks) encodeQuery(ids string) string {
+	if q != nil && q.QueryEncoding == QueryEncodingEscaped {
+		return url.QueryEscape(ids)
+	}
+	return ids
+}
+
+// fillMetadata sets missing formats from the known format of the characteristic type.
+func (q *Quirks) fillMetadata(resps []*CharacteristicReadResponse) {
+	if q == nil || !q.FillMissingMetadata {
+		return
+	}
+	for _, resp := range resps {
+		if resp.Format != nil || resp.Type == nil {
+			continue
+		}
+		if format := characteristic.FormatForType(*resp.Type); format != "" {
+			resp.Format = &format
+		}
+	}
+}
+
+// mapStatus replaces the status of the response if the accessory is known to return the wrong
+// status.
+func (q *Quirks) mapStatus(resp *http.Response) {
+	if q == nil {
+		return
+	}
+	if code, ok := q.StatusCodes[resp.StatusCode]; ok {
+		resp.StatusCode = code
+		resp.Status = fmt.Sprintf("%d %s", code, http.StatusText(code))
+	}
+}
@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindQuirks(t *testing.T) {
+	quirks := []*Quirks{
+		{Name: "hub", Manufacturer: "Acme", Model: "Hub"},
+		{Name: "acme", Manufacturer: "acme"},
+		{Name: "lamp", Model: "Lamp"},
+		{Name: "any"},
+	}
+
+	tests := []struct {
+		manufacturer, model string
+		want                string
+	}{
+		{"Acme", "Hub", "hub"},
+		{"ACME", "Sensor", "acme"},
+		{"Other", "Lamp", "lamp"},
+		// only the model is known from Bonjour
+		{"", "Lamp", "lamp"},
+		{"", "Hub", ""},
+		{"Other", "Sensor", ""},
+	}
+	for _, test := range tests {
+		got := FindQuirks(quirks, test.manufacturer, test.model)
+		if test.want == "" {
+			require.Nil(t, got, "%s/%s", test.manufacturer, test.model)
+			continue
+		}
+		require.NotNil(t, got, "%s/%s", test.manufacturer, test.model)
+		require.Equal(t, test.want, got.Name)
+	}
+}
+
+func TestQuirksValidate(t *testing.T) {
+	require.NoError(t, DefaultQuirks[0].Validate())
+	require.EqualError(t, (&Quirks{Name: "any"}).Validate(), "quirks any must match a manufacturer or model")
+	require.EqualError(t, (&Quirks{Name: "q", Model: "m", QueryEncoding: "comma"}).Validate(), "quirks q has unknown query encoding comma")
+	require.EqualError(t, (&Quirks{Name: "q", Model: "m", StatusCodes: map[int]int{400: 999}}).Validate(), "quirks q maps unknown status code 400 to 999")
+}
+
+func TestQuirksFromAccessoryInfo(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exchanges, err := ReadExchanges(strings.NewReader(`{"exchanges": [
+	  {"method": "GET", "uri": "/accessories", "status": 200, "response": {"json": {"accessories": [
+	    {"aid": 1, "services": [{"iid": 1, "type": "3E", "characteristics": [
+	      {"iid": 2, "type": "20", "format": "string", "value": "VELUX", "perms": ["pr"]},
+	      {"iid": 3, "type": "21", "format": "string", "value": "VELUX Gateway", "perms": ["pr"]}
+	    ]}]}
+	  ]}}},
+	  {"method": "GET", "uri": "/characteristics?id=2.9&meta=1&type=1", "status": 200, "response": {"json": {"characteristics": [
+	    {"aid": 2, "iid": 9, "type": "6D", "value": 40}
+	  ]}}}
+	]}`))
+	require.NoError(t, err)
+	accClient := NewAccessoryClientWithTransport(NewReplayTransport(exchanges), IPConnectionInfo{})
+	require.Nil(t, accClient.Quirks())
+
+	_, err = accClient.Accessories(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Velux", accClient.Quirks().Name)
+
+	resps, err := accClient.Characteristics(ctx, &CharacteristicsReadRequest{
+		Characteristics: []CharacteristicReadRequest{{AccessoryID: 2, CharacteristicID: 9}},
+		Metadata:        true,
+		Type:            true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resps[0].Format)
+	require.Equal(t, "uint8", *resps[0].Format)
+}
+
+func TestQuirksQueryAndStatus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exchanges, err := ReadExchanges(strings.NewReader(`{"exchanges": [
+	  {"method": "GET", "uri": "/characteristics?id=1.9%2C1.10", "status": 200, "response": {"json": {"characteristics": []}}},
+	  {"method": "PUT", "uri": "/characteristics", "request": {"json": {"characteristics": [{"aid": 1, "iid": 10, "value": 1}]}},
+	   "status": 400, "response": {"json": {"characteristics": [
+	    {"aid": 1, "iid": 10, "status": -70402}
+	  ]}}}
+	]}`))
+	require.NoError(t, err)
+	accClient := NewAccessoryClient(NewIPDialer(), &ControllerIdentity{}, &AccessoryConnectionConfig{Model: "Hub"})
+	accClient.SetTransport(NewReplayTransport(exchanges))
+	accClient.SetQuirks([]*Quirks{{
+		Name:          "hub",
+		Model:         "hub",
+		QueryEncoding: QueryEncodingEscaped,
+		StatusCodes:   map[int]int{http.StatusBadRequest: http.StatusMultiStatus},
+	}})
+	require.Equal(t, "hub", accClient.Quirks().Name)
+
+	_, err = accClient.Characteristics(ctx, &CharacteristicsReadRequest{
+		Characteristics: []CharacteristicReadRequest{
+			{AccessoryID: 1, CharacteristicID: 9},
+			{AccessoryID: 1, CharacteristicID: 10},
+		},
+	})
+	require.NoError(t, err)
+
+	resps, err := accClient.SetCharacteristics(ctx, &CharacteristicsWriteRequest{
+		Characteristics: []CharacteristicWriteRequest{{AccessoryID: 1, CharacteristicID: 10, Value: 1}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, -70402, *resps[0].Status)
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, err := deviceServer()
+	require.NoError(t, err)
+	defer testServer.Close()
+
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err)
+	connectionConfig, err := setupDeviceServer(ctx, testServer, controller)
+	require.NoError(t, err)
+
+	accClient := NewAccessoryClient(NewIPDialer(), controller, connectionConfig)
+	defer accClient.Close()
+
+	// requests sent at once share the verified connection rather than corrupting it
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			var err error
+			for j := 0; j < 10 && err == nil; j++ {
+				var resps []*CharacteristicReadResponse
+				resps, err = accClient.Characteristics(ctx, &CharacteristicsReadRequest{
+					Characteristics: []CharacteristicReadRequest{
+						{AccessoryID: 1, CharacteristicID: 9},
+						{AccessoryID: 1, CharacteristicID: 10},
+					},
+				})
+				if err == nil && len(resps) != 2 {
+					err = fmt.Errorf("unexpected responses: %d", len(resps))
+				}
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		require.NoError(t, <-errs)
+	}
+	require.Equal(t, uint64(1), accClient.ConnectionStats().Connections)
+}
+
+func TestQuirksIdleTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, err := deviceServer()
+	require.NoError(t, err)
+	defer testServer.Close()
+
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err)
+	connectionConfig, err := setupDeviceServer(ctx, testServer, controller)
+	require.NoError(t, err)
+
+	accClient := NewAccessoryClient(NewIPDialer(), controller, connectionConfig)
+	defer accClient.Close()
+	accClient.SetQuirks([]*Quirks{{Name: "fake", Manufacturer: "hapfake", IdleTimeout: 200 * time.Millisecond}})
+
+	_, err = accClient.Accessories(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "fake", accClient.Quirks().Name)
+	_, err = accClient.Accessories(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), accClient.ConnectionStats().Connections)
+
+	time.Sleep(300 * time.Millisecond)
+	_, err = accClient.Accessories(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), accClient.ConnectionStats().Connections)
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Exchange is a decrypted request sent to an accessory and the response it returned.
+type Exchange struct {
+	Method     string        `json:"method"`
+	URI        string        `json:"uri"`
+	Request    *RecordedBody `json:"request,omitempty"`
+	StatusCode int           `json:"status"`
+	Response   *RecordedBody `json:"response,omitempty"`
+}
+
+// RecordedBody is the body of a recorded request or response. Valid JSON bodies are stored as
+// JSON so fixtures are readable and can be edited. Other bodies are stored as data.
+type RecordedBody struct {
+	ContentType string          `json:"contentType,omitempty"`
+	JSON        json.RawMessage `json:"json,omitempty"`
+	Data        []byte          `json:"data,omitempty"`
+}
+
+func newRecordedBody(contentType string, body []byte) *RecordedBody {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return &RecordedBody{ContentType: contentType, JSON: body}
+	}
+	return &RecordedBody{ContentType: contentType, Data: body}
+}
+
+func (b *RecordedBody) bytes() []byte {
+	if b == nil {
+		return nil
+	}
+	if b.JSON != nil {
+		return b.JSON
+	}
+	return b.Data
+}
+
+// matches returns true if body is equal to the recorded body. JSON bodies are compared
+// ignoring whitespace.
+func (b *RecordedBody) matches(body []byte) bool {
+	if b == nil || b.JSON == nil {
+		return bytes.Equal(b.bytes(), body)
+	}
+
+	var want, got bytes.Buffer
+	if json.Compact(&want, b.JSON) != nil || json.Compact(&got, body) != nil {
+		return false
+	}
+	return bytes.Equal(want.Bytes(), got.Bytes())
+}
+
+// fixture is the file format of recorded exchanges.
+type fixture struct {
+	Exchanges []*Exchange `json:"exchanges"`
+}
+
+// ReadExchanges reads exchanges written by RecordingTransport.WriteExchanges.
+func ReadExchanges(r io.Reader) ([]*Exchange, error) {
+	var f fixture
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("decode exchanges: %v", err)
+	}
+	return f.Exchanges, nil
+}
+
+// LoadExchanges reads exchanges from a fixture file saved by RecordingTransport.Save.
+func LoadExchanges(filePath string) ([]*Exchange, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open exchanges: %v", err)
+	}
+	defer f.Close()
+
+	return ReadExchanges(f)
+}
+
+// RecordingTransport is an IPTransport that records the exchanges sent with another
+// IPTransport. The recorded exchanges can be served by a ReplayTransport.
+type RecordingTransport struct {
+	transport IPTransport
+
+	mu        sync.Mutex
+	exchanges []*Exchange
+}
+
+// NewRecordingTransport returns a RecordingTransport sending requests with transport.
+func NewRecordingTransport(transport IPTransport) *RecordingTransport {
+	return &RecordingTransport{transport: transport}
+}
+
+// Do sends the request with the wrapped transport and records the exchange if a response is
+// received.
+func (r *RecordingTransport) Do(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request: %v", err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.transport.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response: %v", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exchanges = append(r.exchanges, &Exchange{
+		Method:     req.Method,
+		URI:        req.URL.RequestURI(),
+		Request:    newRecordedBody(req.Header.Get("Content-Type"), reqBody),
+		StatusCode: resp.StatusCode,
+		Response:   newRecordedBody(resp.Header.Get("Content-Type"), respBody),
+	})
+
+	return resp, nil
+}
+
+// Exchanges returns the exchanges recorded so far.
+func (r *RecordingTransport) Exchanges() []*Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Exchange(nil), r.exchanges...)
+}
+
+// WriteExchanges writes the recorded exchanges to w.
+func (r *RecordingTransport) WriteExchanges(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(fixture{Exchanges: r.Exchanges()}); err != nil {
+		return fmt.Errorf("encode exchanges: %v", err)
+	}
+	return nil
+}
+
+// Save writes the recorded exchanges to a fixture file.
+func (r *RecordingTransport) Save(filePath string) error {
+	var buf bytes.Buffer
+	if err := r.WriteExchanges(&buf); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filePath, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("write exchanges: %v", err)
+	}
+	return nil
+}
+
+// ReplayTransport is an IPTransport that serves recorded exchanges instead of sending requests
+// to an accessory.
+type ReplayTransport struct {
+	mu        sync.Mutex
+	exchanges []*Exchange
+	used      []bool
+}
+
+// NewReplayTransport returns a ReplayTransport serving exchanges. Each exchange is served once in
+// the order recorded.
+func NewReplayTransport(exchanges []*Exchange) *ReplayTransport {
+	return &ReplayTransport{
+		exchanges: exchanges,
+		used:      make([]bool, len(exchanges)),
+	}
+}
+
+// Do returns the response of the first unused exchange with the same method, URI and body as
+// the request.
+func (r *ReplayTransport) Do(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request: %v", err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uri := req.URL.RequestURI()
+	for i, e := range r.exchanges {
+		if r.used[i] || e.Method != req.Method || e.URI != uri || !e.Request.matches(reqBody) {
+			continue
+		}
+		r.used[i] = true
+
+		resp := &http.Response{
+			Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+			StatusCode:    e.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        make(http.Header),
+			Body:          ioutil.NopCloser(bytes.NewReader(e.Response.bytes())),
+			ContentLength: int64(len(e.Response.bytes())),
+			Request:       req,
+		}
+		if e.Response != nil && e.Response.ContentType != "" {
+			resp.Header.Set("Content-Type", e.Response.ContentType)
+		}
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("no recorded exchange for %s %s", req.Method, uri)
+}
+
+// Unused returns the exchanges that haven't been served.
+func (r *ReplayTransport) Unused() []*Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []*Exchange
+	for i, e := range r.exchanges {
+		if !r.used[i] {
+			unused = append(unused, e)
+		}
+	}
+	return unused
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, accClient := fakeAccessoryClient(t, ctx)
+	recorder := NewRecordingTransport(accClient.Transport())
+	accClient.SetTransport(recorder)
+
+	readReq := &CharacteristicsReadRequest{
+		Characteristics: []CharacteristicReadRequest{
+			{AccessoryID: 1, CharacteristicID: 9},
+			{AccessoryID: 1, CharacteristicID: 12},
+		},
+		Metadata: true,
+	}
+	writeReq := &CharacteristicsWriteRequest{
+		Characteristics: []CharacteristicWriteRequest{
+			{AccessoryID: 1, CharacteristicID: 10, Value: 101},
+		},
+	}
+
+	accessories, err := accClient.Accessories(ctx)
+	require.NoError(t, err)
+	values, err := accClient.Characteristics(ctx, readReq)
+	require.NoError(t, err)
+	writes, err := accClient.SetCharacteristics(ctx, writeReq)
+	require.NoError(t, err)
+
+	require.Len(t, recorder.Exchanges(), 3)
+	require.Equal(t, "/characteristics?id=1.9,1.12&meta=1", recorder.Exchanges()[1].URI)
+	require.Equal(t, http.StatusMultiStatus, recorder.Exchanges()[2].StatusCode)
+
+	fixturePath := filepath.Join(t.TempDir(), "window.json")
+	require.NoError(t, recorder.Save(fixturePath))
+	exchanges, err := LoadExchanges(fixturePath)
+	require.NoError(t, err)
+
+	replay := NewReplayTransport(exchanges)
+	replayClient := NewAccessoryClientWithTransport(replay, accClient.ipConnectionInfo)
+
+	replayedAccessories, err := replayClient.Accessories(ctx)
+	require.NoError(t, err)
+	require.Equal(t, accessories, replayedAccessories)
+	replayedValues, err := replayClient.Characteristics(ctx, readReq)
+	require.NoError(t, err)
+	require.Equal(t, values, replayedValues)
+	replayedWrites, err := replayClient.SetCharacteristics(ctx, writeReq)
+	require.NoError(t, err)
+	require.Equal(t, writes, replayedWrites)
+	require.Empty(t, replay.Unused())
+
+	// each exchange is only served once
+	_, err = replayClient.Accessories(ctx)
+	require.EqualError(t, err, "no recorded exchange for GET /accessories")
+}
+
+func TestReplayBody(t *testing.T) {
+	exchanges, err := ReadExchanges(strings.NewReader(`{"exchanges": [
+	  {"method": "PUT", "uri": "/characteristics",
+	   "request": {"json": {"characteristics": [{"aid": 1, "iid": 10, "value": 60}]}},
+	   "status": 204},
+	  {"method": "POST", "uri": "/pairings",
+	   "request": {"contentType": "application/pairing+tlv8", "data": "AAEFBgEB"},
+	   "status": 200,
+	   "response": {"contentType": "application/pairing+tlv8", "data": "BgEC"}}
+	]}`))
+	require.NoError(t, err)
+	replay := NewReplayTransport(exchanges)
+
+	// json bodies match regardless of whitespace
+	req, err := http.NewRequest(http.MethodPut, "http://127.0.0.1/characteristics",
+		strings.NewReader(`{"characteristics":[{"aid":1,"iid":10,"value":60}]}`))
+	require.NoError(t, err)
+	resp, err := replay.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, "204 No Content", resp.Status)
+
+	req, err = http.NewRequest(http.MethodPost, "http://127.0.0.1/pairings", bytes.NewReader([]byte{0, 1, 4, 6, 1, 1}))
+	require.NoError(t, err)
+	_, err = replay.Do(req)
+	require.EqualError(t, err, "no recorded exchange for POST /pairings")
+
+	req, err = http.NewRequest(http.MethodPost, "http://127.0.0.1/pairings", bytes.NewReader([]byte{0, 1, 5, 6, 1, 1}))
+	require.NoError(t, err)
+	resp, err = replay.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, "application/pairing+tlv8", resp.Header.Get("Content-Type"))
+}
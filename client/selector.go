@@ -0,0 +1,258 @@
+package client
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+)
+
+// CharacteristicSelector identifies one or more characteristics of an accessory's attribute
+// database. Selectors are parsed from one of the following forms:
+//
+//	2.10                                 accessory id and characteristic instance id
+//	Roof Window/TargetPosition           service/characteristic
+//	Bridge/Roof Window/TargetPosition    accessory/service/characteristic
+//	window:3/TargetPosition              service type or name on accessory id 3
+//	service=Window,char=TargetPosition   key=value pairs (accessory, aid, service, char)
+//
+// Services match by type name (ex: Window) or by the value of their Name characteristic.
+// Accessories match by the name in their info service. Characteristics match by type name
+// (ex: TargetPosition) or type (ex: 7C). Names are case insensitive and may include the
+// wildcards supported by path.Match so */TargetPosition selects every TargetPosition.
+type CharacteristicSelector struct {
+	// AccessoryID limits the selection to a single accessory when non-zero.
+	AccessoryID uint64
+	// CharacteristicID selects a single characteristic by instance id when non-zero.
+	CharacteristicID uint64
+	// Accessory, Service and Characteristic are name patterns. An empty pattern matches
+	// anything.
+	Accessory      string
+	Service        string
+	Characteristic string
+}
+
+// SelectedCharacteristic is a characteristic matched by a CharacteristicSelector.
+type SelectedCharacteristic struct {
+	AccessoryID    uint64
+	Service        *service.RawService
+	Characteristic *characteristic.RawCharacteristic
+}
+
+// ParseCharacteristicSelector parses a selector in one of the forms described by
+// CharacteristicSelector.
+func ParseCharacteristicSelector(s string) (*CharacteristicSelector, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty characteristic selector")
+	}
+
+	if accID, chID, ok := parseIDSelector(s); ok {
+		return &CharacteristicSelector{AccessoryID: accID, CharacteristicID: chID}, nil
+	}
+
+	if strings.Contains(s, "=") {
+		return parseKeyValueSelector(s)
+	}
+
+	parts := strings.Split(s, "/")
+	var sel CharacteristicSelector
+	switch len(parts) {
+	case 2:
+		sel.Service, sel.Characteristic = parts[0], parts[1]
+	case 3:
+		sel.Accessory, sel.Service, sel.Characteristic = parts[0], parts[1], parts[2]
+	default:
+		return nil, fmt.Errorf("invalid characteristic selector %s, expect [accessory/]service/characteristic", s)
+	}
+
+	if i := strings.LastIndex(sel.Service, ":"); i >= 0 {
+		accID, err := strconv.ParseUint(sel.Service[i+1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse accessoryID in %s: %v", s, err)
+		}
+		sel.AccessoryID = accID
+		sel.Service = sel.Service[:i]
+	}
+
+	if err := sel.validate(); err != nil {
+		return nil, fmt.Errorf("invalid characteristic selector %s: %v", s, err)
+	}
+
+	return &sel, nil
+}
+
+func parseIDSelector(s string) (accID, chID uint64, ok bool) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	accID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	chID, err = strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return accID, chID, true
+}
+
+func parseKeyValueSelector(s string) (*CharacteristicSelector, error) {
+	var sel CharacteristicSelector
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid characteristic selector %s, expect key=value pairs", s)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch strings.ToLower(key) {
+		case "accessory", "acc":
+			sel.Accessory = value
+		case "aid":
+			accID, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse accessoryID %s: %v", value, err)
+			}
+			sel.AccessoryID = accID
+		case "service", "svc":
+			sel.Service = value
+		case "characteristic", "char":
+			sel.Characteristic = value
+		default:
+			return nil, fmt.Errorf("invalid characteristic selector %s, unknown key %s", s, key)
+		}
+	}
+
+	if sel.Characteristic == "" {
+		return nil, fmt.Errorf("invalid characteristic selector %s, char is required", s)
+	}
+
+	if err := sel.validate(); err != nil {
+		return nil, fmt.Errorf("invalid characteristic selector %s: %v", s, err)
+	}
+
+	return &sel, nil
+}
+
+func (c *CharacteristicSelector) validate() error {
+	for _, pattern := range []string{c.Accessory, c.Service, c.Characteristic} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("bad pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// ID returns the accessory and characteristic instance ids if the selector identifies
+// a single characteristic by id.
+func (c *CharacteristicSelector) ID() (accID, chID uint64, ok bool) {
+	if c.CharacteristicID == 0 {
+		return 0, 0, false
+	}
+	return c.AccessoryID, c.CharacteristicID, true
+}
+
+// String returns the selector in a form accepted by ParseCharacteristicSelector.
+func (c *CharacteristicSelector) String() string {
+	if accID, chID, ok := c.ID(); ok {
+		return fmt.Sprintf("%d.%d", accID, chID)
+	}
+
+	var pairs []string
+	if c.Accessory != "" {
+		pairs = append(pairs, "accessory="+c.Accessory)
+	}
+	if c.AccessoryID != 0 {
+		pairs = append(pairs, fmt.Sprintf("aid=%d", c.AccessoryID))
+	}
+	if c.Service != "" {
+		pairs = append(pairs, "service="+c.Service)
+	}
+	pairs = append(pairs, "char="+c.Characteristic)
+
+	return strings.Join(pairs, ",")
+}
+
+// Select returns the characteristics of the attribute database matching the selector in
+// the order they appear in the database.
+func (c *CharacteristicSelector) Select(accessories []*RawAccessory) []*SelectedCharacteristic {
+	var selected []*SelectedCharacteristic
+	for _, acc := range accessories {
+		if c.AccessoryID != 0 && acc.ID != c.AccessoryID {
+			continue
+		}
+		if c.Accessory != "" && !matchName(c.Accessory, accessoryName(acc)) {
+			continue
+		}
+		for _, svc := range acc.Services {
+			if c.Service != "" && !matchName(c.Service, service.NameForType(svc.Type), svc.Name()) {
+				continue
+			}
+			for _, ch := range svc.Characteristics {
+				if c.CharacteristicID != 0 && ch.ID != c.CharacteristicID {
+					continue
+				}
+				if c.Characteristic != "" && !matchName(c.Characteristic, characteristic.NameForType(ch.Type), ch.Type) {
+					continue
+				}
+				selected = append(selected, &SelectedCharacteristic{
+					AccessoryID:    acc.ID,
+					Service:        svc,
+					Characteristic: ch,
+				})
+			}
+		}
+	}
+
+	return selected
+}
+
+// SelectCharacteristics returns the characteristics matching any of the selectors without
+// duplicates. An error is returned if a selector does not match any characteristic.
+func SelectCharacteristics(accessories []*RawAccessory, selectors ...*CharacteristicSelector) ([]*SelectedCharacteristic, error) {
+	type key struct{ accID, chID uint64 }
+	seen := make(map[key]bool)
+
+	var selected []*SelectedCharacteristic
+	for _, sel := range selectors {
+		matches := sel.Select(accessories)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no characteristics match %s", sel)
+		}
+		for _, m := range matches {
+			k := key{m.AccessoryID, m.Characteristic.ID}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			selected = append(selected, m)
+		}
+	}
+
+	return selected, nil
+}
+
+func accessoryName(acc *RawAccessory) string {
+	infoSvc := acc.ServiceByType(service.TypeAccessoryInformation)
+	if infoSvc == nil {
+		return ""
+	}
+	return infoSvc.Name()
+}
+
+// matchName returns true if the pattern case insensitively matches any of the names.
+func matchName(pattern string, names ...string) bool {
+	pattern = strings.ToLower(pattern)
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if ok, _ := path.Match(pattern, strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const selectorTestAccessories = `[
+  {
+    "aid": 1,
+    "services": [
+      {"iid": 1, "type": "3E", "characteristics": [
+        {"iid": 2, "type": "23", "format": "string", "value": "Bridge", "perms": ["pr"]}
+      ]}
+    ]
+  },
+  {
+    "aid": 2,
+    "services": [
+      {"iid": 1, "type": "3E", "characteristics": [
+        {"iid": 2, "type": "23", "format": "string", "value": "Roof Window", "perms": ["pr"]}
+      ]},
+      {"iid": 8, "type": "8B", "characteristics": [
+        {"iid": 9, "type": "23", "format": "string", "value": "Roof Window", "perms": ["pr"]},
+        {"iid": 10, "type": "6D", "format": "uint8", "value": 0, "perms": ["pr", "ev"]},
+        {"iid": 11, "type": "7C", "format": "uint8", "value": 0, "perms": ["pr", "pw", "ev"]}
+      ]}
+    ]
+  },
+  {
+    "aid": 3,
+    "services": [
+      {"iid": 1, "type": "3E", "characteristics": [
+        {"iid": 2, "type": "23", "format": "string", "value": "Kitchen Window", "perms": ["pr"]}
+      ]},
+      {"iid": 8, "type": "8B", "characteristics": [
+        {"iid": 9, "type": "23", "format": "string", "value": "Kitchen Window", "perms": ["pr"]},
+        {"iid": 11, "type": "7C", "format": "uint8", "value": 0, "perms": ["pr", "pw", "ev"]}
+      ]}
+    ]
+  }
+]`
+
+func TestCharacteristicSelector(t *testing.T) {
+	var accessories []*RawAccessory
+	require.NoError(t, json.Unmarshal([]byte(selectorTestAccessories), &accessories))
+
+	tests := []struct {
+		selector string
+		expected [][2]uint64
+	}{
+		{"2.10", [][2]uint64{{2, 10}}},
+		{"Roof Window/TargetPosition", [][2]uint64{{2, 11}}},
+		{"roof window/targetposition", [][2]uint64{{2, 11}}},
+		{"window:3/TargetPosition", [][2]uint64{{3, 11}}},
+		{"*/TargetPosition", [][2]uint64{{2, 11}, {3, 11}}},
+		{"Window/7C", [][2]uint64{{2, 11}, {3, 11}}},
+		{"Kitchen*/Window/Target*", [][2]uint64{{3, 11}}},
+		{"service=Window,char=TargetPosition", [][2]uint64{{2, 11}, {3, 11}}},
+		{"aid=2,char=*Position", [][2]uint64{{2, 10}, {2, 11}}},
+		{"accessory=Bridge,char=Name", [][2]uint64{{1, 2}}},
+		{"*/Brightness", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.selector, func(t *testing.T) {
+			sel, err := ParseCharacteristicSelector(test.selector)
+			require.NoError(t, err)
+
+			var ids [][2]uint64
+			for _, match := range sel.Select(accessories) {
+				ids = append(ids, [2]uint64{match.AccessoryID, match.Characteristic.ID})
+			}
+			require.Equal(t, test.expected, ids)
+
+			roundTrip, err := ParseCharacteristicSelector(sel.String())
+			require.NoError(t, err)
+			require.Equal(t, sel, roundTrip)
+		})
+	}
+}
+
+func TestCharacteristicSelectorInvalid(t *testing.T) {
+	for _, selector := range []string{
+		"",
+		"TargetPosition",
+		"a/b/c/d",
+		"window:x/TargetPosition",
+		"service=Window",
+		"room=Kitchen,char=TargetPosition",
+		"[/TargetPosition",
+	} {
+		_, err := ParseCharacteristicSelector(selector)
+		require.Error(t, err, selector)
+	}
+}
+
+func TestSelectCharacteristics(t *testing.T) {
+	var accessories []*RawAccessory
+	require.NoError(t, json.Unmarshal([]byte(selectorTestAccessories), &accessories))
+
+	all, err := ParseCharacteristicSelector("*/TargetPosition")
+	require.NoError(t, err)
+	one, err := ParseCharacteristicSelector("3.11")
+	require.NoError(t, err)
+
+	selected, err := SelectCharacteristics(accessories, all, one)
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+
+	none, err := ParseCharacteristicSelector("*/Brightness")
+	require.NoError(t, err)
+	_, err = SelectCharacteristics(accessories, all, none)
+	require.EqualError(t, err, "no characteristics match service=*,char=Brightness")
+}
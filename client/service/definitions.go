@@ -0,0 +1,28 @@
+package service
+
+import (
+	"io"
+
+	"github.com/mctofu/homekit/client/definitions"
+)
+
+// ReadTypes reads service types defined in the schema of services.yaml. This allows vendor
+// types to be registered with RegisterType without writing code.
+func ReadTypes(r io.Reader) ([]*TypeMetadata, error) {
+	defs, err := definitions.ReadServices(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tms := make([]*TypeMetadata, 0, len(defs))
+	for _, def := range defs {
+		tms = append(tms, &TypeMetadata{
+			Type:                    def.Type,
+			Name:                    def.Name,
+			RequiredCharacteristics: def.RequiredCharacteristics,
+			OptionalCharacteristics: def.OptionalCharacteristics,
+		})
+	}
+
+	return tms, nil
+}
@@ -14,28 +14,56 @@ type AccessoryInfo struct {
 	AccessoryFlags   *characteristic.AccessoryFlags
 }
 
-func ReadAccessoryInfo(chs []*characteristic.RawCharacteristic) *AccessoryInfo {
+func ReadAccessoryInfo(chs []*characteristic.RawCharacteristic) (*AccessoryInfo, error) {
 	var info AccessoryInfo
 	for _, c := range chs {
 		switch c.Type {
 		case characteristic.TypeName:
-			info.Name = *characteristic.ReadName(c)
+			v, err := characteristic.ReadName(c)
+			if err != nil {
+				return nil, err
+			}
+			info.Name = *v
 		case characteristic.TypeFirmwareRevision:
-			info.FirmwareRevision = *characteristic.ReadFirmwareRevision(c)
+			v, err := characteristic.ReadFirmwareRevision(c)
+			if err != nil {
+				return nil, err
+			}
+			info.FirmwareRevision = *v
 		case characteristic.TypeAccessoryFlags:
-			info.AccessoryFlags = characteristic.ReadAccessoryFlags(c)
+			v, err := characteristic.ReadAccessoryFlags(c)
+			if err != nil {
+				return nil, err
+			}
+			info.AccessoryFlags = v
 		case characteristic.TypeManufacturer:
-			info.Manufacturer = *characteristic.ReadManufacturer(c)
+			v, err := characteristic.ReadManufacturer(c)
+			if err != nil {
+				return nil, err
+			}
+			info.Manufacturer = *v
 		case characteristic.TypeModel:
-			info.Model = *characteristic.ReadModel(c)
+			v, err := characteristic.ReadModel(c)
+			if err != nil {
+				return nil, err
+			}
+			info.Model = *v
 		case characteristic.TypeSerialNumber:
-			info.SerialNumber = *characteristic.ReadSerialNumber(c)
+			v, err := characteristic.ReadSerialNumber(c)
+			if err != nil {
+				return nil, err
+			}
+			info.SerialNumber = *v
 		case characteristic.TypeHardwareRevision:
-			info.HardwareRevision = characteristic.ReadHardwareRevision(c)
+			v, err := characteristic.ReadHardwareRevision(c)
+			if err != nil {
+				return nil, err
+			}
+			info.HardwareRevision = v
 		}
 	}
 
-	return &info
+	return &info, nil
 }
 
 type TemperatureSensor struct {
@@ -47,25 +75,49 @@ type TemperatureSensor struct {
 	StatusTampered     *characteristic.StatusTampered
 }
 
-func ReadTemperatureSensor(chs []*characteristic.RawCharacteristic) *TemperatureSensor {
+func ReadTemperatureSensor(chs []*characteristic.RawCharacteristic) (*TemperatureSensor, error) {
 	var ts TemperatureSensor
 	for _, ch := range chs {
 		switch ch.Type {
 		case characteristic.TypeCurrentTemperature:
-			ts.CurrentTemperature = *characteristic.ReadCurrentTemperature(ch)
+			v, err := characteristic.ReadCurrentTemperature(ch)
+			if err != nil {
+				return nil, err
+			}
+			ts.CurrentTemperature = *v
 		case characteristic.TypeName:
-			ts.Name = characteristic.ReadName(ch)
+			v, err := characteristic.ReadName(ch)
+			if err != nil {
+				return nil, err
+			}
+			ts.Name = v
 		case characteristic.TypeStatusActive:
-			ts.StatusActive = characteristic.ReadStatusActive(ch)
+			v, err := characteristic.ReadStatusActive(ch)
+			if err != nil {
+				return nil, err
+			}
+			ts.StatusActive = v
 		case characteristic.TypeStatusTampered:
-			ts.StatusTampered = characteristic.ReadStatusTampered(ch)
+			v, err := characteristic.ReadStatusTampered(ch)
+			if err != nil {
+				return nil, err
+			}
+			ts.StatusTampered = v
 		case characteristic.TypeStatusLowBattery:
-			ts.StatusLowBattery = characteristic.ReadStatusLowBattery(ch)
+			v, err := characteristic.ReadStatusLowBattery(ch)
+			if err != nil {
+				return nil, err
+			}
+			ts.StatusLowBattery = v
 		case characteristic.TypeStatusFault:
-			ts.StatusFault = characteristic.ReadStatusFault(ch)
+			v, err := characteristic.ReadStatusFault(ch)
+			if err != nil {
+				return nil, err
+			}
+			ts.StatusFault = v
 		}
 	}
-	return &ts
+	return &ts, nil
 }
 
 type HumiditySensor struct {
@@ -77,25 +129,49 @@ type HumiditySensor struct {
 	StatusTampered          *characteristic.StatusTampered
 }
 
-func ReadHumiditySensor(chs []*characteristic.RawCharacteristic) *HumiditySensor {
+func ReadHumiditySensor(chs []*characteristic.RawCharacteristic) (*HumiditySensor, error) {
 	var hs HumiditySensor
 	for _, ch := range chs {
 		switch ch.Type {
 		case characteristic.TypeCurrentRelativeHumidity:
-			hs.CurrentRelativeHumidity = *characteristic.ReadCurrentRelativeHumidity(ch)
+			v, err := characteristic.ReadCurrentRelativeHumidity(ch)
+			if err != nil {
+				return nil, err
+			}
+			hs.CurrentRelativeHumidity = *v
 		case characteristic.TypeName:
-			hs.Name = characteristic.ReadName(ch)
+			v, err := characteristic.ReadName(ch)
+			if err != nil {
+				return nil, err
+			}
+			hs.Name = v
 		case characteristic.TypeStatusActive:
-			hs.StatusActive = characteristic.ReadStatusActive(ch)
+			v, err := characteristic.ReadStatusActive(ch)
+			if err != nil {
+				return nil, err
+			}
+			hs.StatusActive = v
 		case characteristic.TypeStatusTampered:
-			hs.StatusTampered = characteristic.ReadStatusTampered(ch)
+			v, err := characteristic.ReadStatusTampered(ch)
+			if err != nil {
+				return nil, err
+			}
+			hs.StatusTampered = v
 		case characteristic.TypeStatusLowBattery:
-			hs.StatusLowBattery = characteristic.ReadStatusLowBattery(ch)
+			v, err := characteristic.ReadStatusLowBattery(ch)
+			if err != nil {
+				return nil, err
+			}
+			hs.StatusLowBattery = v
 		case characteristic.TypeStatusFault:
-			hs.StatusFault = characteristic.ReadStatusFault(ch)
+			v, err := characteristic.ReadStatusFault(ch)
+			if err != nil {
+				return nil, err
+			}
+			hs.StatusFault = v
 		}
 	}
-	return &hs
+	return &hs, nil
 }
 
 type CarbonDioxideSensor struct {
@@ -109,29 +185,61 @@ type CarbonDioxideSensor struct {
 	StatusTampered         *characteristic.StatusTampered
 }
 
-func ReadCarbonDioxideSensor(chs []*characteristic.RawCharacteristic) *CarbonDioxideSensor {
+func ReadCarbonDioxideSensor(chs []*characteristic.RawCharacteristic) (*CarbonDioxideSensor, error) {
 	var cs CarbonDioxideSensor
 	for _, ch := range chs {
 		switch ch.Type {
 		case characteristic.TypeCarbonDioxideDetected:
-			cs.CarbonDioxideDetected = *characteristic.ReadCarbonDioxideDetected(ch)
+			v, err := characteristic.ReadCarbonDioxideDetected(ch)
+			if err != nil {
+				return nil, err
+			}
+			cs.CarbonDioxideDetected = *v
 		case characteristic.TypeCarbonDioxideLevel:
-			cs.CarbonDioxideLevel = characteristic.ReadCarbonDioxideLevel(ch)
+			v, err := characteristic.ReadCarbonDioxideLevel(ch)
+			if err != nil {
+				return nil, err
+			}
+			cs.CarbonDioxideLevel = v
 		case characteristic.TypeCarbonDioxidePeakLevel:
-			cs.CarbonDioxidePeakLevel = characteristic.ReadCarbonDioxidePeakLevel(ch)
+			v, err := characteristic.ReadCarbonDioxidePeakLevel(ch)
+			if err != nil {
+				return nil, err
+			}
+			cs.CarbonDioxidePeakLevel = v
 		case characteristic.TypeName:
-			cs.Name = characteristic.ReadName(ch)
+			v, err := characteristic.ReadName(ch)
+			if err != nil {
+				return nil, err
+			}
+			cs.Name = v
 		case characteristic.TypeStatusActive:
-			cs.StatusActive = characteristic.ReadStatusActive(ch)
+			v, err := characteristic.ReadStatusActive(ch)
+			if err != nil {
+				return nil, err
+			}
+			cs.StatusActive = v
 		case characteristic.TypeStatusTampered:
-			cs.StatusTampered = characteristic.ReadStatusTampered(ch)
+			v, err := characteristic.ReadStatusTampered(ch)
+			if err != nil {
+				return nil, err
+			}
+			cs.StatusTampered = v
 		case characteristic.TypeStatusLowBattery:
-			cs.StatusLowBattery = characteristic.ReadStatusLowBattery(ch)
+			v, err := characteristic.ReadStatusLowBattery(ch)
+			if err != nil {
+				return nil, err
+			}
+			cs.StatusLowBattery = v
 		case characteristic.TypeStatusFault:
-			cs.StatusFault = characteristic.ReadStatusFault(ch)
+			v, err := characteristic.ReadStatusFault(ch)
+			if err != nil {
+				return nil, err
+			}
+			cs.StatusFault = v
 		}
 	}
-	return &cs
+	return &cs, nil
 }
 
 type Window struct {
@@ -142,23 +250,43 @@ type Window struct {
 	ObstructionDetected *characteristic.ObstructionDetected
 }
 
-func ReadWindow(chs []*characteristic.RawCharacteristic) *Window {
+func ReadWindow(chs []*characteristic.RawCharacteristic) (*Window, error) {
 	var w Window
 	for _, ch := range chs {
 		switch ch.Type {
 		case characteristic.TypeCurrentPosition:
-			w.CurrentPosition = *characteristic.ReadCurrentPosition(ch)
+			v, err := characteristic.ReadCurrentPosition(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.CurrentPosition = *v
 		case characteristic.TypeTargetPosition:
-			w.TargetPosition = *characteristic.ReadTargetPosition(ch)
+			v, err := characteristic.ReadTargetPosition(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.TargetPosition = *v
 		case characteristic.TypePositionState:
-			w.PositionState = *characteristic.ReadPositionState(ch)
+			v, err := characteristic.ReadPositionState(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.PositionState = *v
 		case characteristic.TypeName:
-			w.Name = characteristic.ReadName(ch)
+			v, err := characteristic.ReadName(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.Name = v
 		case characteristic.TypeObstructionDetected:
-			w.ObstructionDetected = characteristic.ReadObstructionDetected(ch)
+			v, err := characteristic.ReadObstructionDetected(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.ObstructionDetected = v
 		}
 	}
-	return &w
+	return &w, nil
 }
 
 type WindowCovering struct {
@@ -169,21 +297,41 @@ type WindowCovering struct {
 	ObstructionDetected *characteristic.ObstructionDetected
 }
 
-func ReadWindowCovering(chs []*characteristic.RawCharacteristic) *WindowCovering {
+func ReadWindowCovering(chs []*characteristic.RawCharacteristic) (*WindowCovering, error) {
 	var w WindowCovering
 	for _, ch := range chs {
 		switch ch.Type {
 		case characteristic.TypeCurrentPosition:
-			w.CurrentPosition = *characteristic.ReadCurrentPosition(ch)
+			v, err := characteristic.ReadCurrentPosition(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.CurrentPosition = *v
 		case characteristic.TypeTargetPosition:
-			w.TargetPosition = *characteristic.ReadTargetPosition(ch)
+			v, err := characteristic.ReadTargetPosition(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.TargetPosition = *v
 		case characteristic.TypePositionState:
-			w.PositionState = *characteristic.ReadPositionState(ch)
+			v, err := characteristic.ReadPositionState(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.PositionState = *v
 		case characteristic.TypeName:
-			w.Name = characteristic.ReadName(ch)
+			v, err := characteristic.ReadName(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.Name = v
 		case characteristic.TypeObstructionDetected:
-			w.ObstructionDetected = characteristic.ReadObstructionDetected(ch)
+			v, err := characteristic.ReadObstructionDetected(ch)
+			if err != nil {
+				return nil, err
+			}
+			w.ObstructionDetected = v
 		}
 	}
-	return &w
+	return &w, nil
 }
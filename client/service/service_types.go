@@ -55,53 +55,292 @@ const (
 )
 
 var typeMetadatas = []*TypeMetadata{
-	{TypeAccessoryInformation, "AccessoryInformation"},
-	{TypeAirPurifier, "AirPurifier"},
-	{TypeAirQualitySensor, "AirQualitySensor"},
-	{TypeAudioStreamManagement, "AudioStreamManagement"},
-	{TypeBatteryService, "BatteryService"},
-	{TypeCameraRecordingManagement, "CameraRecordingManagement"},
-	{TypeCameraRTPStreamManagement, "CameraRTPStreamManagement"},
-	{TypeCarbonDioxideSensor, "CarbonDioxideSensor"},
-	{TypeCarbonMonoxideSensor, "CarbonMonoxideSensor"},
-	{TypeContactSensor, "ContactSensor"},
-	{TypeDataStreamTransportManagement, "DataStreamTransportManagement"},
-	{TypeDoor, "Door"},
-	{TypeDoorbell, "Doorbell"},
-	{TypeFan, "Fan"},
-	{TypeFanv2, "Fanv2"},
-	{TypeFaucet, "Faucet"},
-	{TypeFilterMaintenance, "FilterMaintenance"},
-	{TypeGarageDoorOpener, "GarageDoorOpener"},
-	{TypeHAPProtocolInformation, "HAPProtocolInformation"},
-	{TypeHeaterCooler, "HeaterCooler"},
-	{TypeHumidifierDehumidifier, "HumidifierDehumidifier"},
-	{TypeHumiditySensor, "HumiditySensor"},
-	{TypeInputSource, "InputSource"},
-	{TypeIrrigationSystem, "IrrigationSystem"},
-	{TypeLeakSensor, "LeakSensor"},
-	{TypeLightbulb, "Lightbulb"},
-	{TypeLightSensor, "LightSensor"},
-	{TypeLockManagement, "LockManagement"},
-	{TypeLockMechanism, "LockMechanism"},
-	{TypeMicrophone, "Microphone"},
-	{TypeMotionSensor, "MotionSensor"},
-	{TypeOccupancySensor, "OccupancySensor"},
-	{TypeOutlet, "Outlet"},
-	{TypeSecuritySystem, "SecuritySystem"},
-	{TypeServiceLabel, "ServiceLabel"},
-	{TypeSiri, "Siri"},
-	{TypeSlat, "Slat"},
-	{TypeSmokeSensor, "SmokeSensor"},
-	{TypeSpeaker, "Speaker"},
-	{TypeStatelessProgrammableSwitch, "StatelessProgrammableSwitch"},
-	{TypeSwitch, "Switch"},
-	{TypeTargetControl, "TargetControl"},
-	{TypeTargetControlManagement, "TargetControlManagement"},
-	{TypeTelevision, "Television"},
-	{TypeTemperatureSensor, "TemperatureSensor"},
-	{TypeThermostat, "Thermostat"},
-	{TypeValve, "Valve"},
-	{TypeWindow, "Window"},
-	{TypeWindowCovering, "WindowCovering"},
+	{
+		Type:                    TypeAccessoryInformation,
+		Name:                    "AccessoryInformation",
+		RequiredCharacteristics: []string{"14", "20", "21", "23", "30", "52"},
+		OptionalCharacteristics: []string{"53", "A6"},
+	},
+	{
+		Type:                    TypeAirPurifier,
+		Name:                    "AirPurifier",
+		RequiredCharacteristics: []string{"B0", "A9", "A8"},
+		OptionalCharacteristics: []string{"A7", "23", "B6", "29"},
+	},
+	{
+		Type:                    TypeAirQualitySensor,
+		Name:                    "AirQualitySensor",
+		RequiredCharacteristics: []string{"95"},
+		OptionalCharacteristics: []string{"75", "77", "7A", "79", "23", "C3", "C4", "C5", "C6", "C7", "C8", "90", "93"},
+	},
+	{
+		Type:                    TypeAudioStreamManagement,
+		Name:                    "AudioStreamManagement",
+		RequiredCharacteristics: []string{"115", "128"},
+	},
+	{
+		Type:                    TypeBatteryService,
+		Name:                    "BatteryService",
+		RequiredCharacteristics: []string{"68", "8F", "79"},
+		OptionalCharacteristics: []string{"23"},
+	},
+	{
+		Type:                    TypeCameraRecordingManagement,
+		Name:                    "CameraRecordingManagement",
+		RequiredCharacteristics: []string{"205", "206", "207", "209"},
+	},
+	{
+		Type:                    TypeCameraRTPStreamManagement,
+		Name:                    "CameraRTPStreamManagement",
+		RequiredCharacteristics: []string{"114", "115", "116", "117", "120", "118"},
+		OptionalCharacteristics: []string{"23"},
+	},
+	{
+		Type:                    TypeCarbonDioxideSensor,
+		Name:                    "CarbonDioxideSensor",
+		RequiredCharacteristics: []string{"92"},
+		OptionalCharacteristics: []string{"75", "77", "79", "7A", "93", "94", "23"},
+	},
+	{
+		Type:                    TypeCarbonMonoxideSensor,
+		Name:                    "CarbonMonoxideSensor",
+		RequiredCharacteristics: []string{"69"},
+		OptionalCharacteristics: []string{"75", "77", "79", "7A", "90", "91", "23"},
+	},
+	{
+		Type:                    TypeContactSensor,
+		Name:                    "ContactSensor",
+		RequiredCharacteristics: []string{"6A"},
+		OptionalCharacteristics: []string{"75", "77", "7A", "79", "23"},
+	},
+	{
+		Type:                    TypeDataStreamTransportManagement,
+		Name:                    "DataStreamTransportManagement",
+		RequiredCharacteristics: []string{"131", "130", "37"},
+	},
+	{
+		Type:                    TypeDoor,
+		Name:                    "Door",
+		RequiredCharacteristics: []string{"6D", "72", "7C"},
+		OptionalCharacteristics: []string{"6F", "24", "23"},
+	},
+	{
+		Type:                    TypeDoorbell,
+		Name:                    "Doorbell",
+		RequiredCharacteristics: []string{"73"},
+		OptionalCharacteristics: []string{"8", "119", "23"},
+	},
+	{
+		Type:                    TypeFan,
+		Name:                    "Fan",
+		RequiredCharacteristics: []string{"25"},
+		OptionalCharacteristics: []string{"28", "29", "23"},
+	},
+	{
+		Type:                    TypeFanv2,
+		Name:                    "Fanv2",
+		RequiredCharacteristics: []string{"B0"},
+		OptionalCharacteristics: []string{"AF", "BF", "A7", "23", "28", "29", "B6"},
+	},
+	{
+		Type:                    TypeFaucet,
+		Name:                    "Faucet",
+		RequiredCharacteristics: []string{"B0"},
+		OptionalCharacteristics: []string{"23", "77"},
+	},
+	{
+		Type:                    TypeFilterMaintenance,
+		Name:                    "FilterMaintenance",
+		RequiredCharacteristics: []string{"AC"},
+		OptionalCharacteristics: []string{"AB", "AD", "23"},
+	},
+	{
+		Type:                    TypeGarageDoorOpener,
+		Name:                    "GarageDoorOpener",
+		RequiredCharacteristics: []string{"E", "32", "24"},
+		OptionalCharacteristics: []string{"1D", "1E", "23"},
+	},
+	{
+		Type:                    TypeHAPProtocolInformation,
+		Name:                    "HAPProtocolInformation",
+		RequiredCharacteristics: []string{"37"},
+	},
+	{
+		Type:                    TypeHeaterCooler,
+		Name:                    "HeaterCooler",
+		RequiredCharacteristics: []string{"B0", "B1", "B2", "11"},
+		OptionalCharacteristics: []string{"A7", "23", "B6", "D", "12", "36", "29"},
+	},
+	{
+		Type:                    TypeHumidifierDehumidifier,
+		Name:                    "HumidifierDehumidifier",
+		RequiredCharacteristics: []string{"10", "B3", "B4", "B0"},
+		OptionalCharacteristics: []string{"A7", "23", "B6", "B5", "C9", "CA", "29"},
+	},
+	{
+		Type:                    TypeHumiditySensor,
+		Name:                    "HumiditySensor",
+		RequiredCharacteristics: []string{"10"},
+		OptionalCharacteristics: []string{"75", "77", "7A", "79", "23"},
+	},
+	{
+		Type:                    TypeInputSource,
+		Name:                    "InputSource",
+		RequiredCharacteristics: []string{"E3", "DB", "D6", "135"},
+		OptionalCharacteristics: []string{"E6", "DC", "134", "23"},
+	},
+	{
+		Type:                    TypeIrrigationSystem,
+		Name:                    "IrrigationSystem",
+		RequiredCharacteristics: []string{"B0", "D1", "D2"},
+		OptionalCharacteristics: []string{"23", "D4", "77"},
+	},
+	{
+		Type:                    TypeLeakSensor,
+		Name:                    "LeakSensor",
+		RequiredCharacteristics: []string{"70"},
+		OptionalCharacteristics: []string{"75", "77", "7A", "79", "23"},
+	},
+	{
+		Type:                    TypeLightbulb,
+		Name:                    "Lightbulb",
+		RequiredCharacteristics: []string{"25"},
+		OptionalCharacteristics: []string{"8", "13", "2F", "23"},
+	},
+	{
+		Type:                    TypeLightSensor,
+		Name:                    "LightSensor",
+		RequiredCharacteristics: []string{"6B"},
+		OptionalCharacteristics: []string{"23", "75", "77", "7A", "79"},
+	},
+	{
+		Type:                    TypeLockManagement,
+		Name:                    "LockManagement",
+		RequiredCharacteristics: []string{"19", "37"},
+		OptionalCharacteristics: []string{"1F", "5", "1A", "1", "1C", "E", "22", "23"},
+	},
+	{
+		Type:                    TypeLockMechanism,
+		Name:                    "LockMechanism",
+		RequiredCharacteristics: []string{"1D", "1E"},
+		OptionalCharacteristics: []string{"23"},
+	},
+	{
+		Type:                    TypeMicrophone,
+		Name:                    "Microphone",
+		RequiredCharacteristics: []string{"119", "11A"},
+		OptionalCharacteristics: []string{"23"},
+	},
+	{
+		Type:                    TypeMotionSensor,
+		Name:                    "MotionSensor",
+		RequiredCharacteristics: []string{"22"},
+		OptionalCharacteristics: []string{"75", "77", "7A", "79", "23"},
+	},
+	{
+		Type:                    TypeOccupancySensor,
+		Name:                    "OccupancySensor",
+		RequiredCharacteristics: []string{"71"},
+		OptionalCharacteristics: []string{"23", "75", "77", "7A", "79"},
+	},
+	{
+		Type:                    TypeOutlet,
+		Name:                    "Outlet",
+		RequiredCharacteristics: []string{"25", "26"},
+		OptionalCharacteristics: []string{"23"},
+	},
+	{
+		Type:                    TypeSecuritySystem,
+		Name:                    "SecuritySystem",
+		RequiredCharacteristics: []string{"66", "67"},
+		OptionalCharacteristics: []string{"77", "7A", "8E", "23"},
+	},
+	{
+		Type:                    TypeServiceLabel,
+		Name:                    "ServiceLabel",
+		RequiredCharacteristics: []string{"CD"},
+		OptionalCharacteristics: []string{"23"},
+	},
+	{
+		Type:                    TypeSiri,
+		Name:                    "Siri",
+		RequiredCharacteristics: []string{"132"},
+	},
+	{
+		Type:                    TypeSlat,
+		Name:                    "Slat",
+		RequiredCharacteristics: []string{"C0", "AA"},
+		OptionalCharacteristics: []string{"23", "C1", "C2", "B6"},
+	},
+	{
+		Type:                    TypeSmokeSensor,
+		Name:                    "SmokeSensor",
+		RequiredCharacteristics: []string{"76"},
+		OptionalCharacteristics: []string{"75", "77", "7A", "79", "23"},
+	},
+	{
+		Type:                    TypeSpeaker,
+		Name:                    "Speaker",
+		RequiredCharacteristics: []string{"11A"},
+		OptionalCharacteristics: []string{"23", "119"},
+	},
+	{
+		Type:                    TypeStatelessProgrammableSwitch,
+		Name:                    "StatelessProgrammableSwitch",
+		RequiredCharacteristics: []string{"73"},
+		OptionalCharacteristics: []string{"23", "CB"},
+	},
+	{
+		Type:                    TypeSwitch,
+		Name:                    "Switch",
+		RequiredCharacteristics: []string{"25"},
+		OptionalCharacteristics: []string{"23"},
+	},
+	{
+		Type:                    TypeTargetControl,
+		Name:                    "TargetControl",
+		RequiredCharacteristics: []string{"E7", "B0", "126"},
+		OptionalCharacteristics: []string{"23"},
+	},
+	{
+		Type:                    TypeTargetControlManagement,
+		Name:                    "TargetControlManagement",
+		RequiredCharacteristics: []string{"123", "124"},
+	},
+	{
+		Type:                    TypeTelevision,
+		Name:                    "Television",
+		RequiredCharacteristics: []string{"B0", "E7", "E3", "E8"},
+		OptionalCharacteristics: []string{"8", "DD", "136", "E0", "137", "E2", "DF", "E1"},
+	},
+	{
+		Type:                    TypeTemperatureSensor,
+		Name:                    "TemperatureSensor",
+		RequiredCharacteristics: []string{"11"},
+		OptionalCharacteristics: []string{"75", "77", "79", "7A", "23"},
+	},
+	{
+		Type:                    TypeThermostat,
+		Name:                    "Thermostat",
+		RequiredCharacteristics: []string{"F", "33", "11", "35", "36"},
+		OptionalCharacteristics: []string{"10", "34", "D", "12", "23"},
+	},
+	{
+		Type:                    TypeValve,
+		Name:                    "Valve",
+		RequiredCharacteristics: []string{"B0", "D2", "D5"},
+		OptionalCharacteristics: []string{"D3", "D4", "D6", "CB", "77", "23"},
+	},
+	{
+		Type:                    TypeWindow,
+		Name:                    "Window",
+		RequiredCharacteristics: []string{"6D", "7C", "72"},
+		OptionalCharacteristics: []string{"6F", "24", "23"},
+	},
+	{
+		Type:                    TypeWindowCovering,
+		Name:                    "WindowCovering",
+		RequiredCharacteristics: []string{"6D", "7C", "72"},
+		OptionalCharacteristics: []string{"6F", "7B", "7D", "6C", "6E", "24", "23"},
+	},
 }
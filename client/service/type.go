@@ -26,11 +26,32 @@ func (s *RawService) CharacteristicByType(t string) *characteristic.RawCharacter
 	return nil
 }
 
+// Name returns the value of the service's Name characteristic or an empty string
+// if the service is not named.
+func (s *RawService) Name() string {
+	c := s.CharacteristicByType(characteristic.TypeName)
+	if c == nil {
+		return ""
+	}
+
+	name, err := c.Value.String()
+	if err != nil {
+		return ""
+	}
+
+	return name
+}
+
 // TypeMetadata captures standard known metadata that applies to all
 // services with the given type.
 type TypeMetadata struct {
 	Type string
 	Name string
+
+	// RequiredCharacteristics and OptionalCharacteristics are the types of the
+	// characteristics the service is defined with.
+	RequiredCharacteristics []string
+	OptionalCharacteristics []string
 }
 
 var typeMetadataByType map[string]*TypeMetadata
@@ -47,6 +68,12 @@ func RegisterType(tm TypeMetadata) {
 	typeMetadataByType[tm.Type] = &tm
 }
 
+// MetadataForType returns the metadata registered for the type or nil if the type is
+// not registered. The metadata is shared and must not be modified.
+func MetadataForType(t string) *TypeMetadata {
+	return typeMetadataByType[t]
+}
+
 // NameForType returns a human friendly name for the type or "<Unknown>"
 // if the type is not registered.
 func NameForType(t string) string {
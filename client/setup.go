@@ -3,6 +3,8 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,6 +13,7 @@ import (
 	"github.com/brutella/hc/hap"
 	"github.com/brutella/hc/hap/pair"
 	"github.com/brutella/hc/util"
+	"github.com/mctofu/homekit/client/pairing"
 )
 
 // AccessoryPairingConfig contains accessory details needed to perform a pairing.
@@ -29,9 +32,14 @@ const (
 	PairingMethodPairSetupWithAuth
 )
 
+// ErrAccessoryPaired is returned by SetupClient.Identify when the accessory is already paired.
+// Paired accessories are identified with AccessoryClient.Identify instead.
+var ErrAccessoryPaired = errors.New("accessory is already paired")
+
 // SetupClient negotiates an initial pairing between a controller and an accessory.
 type SetupClient struct {
 	ipTransport IPTransport
+	stepHook    pairing.SetupStepHook
 }
 
 // NewSetupClient returns a new SetupClient for ip accessible accessories.
@@ -41,6 +49,12 @@ func NewSetupClient(t IPTransport) *SetupClient {
 	}
 }
 
+// SetStepHook registers a hook that is called with every pair-setup message sent to or
+// received from the accessory. This can be used to log the exchange or to inject failures.
+func (s *SetupClient) SetStepHook(hook pairing.SetupStepHook) {
+	s.stepHook = hook
+}
+
 // Pair will pair the controller c with the accessory a.
 func (s *SetupClient) Pair(ctx context.Context, a *AccessoryPairingConfig, c *ControllerIdentity) (*AccessoryConnectionConfig, error) {
 	deviceDB := &memoryDB{}
@@ -53,52 +67,35 @@ func (s *SetupClient) Pair(ctx context.Context, a *AccessoryPairingConfig, c *Co
 		return nil, err
 	}
 
-	controller := pair.NewSetupClientController(a.PIN, clientDevice, deviceDB)
+	controller := pairing.NewSetupClientController(a.PIN, clientDevice, deviceDB)
 	endpoint := fmt.Sprintf("http://%s:%d/pair-setup", a.IPConnectionInfo.IPAddress, a.IPConnectionInfo.Port)
 
-	out := util.NewTLV8Container()
-	out.SetByte(pair.TagPairingMethod, pairingMethodTLVValue(a.PairingMethod))
-	out.SetByte(pair.TagSequence, pair.PairStepStartRequest.Byte())
-	pairStartReq, err := ioutil.ReadAll(out.BytesBuffer())
-	if err != nil {
-		return nil, err
-	}
-
-	pairStartResp, err := s.sendTLV8(ctx, endpoint, pairStartReq)
-	if err != nil {
-		return nil, fmt.Errorf("pairStartRequest: %v", err)
-	}
-
-	pairVerifyReqReader, err := pair.HandleReaderForHandler(bytes.NewReader(pairStartResp), controller)
-	if err != nil {
-		return nil, fmt.Errorf("handle pairStartResponse: %v", err)
-	}
-	pairVerifyReq, err := ioutil.ReadAll(pairVerifyReqReader)
-	if err != nil {
-		return nil, err
-	}
+	out := controller.InitialPairingRequest(pairingMethodTLVValue(a.PairingMethod))
+	for out != nil {
+		reqStep := pairing.SetupStep(out.GetByte(pair.TagSequence))
+		if err := s.callStepHook(reqStep, out); err != nil {
+			return nil, fmt.Errorf("%v hook: %v", reqStep, err)
+		}
 
-	pairVerifyResp, err := s.sendTLV8(ctx, endpoint, pairVerifyReq)
-	if err != nil {
-		return nil, fmt.Errorf("pairVerifyRequest: %v", err)
-	}
+		respBody, err := s.sendTLV8(ctx, endpoint, out.BytesBuffer().Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", reqStep, err)
+		}
 
-	pairKeyReqReader, err := pair.HandleReaderForHandler(bytes.NewReader(pairVerifyResp), controller)
-	if err != nil {
-		return nil, fmt.Errorf("handle pairVerifyResponse: %v", err)
-	}
-	pairKeyReq, err := ioutil.ReadAll(pairKeyReqReader)
-	if err != nil {
-		return nil, err
-	}
+		in, err := util.NewTLV8ContainerFromReader(bytes.NewReader(respBody))
+		if err != nil {
+			return nil, fmt.Errorf("parse %v response: %v", reqStep, err)
+		}
 
-	pairKeyResp, err := s.sendTLV8(ctx, endpoint, pairKeyReq)
-	if err != nil {
-		return nil, fmt.Errorf("pairKeyRequest: %v", err)
-	}
+		respStep := pairing.SetupStep(in.GetByte(pair.TagSequence))
+		if err := s.callStepHook(respStep, in); err != nil {
+			return nil, fmt.Errorf("%v hook: %v", respStep, err)
+		}
 
-	if _, err := pair.HandleReaderForHandler(bytes.NewReader(pairKeyResp), controller); err != nil {
-		return nil, fmt.Errorf("handle pairKeyResponse: %v", err)
+		out, err = controller.Handle(in)
+		if err != nil {
+			return nil, fmt.Errorf("handle %v: %w", respStep, err)
+		}
 	}
 
 	// accessory's public key should now be in the db
@@ -114,6 +111,48 @@ func (s *SetupClient) Pair(ctx context.Context, a *AccessoryPairingConfig, c *Co
 	}, nil
 }
 
+// Identify asks an unpaired accessory to identify itself, typically by blinking a light or
+// moving briefly.
+func (s *SetupClient) Identify(ctx context.Context, conn IPConnectionInfo) error {
+	endpoint := fmt.Sprintf("http://%s:%d/identify", conn.IPAddress, conn.Port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.ipTransport.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		status := struct {
+			Status int `json:"status"`
+		}{}
+		body, err := ioutil.ReadAll(resp.Body)
+		if err == nil && json.Unmarshal(body, &status) == nil && status.Status == hapStatusInsufficientPrivileges {
+			return ErrAccessoryPaired
+		}
+		return fmt.Errorf("invalid status: %v", resp.Status)
+	default:
+		return fmt.Errorf("invalid status: %v", resp.Status)
+	}
+}
+
+// hapStatusInsufficientPrivileges is the status returned when identifying a paired accessory.
+const hapStatusInsufficientPrivileges = -70401
+
+func (s *SetupClient) callStepHook(step pairing.SetupStep, msg util.Container) error {
+	if s.stepHook == nil {
+		return nil
+	}
+	return s.stepHook(step, msg)
+}
+
 func (s *SetupClient) sendTLV8(ctx context.Context, endpoint string, body []byte) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
 	if err != nil {
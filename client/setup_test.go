@@ -2,20 +2,13 @@ package client
 
 import (
 	"context"
-	"fmt"
-	"net"
-	"net/http/httptest"
-	"net/url"
-	"strconv"
-	"sync"
+	"errors"
+	"net/http"
 	"testing"
 
-	"github.com/brutella/hc/accessory"
-	"github.com/brutella/hc/characteristic"
-	"github.com/brutella/hc/crypto"
-	"github.com/brutella/hc/event"
-	"github.com/brutella/hc/hap"
-	hchttp "github.com/brutella/hc/hap/http"
+	"github.com/brutella/hc/util"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/client/pairing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -35,140 +28,103 @@ func TestSetupClient(t *testing.T) {
 	assert.Equal(t, "5F-7A-CA-6A-83-92", connectionConfig.DeviceID)
 }
 
-func deviceServer() (*httptest.Server, error) {
-	switchAcc := accessory.NewSwitch(
-		accessory.Info{
-			Name: "Test",
-		},
-	)
-
-	container := accessory.NewContainer()
-	if err := container.AddAccessory(switchAcc.Accessory); err != nil {
-		return nil, err
-	}
-
-	serverDB := &memoryDB{}
-
-	switchDev, err := hap.NewSecuredDevice("5F-7A-CA-6A-83-92", "12344321", serverDB)
-	if err != nil {
-		return nil, fmt.Errorf("NewSecuredDevice: %v", err)
-	}
+func TestSetupClientStepHook(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
 
-	switchCtx := &wrapperContext{hap.NewContextForSecuredDevice(switchDev)}
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
 
-	hcServer := hchttp.NewServer(hchttp.Config{
-		Context:   switchCtx,
-		Container: container,
-		Device:    switchDev,
-		Database:  serverDB,
-		Mutex:     &sync.Mutex{},
-		Emitter:   event.NewEmitter(),
+	var steps []pairing.SetupStep
+	setupClient := NewSetupClient(&http.Client{})
+	setupClient.SetStepHook(func(step pairing.SetupStep, msg util.Container) error {
+		steps = append(steps, step)
+		return nil
 	})
 
-	testServer := httptest.NewUnstartedServer(hcServer.Mux)
-	testServer.Listener = hcServer
-	testServer.Start()
-
-	return testServer, nil
-}
-
-func setupDeviceServer(ctx context.Context, testServer *httptest.Server, controller *ControllerIdentity) (*AccessoryConnectionConfig, error) {
-	setupClient := NewSetupClient(testServer.Client())
-	baseURL, err := url.Parse(testServer.URL)
-	if err != nil {
-		return nil, fmt.Errorf("parseURL: %v", err)
-	}
-	port, err := strconv.Atoi(baseURL.Port())
-	if err != nil {
-		return nil, fmt.Errorf("parse URL port: %v", err)
-	}
+	ctx := context.Background()
+	_, err = pairDeviceServer(ctx, setupClient, testServer, controller, "12344321")
+	require.NoError(t, err, "pair")
 
-	return setupClient.Pair(ctx,
-		&AccessoryPairingConfig{
-			IPConnectionInfo: IPConnectionInfo{
-				IPAddress: baseURL.Hostname(),
-				Port:      port,
-			},
-			PIN:      "12344321",
-			DeviceID: "5F-7A-CA-6A-83-92",
+	assert.Equal(t,
+		[]pairing.SetupStep{
+			pairing.SetupStepM1, pairing.SetupStepM2,
+			pairing.SetupStepM3, pairing.SetupStepM4,
+			pairing.SetupStepM5, pairing.SetupStepM6,
 		},
-		controller,
+		steps,
 	)
 }
 
-// wrapperContext is a temporary workaround for a race reported during test
-// due to session cryptographer access.
-type wrapperContext struct {
-	hap.Context
-}
-
-func (w *wrapperContext) SetSessionForConnection(s hap.Session, c net.Conn) {
-	w.Context.SetSessionForConnection(&syncSession{session: s}, c)
-}
-
-// syncSession works around a race condition accessing crypto in tests
-type syncSession struct {
-	session hap.Session
-	mutex   sync.Mutex
-}
-
-// Decrypter returns decrypter for incoming data, may be nil
-func (s *syncSession) Decrypter() crypto.Decrypter {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	return s.session.Decrypter()
-}
-
-// Encrypter returns encrypter for outgoing data, may be nil
-func (s *syncSession) Encrypter() crypto.Encrypter {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+func TestSetupClientStepHookFailure(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
 
-	return s.session.Encrypter()
-}
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
 
-// SetCryptographer sets the new cryptographer used for en-/decryption
-func (s *syncSession) SetCryptographer(c crypto.Cryptographer) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	hookErr := errors.New("injected failure")
+	setupClient := NewSetupClient(&http.Client{})
+	setupClient.SetStepHook(func(step pairing.SetupStep, msg util.Container) error {
+		if step == pairing.SetupStepM4 {
+			return hookErr
+		}
+		return nil
+	})
 
-	s.session.SetCryptographer(c)
+	ctx := context.Background()
+	_, err = pairDeviceServer(ctx, setupClient, testServer, controller, "12344321")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), hookErr.Error())
 }
 
-// PairSetupHandler returns the pairing setup handler
-func (s *syncSession) PairSetupHandler() hap.ContainerHandler {
-	return s.session.PairSetupHandler()
-}
+func TestSetupClientInvalidPIN(t *testing.T) {
+	testServer, err := deviceServer()
+	require.NoError(t, err, "deviceServer")
+	defer testServer.Close()
 
-// SetPairSetupHandler sets the handler for pairing setup
-func (s *syncSession) SetPairSetupHandler(c hap.ContainerHandler) {
-	s.session.SetPairSetupHandler(c)
-}
+	controller, err := NewRandomControllerConfig()
+	require.NoError(t, err, "controller setup")
 
-// PairVerifyHandler returns the pairing verify handler
-func (s *syncSession) PairVerifyHandler() hap.PairVerifyHandler {
-	return s.session.PairVerifyHandler()
-}
+	ctx := context.Background()
+	_, err = pairDeviceServer(ctx, NewSetupClient(&http.Client{}), testServer, controller, "11122333")
+	require.Error(t, err)
 
-// SetPairVerifyHandler sets the handler for pairing verify
-func (s *syncSession) SetPairVerifyHandler(c hap.PairVerifyHandler) {
-	s.session.SetPairVerifyHandler(c)
+	var tlvErr *pairing.TLVError
+	require.True(t, errors.As(err, &tlvErr), "expected TLVError: %v", err)
+	assert.Equal(t, byte(pairing.TLVErrorAuthentication), tlvErr.Code)
 }
 
-// Connection returns the associated connection
-func (s *syncSession) Connection() net.Conn {
-	return s.session.Connection()
+func deviceServer() (*hapfake.Server, error) {
+	return hapfake.NewServer(hapfake.Config{PIN: "12344321"})
 }
 
-func (s *syncSession) IsSubscribedTo(ch *characteristic.Characteristic) bool {
-	return s.session.IsSubscribedTo(ch)
+func setupDeviceServer(ctx context.Context, testServer *hapfake.Server, controller *ControllerIdentity) (*AccessoryConnectionConfig, error) {
+	return pairDeviceServer(ctx, NewSetupClient(&http.Client{}), testServer, controller, testServer.PIN())
 }
 
-func (s *syncSession) Subscribe(ch *characteristic.Characteristic) {
-	s.session.Subscribe(ch)
+func pairDeviceServer(
+	ctx context.Context,
+	setupClient *SetupClient,
+	testServer *hapfake.Server,
+	controller *ControllerIdentity,
+	pin string,
+) (*AccessoryConnectionConfig, error) {
+	return setupClient.Pair(ctx,
+		&AccessoryPairingConfig{
+			IPConnectionInfo: fakeConnectionInfo(testServer),
+			PIN:              pin,
+			DeviceID:         testServer.DeviceID(),
+		},
+		controller,
+	)
 }
 
-func (s *syncSession) Unsubscribe(ch *characteristic.Characteristic) {
-	s.session.Unsubscribe(ch)
+func fakeConnectionInfo(testServer *hapfake.Server) IPConnectionInfo {
+	return IPConnectionInfo{
+		IPAddress: testServer.Host(),
+		Port:      testServer.Port(),
+	}
 }
@@ -0,0 +1,345 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/brutella/hc/hap"
+	"github.com/brutella/hc/hap/pair"
+)
+
+// TraceDirection is the direction of a traced message.
+type TraceDirection int
+
+// Directions of traced messages.
+const (
+	// TraceSent is a message sent to the accessory.
+	TraceSent TraceDirection = iota
+	// TraceReceived is a message received from the accessory.
+	TraceReceived
+)
+
+func (d TraceDirection) String() string {
+	if d == TraceSent {
+		return ">"
+	}
+	return "<"
+}
+
+// TraceMessage is a decrypted HTTP request, response or EVENT message exchanged with an
+// accessory.
+type TraceMessage struct {
+	Time      time.Time
+	Addr      string
+	Direction TraceDirection
+	// StartLine is the request, status or event line of the message. It's empty if the data
+	// couldn't be parsed as a message and Body holds the raw data. Unparsed data from
+	// pair-verify connections is zeroed.
+	StartLine string
+	Header    textproto.MIMEHeader
+	// Body is the message body. Values of TLV8 items holding key material are zeroed.
+	Body []byte
+}
+
+// TraceHandler is called with each message exchanged with an accessory when tracing is
+// enabled. It may be called concurrently for messages sent and received.
+type TraceHandler func(msg *TraceMessage)
+
+// redactedTags are TLV8 tags holding key material.
+var redactedTags = map[byte]bool{
+	pair.TagSalt:          true,
+	pair.TagPublicKey:     true,
+	pair.TagProof:         true,
+	pair.TagEncryptedData: true,
+	pair.TagSignature:     true,
+}
+
+var tlv8TagNames = map[byte]string{
+	pair.TagPairingMethod:  "Method",
+	pair.TagUsername:       "Identifier",
+	pair.TagSalt:           "Salt",
+	pair.TagPublicKey:      "PublicKey",
+	pair.TagProof:          "Proof",
+	pair.TagEncryptedData:  "EncryptedData",
+	pair.TagSequence:       "State",
+	pair.TagErrCode:        "Error",
+	pair.TagMFiCertificate: "Certificate",
+	pair.TagSignature:      "Signature",
+	pair.TagPermission:     "Permissions",
+	tagSeparator:           "Separator",
+}
+
+// NewTraceWriter returns a TraceHandler that writes each message to w with a timestamp. TLV8
+// bodies are written by tag name.
+func NewTraceWriter(w io.Writer) TraceHandler {
+	var mu sync.Mutex
+	return func(msg *TraceMessage) {
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "%s %s %s", msg.Time.Format("2006-01-02T15:04:05.000Z07:00"), msg.Addr, msg.Direction)
+		if msg.StartLine != "" {
+			fmt.Fprintf(&buf, " %s\n", msg.StartLine)
+		} else {
+			buf.WriteString(" (unparsed)\n")
+		}
+
+		keys := make([]string, 0, len(msg.Header))
+		for k := range msg.Header {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&buf, "  %s: %s\n", k, strings.Join(msg.Header[k], ", "))
+		}
+
+		writeTraceBody(&buf, msg)
+
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+func writeTraceBody(buf *bytes.Buffer, msg *TraceMessage) {
+	if len(msg.Body) == 0 {
+		return
+	}
+
+	if msg.Header.Get("Content-Type") == hap.HTTPContentTypePairingTLV8 {
+		if items, err := parseTLV8(msg.Body); err == nil {
+			for _, item := range items {
+				fmt.Fprintf(buf, "  %s\n", formatTLV8Item(item))
+			}
+			return
+		}
+	}
+
+	if utf8.Valid(msg.Body) {
+		for _, line := range strings.Split(strings.TrimRight(string(msg.Body), "\r\n"), "\n") {
+			fmt.Fprintf(buf, "  %s\n", line)
+		}
+		return
+	}
+
+	fmt.Fprintf(buf, "  %s\n", hex.EncodeToString(msg.Body))
+}
+
+type tlv8Item struct {
+	tag   byte
+	value []byte
+}
+
+// parseTLV8 returns the items of a TLV8 body. Fragments of values longer than 255 bytes are
+// joined.
+func parseTLV8(b []byte) ([]tlv8Item, error) {
+	var items []tlv8Item
+	fragment := false
+	for len(b) > 0 {
+		if len(b) < 2 || len(b) < 2+int(b[1]) {
+			return nil, errors.New("truncated item")
+		}
+		tag, value := b[0], b[2:2+int(b[1])]
+		if fragment && items[len(items)-1].tag == tag {
+			items[len(items)-1].value = append(items[len(items)-1].value, value...)
+		} else {
+			items = append(items, tlv8Item{tag: tag, value: append([]byte(nil), value...)})
+		}
+		fragment = len(value) == 255
+		b = b[2+len(value):]
+	}
+	return items, nil
+}
+
+func formatTLV8Item(item tlv8Item) string {
+	name, ok := tlv8TagNames[item.tag]
+	if !ok {
+		name = fmt.Sprintf("0x%02X", item.tag)
+	}
+
+	switch {
+	case item.tag == tagSeparator:
+		return name
+	case redactedTags[item.tag]:
+		return fmt.Sprintf("%s: <redacted %d bytes>", name, len(item.value))
+	case item.tag == pair.TagUsername:
+		return fmt.Sprintf("%s: %s", name, item.value)
+	case len(item.value) == 1:
+		return fmt.Sprintf("%s: %d", name, item.value[0])
+	default:
+		return fmt.Sprintf("%s: %s", name, hex.EncodeToString(item.value))
+	}
+}
+
+// redactTLV8 zeroes the values of items holding key material in place. The value of a
+// truncated last item is zeroed up to the end of b.
+func redactTLV8(b []byte) {
+	for len(b) >= 2 {
+		n := int(b[1])
+		if n > len(b)-2 {
+			n = len(b) - 2
+		}
+		if redactedTags[b[0]] {
+			for i := 2; i < 2+n; i++ {
+				b[i] = 0
+			}
+		}
+		b = b[2+n:]
+	}
+}
+
+// redactUnparsed zeroes key material in data that couldn't be parsed as a message. The body of
+// data with a pairing TLV8 header is redacted like a TLV8 body. All other data is zeroed if
+// it's from a pairing connection as it can't be known to be free of key material.
+func redactUnparsed(b []byte, pairing bool) []byte {
+	b = append([]byte(nil), b...)
+
+	if end := bytes.Index(b, []byte("\r\n\r\n")); end >= 0 &&
+		bytes.Contains(bytes.ToLower(b[:end]), []byte(hap.HTTPContentTypePairingTLV8)) {
+		redactTLV8(b[end+4:])
+		return b
+	}
+
+	if pairing {
+		for i := range b {
+			b[i] = 0
+		}
+	}
+	return b
+}
+
+// traceConn reports the messages read from and written to the underlying connection to a
+// TraceHandler.
+type traceConn struct {
+	net.Conn
+	sent     traceStream
+	received traceStream
+}
+
+// newTraceConn returns a traceConn reporting the messages of conn to handler. pairing is set
+// for connections used for pair-setup or pair-verify before the session is encrypted.
+func newTraceConn(conn net.Conn, handler TraceHandler, pairing bool) *traceConn {
+	addr := ""
+	if remote := conn.RemoteAddr(); remote != nil {
+		addr = remote.String()
+	}
+	return &traceConn{
+		Conn:     conn,
+		sent:     traceStream{handler: handler, addr: addr, direction: TraceSent, pairing: pairing},
+		received: traceStream{handler: handler, addr: addr, direction: TraceReceived, pairing: pairing},
+	}
+}
+
+func (t *traceConn) Read(b []byte) (int, error) {
+	n, err := t.Conn.Read(b)
+	t.received.write(b[:n])
+	return n, err
+}
+
+func (t *traceConn) Write(b []byte) (int, error) {
+	n, err := t.Conn.Write(b)
+	t.sent.write(b[:n])
+	return n, err
+}
+
+// maxTraceMessageSize is the most data buffered waiting for the rest of a message. Larger
+// messages are reported unparsed.
+const maxTraceMessageSize = 1 << 20
+
+// traceStream buffers data in one direction of a connection until it holds a complete
+// message.
+type traceStream struct {
+	handler   TraceHandler
+	addr      string
+	direction TraceDirection
+	pairing   bool
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (s *traceStream) write(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.buf = append(s.buf, b...)
+	var msgs []*TraceMessage
+	for len(s.buf) > 0 {
+		msg, n, err := parseTraceMessage(s.buf)
+		if errors.Is(err, io.ErrUnexpectedEOF) && len(s.buf) <= maxTraceMessageSize {
+			// wait for the rest of the message
+			break
+		}
+		if err != nil {
+			msg = &TraceMessage{Body: redactUnparsed(s.buf, s.pairing)}
+			n = len(s.buf)
+		}
+		msg.Time = time.Now()
+		msg.Addr = s.addr
+		msg.Direction = s.direction
+		msgs = append(msgs, msg)
+		s.buf = append([]byte(nil), s.buf[n:]...)
+	}
+	s.mu.Unlock()
+
+	for _, msg := range msgs {
+		s.handler(msg)
+	}
+}
+
+// parseTraceMessage parses the first message in b and returns the number of bytes it used. It
+// returns io.ErrUnexpectedEOF if b holds part of a message.
+func parseTraceMessage(b []byte) (*TraceMessage, int, error) {
+	src := bytes.NewReader(b)
+	r := bufio.NewReader(src)
+
+	msg, err := readTraceMessage(r)
+	if err != nil {
+		// a truncated line is malformed rather than incomplete so any failure after reading
+		// all the data means the message is incomplete
+		if src.Len() == 0 && r.Buffered() == 0 {
+			return nil, 0, io.ErrUnexpectedEOF
+		}
+		return nil, 0, err
+	}
+
+	return msg, len(b) - src.Len() - r.Buffered(), nil
+}
+
+func readTraceMessage(r *bufio.Reader) (*TraceMessage, error) {
+	tp := textproto.NewReader(r)
+
+	line, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	body, err := readMessageBody(r, tp, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.Get("Content-Type") == hap.HTTPContentTypePairingTLV8 {
+		redactTLV8(body)
+	}
+
+	return &TraceMessage{
+		StartLine: line,
+		Header:    header,
+		Body:      body,
+	}, nil
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/hap/pair"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	testServer, accClient := fakeAccessoryClient(t, ctx)
+
+	var mu sync.Mutex
+	var msgs []*TraceMessage
+	var out bytes.Buffer
+	writer := NewTraceWriter(&out)
+	accClient.SetTrace(func(msg *TraceMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		msgs = append(msgs, msg)
+		writer(msg)
+	})
+
+	_, err := accClient.Characteristics(ctx, &CharacteristicsReadRequest{
+		Characteristics: []CharacteristicReadRequest{
+			{AccessoryID: 1, CharacteristicID: 9},
+			{AccessoryID: 1, CharacteristicID: 10},
+		},
+	})
+	require.NoError(t, err)
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	defer subCancel()
+	events := make(chan *CharacteristicEvent, 1)
+	subErr := make(chan error, 1)
+	go func() {
+		subErr <- accClient.Subscribe(subCtx, []CharacteristicReadRequest{{AccessoryID: 1, CharacteristicID: 9}},
+			func(event *CharacteristicEvent) { events <- event })
+	}()
+	require.Eventually(t, func() bool { return testServer.Subscriptions(1, 9) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, testServer.SetValue(1, 9, 30))
+	<-events
+	subCancel()
+	<-subErr
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var startLines []string
+	for _, msg := range msgs {
+		startLines = append(startLines, msg.Direction.String()+" "+msg.StartLine)
+
+		if msg.Header.Get("Content-Type") != "application/pairing+tlv8" {
+			continue
+		}
+		items, err := parseTLV8(msg.Body)
+		require.NoError(t, err)
+		for _, item := range items {
+			if item.tag == pair.TagPublicKey || item.tag == pair.TagEncryptedData {
+				require.Equal(t, make([]byte, len(item.value)), item.value, "key material is redacted")
+			}
+		}
+	}
+	require.Contains(t, startLines, "> POST /pair-verify HTTP/1.1")
+	require.Contains(t, startLines, "> GET /characteristics?id=1.9,1.10 HTTP/1.1")
+	require.Contains(t, startLines, "< HTTP/1.1 200 OK")
+	require.Contains(t, startLines, "< EVENT/1.0 200 OK")
+
+	require.Contains(t, out.String(), "  PublicKey: <redacted 32 bytes>\n")
+	require.Contains(t, out.String(), `{"characteristics":[{"aid":1,"iid":9,"value":30}]}`)
+}
+
+func TestTraceStream(t *testing.T) {
+	var msgs []*TraceMessage
+	stream := traceStream{handler: func(msg *TraceMessage) { msgs = append(msgs, msg) }, direction: TraceReceived}
+
+	data := "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n0\r\n\r\n" +
+		"EVENT/1.0 200 OK\r\nContent-Length: 2\r\n\r\n{}"
+	// messages are reported once they've been read completely
+	for i := 0; i < len(data); i++ {
+		stream.write([]byte{data[i]})
+		if i < strings.Index(data, "EVENT")-1 {
+			require.Empty(t, msgs)
+		}
+	}
+
+	require.Len(t, msgs, 2)
+	require.Equal(t, "HTTP/1.1 200 OK", msgs[0].StartLine)
+	require.Equal(t, "hello", string(msgs[0].Body))
+	require.Equal(t, "EVENT/1.0 200 OK", msgs[1].StartLine)
+	require.Equal(t, "{}", string(msgs[1].Body))
+}
+
+func TestTraceRedactsTruncatedTLV8(t *testing.T) {
+	var msgs []*TraceMessage
+	handler := func(msg *TraceMessage) { msgs = append(msgs, msg) }
+
+	// the public key claims 32 bytes but only 4 are sent
+	body := []byte{pair.TagSequence, 1, 2, pair.TagPublicKey, 32, 0xAA, 0xBB, 0xCC, 0xDD}
+	message := func(header string) []byte {
+		return append([]byte(header+"Content-Type: application/pairing+tlv8\r\n"+
+			"Content-Length: "+strconv.Itoa(len(body))+"\r\n\r\n"), body...)
+	}
+	redacted := []byte{pair.TagSequence, 1, 2, pair.TagPublicKey, 32, 0, 0, 0, 0}
+
+	stream := traceStream{handler: handler, direction: TraceReceived}
+	stream.write(message("HTTP/1.1 200 OK\r\n"))
+	require.Len(t, msgs, 1)
+	require.Equal(t, "HTTP/1.1 200 OK", msgs[0].StartLine)
+	require.Equal(t, redacted, msgs[0].Body)
+
+	// a malformed start line can't be parsed but the pairing body is still redacted
+	msgs = nil
+	stream.write(message("HTTP/1.1 200 OK\r\nbad header\r\n"))
+	require.Len(t, msgs, 1)
+	require.Empty(t, msgs[0].StartLine)
+	require.True(t, bytes.HasSuffix(msgs[0].Body, redacted))
+
+	// unparsed data of pair-verify connections is zeroed
+	msgs = nil
+	pairingStream := traceStream{handler: handler, direction: TraceReceived, pairing: true}
+	pairingStream.write(append([]byte("garbage\r\nbad header\r\n"), body...))
+	require.Len(t, msgs, 1)
+	require.Equal(t, make([]byte, len("garbage\r\nbad header\r\n")+len(body)), msgs[0].Body)
+}
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -9,8 +11,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/brutella/hc/crypto"
 	"github.com/brutella/hc/db"
-	"github.com/brutella/hc/hap"
 	"github.com/google/uuid"
 )
 
@@ -57,6 +59,20 @@ type HomeKitSecureDialer struct {
 	controller *ControllerIdentity
 	conn       *monitoredConnection
 	connMux    sync.Mutex
+	trace      TraceHandler
+
+	stats    ConnectionStats
+	statsMux sync.Mutex
+}
+
+// ConnectionStats describes the secure connections a HomeKitSecureDialer has established.
+type ConnectionStats struct {
+	// Connections is the number of secure connections established.
+	Connections uint64
+	// Failures is the number of attempts to establish a secure connection that failed.
+	Failures uint64
+	// VerifyDuration is the total time spent in pair-verify for the established connections.
+	VerifyDuration time.Duration
 }
 
 // NewHomeKitSecureDialer returns a new HomeKitSecureDialer suitable for use between controller c and
@@ -71,14 +87,18 @@ func NewHomeKitSecureDialer(dialer IPDialer, c *ControllerIdentity, a *Accessory
 
 // Dial will create a connection and negotiate secure communication using the pair-verify procedure
 // prior to returning it. Further communication on the connection will be transparently encrypted.
-// If a connection has already been established then the existing connection is returned.
+// If a connection has already been established then the existing connection is returned. A new
+// connection is established if the previous one was closed.
 func (h *HomeKitSecureDialer) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
 	h.connMux.Lock()
 	defer h.connMux.Unlock()
 
-	if h.conn == nil {
+	if h.conn == nil || h.conn.Closed() {
 		conn, err := h.establishConnection(ctx, network, addr)
 		if err != nil {
+			h.statsMux.Lock()
+			h.stats.Failures++
+			h.statsMux.Unlock()
 			return nil, fmt.Errorf("establish connection: %v", err)
 		}
 		h.conn = &monitoredConnection{conn: conn}
@@ -87,6 +107,23 @@ func (h *HomeKitSecureDialer) Dial(ctx context.Context, network, addr string) (n
 	return h.conn, nil
 }
 
+// SetTrace enables tracing of the decrypted messages exchanged on connections established
+// after it's called. Tracing is disabled if handler is nil.
+func (h *HomeKitSecureDialer) SetTrace(handler TraceHandler) {
+	h.connMux.Lock()
+	defer h.connMux.Unlock()
+
+	h.trace = handler
+}
+
+// Stats returns the statistics of the connections established by the dialer.
+func (h *HomeKitSecureDialer) Stats() ConnectionStats {
+	h.statsMux.Lock()
+	defer h.statsMux.Unlock()
+
+	return h.stats
+}
+
 func (h *HomeKitSecureDialer) establishConnection(ctx context.Context, network, addr string) (net.Conn, error) {
 	conn, err := h.dialer(ctx, network, addr)
 	if err != nil {
@@ -96,6 +133,11 @@ func (h *HomeKitSecureDialer) establishConnection(ctx context.Context, network,
 	detachableConn := &detachableConnection{conn: conn}
 	defer detachableConn.Detach()
 
+	var verifyConn net.Conn = detachableConn
+	if h.trace != nil {
+		verifyConn = newTraceConn(detachableConn, h.trace, true)
+	}
+
 	httpClient := &http.Client{
 		Transport: &http.Transport{
 			DisableCompression: true,
@@ -103,7 +145,7 @@ func (h *HomeKitSecureDialer) establishConnection(ctx context.Context, network,
 				if dialNetwork != network || dialAddr != addr {
 					return nil, fmt.Errorf("no connection found for %s:%s", dialNetwork, dialAddr)
 				}
-				return detachableConn, nil
+				return verifyConn, nil
 			},
 		},
 	}
@@ -111,30 +153,25 @@ func (h *HomeKitSecureDialer) establishConnection(ctx context.Context, network,
 
 	verifyClient := NewVerifyClient(httpClient, h.accessory, h.controller)
 
+	verifyStart := time.Now()
 	cryptographer, err := verifyClient.Verify(ctx)
 	if err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("pair verify: %v", err)
 	}
+	verifyDuration := time.Since(verifyStart)
 
-	// TODO: more direct way to setup encryption session on connection
-	dev, err := hap.NewSecuredDevice("unused", "11122333", &memoryDB{})
-	if err != nil {
-		return nil, fmt.Errorf("init device: %v", err)
-	}
-	hapCtx := hap.NewContextForSecuredDevice(dev)
-
-	hapConn, err := hap.NewConnection(conn, hapCtx), nil
-	if err != nil {
-		return nil, fmt.Errorf("hap.NewConnection: %v", err)
-	}
+	secureConn := newSecureConnection(conn, cryptographer)
 
-	sess := hapCtx.GetSessionForConnection(hapConn)
-	sess.SetCryptographer(cryptographer)
-	// hack: trigger Encrypter setup
-	_ = sess.Decrypter()
+	h.statsMux.Lock()
+	h.stats.Connections++
+	h.stats.VerifyDuration += verifyDuration
+	h.statsMux.Unlock()
 
-	return hapConn, nil
+	if h.trace != nil {
+		return newTraceConn(secureConn, h.trace, false), nil
+	}
+	return secureConn, nil
 }
 
 // Close any underlying connections if needed.
@@ -148,6 +185,51 @@ func (h *HomeKitSecureDialer) Close() error {
 	return nil
 }
 
+// secureConnection encrypts and decrypts the data of a verified connection. Encrypted frames
+// are read through a single buffered reader so frames received together aren't dropped.
+type secureConnection struct {
+	net.Conn
+	cryptographer crypto.Cryptographer
+	reader        *bufio.Reader
+	readBuffer    bytes.Buffer
+}
+
+func newSecureConnection(conn net.Conn, cryptographer crypto.Cryptographer) *secureConnection {
+	return &secureConnection{
+		Conn:          conn,
+		cryptographer: cryptographer,
+		reader:        bufio.NewReader(conn),
+	}
+}
+
+// Read reads and decrypts data from the connection.
+func (s *secureConnection) Read(b []byte) (int, error) {
+	if s.readBuffer.Len() == 0 {
+		decrypted, err := s.cryptographer.Decrypt(s.reader)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := s.readBuffer.ReadFrom(decrypted); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.readBuffer.Read(b)
+}
+
+// Write encrypts and writes data to the connection.
+func (s *secureConnection) Write(b []byte) (int, error) {
+	encrypted, err := s.cryptographer.Encrypt(bytes.NewReader(b))
+	if err != nil {
+		return 0, fmt.Errorf("encrypt: %v", err)
+	}
+	if _, err := io.Copy(s.Conn, encrypted); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
 type detachableConnection struct {
 	conn     net.Conn
 	closeMux sync.Mutex
@@ -260,6 +342,14 @@ func (m *monitoredConnection) CloseIfNeeded() error {
 	return m.close()
 }
 
+// Closed returns true if Close has been called.
+func (m *monitoredConnection) Closed() bool {
+	m.closeMux.Lock()
+	defer m.closeMux.Unlock()
+
+	return m.closed
+}
+
 func (m *monitoredConnection) close() error {
 	m.closed = true
 	return m.conn.Close()
@@ -0,0 +1,129 @@
+// Package units describes the units of characteristic values and converts values between
+// unit systems.
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unit of a characteristic value.
+type Unit string
+
+// Units of characteristic values. Celsius, Percentage, ArcDegrees, Lux and Seconds are
+// defined by the HAP specification. The others are implied by the description of their
+// characteristics.
+const (
+	Celsius                 Unit = "celsius"
+	Percentage              Unit = "percentage"
+	ArcDegrees              Unit = "arcdegrees"
+	Lux                     Unit = "lux"
+	Seconds                 Unit = "seconds"
+	PartsPerMillion         Unit = "ppm"
+	MicrogramsPerCubicMeter Unit = "micrograms/m^3"
+
+	// Fahrenheit is only used to present Celsius values in the imperial system.
+	Fahrenheit Unit = "fahrenheit"
+)
+
+var symbols = map[Unit]string{
+	Celsius:                 "°C",
+	Percentage:              "%",
+	ArcDegrees:              "°",
+	Lux:                     "lx",
+	Seconds:                 "s",
+	PartsPerMillion:         "ppm",
+	MicrogramsPerCubicMeter: "µg/m³",
+	Fahrenheit:              "°F",
+}
+
+// Symbol returns the symbol of the unit or an empty string if the unit is unknown.
+func (u Unit) Symbol() string {
+	return symbols[u]
+}
+
+// Format formats v followed by the symbol of the unit, like 28.5 °C. Unknown units are
+// written as is.
+func (u Unit) Format(v interface{}) string {
+	switch u {
+	case "":
+		return fmt.Sprint(v)
+	case Percentage, ArcDegrees:
+		return fmt.Sprintf("%v%s", v, u.Symbol())
+	}
+	if symbol := u.Symbol(); symbol != "" {
+		return fmt.Sprintf("%v %s", v, symbol)
+	}
+	return fmt.Sprintf("%v %s", v, u)
+}
+
+// System is a system of units values are presented in.
+type System string
+
+// Supported unit systems.
+const (
+	// Metric presents values in the units used by accessories.
+	Metric System = "metric"
+	// Imperial presents temperatures in Fahrenheit.
+	Imperial System = "imperial"
+)
+
+// Systems are the supported unit systems.
+var Systems = []System{Metric, Imperial}
+
+// ParseSystem returns the unit system named s.
+func ParseSystem(s string) (System, error) {
+	for _, system := range Systems {
+		if string(system) == s {
+			return system, nil
+		}
+	}
+	names := make([]string, 0, len(Systems))
+	for _, system := range Systems {
+		names = append(names, string(system))
+	}
+	return "", fmt.Errorf("invalid unit system %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// Unit returns the unit values of unit u are presented in by the system.
+func (s System) Unit(u Unit) Unit {
+	if s == Imperial && u == Celsius {
+		return Fahrenheit
+	}
+	return u
+}
+
+// Convert converts v of unit u to the unit returned by Unit.
+func (s System) Convert(u Unit, v float64) float64 {
+	if s.Unit(u) == Fahrenheit {
+		return CelsiusToFahrenheit(v)
+	}
+	return v
+}
+
+// ConvertBack converts v in the unit returned by Unit back to unit u.
+func (s System) ConvertBack(u Unit, v float64) float64 {
+	if s.Unit(u) == Fahrenheit {
+		return FahrenheitToCelsius(v)
+	}
+	return v
+}
+
+// ConvertStep converts a difference between values of unit u such as the minimum step of a
+// characteristic to the unit returned by Unit.
+func (s System) ConvertStep(u Unit, v float64) float64 {
+	if s.Unit(u) == Fahrenheit {
+		return v * 9 / 5
+	}
+	return v
+}
+
+// CelsiusToFahrenheit converts a temperature in degrees Celsius to degrees Fahrenheit.
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// FahrenheitToCelsius converts a temperature in degrees Fahrenheit to degrees Celsius.
+func FahrenheitToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
@@ -0,0 +1,31 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	require.Equal(t, "28.5 °C", Celsius.Format(28.5))
+	require.Equal(t, "50%", Percentage.Format(50))
+	require.Equal(t, "12 µg/m³", MicrogramsPerCubicMeter.Format(12))
+	require.Equal(t, "3 mired", Unit("mired").Format(3))
+	require.Equal(t, "3", Unit("").Format(3))
+}
+
+func TestSystem(t *testing.T) {
+	system, err := ParseSystem("imperial")
+	require.NoError(t, err)
+	require.Equal(t, Fahrenheit, system.Unit(Celsius))
+	require.Equal(t, 77.0, system.Convert(Celsius, 25))
+	require.Equal(t, 25.0, system.ConvertBack(Celsius, 77))
+	require.Equal(t, 0.9, system.ConvertStep(Celsius, 0.5))
+	require.Equal(t, Percentage, system.Unit(Percentage))
+	require.Equal(t, 50.0, system.Convert(Percentage, 50))
+
+	require.Equal(t, 25.0, Metric.Convert(Celsius, 25))
+
+	_, err = ParseSystem("kelvin")
+	require.EqualError(t, err, `invalid unit system "kelvin", expected one of metric, imperial`)
+}
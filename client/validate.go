@@ -0,0 +1,199 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+)
+
+// Severity of a ValidationIssue.
+type Severity string
+
+// Severities of validation issues.
+const (
+	// SeverityError is a violation of the HAP specification.
+	SeverityError Severity = "error"
+	// SeverityWarning is allowed by the specification but may not be understood by controllers.
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue is a deviation of an attribute database from the registered service and
+// characteristic types.
+type ValidationIssue struct {
+	Severity    Severity
+	AccessoryID uint64
+	// ServiceID is the iid of the service with the issue or 0 if the issue is with the
+	// accessory.
+	ServiceID uint64
+	// CharacteristicID is the iid of the characteristic with the issue or 0 if the issue isn't
+	// with a characteristic.
+	CharacteristicID uint64
+	Message          string
+}
+
+// String describes the issue and where it was found.
+func (v *ValidationIssue) String() string {
+	location := fmt.Sprintf("accessory %d", v.AccessoryID)
+	if v.ServiceID != 0 {
+		location += fmt.Sprintf(", service %d", v.ServiceID)
+	}
+	if v.CharacteristicID != 0 {
+		location += fmt.Sprintf(", characteristic %d", v.CharacteristicID)
+	}
+	return fmt.Sprintf("%s: %s: %s", v.Severity, location, v.Message)
+}
+
+// corePermissions are the permissions compared with the permissions of the characteristic
+// type. Other permissions are optional.
+var corePermissions = []string{
+	characteristic.PermissionPairedRead,
+	characteristic.PermissionPairedWrite,
+	characteristic.PermissionEvents,
+}
+
+// ValidateAccessories checks an attribute database returned by Accessories against the
+// registered service and characteristic types. Services and characteristics of unregistered
+// types are only checked for duplicate instance ids.
+func ValidateAccessories(accessories []*RawAccessory) []*ValidationIssue {
+	var v validator
+	seen := make(map[uint64]bool)
+	for _, acc := range accessories {
+		if seen[acc.ID] {
+			v.errorf(acc.ID, 0, 0, "duplicate accessory id %d", acc.ID)
+		}
+		seen[acc.ID] = true
+
+		v.validateAccessory(acc)
+	}
+	return v.issues
+}
+
+type validator struct {
+	issues []*ValidationIssue
+}
+
+func (v *validator) add(severity Severity, aid, sid, cid uint64, format string, args ...interface{}) {
+	v.issues = append(v.issues, &ValidationIssue{
+		Severity:         severity,
+		AccessoryID:      aid,
+		ServiceID:        sid,
+		CharacteristicID: cid,
+		Message:          fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) errorf(aid, sid, cid uint64, format string, args ...interface{}) {
+	v.add(SeverityError, aid, sid, cid, format, args...)
+}
+
+func (v *validator) warnf(aid, sid, cid uint64, format string, args ...interface{}) {
+	v.add(SeverityWarning, aid, sid, cid, format, args...)
+}
+
+func (v *validator) validateAccessory(acc *RawAccessory) {
+	if acc.ServiceByType(service.TypeAccessoryInformation) == nil {
+		v.errorf(acc.ID, 0, 0, "missing %s service", service.NameForType(service.TypeAccessoryInformation))
+	}
+
+	// services and characteristics share the instance ids of an accessory
+	iids := make(map[uint64]bool)
+	for _, svc := range acc.Services {
+		if iids[svc.ID] {
+			v.errorf(acc.ID, svc.ID, 0, "duplicate instance id %d", svc.ID)
+		}
+		iids[svc.ID] = true
+		for _, ch := range svc.Characteristics {
+			if iids[ch.ID] {
+				v.errorf(acc.ID, svc.ID, ch.ID, "duplicate instance id %d", ch.ID)
+			}
+			iids[ch.ID] = true
+		}
+	}
+
+	for _, svc := range acc.Services {
+		v.validateService(acc, svc)
+	}
+}
+
+func (v *validator) validateService(acc *RawAccessory, svc *service.RawService) {
+	for _, linked := range svc.Linked {
+		if linked == svc.ID {
+			v.errorf(acc.ID, svc.ID, 0, "service links to itself")
+		} else if acc.serviceByID(linked) == nil {
+			v.errorf(acc.ID, svc.ID, 0, "linked service %d doesn't exist", linked)
+		}
+	}
+
+	for _, ch := range svc.Characteristics {
+		v.validateCharacteristic(acc.ID, svc.ID, ch)
+	}
+
+	tm := service.MetadataForType(svc.Type)
+	if tm == nil {
+		return
+	}
+
+	for _, t := range tm.RequiredCharacteristics {
+		if svc.CharacteristicByType(t) == nil {
+			v.errorf(acc.ID, svc.ID, 0, "%s service is missing required characteristic %s (%s)",
+				tm.Name, characteristic.NameForType(t), t)
+		}
+	}
+
+	for _, ch := range svc.Characteristics {
+		if !containsString(tm.RequiredCharacteristics, ch.Type) && !containsString(tm.OptionalCharacteristics, ch.Type) {
+			v.warnf(acc.ID, svc.ID, ch.ID, "characteristic %s (%s) isn't defined for the %s service",
+				characteristic.NameForType(ch.Type), ch.Type, tm.Name)
+		}
+	}
+}
+
+func (v *validator) validateCharacteristic(aid, sid uint64, ch *characteristic.RawCharacteristic) {
+	tm := characteristic.MetadataForType(ch.Type)
+	if tm == nil {
+		return
+	}
+
+	switch ch.Format {
+	case tm.Format:
+	case "":
+		v.errorf(aid, sid, ch.ID, "%s is missing its format, expected %s", tm.Name, tm.Format)
+	default:
+		v.errorf(aid, sid, ch.ID, "%s format %s doesn't match %s", tm.Name, ch.Format, tm.Format)
+	}
+
+	var want, got []string
+	for _, p := range corePermissions {
+		if tm.HasPermission(p) {
+			want = append(want, p)
+		}
+		if ch.HasPermission(p) {
+			got = append(got, p)
+		}
+	}
+	if strings.Join(want, " ") != strings.Join(got, " ") {
+		v.errorf(aid, sid, ch.ID, "%s permissions %v don't match %v", tm.Name, got, want)
+	}
+}
+
+// serviceByID returns the service of the accessory with the iid or nil if not found.
+func (r *RawAccessory) serviceByID(iid uint64) *service.RawService {
+	for _, svc := range r.Services {
+		if svc.ID == iid {
+			return svc
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAccessories(t *testing.T) {
+	var accessories []*RawAccessory
+	require.NoError(t, json.Unmarshal([]byte(`[
+	  {"aid": 1, "services": [
+	    {"iid": 1, "type": "3E", "characteristics": [
+	      {"iid": 2, "type": "14", "format": "bool", "perms": ["pw"]},
+	      {"iid": 3, "type": "20", "format": "string", "value": "Acme", "perms": ["pr"]},
+	      {"iid": 4, "type": "21", "format": "string", "value": "Sensor", "perms": ["pr"]},
+	      {"iid": 5, "type": "23", "format": "string", "value": "Sensor", "perms": ["pr"]},
+	      {"iid": 6, "type": "30", "format": "string", "value": "abc", "perms": ["pr"]},
+	      {"iid": 7, "type": "52", "format": "string", "value": "1.0", "perms": ["pr"]}
+	    ]},
+	    {"iid": 8, "type": "8A", "linked": [8, 20], "characteristics": [
+	      {"iid": 9, "type": "11", "format": "uint8", "value": 20, "perms": ["pr", "ev"]},
+	      {"iid": 10, "type": "6D", "format": "uint8", "value": 20, "perms": ["pr", "ev"]},
+	      {"iid": 10, "type": "75", "format": "bool", "value": true, "perms": ["pr"]},
+	      {"iid": 11, "type": "E863F10D-079E-48FF-8F27-9C2605A29F52", "format": "float", "value": 1, "perms": ["pr"]}
+	    ]}
+	  ]},
+	  {"aid": 2, "services": [
+	    {"iid": 1, "type": "8B", "characteristics": [
+	      {"iid": 2, "type": "6D", "format": "uint8", "value": 0, "perms": ["pr", "ev"]},
+	      {"iid": 3, "type": "7C", "perms": ["pr", "pw", "ev"]},
+	      {"iid": 4, "type": "72", "format": "uint8", "perms": ["pr", "ev"]}
+	    ]}
+	  ]}
+	]`), &accessories))
+
+	var issues []string
+	for _, issue := range ValidateAccessories(accessories) {
+		issues = append(issues, issue.String())
+	}
+	require.Equal(t, []string{
+		"error: accessory 1, service 8, characteristic 10: duplicate instance id 10",
+		"error: accessory 1, service 8: service links to itself",
+		"error: accessory 1, service 8: linked service 20 doesn't exist",
+		"error: accessory 1, service 8, characteristic 9: CurrentTemperature format uint8 doesn't match float",
+		"error: accessory 1, service 8, characteristic 10: StatusActive permissions [pr] don't match [pr ev]",
+		"warning: accessory 1, service 8, characteristic 10: characteristic CurrentPosition (6D) isn't defined for the TemperatureSensor service",
+		"warning: accessory 1, service 8, characteristic 11: characteristic <Unknown> (E863F10D-079E-48FF-8F27-9C2605A29F52) isn't defined for the TemperatureSensor service",
+		"error: accessory 2: missing AccessoryInformation service",
+		"error: accessory 2, service 1, characteristic 3: TargetPosition is missing its format, expected uint8",
+	}, issues)
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 
 	"github.com/mctofu/homekit/client"
@@ -47,21 +46,15 @@ func addPairing(
 		PublicKey: publicKey,
 	}
 	if admin {
-		addReq.Permissions = 1
+		addReq.Permissions = client.PermissionAdmin
 	}
 
 	if err := accClient.AddPairing(ctx, addReq); err != nil {
 		return fmt.Errorf("addPairing: %v", err)
 	}
 
-	fmt.Println("Add pairing successful. Import these accessory settings on the added controller:")
-	for _, pair := range clientCtx.Config.AccessoryPairings {
-		if pair.Name == clientCtx.AccessoryName {
-			fmt.Printf("ID: %s\n", pair.DeviceID)
-			fmt.Printf("Key: %s\n", base64.StdEncoding.EncodeToString(pair.PublicKey))
-			break
-		}
-	}
+	accPairing := findAccessoryPairing(clientCtx.Config, clientCtx.AccessoryName)
 
-	return nil
+	return renderPairingImport(clientCtx.Config, accPairing,
+		"Add pairing successful. Import these accessory settings on the added controller:")
 }
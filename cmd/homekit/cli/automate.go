@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/cmd/homekit/cli/automation"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func automateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "automate",
+		Short: "Run automation rules against paired accessories",
+		Long: "Run automation rules against paired accessories until interrupted.\n\n" +
+			"Rules are triggered by characteristic changes, threshold crossings or a time of day. " +
+			"When all of a rule's conditions hold its actions write values to paired accessories.",
+	}
+
+	rulesPath := cmd.Flags().String("rules", "", "Path to a yaml rules file")
+	markFlagRequired(cmd, "rules")
+	dryRun := cmd.Flags().Bool("dry-run", false, "Log the writes rules would make instead of making them")
+	pollInterval := cmd.Flags().Duration("poll", time.Minute, "Interval to read characteristics that don't support events")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			return automate(ctx, configPath, controllerName, *rulesPath, *dryRun, *pollInterval)
+		},
+	)
+
+	return cmd
+}
+
+func automate(ctx context.Context, configPath, controllerName, rulesPath string, dryRun bool, pollInterval time.Duration) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	rules, err := automation.ReadRules(rulesPath)
+	if err != nil {
+		return fmt.Errorf("read rules: %v", err)
+	}
+
+	accessories := make(map[string]client.Accessory)
+	for _, name := range rules.AccessoryNames() {
+		accClient, err := accessoryClient(cfg, name)
+		if err != nil {
+			return err
+		}
+		defer accClient.Close()
+		accessories[name] = accClient
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	engine := automation.NewEngine(rules, accessories, automation.EngineConfig{
+		DryRun:       dryRun,
+		PollInterval: pollInterval,
+		Logger:       log.New(progressWriter(), "", log.LstdFlags),
+	})
+
+	if err := engine.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,537 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+)
+
+// EngineConfig controls how the engine runs rules.
+type EngineConfig struct {
+	// DryRun logs the writes actions would make instead of making them.
+	DryRun bool
+	// PollInterval is how often characteristics that don't support events are read.
+	PollInterval time.Duration
+	// ReconnectDelay is how long to wait before resubscribing to events after a failure.
+	ReconnectDelay time.Duration
+	Logger         *log.Logger
+}
+
+// Engine runs a RuleSet against paired accessories.
+type Engine struct {
+	rules       *RuleSet
+	accessories map[string]*client.LockedAccessory
+	cfg         EngineConfig
+	now         func() time.Time
+
+	// state below is only accessed by the Run loop
+	dbs         map[string][]*client.RawAccessory
+	bindings    map[charKey][]*Trigger
+	triggerRule map[*Trigger]*Rule
+	values      map[charKey]interface{}
+	matching    map[*Trigger]map[charKey]bool
+	ruleStates  map[*Rule]*ruleState
+}
+
+// charKey identifies a characteristic of a named accessory.
+type charKey struct {
+	accessory string
+	aid       uint64
+	iid       uint64
+}
+
+func (c charKey) String() string {
+	return fmt.Sprintf("%s %d.%d", c.accessory, c.aid, c.iid)
+}
+
+type ruleState struct {
+	lastRun  time.Time
+	timer    *time.Timer
+	gen      int
+	pending  *Trigger
+	pendingK charKey
+}
+
+type valueEvent struct {
+	key   charKey
+	value interface{}
+}
+
+type fireEvent struct {
+	rule    *Rule
+	trigger *Trigger
+	gen     int
+}
+
+// NewEngine returns an engine that runs the rules against the accessories keyed by
+// the names used in the rules.
+func NewEngine(rules *RuleSet, accessories map[string]client.Accessory, cfg EngineConfig) *Engine {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = client.DefaultReconnectDelay
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(ioutil.Discard, "", 0)
+	}
+
+	locked := make(map[string]*client.LockedAccessory, len(accessories))
+	for name, acc := range accessories {
+		locked[name] = client.NewLockedAccessory(acc)
+	}
+
+	return &Engine{
+		rules:       rules,
+		accessories: locked,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+// Run watches the accessories and runs rules as they trigger until ctx is cancelled.
+func (e *Engine) Run(ctx context.Context) error {
+	if err := e.setup(ctx); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	values := make(chan valueEvent)
+	fires := make(chan fireEvent)
+
+	evChars, pollChars := e.watchedCharacteristics()
+	for name := range e.accessories {
+		if len(evChars[name]) > 0 {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				e.subscribe(ctx, name, evChars[name], values)
+			}(name)
+		}
+		if len(pollChars[name]) > 0 {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				e.poll(ctx, name, pollChars[name], values)
+			}(name)
+		}
+	}
+
+	for _, rule := range e.rules.Rules {
+		for _, t := range rule.Triggers {
+			if t.selector != nil {
+				continue
+			}
+			wg.Add(1)
+			go func(rule *Rule, t *Trigger) {
+				defer wg.Done()
+				e.schedule(ctx, rule, t, fires)
+			}(rule, t)
+		}
+	}
+
+	e.cfg.Logger.Printf("Running %d rules", len(e.rules.Rules))
+
+	for {
+		select {
+		case <-ctx.Done():
+			for _, st := range e.ruleStates {
+				if st.timer != nil {
+					st.timer.Stop()
+				}
+			}
+			return ctx.Err()
+		case v := <-values:
+			e.handleValue(ctx, v, fires)
+		case f := <-fires:
+			e.handleFire(ctx, f, fires)
+		}
+	}
+}
+
+// setup reads the attribute database of each accessory and resolves the selectors used
+// by the rules.
+func (e *Engine) setup(ctx context.Context) error {
+	e.dbs = make(map[string][]*client.RawAccessory)
+	e.bindings = make(map[charKey][]*Trigger)
+	e.triggerRule = make(map[*Trigger]*Rule)
+	e.values = make(map[charKey]interface{})
+	e.matching = make(map[*Trigger]map[charKey]bool)
+	e.ruleStates = make(map[*Rule]*ruleState)
+
+	for _, rule := range e.rules.Rules {
+		e.ruleStates[rule] = &ruleState{}
+
+		for i, t := range rule.Triggers {
+			e.triggerRule[t] = rule
+			if t.selector == nil {
+				continue
+			}
+			keys, err := e.resolve(ctx, t.Accessory, t.selector, characteristic.PermissionPairedRead)
+			if err != nil {
+				return fmt.Errorf("rule %s trigger %d: %v", rule.Name, i+1, err)
+			}
+			e.matching[t] = make(map[charKey]bool)
+			for _, k := range keys {
+				e.bindings[k] = append(e.bindings[k], t)
+			}
+		}
+		for i, c := range rule.Conditions {
+			if _, err := e.resolve(ctx, c.Accessory, c.selector, characteristic.PermissionPairedRead); err != nil {
+				return fmt.Errorf("rule %s condition %d: %v", rule.Name, i+1, err)
+			}
+		}
+		for i, a := range rule.Actions {
+			if _, err := e.resolve(ctx, a.Accessory, a.selector, characteristic.PermissionPairedWrite); err != nil {
+				return fmt.Errorf("rule %s action %d: %v", rule.Name, i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve returns the characteristics of the accessory matching the selector that have the
+// permission. The accessory's attribute database is read the first time it's needed.
+func (e *Engine) resolve(ctx context.Context, name string, sel *client.CharacteristicSelector, perm string) ([]charKey, error) {
+	db, ok := e.dbs[name]
+	if !ok {
+		acc, ok := e.accessories[name]
+		if !ok {
+			return nil, fmt.Errorf("accessory %s not found", name)
+		}
+		var err error
+		db, err = acc.Accessories(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("read %s accessories: %v", name, err)
+		}
+		e.dbs[name] = db
+	}
+
+	var keys []charKey
+	for _, match := range sel.Select(db) {
+		if !match.Characteristic.HasPermission(perm) {
+			continue
+		}
+		keys = append(keys, charKey{accessory: name, aid: match.AccessoryID, iid: match.Characteristic.ID})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no characteristics of %s match %s", name, sel)
+	}
+
+	return keys, nil
+}
+
+// watchedCharacteristics returns the trigger characteristics of each accessory split by whether
+// they support events or need to be polled.
+func (e *Engine) watchedCharacteristics() (evChars, pollChars map[string][]client.CharacteristicReadRequest) {
+	evChars = make(map[string][]client.CharacteristicReadRequest)
+	pollChars = make(map[string][]client.CharacteristicReadRequest)
+
+	for k := range e.bindings {
+		req := client.CharacteristicReadRequest{AccessoryID: k.aid, CharacteristicID: k.iid}
+		if e.supportsEvents(k) {
+			evChars[k.accessory] = append(evChars[k.accessory], req)
+		} else {
+			pollChars[k.accessory] = append(pollChars[k.accessory], req)
+		}
+	}
+
+	return evChars, pollChars
+}
+
+func (e *Engine) supportsEvents(k charKey) bool {
+	for _, acc := range e.dbs[k.accessory] {
+		if acc.ID != k.aid {
+			continue
+		}
+		for _, svc := range acc.Services {
+			for _, ch := range svc.Characteristics {
+				if ch.ID == k.iid {
+					return ch.HasPermission(characteristic.PermissionEvents)
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// subscribe reads the current values of the characteristics and then streams events for them.
+// If the subscription fails it's retried after a delay.
+func (e *Engine) subscribe(ctx context.Context, name string, chars []client.CharacteristicReadRequest, values chan<- valueEvent) {
+	acc := &resyncAccessory{Accessory: e.accessories[name], read: func(ctx context.Context) {
+		e.read(ctx, name, chars, values)
+	}}
+	client.SubscribeWithRetry(ctx, acc, chars, func(event *client.CharacteristicEvent) {
+		e.emit(ctx, name, event.AccessoryID, event.CharacteristicID, event.Value, values)
+	}, e.cfg.ReconnectDelay, e.cfg.Logger, name)
+}
+
+// resyncAccessory reads the current values before each subscription so changes missed while
+// the subscription was down are seen.
+type resyncAccessory struct {
+	client.Accessory
+	read func(ctx context.Context)
+}
+
+func (r *resyncAccessory) Subscribe(ctx context.Context, chars []client.CharacteristicReadRequest, handler client.EventHandler) error {
+	r.read(ctx)
+	return r.Accessory.Subscribe(ctx, chars, handler)
+}
+
+// poll reads the characteristics on an interval.
+func (e *Engine) poll(ctx context.Context, name string, chars []client.CharacteristicReadRequest, values chan<- valueEvent) {
+	ticker := time.NewTicker(e.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		e.read(ctx, name, chars, values)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Engine) read(ctx context.Context, name string, chars []client.CharacteristicReadRequest, values chan<- valueEvent) {
+	resps, err := e.accessories[name].Characteristics(ctx, &client.CharacteristicsReadRequest{
+		Characteristics: chars,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			e.cfg.Logger.Printf("%s: read characteristics: %v", name, err)
+		}
+		return
+	}
+
+	for _, resp := range resps {
+		if resp.Status != nil && *resp.Status != 0 {
+			e.cfg.Logger.Printf("%s: read %d.%d failed with status %d", name, resp.AccessoryID, resp.CharacteristicID, *resp.Status)
+			continue
+		}
+		e.emit(ctx, name, resp.AccessoryID, resp.CharacteristicID, resp.Value, values)
+	}
+}
+
+func (e *Engine) emit(ctx context.Context, name string, aid, iid uint64, raw characteristic.Value, values chan<- valueEvent) {
+	key := charKey{accessory: name, aid: aid, iid: iid}
+	value, err := decodeValue(raw)
+	if err != nil {
+		e.cfg.Logger.Printf("%s: decode value %s: %v", key, string(raw), err)
+		return
+	}
+
+	select {
+	case values <- valueEvent{key: key, value: value}:
+	case <-ctx.Done():
+	}
+}
+
+// schedule fires a time of day trigger every day until ctx is cancelled.
+func (e *Engine) schedule(ctx context.Context, rule *Rule, t *Trigger, fires chan<- fireEvent) {
+	for {
+		timer := time.NewTimer(nextOccurrence(e.now(), t.at).Sub(e.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		select {
+		case fires <- fireEvent{rule: rule, trigger: t, gen: -1}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (e *Engine) handleValue(ctx context.Context, v valueEvent, fires chan<- fireEvent) {
+	prev, seen := e.values[v.key]
+	e.values[v.key] = v.value
+
+	for _, t := range e.bindings[v.key] {
+		fire := false
+		if t.Comparison.empty() {
+			fire = seen && !valuesEqual(prev, v.value)
+		} else {
+			matched := t.Match(v.value)
+			fire = matched && !e.matching[t][v.key]
+			e.matching[t][v.key] = matched
+		}
+
+		if fire {
+			rule := e.triggerRule[t]
+			e.cfg.Logger.Printf("rule %s: triggered by %s = %v", rule.Name, v.key, v.value)
+			e.trigger(ctx, rule, t, v.key, fires)
+		}
+	}
+}
+
+// trigger runs the rule or, if the rule is debounced, schedules it to run once its triggers
+// have been quiet for the debounce duration.
+func (e *Engine) trigger(ctx context.Context, rule *Rule, t *Trigger, key charKey, fires chan<- fireEvent) {
+	if rule.Debounce <= 0 {
+		e.run(ctx, rule)
+		return
+	}
+
+	st := e.ruleStates[rule]
+	st.gen++
+	st.pending = t
+	st.pendingK = key
+	if st.timer != nil {
+		st.timer.Stop()
+	}
+
+	fire := fireEvent{rule: rule, trigger: t, gen: st.gen}
+	st.timer = time.AfterFunc(rule.Debounce, func() {
+		select {
+		case fires <- fire:
+		case <-ctx.Done():
+		}
+	})
+}
+
+func (e *Engine) handleFire(ctx context.Context, f fireEvent, fires chan<- fireEvent) {
+	if f.gen < 0 {
+		e.cfg.Logger.Printf("rule %s: triggered at %s", f.rule.Name, f.trigger.At)
+		e.trigger(ctx, f.rule, f.trigger, charKey{}, fires)
+		return
+	}
+
+	st := e.ruleStates[f.rule]
+	if f.gen != st.gen {
+		// superseded by a later trigger
+		return
+	}
+	st.timer = nil
+
+	if t := st.pending; t != nil && t.selector != nil && !t.Comparison.empty() && !t.Match(e.values[st.pendingK]) {
+		e.cfg.Logger.Printf("rule %s: skipped, %s no longer matches after debounce", f.rule.Name, st.pendingK)
+		return
+	}
+
+	e.run(ctx, f.rule)
+}
+
+// run checks the rule's cooldown and conditions and then runs its actions.
+func (e *Engine) run(ctx context.Context, rule *Rule) {
+	st := e.ruleStates[rule]
+	now := e.now()
+	if rule.Cooldown > 0 && !st.lastRun.IsZero() && now.Sub(st.lastRun) < rule.Cooldown {
+		e.cfg.Logger.Printf("rule %s: skipped, cooling down until %s", rule.Name, st.lastRun.Add(rule.Cooldown).Format(time.RFC3339))
+		return
+	}
+
+	for i, c := range rule.Conditions {
+		ok, err := e.checkCondition(ctx, c)
+		if err != nil {
+			e.cfg.Logger.Printf("rule %s: condition %d: %v", rule.Name, i+1, err)
+			return
+		}
+		if !ok {
+			e.cfg.Logger.Printf("rule %s: skipped, condition %d not met", rule.Name, i+1)
+			return
+		}
+	}
+
+	st.lastRun = now
+	e.runActions(ctx, rule)
+}
+
+// checkCondition reads the current values of the condition's characteristics and returns true
+// if they all match.
+func (e *Engine) checkCondition(ctx context.Context, c *Condition) (bool, error) {
+	keys, err := e.resolve(ctx, c.Accessory, c.selector, characteristic.PermissionPairedRead)
+	if err != nil {
+		return false, err
+	}
+
+	reads := make([]client.CharacteristicReadRequest, 0, len(keys))
+	for _, k := range keys {
+		reads = append(reads, client.CharacteristicReadRequest{AccessoryID: k.aid, CharacteristicID: k.iid})
+	}
+
+	resps, err := e.accessories[c.Accessory].Characteristics(ctx, &client.CharacteristicsReadRequest{
+		Characteristics: reads,
+	})
+	if err != nil {
+		return false, fmt.Errorf("read characteristics: %v", err)
+	}
+
+	for _, resp := range resps {
+		if resp.Status != nil && *resp.Status != 0 {
+			return false, fmt.Errorf("read %d.%d failed with status %d", resp.AccessoryID, resp.CharacteristicID, *resp.Status)
+		}
+		value, err := decodeValue(resp.Value)
+		if err != nil {
+			return false, fmt.Errorf("decode %d.%d: %v", resp.AccessoryID, resp.CharacteristicID, err)
+		}
+		if !c.Match(value) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// runActions writes the action values with one request per accessory.
+func (e *Engine) runActions(ctx context.Context, rule *Rule) {
+	var names []string
+	writes := make(map[string][]client.CharacteristicWriteRequest)
+	for i, a := range rule.Actions {
+		keys, err := e.resolve(ctx, a.Accessory, a.selector, characteristic.PermissionPairedWrite)
+		if err != nil {
+			e.cfg.Logger.Printf("rule %s: action %d: %v", rule.Name, i+1, err)
+			return
+		}
+		if _, ok := writes[a.Accessory]; !ok {
+			names = append(names, a.Accessory)
+		}
+		for _, k := range keys {
+			writes[a.Accessory] = append(writes[a.Accessory], client.CharacteristicWriteRequest{
+				AccessoryID:      k.aid,
+				CharacteristicID: k.iid,
+				Value:            a.Value,
+			})
+		}
+	}
+
+	for _, name := range names {
+		if e.cfg.DryRun {
+			for _, w := range writes[name] {
+				e.cfg.Logger.Printf("rule %s: dry run, would write %v to %s %d.%d", rule.Name, w.Value, name, w.AccessoryID, w.CharacteristicID)
+			}
+			continue
+		}
+
+		resps, err := e.accessories[name].SetCharacteristics(ctx, &client.CharacteristicsWriteRequest{
+			Characteristics: writes[name],
+		})
+		if err != nil {
+			e.cfg.Logger.Printf("rule %s: write %s: %v", rule.Name, name, err)
+			continue
+		}
+		for _, resp := range resps {
+			if resp.Status != nil && *resp.Status != 0 {
+				e.cfg.Logger.Printf("rule %s: write %s %d.%d failed with status %d", rule.Name, name, resp.AccessoryID, resp.CharacteristicID, *resp.Status)
+			}
+		}
+		e.cfg.Logger.Printf("rule %s: wrote %d values to %s", rule.Name, len(writes[name]), name)
+	}
+}
@@ -0,0 +1,170 @@
+package automation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/client/hapfake/hapfaketest"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccessories = `[
+  {
+    "aid": 1,
+    "services": [
+      {"iid": 1, "type": "3E", "characteristics": [
+        {"iid": 2, "type": "23", "format": "string", "value": "Velux", "perms": ["pr"]}
+      ]},
+      {"iid": 8, "type": "97", "characteristics": [
+        {"iid": 9, "type": "23", "format": "string", "value": "Air", "perms": ["pr"]},
+        {"iid": 10, "type": "93", "format": "float", "value": 800, "perms": ["pr", "ev"]}
+      ]},
+      {"iid": 12, "type": "8B", "characteristics": [
+        {"iid": 13, "type": "23", "format": "string", "value": "Roof Window", "perms": ["pr"]},
+        {"iid": 14, "type": "7C", "format": "uint8", "value": 0, "perms": ["pr", "pw", "ev"]}
+      ]},
+      {"iid": 16, "type": "86", "characteristics": [
+        {"iid": 17, "type": "23", "format": "string", "value": "Rain", "perms": ["pr"]},
+        {"iid": 18, "type": "71", "format": "uint8", "value": 0, "perms": ["pr"]}
+      ]}
+    ]
+  }
+]`
+
+const testRules = `
+rules:
+  - name: vent
+    triggers:
+      - accessory: velux
+        characteristic: "*/CarbonDioxideLevel"
+        above: 1200
+    conditions:
+      - accessory: velux
+        characteristic: Rain/OccupancyDetected
+        equals: false
+    actions:
+      - accessory: velux
+        characteristic: Roof Window/TargetPosition
+        value: 100
+    cooldown: 1h
+`
+
+// writeCount returns the number of values written to the fake accessory.
+func writeCount(server *hapfake.Server) func() int {
+	return func() int { return len(server.Writes()) }
+}
+
+func runEngine(t *testing.T, rules string, cfg EngineConfig) (*hapfake.Server, func()) {
+	ruleSet, err := ParseRules([]byte(rules))
+	require.NoError(t, err)
+
+	server, accClient := hapfaketest.NewClient(t, testAccessories)
+	engine := NewEngine(ruleSet, map[string]client.Accessory{"velux": accClient}, cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	done := make(chan error)
+	go func() {
+		done <- engine.Run(ctx)
+	}()
+
+	// values only trigger rules once the engine is subscribed to their events
+	require.Eventually(t, func() bool { return server.Subscriptions(1, 10) > 0 }, time.Second, 5*time.Millisecond)
+
+	return server, func() {
+		cancel()
+		require.Equal(t, context.Canceled, <-done)
+	}
+}
+
+func TestEngineThresholdTrigger(t *testing.T) {
+	server, stop := runEngine(t, testRules, EngineConfig{})
+	defer stop()
+	writes := writeCount(server)
+
+	require.NoError(t, server.SetValue(1, 10, 1000))
+	require.NoError(t, server.SetValue(1, 10, 1300))
+	require.Eventually(t, func() bool { return writes() == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, hapfake.Write{AccessoryID: 1, CharacteristicID: 14, Value: characteristic.Value("100")}, server.Writes()[0])
+
+	// crossing again is ignored during the cooldown
+	require.NoError(t, server.SetValue(1, 10, 1000))
+	require.NoError(t, server.SetValue(1, 10, 1300))
+	require.NoError(t, server.SetValue(1, 10, 1000))
+	require.Never(t, func() bool { return writes() != 1 }, 100*time.Millisecond, 5*time.Millisecond)
+}
+
+func TestEngineCondition(t *testing.T) {
+	server, stop := runEngine(t, testRules, EngineConfig{})
+	defer stop()
+	writes := writeCount(server)
+
+	// raining
+	require.NoError(t, server.SetValue(1, 18, 1))
+	require.NoError(t, server.SetValue(1, 10, 1300))
+	require.NoError(t, server.SetValue(1, 10, 1000))
+	require.Never(t, func() bool { return writes() != 0 }, 100*time.Millisecond, 5*time.Millisecond)
+
+	require.NoError(t, server.SetValue(1, 18, 0))
+	require.NoError(t, server.SetValue(1, 10, 1300))
+	require.Eventually(t, func() bool { return writes() == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestEngineDryRun(t *testing.T) {
+	server, stop := runEngine(t, testRules, EngineConfig{DryRun: true})
+	defer stop()
+	writes := writeCount(server)
+
+	require.NoError(t, server.SetValue(1, 10, 1300))
+	require.NoError(t, server.SetValue(1, 10, 1000))
+	require.Never(t, func() bool { return writes() != 0 }, 100*time.Millisecond, 5*time.Millisecond)
+}
+
+func TestEngineDebounce(t *testing.T) {
+	rules := `
+rules:
+  - name: close
+    triggers:
+      - accessory: velux
+        characteristic: Air/CarbonDioxideLevel
+        below: 600
+    actions:
+      - accessory: velux
+        characteristic: "*/TargetPosition"
+        value: 0
+    debounce: 50ms
+`
+	server, stop := runEngine(t, rules, EngineConfig{})
+	defer stop()
+	writes := writeCount(server)
+
+	// recovers before the debounce expires
+	require.NoError(t, server.SetValue(1, 10, 500))
+	require.NoError(t, server.SetValue(1, 10, 700))
+	require.Never(t, func() bool { return writes() != 0 }, 100*time.Millisecond, 5*time.Millisecond)
+
+	require.NoError(t, server.SetValue(1, 10, 500))
+	require.Eventually(t, func() bool { return writes() == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	for _, rules := range []string{
+		`rules: [{name: a, actions: [{accessory: v, characteristic: a/b, value: 1}]}]`,
+		`rules: [{name: a, triggers: [{at: "7pm"}], actions: [{accessory: v, characteristic: a/b, value: 1}]}]`,
+		`rules: [{name: a, triggers: [{accessory: v, characteristic: a/b}], actions: [{accessory: v, characteristic: a/b}]}]`,
+		`rules: [{name: a, triggers: [{accessory: v, characteristic: a/b}], conditions: [{accessory: v, characteristic: a/b}], actions: [{accessory: v, characteristic: a/b, value: 1}]}]`,
+	} {
+		_, err := ParseRules([]byte(rules))
+		require.Error(t, err, rules)
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	now := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2020, 3, 1, 19, 30, 0, 0, time.UTC), nextOccurrence(now, 19*time.Hour+30*time.Minute))
+	require.Equal(t, time.Date(2020, 3, 2, 7, 0, 0, 0, time.UTC), nextOccurrence(now, 7*time.Hour))
+	require.Equal(t, time.Date(2020, 3, 2, 12, 0, 0, 0, time.UTC), nextOccurrence(now, 12*time.Hour))
+}
@@ -0,0 +1,280 @@
+package automation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleSet is a set of automation rules
+type RuleSet struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Rule runs its actions when any of its triggers fire and all of its conditions hold.
+type Rule struct {
+	Name       string       `yaml:"name"`
+	Triggers   []*Trigger   `yaml:"triggers"`
+	Conditions []*Condition `yaml:"conditions,omitempty"`
+	Actions    []*Action    `yaml:"actions"`
+	// Debounce delays running the rule until its triggers have been quiet for the duration.
+	Debounce time.Duration `yaml:"debounce,omitempty"`
+	// Cooldown is the minimum time between runs of the rule.
+	Cooldown time.Duration `yaml:"cooldown,omitempty"`
+}
+
+// Trigger fires when a characteristic changes or at a time of day. When a comparison is set
+// the trigger only fires when the value crosses into matching the comparison.
+type Trigger struct {
+	Accessory      string `yaml:"accessory,omitempty"`
+	Characteristic string `yaml:"characteristic,omitempty"`
+	Comparison     `yaml:",inline"`
+	// At is a time of day formatted as 15:04.
+	At string `yaml:"at,omitempty"`
+
+	selector *client.CharacteristicSelector
+	at       time.Duration
+}
+
+// Condition holds when the current values of the characteristics match the comparison.
+type Condition struct {
+	Accessory      string `yaml:"accessory"`
+	Characteristic string `yaml:"characteristic"`
+	Comparison     `yaml:",inline"`
+
+	selector *client.CharacteristicSelector
+}
+
+// Action writes a value to characteristics of an accessory.
+type Action struct {
+	Accessory      string      `yaml:"accessory"`
+	Characteristic string      `yaml:"characteristic"`
+	Value          interface{} `yaml:"value"`
+
+	selector *client.CharacteristicSelector
+}
+
+// Comparison matches characteristic values. All of the fields that are set must match.
+type Comparison struct {
+	Above  *float64    `yaml:"above,omitempty"`
+	Below  *float64    `yaml:"below,omitempty"`
+	Equals interface{} `yaml:"equals,omitempty"`
+}
+
+// ReadRules reads and validates a RuleSet from a yaml file.
+func ReadRules(path string) (*RuleSet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRules(data)
+}
+
+// ParseRules parses and validates a RuleSet from yaml.
+func ParseRules(data []byte) (*RuleSet, error) {
+	var rules RuleSet
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse rules: %v", err)
+	}
+
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+func (r *RuleSet) validate() error {
+	names := make(map[string]bool)
+	for i, rule := range r.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rule %s: duplicate name", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %s: %v", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) validate() error {
+	if len(r.Triggers) == 0 {
+		return errors.New("at least one trigger is required")
+	}
+	if len(r.Actions) == 0 {
+		return errors.New("at least one action is required")
+	}
+
+	for i, t := range r.Triggers {
+		if err := t.validate(); err != nil {
+			return fmt.Errorf("trigger %d: %v", i+1, err)
+		}
+	}
+	for i, c := range r.Conditions {
+		sel, err := parseSelector(c.Accessory, c.Characteristic)
+		if err != nil {
+			return fmt.Errorf("condition %d: %v", i+1, err)
+		}
+		if c.Comparison.empty() {
+			return fmt.Errorf("condition %d: one of above, below or equals is required", i+1)
+		}
+		c.selector = sel
+	}
+	for i, a := range r.Actions {
+		sel, err := parseSelector(a.Accessory, a.Characteristic)
+		if err != nil {
+			return fmt.Errorf("action %d: %v", i+1, err)
+		}
+		if a.Value == nil {
+			return fmt.Errorf("action %d: value is required", i+1)
+		}
+		a.selector = sel
+	}
+
+	return nil
+}
+
+func (t *Trigger) validate() error {
+	if t.At != "" {
+		if t.Accessory != "" || t.Characteristic != "" || !t.Comparison.empty() {
+			return errors.New("at can't be combined with a characteristic trigger")
+		}
+		at, err := time.Parse("15:04", t.At)
+		if err != nil {
+			return fmt.Errorf("invalid time of day %s, expect 15:04", t.At)
+		}
+		t.at = time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+		return nil
+	}
+
+	sel, err := parseSelector(t.Accessory, t.Characteristic)
+	if err != nil {
+		return err
+	}
+	t.selector = sel
+
+	return nil
+}
+
+func parseSelector(accessory, characteristic string) (*client.CharacteristicSelector, error) {
+	if accessory == "" {
+		return nil, errors.New("accessory is required")
+	}
+	if characteristic == "" {
+		return nil, errors.New("characteristic is required")
+	}
+
+	return client.ParseCharacteristicSelector(characteristic)
+}
+
+// nextOccurrence returns the next time after now that the time of day occurs in now's location.
+func nextOccurrence(now time.Time, timeOfDay time.Duration) time.Time {
+	year, month, day := now.Date()
+	next := time.Date(year, month, day, 0, 0, 0, 0, now.Location()).Add(timeOfDay)
+	if !next.After(now) {
+		next = time.Date(year, month, day+1, 0, 0, 0, 0, now.Location()).Add(timeOfDay)
+	}
+	return next
+}
+
+func (c *Comparison) empty() bool {
+	return c.Above == nil && c.Below == nil && c.Equals == nil
+}
+
+// Match returns true if the value matches all of the comparisons that are set.
+func (c *Comparison) Match(v interface{}) bool {
+	if c.Above != nil || c.Below != nil {
+		f, ok := toFloat(v)
+		if !ok {
+			return false
+		}
+		if c.Above != nil && !(f > *c.Above) {
+			return false
+		}
+		if c.Below != nil && !(f < *c.Below) {
+			return false
+		}
+	}
+
+	if c.Equals != nil && !valuesEqual(c.Equals, v) {
+		return false
+	}
+
+	return true
+}
+
+// decodeValue decodes a characteristic value to a float64, bool or string.
+func decodeValue(v characteristic.Value) (interface{}, error) {
+	var decoded interface{}
+	if err := json.Unmarshal(v, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// toFloat converts numeric values to a float64. Some accessories report bool
+// characteristics as 0 or 1 so bools are converted as well.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+func valuesEqual(a, b interface{}) bool {
+	af, aOK := toFloat(a)
+	bf, bOK := toFloat(b)
+	if aOK && bOK {
+		return af == bf
+	}
+
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// AccessoryNames returns the names of the accessories used by the rules.
+func (r *RuleSet) AccessoryNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, rule := range r.Rules {
+		for _, t := range rule.Triggers {
+			add(t.Accessory)
+		}
+		for _, c := range rule.Conditions {
+			add(c.Accessory)
+		}
+		for _, a := range rule.Actions {
+			add(a.Accessory)
+		}
+	}
+
+	return names
+}
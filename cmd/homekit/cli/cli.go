@@ -4,25 +4,47 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path"
-	"strconv"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+	"github.com/mctofu/homekit/client/units"
 	"github.com/mctofu/homekit/cmd/homekit/cli/config"
 	"github.com/spf13/cobra"
 )
 
+// traceMessages enables writing the decrypted messages exchanged with accessories to stderr.
+var traceMessages bool
+
+// accessoryQuirks are the quirk definitions read from the config path. They take precedence
+// over client.DefaultQuirks.
+var accessoryQuirks []*client.Quirks
+
 var rootCommand = &cobra.Command{
 	Use: "homekit",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(); err != nil {
+			return err
+		}
+		return validateUnitSystem()
+	},
 }
 
 func init() {
+	rootCommand.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText,
+		"Output format: "+strings.Join(outputFormats, ", "))
+	rootCommand.PersistentFlags().StringVar(&unitSystemName, "units", string(units.Metric),
+		"Unit system to present values in: "+strings.Join(unitSystemNames(), ", "))
+	rootCommand.PersistentFlags().BoolVar(&traceMessages, "trace", false,
+		"Write decrypted messages exchanged with accessories to stderr")
+
 	rootCommand.AddCommand(createControllerCmd())
+	rootCommand.AddCommand(controllerCmd())
 	rootCommand.AddCommand(discoverCmd())
 	rootCommand.AddCommand(listPairedAccessoriesCmd())
 	rootCommand.AddCommand(listPairingsCmd())
@@ -33,6 +55,21 @@ func init() {
 	rootCommand.AddCommand(setCharacteristicsCmd())
 	rootCommand.AddCommand(addPairingCmd())
 	rootCommand.AddCommand(importPairingCmd())
+	rootCommand.AddCommand(exportPairingCmd())
+	rootCommand.AddCommand(removePairingCmd())
+	rootCommand.AddCommand(updatePairingCmd())
+	rootCommand.AddCommand(handoffCmd())
+	rootCommand.AddCommand(sceneCmd())
+	rootCommand.AddCommand(automateCmd())
+	rootCommand.AddCommand(serveCmd())
+	rootCommand.AddCommand(mqttCmd())
+	rootCommand.AddCommand(exporterCmd())
+	rootCommand.AddCommand(recordCmd())
+	rootCommand.AddCommand(historyCmd())
+	rootCommand.AddCommand(shellCmd())
+	rootCommand.AddCommand(identifyCmd())
+	rootCommand.AddCommand(simulateCmd())
+	rootCommand.AddCommand(validateCmd())
 }
 
 // Execute the command line interface
@@ -40,16 +77,6 @@ func Execute() error {
 	return rootCommand.ExecuteContext(context.Background())
 }
 
-func dump(v interface{}) {
-	j, err := json.MarshalIndent(v, "", "  ")
-	if err != nil {
-		fmt.Printf("Failed to marshal: %v\n", err)
-		return
-	}
-
-	fmt.Printf("%s\n", string(j))
-}
-
 func markFlagRequired(cmd *cobra.Command, name string) {
 	if err := cmd.MarkFlagRequired(name); err != nil {
 		panic(err)
@@ -73,10 +100,42 @@ func configCommandRunner(cmd *cobra.Command, cfgCmd configCommand) runner {
 			return fmt.Errorf("resolve config dir: %v", configDirErr)
 		}
 
+		quirks, err := config.ReadQuirks(*configPath)
+		if err != nil {
+			return fmt.Errorf("read quirks: %v", err)
+		}
+		accessoryQuirks = quirks
+
+		if err := registerTypes(*configPath); err != nil {
+			return fmt.Errorf("read types: %v", err)
+		}
+
 		return cfgCmd(cmd.Context(), *configPath, *controllerName)
 	}
 }
 
+// registerTypes registers the vendor characteristic and service types defined in the config
+// path so they're named and decoded like the built in types.
+func registerTypes(configPath string) error {
+	chTypes, err := config.ReadCharacteristicTypes(configPath)
+	if err != nil {
+		return err
+	}
+	for _, tm := range chTypes {
+		characteristic.RegisterType(*tm)
+	}
+
+	svcTypes, err := config.ReadServiceTypes(configPath)
+	if err != nil {
+		return err
+	}
+	for _, tm := range svcTypes {
+		service.RegisterType(*tm)
+	}
+
+	return nil
+}
+
 type clientContext struct {
 	AccessoryName string
 	Config        *config.ControllerConfig
@@ -88,6 +147,7 @@ type clientCommand func(ctx context.Context, clientCtx *clientContext, accClient
 func clientCommandRunner(cmd *cobra.Command, clientCmd clientCommand) runner {
 	name := cmd.Flags().StringP("name", "n", "", "Name of accessory to act on")
 	markFlagRequired(cmd, "name")
+	recordPath := cmd.Flags().String("record", "", "Save the decrypted requests and responses to a fixture file for replay in tests")
 
 	cfgCmd := func(ctx context.Context, configPath, controllerName string) (rErr error) {
 		cfg, err := config.ReadControllerConfig(configPath, controllerName)
@@ -105,6 +165,16 @@ func clientCommandRunner(cmd *cobra.Command, clientCmd clientCommand) runner {
 			}
 		}()
 
+		if *recordPath != "" {
+			recorder := client.NewRecordingTransport(accClient.Transport())
+			accClient.SetTransport(recorder)
+			defer func() {
+				if sErr := recorder.Save(*recordPath); sErr != nil {
+					rErr = multierror.Append(rErr, sErr)
+				}
+			}()
+		}
+
 		clientCtx := clientContext{
 			AccessoryName: *name,
 			Config:        cfg,
@@ -118,48 +188,112 @@ func clientCommandRunner(cmd *cobra.Command, clientCmd clientCommand) runner {
 }
 
 func accessoryClient(cfg *config.ControllerConfig, name string) (*client.AccessoryClient, error) {
-	var accPairing *config.AccessoryPairing
+	accPairing := findAccessoryPairing(cfg, name)
+	if accPairing == nil {
+		return nil, fmt.Errorf("accessory %s not found", name)
+	}
+
+	return pairingClient(controllerIdentity(cfg), accPairing), nil
+}
 
+// findAccessoryPairing returns the pairing aliased by name or nil if not found.
+func findAccessoryPairing(cfg *config.ControllerConfig, name string) *config.AccessoryPairing {
 	for _, pair := range cfg.AccessoryPairings {
 		if pair.Name == name {
-			accPairing = pair
-			break
+			return pair
 		}
 	}
 
-	if accPairing == nil {
-		return nil, fmt.Errorf("accessory %s not found", name)
+	return nil
+}
+
+func controllerIdentity(cfg *config.ControllerConfig) *client.ControllerIdentity {
+	return &client.ControllerIdentity{
+		DeviceID:   cfg.DeviceID,
+		PrivateKey: cfg.PrivateKey,
+		PublicKey:  cfg.PublicKey,
 	}
+}
 
-	return client.NewAccessoryClient(
+func pairingClient(c *client.ControllerIdentity, accPairing *config.AccessoryPairing) *client.AccessoryClient {
+	accClient := client.NewAccessoryClient(
 		client.NewIPDialer(),
-		&client.ControllerIdentity{
-			DeviceID:   cfg.DeviceID,
-			PrivateKey: cfg.PrivateKey,
-			PublicKey:  cfg.PublicKey,
-		},
+		c,
 		&client.AccessoryConnectionConfig{
 			DeviceID:         accPairing.DeviceID,
 			PublicKey:        accPairing.PublicKey,
 			IPConnectionInfo: accPairing.IPConnectionInfo,
+			Model:            accPairing.Model,
 		},
-	), nil
+	)
+	accClient.SetQuirks(append(append([]*client.Quirks(nil), accessoryQuirks...), client.DefaultQuirks...))
+	if traceMessages {
+		accClient.SetTrace(client.NewTraceWriter(os.Stderr))
+	}
+
+	return accClient
 }
 
-func parseCharacteristicIDs(charateristicIDParam string) (accID, chID uint64, err error) {
-	parts := strings.Split(charateristicIDParam, ".")
-	if len(parts) != 2 {
-		return 0, 0, fmt.Errorf("invalid characteristicID format %s, expect x.x", charateristicIDParam)
+// characteristicClient is the part of client.AccessoryClient used to read and write
+// characteristics.
+type characteristicClient interface {
+	Accessories(ctx context.Context) ([]*client.RawAccessory, error)
+	Characteristics(ctx context.Context, readReq *client.CharacteristicsReadRequest) ([]*client.CharacteristicReadResponse, error)
+	SetCharacteristics(ctx context.Context, writeReq *client.CharacteristicsWriteRequest) ([]*client.CharacteristicWriteResponse, error)
+}
+
+// resolveSelectors resolves each characteristic selector to the characteristics it matches.
+// The accessory database is only fetched if a selector refers to characteristics by name.
+// When writable is set, characteristics matched by name that can't be written are skipped.
+func resolveSelectors(ctx context.Context, accClient characteristicClient, params []string, writable bool) ([][]client.CharacteristicReadRequest, error) {
+	selectors := make([]*client.CharacteristicSelector, 0, len(params))
+	byName := false
+	for _, param := range params {
+		sel, err := client.ParseCharacteristicSelector(param)
+		if err != nil {
+			return nil, err
+		}
+		if _, _, ok := sel.ID(); !ok {
+			byName = true
+		}
+		selectors = append(selectors, sel)
 	}
-	accID, err = strconv.ParseUint(parts[0], 10, 64)
-	if err != nil {
-		return 0, 0, fmt.Errorf("could not parse accessoryID %s: %v", parts[0], err)
+
+	var accessories []*client.RawAccessory
+	if byName {
+		var err error
+		accessories, err = accClient.Accessories(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
-	chID, err = strconv.ParseUint(parts[1], 10, 64)
-	if err != nil {
-		return 0, 0, fmt.Errorf("could not parse charactersticID %s: %v", parts[1], err)
+
+	resolved := make([][]client.CharacteristicReadRequest, 0, len(selectors))
+	for _, sel := range selectors {
+		if accID, chID, ok := sel.ID(); ok {
+			resolved = append(resolved, []client.CharacteristicReadRequest{
+				{AccessoryID: accID, CharacteristicID: chID},
+			})
+			continue
+		}
+
+		var reqs []client.CharacteristicReadRequest
+		for _, match := range sel.Select(accessories) {
+			if writable && !match.Characteristic.HasPermission(characteristic.PermissionPairedWrite) {
+				continue
+			}
+			reqs = append(reqs, client.CharacteristicReadRequest{
+				AccessoryID:      match.AccessoryID,
+				CharacteristicID: match.Characteristic.ID,
+			})
+		}
+		if len(reqs) == 0 {
+			return nil, fmt.Errorf("no characteristics match %s", sel)
+		}
+		resolved = append(resolved, reqs)
 	}
-	return accID, chID, nil
+
+	return resolved, nil
 }
 
 func parsePublicKey(key string) ([]byte, error) {
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const bundlePrefix = "homekit-pairing-v1:"
+
+// PairingBundle packages an accessory pairing so it can be shared with another controller.
+// The bundle is signed by the exporting controller and is only accepted if the importer
+// trusts the signer's public key, so a bundle can't be altered without the signer's
+// private key.
+//
+// Bundles aren't encrypted to the receiving controller. They only contain public
+// information about the accessory that doesn't grant access to it by itself.
+type PairingBundle struct {
+	Pairing         *AccessoryPairing
+	SignerID        string
+	SignerPublicKey []byte
+	Signature       []byte
+}
+
+// EncodePairingBundle returns a signed, text encoded bundle of the accessory pairing that can
+// be imported by another controller using DecodePairingBundle.
+func EncodePairingBundle(cfg *ControllerConfig, pairing *AccessoryPairing) (string, error) {
+	if len(cfg.PrivateKey) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("invalid controller private key size %d", len(cfg.PrivateKey))
+	}
+
+	pairingData, err := json.Marshal(pairing)
+	if err != nil {
+		return "", fmt.Errorf("marshal pairing: %v", err)
+	}
+
+	bundle := PairingBundle{
+		Pairing:         pairing,
+		SignerID:        cfg.DeviceID,
+		SignerPublicKey: cfg.PublicKey,
+		Signature:       ed25519.Sign(ed25519.PrivateKey(cfg.PrivateKey), pairingData),
+	}
+
+	bundleData, err := json.Marshal(&bundle)
+	if err != nil {
+		return "", fmt.Errorf("marshal bundle: %v", err)
+	}
+
+	return bundlePrefix + base64.RawURLEncoding.EncodeToString(bundleData), nil
+}
+
+// DecodePairingBundle parses a bundle created by EncodePairingBundle and verifies it's
+// signed by one of the trustedKeys.
+func DecodePairingBundle(encoded string, trustedKeys [][]byte) (*PairingBundle, error) {
+	encoded = strings.TrimSpace(encoded)
+	if !strings.HasPrefix(encoded, bundlePrefix) {
+		return nil, errors.New("not a pairing bundle")
+	}
+
+	bundleData, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(encoded, bundlePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("decode bundle: %v", err)
+	}
+
+	var bundle PairingBundle
+	if err := json.Unmarshal(bundleData, &bundle); err != nil {
+		return nil, fmt.Errorf("parse bundle: %v", err)
+	}
+
+	if bundle.Pairing == nil {
+		return nil, errors.New("bundle is missing pairing")
+	}
+
+	pairingData, err := json.Marshal(bundle.Pairing)
+	if err != nil {
+		return nil, fmt.Errorf("marshal pairing: %v", err)
+	}
+
+	if len(bundle.SignerPublicKey) != ed25519.PublicKeySize ||
+		!ed25519.Verify(ed25519.PublicKey(bundle.SignerPublicKey), pairingData, bundle.Signature) {
+		return nil, errors.New("bundle signature is invalid")
+	}
+
+	trusted := false
+	for _, key := range trustedKeys {
+		if bytes.Equal(key, bundle.SignerPublicKey) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return nil, fmt.Errorf("bundle is signed by untrusted controller %s with key %s",
+			bundle.SignerID, base64.StdEncoding.EncodeToString(bundle.SignerPublicKey))
+	}
+
+	return &bundle, nil
+}
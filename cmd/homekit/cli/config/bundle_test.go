@@ -0,0 +1,47 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPairingBundle(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	cfg := controllerConfig("t")
+	cfg.PublicKey = publicKey
+	cfg.PrivateKey = privateKey
+
+	encoded, err := EncodePairingBundle(cfg, cfg.AccessoryPairings[0])
+	require.NoError(t, err)
+
+	bundle, err := DecodePairingBundle(encoded, [][]byte{publicKey})
+	require.NoError(t, err)
+	require.Equal(t, cfg.AccessoryPairings[0], bundle.Pairing)
+	require.Equal(t, cfg.DeviceID, bundle.SignerID)
+
+	// tamper with the encoded pairing
+	_, err = DecodePairingBundle(strings.Replace(encoded, encoded[len(encoded)-8:len(encoded)-4], "AAAA", 1), [][]byte{publicKey})
+	require.Error(t, err)
+
+	// a modified bundle re-signed with another key isn't trusted
+	otherPublicKey, otherPrivateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	other := controllerConfig("other")
+	other.PublicKey = otherPublicKey
+	other.PrivateKey = otherPrivateKey
+	other.AccessoryPairings[0].IPConnectionInfo.IPAddress = "10.0.0.66"
+	forged, err := EncodePairingBundle(other, other.AccessoryPairings[0])
+	require.NoError(t, err)
+	_, err = DecodePairingBundle(forged, [][]byte{publicKey})
+	require.EqualError(t, err, "bundle is signed by untrusted controller "+other.DeviceID+
+		" with key "+base64.StdEncoding.EncodeToString(otherPublicKey))
+
+	_, err = DecodePairingBundle("something else", [][]byte{publicKey})
+	require.Error(t, err)
+}
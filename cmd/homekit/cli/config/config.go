@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/mctofu/homekit/client"
 )
@@ -18,6 +19,9 @@ type ControllerConfig struct {
 	PublicKey         []byte
 	PrivateKey        []byte
 	AccessoryPairings []*AccessoryPairing
+	Scenes            []*Scene `json:",omitempty"`
+	// GatewayToken is the bearer token required by the serve command.
+	GatewayToken string `json:",omitempty"`
 }
 
 // AccessoryPairing details a paired accessory
@@ -78,6 +82,34 @@ func SaveControllerConfig(configPath string, cfg *ControllerConfig, overwrite bo
 	return nil
 }
 
+// ListControllerConfigs returns the names of all controller configs stored under the
+// configPath directory.
+func ListControllerConfigs(configPath string) ([]string, error) {
+	entries, err := ioutil.ReadDir(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+
+	return names, nil
+}
+
+// DeleteControllerConfig removes the config for the controller with the given name
+// from the configPath directory.
+func DeleteControllerConfig(configPath string, name string) error {
+	return os.Remove(path.Join(configPath, name+".json"))
+}
+
 // WriteConfig writes data to path but first writes it to a temporary file
 // to avoid errors where the write fails & the data from the original config
 // is lost.
@@ -26,6 +26,30 @@ func TestConfigSaveAndRead(t *testing.T) {
 	require.Error(t, SaveControllerConfig(cfgPath, controllerConfig("a"), false))
 }
 
+func TestConfigListAndDelete(t *testing.T) {
+	cfgPath := t.TempDir()
+
+	names, err := ListControllerConfigs(cfgPath)
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	require.NoError(t, SaveControllerConfig(cfgPath, controllerConfig("t"), false))
+	other := controllerConfig("t")
+	other.Name = "other"
+	require.NoError(t, SaveControllerConfig(cfgPath, other, false))
+
+	names, err = ListControllerConfigs(cfgPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"other", "test-controller"}, names)
+
+	require.NoError(t, DeleteControllerConfig(cfgPath, "other"))
+	names, err = ListControllerConfigs(cfgPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"test-controller"}, names)
+
+	require.Error(t, DeleteControllerConfig(cfgPath, "other"))
+}
+
 func controllerConfig(model string) *ControllerConfig {
 	return &ControllerConfig{
 		DeviceID:   "dID",
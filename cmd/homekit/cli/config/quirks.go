@@ -0,0 +1,43 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/mctofu/homekit/client"
+	"gopkg.in/yaml.v3"
+)
+
+// quirksFile holds quirks of accessories in addition to client.DefaultQuirks.
+const quirksFile = "quirks.yaml"
+
+// ReadQuirks reads the quirk definitions stored under the configPath directory. Nil is returned
+// if there aren't any.
+func ReadQuirks(configPath string) ([]*client.Quirks, error) {
+	data, err := ioutil.ReadFile(path.Join(configPath, quirksFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// unknown keys are rejected so misspelled or unsupported quirks aren't silently ignored
+	var quirks []*client.Quirks
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&quirks); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("parse quirks: %v", err)
+	}
+	for _, q := range quirks {
+		if err := q.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return quirks, nil
+}
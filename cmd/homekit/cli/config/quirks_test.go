@@ -0,0 +1,58 @@
+package config
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadQuirks(t *testing.T) {
+	configPath := t.TempDir()
+
+	quirks, err := ReadQuirks(configPath)
+	require.NoError(t, err)
+	require.Nil(t, quirks)
+
+	require.NoError(t, ioutil.WriteFile(path.Join(configPath, quirksFile), []byte(`
+- name: Hub
+  manufacturer: Acme
+  model: Hub 2
+  queryEncoding: escaped
+  idleTimeout: 30s
+  statusCodes:
+    400: 207
+`), 0600))
+
+	quirks, err = ReadQuirks(configPath)
+	require.NoError(t, err)
+	require.Equal(t, []*client.Quirks{{
+		Name:          "Hub",
+		Manufacturer:  "Acme",
+		Model:         "Hub 2",
+		QueryEncoding: client.QueryEncodingEscaped,
+		IdleTimeout:   30 * time.Second,
+		StatusCodes:   map[int]int{400: 207},
+	}}, quirks)
+
+	// the quirks must identify the accessories they apply to
+	require.NoError(t, ioutil.WriteFile(path.Join(configPath, quirksFile), []byte(`- name: Any`), 0600))
+	_, err = ReadQuirks(configPath)
+	require.EqualError(t, err, "quirks Any must match a manufacturer or model")
+
+	// requests are always serialized so there's no quirk limiting them
+	require.NoError(t, ioutil.WriteFile(path.Join(configPath, quirksFile), []byte(`
+- name: Hub
+  manufacturer: Acme
+  maxConcurrentRequests: 1
+`), 0600))
+	_, err = ReadQuirks(configPath)
+	require.ErrorContains(t, err, "field maxConcurrentRequests not found")
+
+	names, err := ListControllerConfigs(configPath)
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
@@ -0,0 +1,74 @@
+package config
+
+import "github.com/mctofu/homekit/client/characteristic"
+
+// Scene is a named snapshot of characteristic values across one or more paired accessories
+type Scene struct {
+	Name   string
+	Values []*SceneValue
+}
+
+// SceneValue is the value of a characteristic of a paired accessory captured by a scene
+type SceneValue struct {
+	Accessory        string
+	AccessoryID      uint64
+	CharacteristicID uint64
+	Type             string
+	Value            characteristic.Value
+}
+
+// FindScene returns the scene with the given name or nil if not found.
+func (c *ControllerConfig) FindScene(name string) *Scene {
+	for _, scene := range c.Scenes {
+		if scene.Name == name {
+			return scene
+		}
+	}
+
+	return nil
+}
+
+// SaveScene adds the scene to the config replacing any scene with the same name.
+func (c *ControllerConfig) SaveScene(scene *Scene) {
+	for i, s := range c.Scenes {
+		if s.Name == scene.Name {
+			c.Scenes[i] = scene
+			return
+		}
+	}
+
+	c.Scenes = append(c.Scenes, scene)
+}
+
+// RemoveScene removes the scene with the given name. False is returned if the scene
+// was not found.
+func (c *ControllerConfig) RemoveScene(name string) bool {
+	for i, s := range c.Scenes {
+		if s.Name == name {
+			c.Scenes = append(c.Scenes[:i], c.Scenes[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Merge adds the values to the scene replacing any existing values for the same
+// characteristics.
+func (s *Scene) Merge(values ...*SceneValue) {
+	for _, v := range values {
+		replaced := false
+		for i, existing := range s.Values {
+			if existing.Accessory == v.Accessory &&
+				existing.AccessoryID == v.AccessoryID &&
+				existing.CharacteristicID == v.CharacteristicID {
+				s.Values[i] = v
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.Values = append(s.Values, v)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenes(t *testing.T) {
+	cfgPath := t.TempDir()
+	cfg := controllerConfig("t")
+
+	scene := &Scene{Name: "evening"}
+	scene.Merge(
+		&SceneValue{Accessory: "alias", AccessoryID: 2, CharacteristicID: 11, Type: "7C", Value: characteristic.Value("0")},
+		&SceneValue{Accessory: "alias", AccessoryID: 3, CharacteristicID: 11, Type: "7C", Value: characteristic.Value("0")},
+	)
+	scene.Merge(
+		&SceneValue{Accessory: "alias", AccessoryID: 3, CharacteristicID: 11, Type: "7C", Value: characteristic.Value("50")},
+	)
+	require.Len(t, scene.Values, 2)
+	require.Equal(t, characteristic.Value("50"), scene.Values[1].Value)
+
+	cfg.SaveScene(scene)
+	cfg.SaveScene(&Scene{Name: "morning"})
+	require.NoError(t, SaveControllerConfig(cfgPath, cfg, false))
+
+	readCfg, err := ReadControllerConfig(cfgPath, cfg.Name)
+	require.NoError(t, err)
+	require.Equal(t, cfg, readCfg)
+	require.Equal(t, scene, readCfg.FindScene("evening"))
+
+	readCfg.SaveScene(&Scene{Name: "evening"})
+	require.Len(t, readCfg.Scenes, 2)
+	require.Empty(t, readCfg.FindScene("evening").Values)
+
+	require.True(t, readCfg.RemoveScene("evening"))
+	require.False(t, readCfg.RemoveScene("evening"))
+	require.Nil(t, readCfg.FindScene("evening"))
+	require.NotNil(t, readCfg.FindScene("morning"))
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+)
+
+// Files holding vendor characteristic and service types in the schema of the characteristics.yaml
+// and services.yaml the built in types are generated from.
+const (
+	characteristicTypesFile = "characteristics.yaml"
+	serviceTypesFile        = "services.yaml"
+)
+
+// ReadCharacteristicTypes reads the characteristic types defined under the configPath
+// directory. Nil is returned if there aren't any.
+func ReadCharacteristicTypes(configPath string) ([]*characteristic.TypeMetadata, error) {
+	data, err := ioutil.ReadFile(path.Join(configPath, characteristicTypesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return characteristic.ReadTypes(bytes.NewReader(data))
+}
+
+// ReadServiceTypes reads the service types defined under the configPath directory. Nil is
+// returned if there aren't any.
+func ReadServiceTypes(configPath string) ([]*service.TypeMetadata, error) {
+	data, err := ioutil.ReadFile(path.Join(configPath, serviceTypesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return service.ReadTypes(bytes.NewReader(data))
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTypes(t *testing.T) {
+	configPath := t.TempDir()
+
+	chTypes, err := ReadCharacteristicTypes(configPath)
+	require.NoError(t, err)
+	require.Nil(t, chTypes)
+	svcTypes, err := ReadServiceTypes(configPath)
+	require.NoError(t, err)
+	require.Nil(t, svcTypes)
+
+	require.NoError(t, ioutil.WriteFile(path.Join(configPath, characteristicTypesFile), []byte(`
+- name: Eve Voltage
+  uuid: E863F10A-079E-48FF-8F27-9C2605A29F52
+  format: float
+  unit: V
+`), 0600))
+	require.NoError(t, ioutil.WriteFile(path.Join(configPath, serviceTypesFile), []byte(`
+- name: Eve Power Meter
+  uuid: E863F008-079E-48FF-8F27-9C2605A29F52
+  requiredCharacteristics:
+    - E863F10A-079E-48FF-8F27-9C2605A29F52
+  optionalCharacteristics:
+    - 00000023-0000-1000-8000-0026BB765291
+`), 0600))
+
+	chTypes, err = ReadCharacteristicTypes(configPath)
+	require.NoError(t, err)
+	require.Len(t, chTypes, 1)
+	require.Equal(t, "EveVoltage", chTypes[0].Name)
+	require.Equal(t, "float", chTypes[0].Format)
+
+	svcTypes, err = ReadServiceTypes(configPath)
+	require.NoError(t, err)
+	require.Len(t, svcTypes, 1)
+	require.Equal(t, "E863F008-079E-48FF-8F27-9C2605A29F52", svcTypes[0].Type)
+	require.Equal(t, "EvePowerMeter", svcTypes[0].Name)
+	require.Equal(t, []string{"E863F10A-079E-48FF-8F27-9C2605A29F52"}, svcTypes[0].RequiredCharacteristics)
+	require.Equal(t, []string{"23"}, svcTypes[0].OptionalCharacteristics)
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func controllerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "controller",
+		Short: "Manage controller profiles",
+	}
+
+	cmd.AddCommand(controllerShowCmd())
+	cmd.AddCommand(controllerListCmd())
+	cmd.AddCommand(controllerRotateCmd())
+	cmd.AddCommand(controllerDeleteCmd())
+
+	return cmd
+}
+
+func controllerShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the identity of a controller profile",
+	}
+
+	cmd.RunE = configCommandRunner(cmd, controllerShow)
+
+	return cmd
+}
+
+// controllerOutput is the output schema of a controller profile shown by the controller commands.
+type controllerOutput struct {
+	Name              string `json:"name" yaml:"name"`
+	DeviceID          string `json:"deviceId" yaml:"deviceId"`
+	PublicKeyHex      string `json:"publicKeyHex" yaml:"publicKeyHex"`
+	PublicKeyBase64   string `json:"publicKeyBase64" yaml:"publicKeyBase64"`
+	PairedAccessories int    `json:"pairedAccessories" yaml:"pairedAccessories"`
+}
+
+func newControllerOutput(cfg *config.ControllerConfig) controllerOutput {
+	return controllerOutput{
+		Name:              cfg.Name,
+		DeviceID:          cfg.DeviceID,
+		PublicKeyHex:      hex.EncodeToString(cfg.PublicKey),
+		PublicKeyBase64:   base64.StdEncoding.EncodeToString(cfg.PublicKey),
+		PairedAccessories: len(cfg.AccessoryPairings),
+	}
+}
+
+type controllersOutput []controllerOutput
+
+func (c controllersOutput) csvHeader() []string {
+	return []string{"name", "deviceId", "publicKeyHex", "publicKeyBase64", "pairedAccessories"}
+}
+
+func (c controllersOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(c))
+	for _, ctrl := range c {
+		records = append(records, []string{
+			ctrl.Name,
+			ctrl.DeviceID,
+			ctrl.PublicKeyHex,
+			ctrl.PublicKeyBase64,
+			strconv.Itoa(ctrl.PairedAccessories),
+		})
+	}
+	return records
+}
+
+func (c controllerOutput) csvHeader() []string {
+	return controllersOutput{c}.csvHeader()
+}
+
+func (c controllerOutput) csvRecords() [][]string {
+	return controllersOutput{c}.csvRecords()
+}
+
+func controllerShow(ctx context.Context, configPath, controllerName string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	ctrl := newControllerOutput(cfg)
+
+	return render(ctrl, func(w io.Writer) {
+		fmt.Fprintf(w, "Controller: %s\n", ctrl.Name)
+		fmt.Fprintf(w, "ID: %s\n", ctrl.DeviceID)
+		fmt.Fprintf(w, "Public Key (hex): %s\n", ctrl.PublicKeyHex)
+		fmt.Fprintf(w, "Public Key (base64): %s\n", ctrl.PublicKeyBase64)
+		fmt.Fprintf(w, "Paired accessories: %d\n", ctrl.PairedAccessories)
+	})
+}
+
+func controllerListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List controller profiles",
+	}
+
+	cmd.RunE = configCommandRunner(cmd, controllerList)
+
+	return cmd
+}
+
+func controllerList(ctx context.Context, configPath, controllerName string) error {
+	names, err := config.ListControllerConfigs(configPath)
+	if err != nil {
+		return fmt.Errorf("list controller configs: %v", err)
+	}
+
+	controllers := make(controllersOutput, 0, len(names))
+	for _, name := range names {
+		cfg, err := config.ReadControllerConfig(configPath, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: <invalid: %v>\n", name, err)
+			continue
+		}
+		controllers = append(controllers, newControllerOutput(cfg))
+	}
+
+	return render(controllers, func(w io.Writer) {
+		if len(controllers) == 0 {
+			fmt.Fprintln(w, "No controllers")
+			return
+		}
+
+		for _, ctrl := range controllers {
+			fmt.Fprintf(w, "%s (%s) %d paired accessories\n", ctrl.Name, ctrl.DeviceID, ctrl.PairedAccessories)
+		}
+	})
+}
+
+func controllerDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a controller profile",
+	}
+
+	force := cmd.Flags().Bool("force", false, "Delete even if accessories are still paired")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			return controllerDelete(configPath, controllerName, *force)
+		},
+	)
+
+	return cmd
+}
+
+func controllerDelete(configPath, controllerName string, force bool) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	if len(cfg.AccessoryPairings) > 0 && !force {
+		return errors.New("controller still has paired accessories which will be inaccessible once its " +
+			"keys are deleted. Unpair them first or use --force")
+	}
+
+	if err := config.DeleteControllerConfig(configPath, controllerName); err != nil {
+		return err
+	}
+
+	return renderMessage("Deleted controller %s", controllerName)
+}
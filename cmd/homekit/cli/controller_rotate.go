@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func controllerRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace a controller's identity on every paired accessory",
+		Long: "Generate a new identity for the controller, add it as an admin on every paired " +
+			"accessory and then remove the old identity.\n\n" +
+			"If the new identity can't be added to every accessory or the updated config can't be " +
+			"saved the rotation is rolled back.",
+	}
+
+	cmd.RunE = configCommandRunner(cmd, controllerRotate)
+
+	return cmd
+}
+
+func controllerRotate(ctx context.Context, configPath, controllerName string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	oldIdentity := controllerIdentity(cfg)
+	newIdentity, err := client.NewRandomControllerConfig()
+	if err != nil {
+		return fmt.Errorf("NewRandomControllerConfig: %v", err)
+	}
+
+	var added []*config.AccessoryPairing
+	for _, accPairing := range cfg.AccessoryPairings {
+		if err := addRotatedIdentity(ctx, oldIdentity, newIdentity, accPairing); err != nil {
+			fmt.Fprintf(progressWriter(), "%s: failed to add new identity: %v\n", accPairing.Name, err)
+
+			if rollbackErr := rollbackRotation(ctx, oldIdentity, newIdentity, added); rollbackErr != nil {
+				return fmt.Errorf("rotation aborted and rollback failed: %v", rollbackErr)
+			}
+
+			return fmt.Errorf("rotation aborted: %s: %v", accPairing.Name, err)
+		}
+		fmt.Fprintf(progressWriter(), "%s: added new identity\n", accPairing.Name)
+		added = append(added, accPairing)
+	}
+
+	cfg.DeviceID = newIdentity.DeviceID
+	cfg.PublicKey = newIdentity.PublicKey
+	cfg.PrivateKey = newIdentity.PrivateKey
+
+	if err := config.SaveControllerConfig(configPath, cfg, true); err != nil {
+		// The new private key is lost with the config so its pairings would be orphaned.
+		if rollbackErr := rollbackRotation(ctx, oldIdentity, newIdentity, added); rollbackErr != nil {
+			return fmt.Errorf("save controller config: %v (rollback failed: %v)", err, rollbackErr)
+		}
+		return fmt.Errorf("save controller config: %v", err)
+	}
+
+	// The old identity is only removed once the new one is safely stored. A failure here
+	// leaves behind an unused pairing but doesn't lose access.
+	var removeErr error
+	for _, accPairing := range cfg.AccessoryPairings {
+		if err := removeIdentity(ctx, newIdentity, oldIdentity.DeviceID, accPairing); err != nil {
+			fmt.Fprintf(progressWriter(), "%s: failed to remove old identity: %v\n", accPairing.Name, err)
+			removeErr = multierror.Append(removeErr, fmt.Errorf("%s: %v", accPairing.Name, err))
+			continue
+		}
+		fmt.Fprintf(progressWriter(), "%s: removed old identity\n", accPairing.Name)
+	}
+
+	if removeErr != nil {
+		return fmt.Errorf("controller %s rotated to %s but old identity %s is still paired with some accessories: %v",
+			cfg.Name, cfg.DeviceID, oldIdentity.DeviceID, removeErr)
+	}
+
+	return renderMessage("Controller %s rotated to %s", cfg.Name, cfg.DeviceID)
+}
+
+// addRotatedIdentity adds newIdentity as an admin to the accessory and confirms it can
+// connect. The new identity is removed again if it can't connect.
+func addRotatedIdentity(
+	ctx context.Context,
+	oldIdentity, newIdentity *client.ControllerIdentity,
+	accPairing *config.AccessoryPairing,
+) (rErr error) {
+	accClient := pairingClient(oldIdentity, accPairing)
+	defer func() {
+		if cErr := accClient.Close(); cErr != nil {
+			rErr = multierror.Append(rErr, cErr)
+		}
+	}()
+
+	if err := accClient.AddPairing(ctx, &client.AddPairingRequest{
+		DeviceID:    newIdentity.DeviceID,
+		PublicKey:   newIdentity.PublicKey,
+		Permissions: client.PermissionAdmin,
+	}); err != nil {
+		return fmt.Errorf("addPairing: %v", err)
+	}
+
+	newClient := pairingClient(newIdentity, accPairing)
+	defer func() {
+		if cErr := newClient.Close(); cErr != nil {
+			rErr = multierror.Append(rErr, cErr)
+		}
+	}()
+
+	if _, err := newClient.Accessories(ctx); err != nil {
+		if rmErr := accClient.RemovePairing(ctx, newIdentity.DeviceID); rmErr != nil {
+			return fmt.Errorf("connect with new identity: %v (remove new identity: %v)", err, rmErr)
+		}
+		return fmt.Errorf("connect with new identity: %v", err)
+	}
+
+	return nil
+}
+
+// rollbackRotation removes newIdentity from the accessories it was added to.
+func rollbackRotation(
+	ctx context.Context,
+	oldIdentity, newIdentity *client.ControllerIdentity,
+	added []*config.AccessoryPairing,
+) error {
+	var rollbackErr error
+	for _, accPairing := range added {
+		if err := removeIdentity(ctx, oldIdentity, newIdentity.DeviceID, accPairing); err != nil {
+			rollbackErr = multierror.Append(rollbackErr, fmt.Errorf("%s: %v", accPairing.Name, err))
+		}
+	}
+
+	return rollbackErr
+}
+
+// removeIdentity connects to the accessory as identity and removes the pairing for
+// the controller with deviceID.
+func removeIdentity(
+	ctx context.Context,
+	identity *client.ControllerIdentity,
+	deviceID string,
+	accPairing *config.AccessoryPairing,
+) (rErr error) {
+	accClient := pairingClient(identity, accPairing)
+	defer func() {
+		if cErr := accClient.Close(); cErr != nil {
+			rErr = multierror.Append(rErr, cErr)
+		}
+	}()
+
+	return accClient.RemovePairing(ctx, deviceID)
+}
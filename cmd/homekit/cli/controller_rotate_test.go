@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRotatedIdentityRemovesUnverifiedIdentity(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server, err := hapfake.NewServer(hapfake.Config{})
+	require.NoError(t, err)
+	defer server.Close()
+
+	oldIdentity, err := client.NewRandomControllerConfig()
+	require.NoError(t, err)
+	connInfo := client.IPConnectionInfo{IPAddress: server.Host(), Port: server.Port()}
+	connCfg, err := client.NewSetupClient(&http.Client{}).Pair(ctx,
+		&client.AccessoryPairingConfig{
+			IPConnectionInfo: connInfo,
+			PIN:              server.PIN(),
+			DeviceID:         server.DeviceID(),
+		},
+		oldIdentity,
+	)
+	require.NoError(t, err)
+	accPairing := &config.AccessoryPairing{
+		Name:             "fake",
+		DeviceID:         connCfg.DeviceID,
+		PublicKey:        connCfg.PublicKey,
+		IPConnectionInfo: connCfg.IPConnectionInfo,
+	}
+
+	newIdentity, err := client.NewRandomControllerConfig()
+	require.NoError(t, err)
+
+	// the connection check with the new identity fails after it's added
+	server.Fail(hapfake.Failure{Method: http.MethodGet, Path: "/accessories", StatusCode: http.StatusInternalServerError, Count: 1})
+	require.Error(t, addRotatedIdentity(ctx, oldIdentity, newIdentity, accPairing))
+
+	pairings := server.Pairings()
+	require.Len(t, pairings, 1)
+	require.Equal(t, oldIdentity.DeviceID, pairings[0].ControllerID)
+}
@@ -36,7 +36,5 @@ func createController(ctx context.Context, configPath, controllerName string) er
 		return err
 	}
 
-	fmt.Printf("Created controller %s\n", controllerName)
-
-	return nil
+	return renderMessage("Created controller %s", controllerName)
 }
@@ -3,6 +3,9 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mctofu/homekit/client"
@@ -24,11 +27,71 @@ func discoverCmd() *cobra.Command {
 	return cmd
 }
 
+// discoveredDevice is the output schema of a device found by the discover command.
+type discoveredDevice struct {
+	Name                    string   `json:"name" yaml:"name"`
+	Model                   string   `json:"model" yaml:"model"`
+	ID                      string   `json:"id" yaml:"id"`
+	IPs                     []string `json:"ips" yaml:"ips"`
+	Port                    int      `json:"port" yaml:"port"`
+	FeatureFlags            byte     `json:"featureFlags" yaml:"featureFlags"`
+	FeatureFlagsDescription string   `json:"featureFlagsDescription" yaml:"featureFlagsDescription"`
+	StatusFlags             byte     `json:"statusFlags" yaml:"statusFlags"`
+	StatusFlagsDescription  string   `json:"statusFlagsDescription" yaml:"statusFlagsDescription"`
+	Paired                  bool     `json:"paired" yaml:"paired"`
+}
+
+func newDiscoveredDevice(d *client.AccessoryDevice) discoveredDevice {
+	ips := make([]string, 0, len(d.IPs))
+	for _, ip := range d.IPs {
+		ips = append(ips, ip.String())
+	}
+
+	return discoveredDevice{
+		Name:                    d.Name,
+		Model:                   d.Model,
+		ID:                      d.ID,
+		IPs:                     ips,
+		Port:                    d.Port,
+		FeatureFlags:            byte(d.FeatureFlags),
+		FeatureFlagsDescription: d.FeatureFlags.String(),
+		StatusFlags:             byte(d.StatusFlags),
+		StatusFlagsDescription:  d.StatusFlags.String(),
+		Paired:                  d.StatusFlags.Paired(),
+	}
+}
+
+type discoveredDevices []discoveredDevice
+
+func (d discoveredDevices) csvHeader() []string {
+	return []string{"name", "model", "id", "ips", "port", "featureFlags", "statusFlags", "paired"}
+}
+
+func (d discoveredDevices) csvRecords() [][]string {
+	records := make([][]string, 0, len(d))
+	for _, dev := range d {
+		records = append(records, []string{
+			dev.Name,
+			dev.Model,
+			dev.ID,
+			strings.Join(dev.IPs, " "),
+			strconv.Itoa(dev.Port),
+			strconv.Itoa(int(dev.FeatureFlags)),
+			strconv.Itoa(int(dev.StatusFlags)),
+			strconv.FormatBool(dev.Paired),
+		})
+	}
+	return records
+}
+
 func discover(ctx context.Context, timeout int) error {
-	var found uint64
+	found := discoveredDevices{}
 
 	foundFn := func(ctx context.Context, d *client.AccessoryDevice) {
-		found++
+		found = append(found, newDiscoveredDevice(d))
+		if structuredOutput() {
+			return
+		}
 		fmt.Printf("Detected Device: %s\n", d.Name)
 		fmt.Printf("Model: %s\n", d.Model)
 		fmt.Printf("ID: %s\n", d.ID)
@@ -43,7 +106,7 @@ func discover(ctx context.Context, timeout int) error {
 		return err
 	}
 
-	fmt.Printf("Found %d devices\n", found)
-
-	return nil
+	return render(found, func(w io.Writer) {
+		fmt.Fprintf(w, "Found %d devices\n", len(found))
+	})
 }
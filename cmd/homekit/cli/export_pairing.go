@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func exportPairingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exportPairing",
+		Short: "Export a pairing as a bundle that can be imported by another controller",
+		Long: "Export a pairing as a bundle that can be imported by another controller.\n\n" +
+			"The receiving controller must also be added to the accessory using addPairing before it can connect.",
+	}
+
+	name := cmd.Flags().StringP("name", "n", "", "Name of accessory to export")
+	markFlagRequired(cmd, "name")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			return exportPairing(configPath, controllerName, *name)
+		},
+	)
+
+	return cmd
+}
+
+// pairingImportOutput is the output schema of commands that share an accessory pairing
+// with another controller.
+type pairingImportOutput struct {
+	Name            string `json:"name" yaml:"name"`
+	DeviceID        string `json:"deviceId" yaml:"deviceId"`
+	PublicKey       string `json:"publicKey" yaml:"publicKey"`
+	Bundle          string `json:"bundle" yaml:"bundle"`
+	SignerPublicKey string `json:"signerPublicKey" yaml:"signerPublicKey"`
+}
+
+func (p pairingImportOutput) csvHeader() []string {
+	return []string{"name", "deviceId", "publicKey", "bundle", "signerPublicKey"}
+}
+
+func (p pairingImportOutput) csvRecords() [][]string {
+	return [][]string{{p.Name, p.DeviceID, p.PublicKey, p.Bundle, p.SignerPublicKey}}
+}
+
+func newPairingImportOutput(cfg *config.ControllerConfig, accPairing *config.AccessoryPairing) (pairingImportOutput, error) {
+	bundle, err := config.EncodePairingBundle(cfg, accPairing)
+	if err != nil {
+		return pairingImportOutput{}, fmt.Errorf("encode bundle: %v", err)
+	}
+
+	return pairingImportOutput{
+		Name:            accPairing.Name,
+		DeviceID:        accPairing.DeviceID,
+		PublicKey:       base64.StdEncoding.EncodeToString(accPairing.PublicKey),
+		Bundle:          bundle,
+		SignerPublicKey: base64.StdEncoding.EncodeToString(cfg.PublicKey),
+	}, nil
+}
+
+// renderPairingImport prints the details another controller needs to import the pairing.
+func renderPairingImport(cfg *config.ControllerConfig, accPairing *config.AccessoryPairing, heading string) error {
+	out, err := newPairingImportOutput(cfg, accPairing)
+	if err != nil {
+		return err
+	}
+
+	return render(out, func(w io.Writer) {
+		fmt.Fprintln(w, heading)
+		fmt.Fprintf(w, "ID: %s\n", out.DeviceID)
+		fmt.Fprintf(w, "Key: %s\n", out.PublicKey)
+		fmt.Fprintf(w, "Bundle: %s\n", out.Bundle)
+		fmt.Fprintf(w, "Signer: %s\n", out.SignerPublicKey)
+	})
+}
+
+func exportPairing(configPath, controllerName, name string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	accPairing := findAccessoryPairing(cfg, name)
+	if accPairing == nil {
+		return fmt.Errorf("accessory %s not found", name)
+	}
+
+	out, err := newPairingImportOutput(cfg, accPairing)
+	if err != nil {
+		return err
+	}
+
+	return render(out, func(w io.Writer) {
+		fmt.Fprintln(w, out.Bundle)
+	})
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/mctofu/homekit/cmd/homekit/cli/exporter"
+	"github.com/spf13/cobra"
+)
+
+func exporterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exporter",
+		Short: "Export sensor characteristics as Prometheus metrics",
+		Long: "Serve numeric characteristics of paired accessories as Prometheus metrics until interrupted.\n\n" +
+			"Temperature, humidity, air quality, light level, battery level and position characteristics are " +
+			"exported by default. Connection health metrics are exported for each accessory.",
+	}
+
+	listen := cmd.Flags().String("listen", ":9101", "Address to listen on")
+	names := cmd.Flags().StringArrayP("name", "n", nil, "Name of accessory to export. Defaults to all paired accessories.")
+	all := cmd.Flags().Bool("all", false, "Export every numeric characteristic")
+	pollInterval := cmd.Flags().Duration("poll", time.Minute, "Interval to read characteristics that don't support events")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			return export(ctx, configPath, controllerName, *listen, *names, *all, *pollInterval)
+		},
+	)
+
+	return cmd
+}
+
+func export(ctx context.Context, configPath, controllerName, listen string, names []string, all bool, pollInterval time.Duration) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	if len(names) == 0 {
+		for _, pair := range cfg.AccessoryPairings {
+			names = append(names, pair.Name)
+		}
+	}
+
+	accessories := make(map[string]client.Accessory)
+	for _, name := range names {
+		accClient, err := accessoryClient(cfg, name)
+		if err != nil {
+			return err
+		}
+		defer accClient.Close()
+		accessories[name] = accClient
+	}
+
+	logger := log.New(progressWriter(), "", log.LstdFlags)
+	exp := exporter.NewExporter(accessories, exporter.Config{
+		AllNumeric:   all,
+		PollInterval: pollInterval,
+		Logger:       logger,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", exp)
+	server := &http.Server{
+		Addr:     listen,
+		Handler:  mux,
+		ErrorLog: logger,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	go func() {
+		errs <- exp.Run(ctx)
+	}()
+	logger.Printf("exporting %d accessories on %s/metrics", len(accessories), listen)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			return err
+		}
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %v", err)
+	}
+
+	return nil
+}
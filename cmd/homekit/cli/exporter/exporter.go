@@ -0,0 +1,460 @@
+package exporter
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+	"github.com/mctofu/homekit/client/units"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// statsAccessory is implemented by accessories that report connection statistics.
+type statsAccessory interface {
+	ConnectionStats() client.ConnectionStats
+}
+
+// DefaultTypes are the characteristic types exported by default.
+var DefaultTypes = []string{
+	characteristic.TypeCurrentTemperature,
+	characteristic.TypeTargetTemperature,
+	characteristic.TypeCurrentRelativeHumidity,
+	characteristic.TypeCarbonDioxideLevel,
+	characteristic.TypeCarbonMonoxideLevel,
+	characteristic.TypeAirQuality,
+	characteristic.TypeCurrentAmbientLightLevel,
+	characteristic.TypeBatteryLevel,
+	characteristic.TypeCurrentPosition,
+	characteristic.TypeTargetPosition,
+}
+
+// Config configures an Exporter.
+type Config struct {
+	// Types are the characteristic types to export. Defaults to DefaultTypes. Characteristics
+	// must be numeric and readable to be exported.
+	Types []string
+	// AllNumeric exports every numeric readable characteristic instead of Types.
+	AllNumeric bool
+	// PollInterval is how often characteristics that don't support events are read.
+	// Defaults to 1 minute.
+	PollInterval time.Duration
+	// ReconnectDelay is how long to wait before retrying a failed accessory connection.
+	// Defaults to client.DefaultReconnectDelay.
+	ReconnectDelay time.Duration
+	Logger         *log.Logger
+}
+
+// Exporter reads numeric characteristics of accessories and serves them as Prometheus gauges
+// named after the characteristic type and unit. Gauges are labelled by accessory alias, serial
+// number, service name and characteristic name.
+type Exporter struct {
+	accessories map[string]*client.LockedAccessory
+	cfg         Config
+	types       map[string]bool
+
+	registry      *prometheus.Registry
+	handler       http.Handler
+	up            *prometheus.GaugeVec
+	requestErrors *prometheus.CounterVec
+
+	mu     sync.Mutex
+	gauges map[string]*prometheus.GaugeVec
+}
+
+// characteristicLabels are the labels of characteristic gauges.
+var characteristicLabels = []string{"accessory", "serial", "service", "characteristic"}
+
+// NewExporter returns an exporter for the accessories keyed by alias.
+func NewExporter(accessories map[string]client.Accessory, cfg Config) *Exporter {
+	if len(cfg.Types) == 0 {
+		cfg.Types = DefaultTypes
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = client.DefaultReconnectDelay
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(ioutil.Discard, "", 0)
+	}
+
+	types := make(map[string]bool, len(cfg.Types))
+	for _, t := range cfg.Types {
+		types[t] = true
+	}
+
+	e := &Exporter{
+		accessories: make(map[string]*client.LockedAccessory, len(accessories)),
+		cfg:         cfg,
+		types:       types,
+		registry:    prometheus.NewRegistry(),
+		up: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "homekit_up",
+			Help: "Whether the last request to the accessory succeeded",
+		}, []string{"accessory"}),
+		requestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "homekit_request_errors_total",
+			Help: "Failed requests to the accessory",
+		}, []string{"accessory"}),
+		gauges: make(map[string]*prometheus.GaugeVec),
+	}
+	for name, acc := range accessories {
+		e.accessories[name] = client.NewLockedAccessory(acc)
+		e.up.WithLabelValues(name).Set(0)
+		e.requestErrors.WithLabelValues(name).Add(0)
+	}
+
+	e.registry.MustRegister(e.up, e.requestErrors, &statsCollector{accessories: e.accessories})
+	e.handler = promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+
+	return e
+}
+
+// Run reads the accessories' characteristics until ctx is cancelled.
+func (e *Exporter) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for alias, acc := range e.accessories {
+		wg.Add(1)
+		go func(alias string, acc *client.LockedAccessory) {
+			defer wg.Done()
+			e.runAccessory(ctx, alias, acc)
+		}(alias, acc)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// exported is a characteristic read by the exporter.
+type exported struct {
+	id      client.CharacteristicReadRequest
+	name    string
+	help    string
+	labels  prometheus.Labels
+	evented bool
+}
+
+func (e *Exporter) runAccessory(ctx context.Context, alias string, acc *client.LockedAccessory) {
+	var db []*client.RawAccessory
+	for {
+		var err error
+		db, err = acc.Accessories(ctx)
+		e.recordRequest(alias, err)
+		if err == nil {
+			break
+		}
+		e.cfg.Logger.Printf("%s: read accessories: %v", alias, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.cfg.ReconnectDelay):
+		}
+	}
+
+	chars := e.exportedCharacteristics(alias, db)
+	byID := make(map[client.CharacteristicReadRequest]*exported, len(chars))
+	var evented, polled []client.CharacteristicReadRequest
+	for _, c := range chars {
+		byID[c.id] = c
+		if c.evented {
+			evented = append(evented, c.id)
+		} else {
+			polled = append(polled, c.id)
+		}
+	}
+
+	for _, acc := range db {
+		for _, svc := range acc.Services {
+			for _, ch := range svc.Characteristics {
+				if c := byID[client.CharacteristicReadRequest{AccessoryID: acc.ID, CharacteristicID: ch.ID}]; c != nil {
+					e.record(c, ch.Value)
+				}
+			}
+		}
+	}
+
+	if len(evented) > 0 {
+		done := make(chan struct{})
+		defer func() { <-done }()
+		go func() {
+			defer close(done)
+			e.subscribe(ctx, alias, acc, evented, byID)
+		}()
+	}
+
+	if len(polled) == 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(e.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.poll(ctx, alias, acc, polled, byID)
+		}
+	}
+}
+
+// subscribe keeps an event subscription to the accessory open until ctx is cancelled.
+func (e *Exporter) subscribe(ctx context.Context, alias string, acc client.Accessory, ids []client.CharacteristicReadRequest, byID map[client.CharacteristicReadRequest]*exported) {
+	handler := func(event *client.CharacteristicEvent) {
+		// the subscription is healthy again once events are received
+		e.recordRequest(alias, nil)
+		if c := byID[client.CharacteristicReadRequest{AccessoryID: event.AccessoryID, CharacteristicID: event.CharacteristicID}]; c != nil {
+			e.record(c, event.Value)
+		}
+	}
+
+	sub := &recordingAccessory{Accessory: acc, exporter: e, alias: alias}
+	client.SubscribeWithRetry(ctx, sub, ids, handler, e.cfg.ReconnectDelay, e.cfg.Logger, alias)
+}
+
+// recordingAccessory records the result of event subscriptions in homekit_up and
+// homekit_request_errors_total.
+type recordingAccessory struct {
+	client.Accessory
+	exporter *Exporter
+	alias    string
+}
+
+func (r *recordingAccessory) Subscribe(ctx context.Context, ids []client.CharacteristicReadRequest, handler client.EventHandler) error {
+	err := r.Accessory.Subscribe(ctx, ids, handler)
+	if ctx.Err() == nil {
+		r.exporter.recordRequest(r.alias, err)
+	}
+	return err
+}
+
+func (e *Exporter) poll(ctx context.Context, alias string, acc client.Accessory, ids []client.CharacteristicReadRequest, byID map[client.CharacteristicReadRequest]*exported) {
+	resps, err := acc.Characteristics(ctx, &client.CharacteristicsReadRequest{Characteristics: ids})
+	e.recordRequest(alias, err)
+	if err != nil {
+		e.cfg.Logger.Printf("%s: read characteristics: %v", alias, err)
+		return
+	}
+
+	for _, resp := range resps {
+		if resp.Status != nil && *resp.Status != 0 {
+			continue
+		}
+		if c := byID[client.CharacteristicReadRequest{AccessoryID: resp.AccessoryID, CharacteristicID: resp.CharacteristicID}]; c != nil {
+			e.record(c, resp.Value)
+		}
+	}
+}
+
+// exportedCharacteristics returns the numeric readable characteristics of the accessories that
+// should be exported.
+func (e *Exporter) exportedCharacteristics(alias string, db []*client.RawAccessory) []*exported {
+	var chars []*exported
+	for _, acc := range db {
+		serial := ""
+		if infoSvc := acc.ServiceByType(service.TypeAccessoryInformation); infoSvc != nil {
+			if c := infoSvc.CharacteristicByType(characteristic.TypeSerialNumber); c != nil {
+				serial, _ = c.Value.String()
+			}
+		}
+
+		for _, svc := range acc.Services {
+			svcName := svc.Name()
+			if svcName == "" {
+				svcName = service.NameForType(svc.Type)
+			}
+
+			for _, ch := range svc.Characteristics {
+				if !ch.HasPermission(characteristic.PermissionPairedRead) || !characteristic.IsNumericFormat(ch.ValueFormat()) {
+					continue
+				}
+				if !e.cfg.AllNumeric && !e.types[ch.Type] {
+					continue
+				}
+
+				charName := characteristic.NameForType(ch.Type)
+				if charName == "<Unknown>" {
+					charName = ch.Type
+				}
+
+				name, unit := metricName(charName, characteristicUnit(ch))
+				help := charName
+				if unit != "" {
+					help += " in " + unit
+				}
+
+				chars = append(chars, &exported{
+					id:   client.CharacteristicReadRequest{AccessoryID: acc.ID, CharacteristicID: ch.ID},
+					name: name,
+					help: help,
+					labels: prometheus.Labels{
+						"accessory":      alias,
+						"serial":         serial,
+						"service":        svcName,
+						"characteristic": charName,
+					},
+					evented: ch.HasPermission(characteristic.PermissionEvents),
+				})
+			}
+		}
+	}
+
+	return chars
+}
+
+func (e *Exporter) record(c *exported, v characteristic.Value) {
+	f, err := v.Float64()
+	if err != nil {
+		// some accessories report numeric characteristics as bools
+		b, bErr := v.Bool()
+		if bErr != nil {
+			return
+		}
+		if b {
+			f = 1
+		}
+	}
+
+	e.gauge(c).With(c.labels).Set(f)
+}
+
+// gauge returns the gauge of a characteristic, registering it if it's the first
+// characteristic with its name.
+func (e *Exporter) gauge(c *exported) *prometheus.GaugeVec {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	g, ok := e.gauges[c.name]
+	if !ok {
+		g = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: c.name, Help: c.help}, characteristicLabels)
+		e.registry.MustRegister(g)
+		e.gauges[c.name] = g
+	}
+
+	return g
+}
+
+func (e *Exporter) recordRequest(alias string, err error) {
+	if err != nil {
+		e.up.WithLabelValues(alias).Set(0)
+		e.requestErrors.WithLabelValues(alias).Inc()
+		return
+	}
+	e.up.WithLabelValues(alias).Set(1)
+}
+
+// ServeHTTP serves the metrics in the Prometheus exposition format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.handler.ServeHTTP(w, r)
+}
+
+var (
+	verifyDurationDesc = prometheus.NewDesc(
+		"homekit_verify_duration_seconds",
+		"Time spent in pair-verify establishing secure connections",
+		[]string{"accessory"}, nil,
+	)
+	reconnectsDesc = prometheus.NewDesc(
+		"homekit_reconnects_total",
+		"Secure connections established after the first",
+		[]string{"accessory"}, nil,
+	)
+	connectionFailuresDesc = prometheus.NewDesc(
+		"homekit_connection_failures_total",
+		"Failed attempts to establish a secure connection",
+		[]string{"accessory"}, nil,
+	)
+)
+
+// statsCollector collects the connection statistics of accessories that report them.
+type statsCollector struct {
+	accessories map[string]*client.LockedAccessory
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- verifyDurationDesc
+	ch <- reconnectsDesc
+	ch <- connectionFailuresDesc
+}
+
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	for alias, acc := range c.accessories {
+		sa, ok := acc.Accessory.(statsAccessory)
+		if !ok {
+			continue
+		}
+		s := sa.ConnectionStats()
+
+		reconnects := uint64(0)
+		if s.Connections > 1 {
+			reconnects = s.Connections - 1
+		}
+
+		ch <- prometheus.MustNewConstSummary(verifyDurationDesc, s.Connections, s.VerifyDuration.Seconds(), nil, alias)
+		ch <- prometheus.MustNewConstMetric(reconnectsDesc, prometheus.CounterValue, float64(reconnects), alias)
+		ch <- prometheus.MustNewConstMetric(connectionFailuresDesc, prometheus.CounterValue, float64(s.Failures), alias)
+	}
+}
+
+// unitSuffixes maps the units of characteristics to Prometheus metric name suffixes.
+var unitSuffixes = map[units.Unit]string{
+	units.Celsius:                 "celsius",
+	units.Percentage:              "percent",
+	units.ArcDegrees:              "degrees",
+	units.Lux:                     "lux",
+	units.Seconds:                 "seconds",
+	units.PartsPerMillion:         "ppm",
+	units.MicrogramsPerCubicMeter: "micrograms_per_cubic_meter",
+}
+
+// characteristicUnit returns the unit reported for a characteristic or the unit of its type if
+// the accessory doesn't report one, so metrics are named the same for every accessory.
+func characteristicUnit(ch *characteristic.RawCharacteristic) units.Unit {
+	if ch.Unit != "" {
+		return units.Unit(ch.Unit)
+	}
+	return characteristic.UnitForType(ch.Type)
+}
+
+// metricName returns the metric name for a characteristic such as homekit_current_temperature_celsius
+// and the unit suffix used.
+func metricName(charName string, unit units.Unit) (string, string) {
+	var b strings.Builder
+	b.WriteString("homekit_")
+	prevLower := false
+	for _, r := range charName {
+		switch {
+		case unicode.IsUpper(r):
+			if prevLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			prevLower = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			prevLower = true
+		default:
+			b.WriteByte('_')
+			prevLower = false
+		}
+	}
+
+	suffix := unitSuffixes[unit]
+	if suffix != "" {
+		b.WriteString("_" + suffix)
+	}
+
+	return b.String(), suffix
+}
@@ -0,0 +1,173 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brutella/hc/db"
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/client/hapfake/hapfaketest"
+	"github.com/mctofu/homekit/client/units"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccessories = `[
+  {
+    "aid": 1,
+    "services": [
+      {"iid": 1, "type": "3E", "characteristics": [
+        {"iid": 2, "type": "23", "format": "string", "value": "Velux", "perms": ["pr"]},
+        {"iid": 3, "type": "30", "format": "string", "value": "AB12", "perms": ["pr"]}
+      ]},
+      {"iid": 8, "type": "97", "characteristics": [
+        {"iid": 9, "type": "23", "format": "string", "value": "Air", "perms": ["pr"]},
+        {"iid": 10, "type": "93", "format": "float", "value": 800, "perms": ["pr", "ev"]},
+        {"iid": 11, "type": "11", "format": "float", "unit": "celsius", "value": 21.5, "perms": ["pr"]}
+      ]},
+      {"iid": 12, "type": "8B", "characteristics": [
+        {"iid": 13, "type": "23", "format": "string", "value": "Roof \"Window\"", "perms": ["pr"]},
+        {"iid": 14, "type": "7C", "format": "uint8", "unit": "percentage", "value": 0, "perms": ["pr", "pw", "ev"]},
+        {"iid": 15, "type": "72", "format": "uint8", "value": 2, "perms": ["pr", "ev"]}
+      ]}
+    ]
+  }
+]`
+
+func runExporter(t *testing.T) (*hapfake.Server, *Exporter, func()) {
+	acc, accClient := hapfaketest.NewClient(t, testAccessories)
+	exporter := NewExporter(map[string]client.Accessory{"velux": accClient}, Config{PollInterval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	done := make(chan error)
+	go func() {
+		done <- exporter.Run(ctx)
+	}()
+
+	return acc, exporter, func() {
+		cancel()
+		require.Equal(t, context.Canceled, <-done)
+	}
+}
+
+func metrics(e *Exporter) string {
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	return w.Body.String()
+}
+
+func TestExporter(t *testing.T) {
+	acc, exporter, stop := runExporter(t)
+	defer stop()
+
+	// initial values come from the attribute database
+	require.Eventually(t, func() bool {
+		return strings.Contains(metrics(exporter), `homekit_carbon_dioxide_level_ppm{accessory="velux",characteristic="CarbonDioxideLevel",serial="AB12",service="Air"} 800`)
+	}, time.Second, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool { return acc.Subscriptions(1, 10) > 0 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, acc.SetValue(1, 10, 1250))
+	require.NoError(t, acc.SetValue(1, 11, 22))
+
+	// temperature doesn't support events so it's polled
+	var out string
+	require.Eventually(t, func() bool {
+		out = metrics(exporter)
+		return strings.Contains(out, `homekit_carbon_dioxide_level_ppm{accessory="velux",characteristic="CarbonDioxideLevel",serial="AB12",service="Air"} 1250`) &&
+			strings.Contains(out, `homekit_current_temperature_celsius{accessory="velux",characteristic="CurrentTemperature",serial="AB12",service="Air"} 22`)
+	}, time.Second, 5*time.Millisecond)
+
+	require.Contains(t, out, "# HELP homekit_carbon_dioxide_level_ppm CarbonDioxideLevel in ppm\n# TYPE homekit_carbon_dioxide_level_ppm gauge\n")
+	require.Contains(t, out, "# HELP homekit_current_temperature_celsius CurrentTemperature in celsius\n")
+	require.Contains(t, out, `homekit_target_position_percent{accessory="velux",characteristic="TargetPosition",serial="AB12",service="Roof \"Window\""} 0`)
+	// not a default type
+	require.NotContains(t, out, "homekit_position_state")
+
+	require.Contains(t, out, `homekit_up{accessory="velux"} 1`)
+	require.Contains(t, out, `homekit_request_errors_total{accessory="velux"} 0`)
+	require.Contains(t, out, `homekit_verify_duration_seconds_sum{accessory="velux"} `)
+	require.Contains(t, out, `homekit_verify_duration_seconds_count{accessory="velux"} 1`)
+	require.Contains(t, out, `homekit_reconnects_total{accessory="velux"} 0`)
+	require.Contains(t, out, `homekit_connection_failures_total{accessory="velux"} 0`)
+}
+
+func TestExporterRequestErrors(t *testing.T) {
+	acc, exporter, stop := runExporter(t)
+	defer stop()
+
+	acc.Fail(hapfake.Failure{Method: http.MethodGet, Path: "/characteristics", StatusCode: http.StatusServiceUnavailable})
+
+	require.Eventually(t, func() bool {
+		out := metrics(exporter)
+		return strings.Contains(out, `homekit_up{accessory="velux"} 0`) &&
+			!strings.Contains(out, `homekit_request_errors_total{accessory="velux"} 0`)
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestExporterSubscriptionRecovers(t *testing.T) {
+	database, err := db.NewDatabase(t.TempDir())
+	require.NoError(t, err)
+	acc := hapfaketest.NewServer(t, testAccessories, hapfake.Config{Database: database})
+	controller, connCfg := hapfaketest.Pair(t, acc)
+	accClient := client.NewAccessoryClient(client.NewIPDialer(), controller, connCfg)
+	defer accClient.Close()
+
+	// only evented characteristics are exported so nothing is polled
+	exporter := NewExporter(map[string]client.Accessory{"velux": accClient}, Config{
+		Types:          []string{characteristic.TypeCarbonDioxideLevel},
+		ReconnectDelay: 10 * time.Millisecond,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- exporter.Run(ctx)
+	}()
+
+	up := func(v string) func() bool {
+		return func() bool {
+			return strings.Contains(metrics(exporter), `homekit_up{accessory="velux"} `+v)
+		}
+	}
+	require.Eventually(t, func() bool { return acc.Subscriptions(1, 10) > 0 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, up("1"), time.Second, 5*time.Millisecond)
+
+	require.NoError(t, acc.Close())
+	require.Eventually(t, up("0"), time.Second, 5*time.Millisecond)
+
+	// the accessory comes back on the same address with the same pairings
+	acc = hapfaketest.NewServer(t, testAccessories, hapfake.Config{Addr: acc.Addr(), Database: database})
+	require.Eventually(t, func() bool { return acc.Subscriptions(1, 10) > 0 }, 2*time.Second, 5*time.Millisecond)
+	require.NoError(t, acc.SetValue(1, 10, 900))
+	require.Eventually(t, up("1"), time.Second, 5*time.Millisecond)
+	require.Contains(t, metrics(exporter), `homekit_carbon_dioxide_level_ppm{accessory="velux",characteristic="CarbonDioxideLevel",serial="AB12",service="Air"} 900`)
+
+	cancel()
+	require.Equal(t, context.Canceled, <-done)
+}
+
+func TestMetricName(t *testing.T) {
+	name, unit := metricName("CurrentRelativeHumidity", "percentage")
+	require.Equal(t, "homekit_current_relative_humidity_percent", name)
+	require.Equal(t, "percent", unit)
+
+	name, unit = metricName("PM2.5Density", units.MicrogramsPerCubicMeter)
+	require.Equal(t, "homekit_pm2_5_density_micrograms_per_cubic_meter", name)
+	require.Equal(t, "micrograms_per_cubic_meter", unit)
+
+	name, unit = metricName("PositionState", "")
+	require.Equal(t, "homekit_position_state", name)
+	require.Equal(t, "", unit)
+}
+
+func TestCharacteristicUnit(t *testing.T) {
+	// the unit of the type is used if the accessory omits it
+	require.Equal(t, units.PartsPerMillion, characteristicUnit(&characteristic.RawCharacteristic{Type: characteristic.TypeCarbonDioxideLevel}))
+	require.Equal(t, units.Celsius, characteristicUnit(&characteristic.RawCharacteristic{Type: characteristic.TypeCurrentTemperature, Unit: "celsius"}))
+	require.Equal(t, units.Unit(""), characteristicUnit(&characteristic.RawCharacteristic{Type: characteristic.TypePositionState}))
+}
@@ -0,0 +1,434 @@
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+)
+
+// Server exposes paired accessories over HTTP:
+//
+//	GET /accessories                                   aliases of the paired accessories
+//	GET /accessories/{alias}                           cached attribute database
+//	GET /accessories/{alias}/characteristics/{aid.iid} read a characteristic
+//	PUT /accessories/{alias}/characteristics/{aid.iid} write a characteristic: {"value": ...}
+//	GET /accessories/{alias}/events                    Server-Sent Events of characteristic changes
+//
+// Every request must include the bearer token in the Authorization header.
+type Server struct {
+	accessories map[string]*accessory
+	token       string
+	logger      *log.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// accessory serializes requests to a long lived client since it isn't thread safe, caches
+// its attribute database and shares a single event subscription between event streams.
+type accessory struct {
+	name   string
+	client *client.LockedAccessory
+
+	dbMu sync.Mutex
+	db   []*client.RawAccessory
+
+	listenersMu sync.Mutex
+	listeners   map[chan *client.CharacteristicEvent]bool
+	subscribed  bool
+}
+
+// NewServer returns a server for the accessories keyed by alias. Requests are authorized with
+// the bearer token.
+func NewServer(accessories map[string]client.Accessory, token string, logger *log.Logger) *Server {
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", 0)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		accessories: make(map[string]*accessory, len(accessories)),
+		token:       token,
+		logger:      logger,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	for name, acc := range accessories {
+		s.accessories[name] = &accessory{
+			name:      name,
+			client:    client.NewLockedAccessory(acc),
+			listeners: make(map[chan *client.CharacteristicEvent]bool),
+		}
+	}
+
+	return s
+}
+
+// Close stops the event subscriptions.
+func (s *Server) Close() {
+	s.cancel()
+}
+
+// Handler returns the http handler serving the gateway's endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /accessories", s.listAccessories)
+	mux.HandleFunc("GET /accessories/{alias}", s.getAccessory)
+	mux.HandleFunc("GET /accessories/{alias}/characteristics/{id}", s.getCharacteristic)
+	mux.HandleFunc("PUT /accessories/{alias}/characteristics/{id}", s.putCharacteristic)
+	mux.HandleFunc("GET /accessories/{alias}/events", s.events)
+
+	return s.authorize(mux)
+}
+
+func (s *Server) authorize(next http.Handler) http.Handler {
+	expected := []byte("Bearer " + s.token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) listAccessories(w http.ResponseWriter, r *http.Request) {
+	aliases := make([]string, 0, len(s.accessories))
+	for name := range s.accessories {
+		aliases = append(aliases, name)
+	}
+	sort.Strings(aliases)
+
+	writeJSON(w, http.StatusOK, struct {
+		Accessories []string `json:"accessories"`
+	}{aliases})
+}
+
+func (s *Server) getAccessory(w http.ResponseWriter, r *http.Request) {
+	acc, ok := s.accessory(w, r)
+	if !ok {
+		return
+	}
+
+	db, err := acc.attributeDatabase(r.Context(), r.URL.Query().Get("refresh") != "")
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Accessories []*client.RawAccessory `json:"accessories"`
+	}{db})
+}
+
+// characteristicResponse is the result of reading a characteristic.
+type characteristicResponse struct {
+	AccessoryID      uint64               `json:"aid"`
+	CharacteristicID uint64               `json:"iid"`
+	Type             string               `json:"type,omitempty"`
+	TypeName         string               `json:"typeName,omitempty"`
+	Format           string               `json:"format,omitempty"`
+	Unit             string               `json:"unit,omitempty"`
+	Value            characteristic.Value `json:"value"`
+	Status           int                  `json:"status"`
+}
+
+func (s *Server) getCharacteristic(w http.ResponseWriter, r *http.Request) {
+	acc, ok := s.accessory(w, r)
+	if !ok {
+		return
+	}
+	id, ok := characteristicID(w, r)
+	if !ok {
+		return
+	}
+
+	resps, err := acc.client.Characteristics(r.Context(), &client.CharacteristicsReadRequest{
+		Characteristics: []client.CharacteristicReadRequest{id},
+		Metadata:        true,
+		Type:            true,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if len(resps) != 1 {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("expected 1 characteristic, got %d", len(resps)))
+		return
+	}
+
+	resp := resps[0]
+	out := characteristicResponse{
+		AccessoryID:      resp.AccessoryID,
+		CharacteristicID: resp.CharacteristicID,
+		Value:            resp.Value,
+	}
+	if resp.Type != nil {
+		out.Type = *resp.Type
+		out.TypeName = characteristic.NameForType(out.Type)
+		out.Format = characteristic.FormatForType(out.Type)
+	}
+	if resp.Format != nil {
+		out.Format = *resp.Format
+	}
+	if resp.Unit != nil {
+		out.Unit = *resp.Unit
+	}
+	if resp.Status != nil {
+		out.Status = *resp.Status
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+// writeResponse is the result of writing a characteristic.
+type writeResponse struct {
+	AccessoryID      uint64 `json:"aid"`
+	CharacteristicID uint64 `json:"iid"`
+	Status           int    `json:"status"`
+}
+
+func (s *Server) putCharacteristic(w http.ResponseWriter, r *http.Request) {
+	acc, ok := s.accessory(w, r)
+	if !ok {
+		return
+	}
+	id, ok := characteristicID(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Value characteristic.Value `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decode body: %v", err))
+		return
+	}
+	if body.Value == nil {
+		writeError(w, http.StatusBadRequest, errors.New("value is required"))
+		return
+	}
+
+	resps, err := acc.client.SetCharacteristics(r.Context(), &client.CharacteristicsWriteRequest{
+		Characteristics: []client.CharacteristicWriteRequest{
+			{AccessoryID: id.AccessoryID, CharacteristicID: id.CharacteristicID, Value: body.Value},
+		},
+	})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	out := writeResponse{AccessoryID: id.AccessoryID, CharacteristicID: id.CharacteristicID}
+	for _, resp := range resps {
+		if resp.Status != nil {
+			out.Status = *resp.Status
+		}
+	}
+
+	status := http.StatusOK
+	if out.Status != 0 {
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, out)
+}
+
+// events streams characteristic events as Server-Sent Events. Characteristics can be filtered
+// with repeated c=aid.iid query parameters.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	acc, ok := s.accessory(w, r)
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	filter := make(map[client.CharacteristicReadRequest]bool)
+	for _, c := range r.URL.Query()["c"] {
+		id, err := parseCharacteristicID(c)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		filter[id] = true
+	}
+
+	events, err := s.listen(r.Context(), acc)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	defer acc.removeListener(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		case e := <-events:
+			id := client.CharacteristicReadRequest{AccessoryID: e.AccessoryID, CharacteristicID: e.CharacteristicID}
+			if len(filter) > 0 && !filter[id] {
+				continue
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: characteristic\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// listen registers a listener for the accessory's events and starts the accessory's event
+// subscription if needed.
+func (s *Server) listen(ctx context.Context, acc *accessory) (chan *client.CharacteristicEvent, error) {
+	db, err := acc.attributeDatabase(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *client.CharacteristicEvent, 16)
+
+	acc.listenersMu.Lock()
+	defer acc.listenersMu.Unlock()
+
+	acc.listeners[events] = true
+	if chars := eventCharacteristics(db); !acc.subscribed && len(chars) > 0 {
+		acc.subscribed = true
+		go s.subscribe(acc, chars)
+	}
+
+	return events, nil
+}
+
+// subscribe keeps an event subscription to the accessory open until the server is closed.
+func (s *Server) subscribe(acc *accessory, chars []client.CharacteristicReadRequest) {
+	client.SubscribeWithRetry(s.ctx, acc.client, chars, acc.broadcast, client.DefaultReconnectDelay, s.logger, acc.name)
+}
+
+func (a *accessory) broadcast(e *client.CharacteristicEvent) {
+	a.listenersMu.Lock()
+	defer a.listenersMu.Unlock()
+
+	for l := range a.listeners {
+		select {
+		case l <- e:
+		default:
+			// drop events for slow listeners rather than block the subscription
+		}
+	}
+}
+
+func (a *accessory) removeListener(l chan *client.CharacteristicEvent) {
+	a.listenersMu.Lock()
+	defer a.listenersMu.Unlock()
+
+	delete(a.listeners, l)
+}
+
+// attributeDatabase returns the cached attribute database of the accessory, reading it if it's
+// not cached or a refresh is requested.
+func (a *accessory) attributeDatabase(ctx context.Context, refresh bool) ([]*client.RawAccessory, error) {
+	a.dbMu.Lock()
+	defer a.dbMu.Unlock()
+
+	if a.db != nil && !refresh {
+		return a.db, nil
+	}
+
+	db, err := a.client.Accessories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.db = db
+
+	return db, nil
+}
+
+func eventCharacteristics(db []*client.RawAccessory) []client.CharacteristicReadRequest {
+	var chars []client.CharacteristicReadRequest
+	for _, acc := range db {
+		for _, svc := range acc.Services {
+			for _, ch := range svc.Characteristics {
+				if ch.HasPermission(characteristic.PermissionEvents) {
+					chars = append(chars, client.CharacteristicReadRequest{AccessoryID: acc.ID, CharacteristicID: ch.ID})
+				}
+			}
+		}
+	}
+	return chars
+}
+
+func (s *Server) accessory(w http.ResponseWriter, r *http.Request) (*accessory, bool) {
+	alias := r.PathValue("alias")
+	acc, ok := s.accessories[alias]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("accessory %s not found", alias))
+		return nil, false
+	}
+	return acc, true
+}
+
+func characteristicID(w http.ResponseWriter, r *http.Request) (client.CharacteristicReadRequest, bool) {
+	id, err := parseCharacteristicID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return id, false
+	}
+	return id, true
+}
+
+func parseCharacteristicID(s string) (client.CharacteristicReadRequest, error) {
+	var id client.CharacteristicReadRequest
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return id, fmt.Errorf("invalid characteristicID format %s, expect aid.iid", s)
+	}
+	var err error
+	if id.AccessoryID, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return id, fmt.Errorf("could not parse accessoryID %s: %v", parts[0], err)
+	}
+	if id.CharacteristicID, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+		return id, fmt.Errorf("could not parse characteristicID %s: %v", parts[1], err)
+	}
+	return id, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
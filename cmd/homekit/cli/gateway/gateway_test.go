@@ -0,0 +1,156 @@
+package gateway
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/client/hapfake/hapfaketest"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccessories = `[
+  {
+    "aid": 1,
+    "services": [
+      {"iid": 1, "type": "3E", "characteristics": [
+        {"iid": 2, "type": "23", "format": "string", "value": "Velux", "perms": ["pr"]}
+      ]},
+      {"iid": 12, "type": "8B", "characteristics": [
+        {"iid": 13, "type": "23", "format": "string", "value": "Roof Window", "perms": ["pr"]},
+        {"iid": 14, "type": "7C", "format": "uint8", "value": 0, "perms": ["pr", "pw", "ev"]},
+        {"iid": 15, "type": "6D", "format": "uint8", "value": 0, "perms": ["pr", "ev"]}
+      ]}
+    ]
+  }
+]`
+
+func testServer(t *testing.T) (*hapfake.Server, *httptest.Server, func()) {
+	acc, accClient := hapfaketest.NewClient(t, testAccessories)
+	gw := NewServer(map[string]client.Accessory{"velux": accClient}, "secret", nil)
+	server := httptest.NewServer(gw.Handler())
+
+	return acc, server, func() {
+		server.Close()
+		gw.Close()
+	}
+}
+
+func request(t *testing.T, method, url, token, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode(t *testing.T, resp *http.Response, v interface{}) {
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
+
+func TestAuthorization(t *testing.T) {
+	_, server, closeFn := testServer(t)
+	defer closeFn()
+
+	resp := request(t, http.MethodGet, server.URL+"/accessories", "", "")
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, server.URL+"/accessories", "wrong", "")
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, server.URL+"/accessories", "secret", "")
+	var list struct {
+		Accessories []string `json:"accessories"`
+	}
+	decode(t, resp, &list)
+	require.Equal(t, []string{"velux"}, list.Accessories)
+}
+
+func TestAccessoryCache(t *testing.T) {
+	acc, server, closeFn := testServer(t)
+	defer closeFn()
+
+	for i := 0; i < 2; i++ {
+		resp := request(t, http.MethodGet, server.URL+"/accessories/velux", "secret", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var db struct {
+			Accessories []*client.RawAccessory `json:"accessories"`
+		}
+		decode(t, resp, &db)
+		require.Len(t, db.Accessories, 1)
+
+		// later requests are served from the cache
+		acc.Fail(hapfake.Failure{Method: http.MethodGet, Path: "/accessories", StatusCode: http.StatusInternalServerError})
+	}
+
+	resp := request(t, http.MethodGet, server.URL+"/accessories/velux?refresh=1", "secret", "")
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, server.URL+"/accessories/other", "secret", "")
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestReadWriteCharacteristic(t *testing.T) {
+	_, server, closeFn := testServer(t)
+	defer closeFn()
+
+	url := server.URL + "/accessories/velux/characteristics/1.14"
+
+	resp := request(t, http.MethodPut, url, "secret", `{"value": 100}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var write writeResponse
+	decode(t, resp, &write)
+	require.Equal(t, writeResponse{AccessoryID: 1, CharacteristicID: 14}, write)
+
+	resp = request(t, http.MethodGet, url, "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var read characteristicResponse
+	decode(t, resp, &read)
+	require.Equal(t, "TargetPosition", read.TypeName)
+	require.Equal(t, characteristic.Value("100"), read.Value)
+
+	resp = request(t, http.MethodGet, server.URL+"/accessories/velux/characteristics/1", "secret", "")
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = request(t, http.MethodPut, url, "secret", `{}`)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEvents(t *testing.T) {
+	acc, server, closeFn := testServer(t)
+	defer closeFn()
+
+	resp := request(t, http.MethodGet, server.URL+"/accessories/velux/events?c=1.14", "secret", "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.Eventually(t, func() bool { return acc.Subscriptions(1, 14) > 0 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, acc.SetValue(1, 15, 20))
+	require.NoError(t, acc.SetValue(1, 14, 50))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "event: characteristic\n", line)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, `data: {"aid":1,"iid":14,"value":50}`+"\n", line)
+}
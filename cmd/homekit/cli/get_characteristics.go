@@ -3,9 +3,13 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 
 	"github.com/mctofu/homekit/client"
 	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/units"
 	"github.com/spf13/cobra"
 )
 
@@ -16,32 +20,110 @@ func getCharacteristicsCmd() *cobra.Command {
 		Short:   "Read values of accessory characteristics",
 	}
 
-	characteristicIDs := cmd.Flags().StringArrayP("c", "c", nil, "Characteristic ID (ex: 1.4)")
+	characteristicIDs := cmd.Flags().StringArrayP("c", "c", nil, "Characteristic ID or selector (ex: 1.4, Window/TargetPosition)")
 	markFlagRequired(cmd, "c")
 
 	cmd.RunE = clientCommandRunner(cmd,
 		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
-			return getCharacteristics(ctx, accClient, *characteristicIDs)
+			return getCharacteristics(ctx, os.Stdout, accClient, *characteristicIDs)
 		},
 	)
 
 	return cmd
 }
 
-func getCharacteristics(ctx context.Context, accClient *client.AccessoryClient, characteristicIDs []string) error {
-	var cReqs []client.CharacteristicReadRequest
+// characteristicValueOutput is the output schema of a characteristic read by getCharacteristics.
+type characteristicValueOutput struct {
+	AccessoryID      uint64      `json:"aid" yaml:"aid"`
+	CharacteristicID uint64      `json:"iid" yaml:"iid"`
+	Type             string      `json:"type" yaml:"type"`
+	TypeName         string      `json:"typeName" yaml:"typeName"`
+	Format           string      `json:"format" yaml:"format"`
+	Unit             string      `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Value            interface{} `json:"value" yaml:"value"`
+	Status           int         `json:"status" yaml:"status"`
+}
 
-	for _, cID := range characteristicIDs {
-		accID, chID, err := parseCharacteristicIDs(cID)
-		if err != nil {
-			return err
-		}
+type characteristicValuesOutput []characteristicValueOutput
 
-		cReqs = append(cReqs, client.CharacteristicReadRequest{
-			AccessoryID:      accID,
-			CharacteristicID: chID,
+func (c characteristicValuesOutput) csvHeader() []string {
+	return []string{"aid", "iid", "type", "typeName", "format", "unit", "value", "status"}
+}
+
+func (c characteristicValuesOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(c))
+	for _, ch := range c {
+		records = append(records, []string{
+			strconv.FormatUint(ch.AccessoryID, 10),
+			strconv.FormatUint(ch.CharacteristicID, 10),
+			ch.Type,
+			ch.TypeName,
+			ch.Format,
+			ch.Unit,
+			formatCSVValue(ch.Value),
+			strconv.Itoa(ch.Status),
 		})
 	}
+	return records
+}
+
+func newCharacteristicValueOutput(resp *client.CharacteristicReadResponse) characteristicValueOutput {
+	out := characteristicValueOutput{
+		AccessoryID:      resp.AccessoryID,
+		CharacteristicID: resp.CharacteristicID,
+	}
+	if resp.Type != nil {
+		out.Type = *resp.Type
+		out.TypeName = characteristic.NameForType(out.Type)
+		out.Format = characteristic.FormatForType(out.Type)
+	}
+	// Some accessories such as Velux omit the format from the metadata. Their quirks only
+	// fill it in once Accessories has identified them, which reads by id skip, so the known
+	// format of the type is used when the response doesn't have one.
+	if resp.Format != nil {
+		out.Format = *resp.Format
+	}
+	if resp.Status != nil {
+		out.Status = *resp.Status
+	}
+	value, unit := convertValue(formatOutputValue(out.Format, resp.Value), responseUnit(resp))
+	out.Value = value
+	out.Unit = string(unit)
+
+	return out
+}
+
+// responseUnit returns the unit of a characteristic read with metadata. The unit of the type is
+// used if the accessory doesn't report one.
+func responseUnit(resp *client.CharacteristicReadResponse) units.Unit {
+	var unit, typ string
+	if resp.Unit != nil {
+		unit = *resp.Unit
+	}
+	if resp.Type != nil {
+		typ = *resp.Type
+	}
+	return characteristicUnit(unit, typ)
+}
+
+// getCharacteristics reads the characteristics and renders their values to out.
+func getCharacteristics(ctx context.Context, out io.Writer, accClient characteristicClient, characteristicIDs []string) error {
+	resolved, err := resolveSelectors(ctx, accClient, characteristicIDs, false)
+	if err != nil {
+		return err
+	}
+
+	var cReqs []client.CharacteristicReadRequest
+	seen := make(map[client.CharacteristicReadRequest]bool)
+	for _, reqs := range resolved {
+		for _, req := range reqs {
+			if seen[req] {
+				continue
+			}
+			seen[req] = true
+			cReqs = append(cReqs, req)
+		}
+	}
 
 	req := &client.CharacteristicsReadRequest{
 		Characteristics: cReqs,
@@ -54,13 +136,19 @@ func getCharacteristics(ctx context.Context, accClient *client.AccessoryClient,
 		return err
 	}
 
+	values := make(characteristicValuesOutput, 0, len(resps))
 	for _, resp := range resps {
-		fmt.Printf("%d.%d: %s\n", resp.AccessoryID, resp.CharacteristicID, characteristic.NameForType(*resp.Type))
-		// Velux is not returning format as part of the metadata so we rely on known types
-		// to determine the value format. We can consider getting the format from the
-		// accClient.Accessories response instead.
-		fmt.Printf("Value: %v\n", characteristic.ValueForType(*resp.Type, resp.Value))
+		values = append(values, newCharacteristicValueOutput(resp))
 	}
 
-	return nil
+	return renderTo(out, outputFormat, values, func(w io.Writer) {
+		for i, resp := range resps {
+			fmt.Fprintf(w, "%d.%d: %s\n", resp.AccessoryID, resp.CharacteristicID, values[i].TypeName)
+			value, err := characteristic.ValueForFormat(values[i].Format, resp.Value)
+			if err == nil {
+				value = outputValue(value, resp.Value)
+			}
+			fmt.Fprintf(w, "Value: %s\n", formatTextValue(value, err, responseUnit(resp)))
+		}
+	})
 }
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCharacteristicValueOutputMissingFormat(t *testing.T) {
+	// VELUX accessories don't include the format in the metadata. Their quirks fill it in once
+	// the attribute database identifies the accessory but reading by numeric id doesn't read
+	// the attribute database first.
+	for name, identify := range map[string]bool{"identified": true, "numeric ids": false} {
+		t.Run(name, func(t *testing.T) {
+			exchanges, err := client.LoadExchanges("testdata/missing_format.json")
+			require.NoError(t, err)
+			accClient := client.NewAccessoryClientWithTransport(client.NewReplayTransport(exchanges), client.IPConnectionInfo{})
+
+			if identify {
+				_, err = accClient.Accessories(context.Background())
+				require.NoError(t, err)
+			}
+
+			resps, err := accClient.Characteristics(context.Background(), &client.CharacteristicsReadRequest{
+				Characteristics: []client.CharacteristicReadRequest{
+					{AccessoryID: 1, CharacteristicID: 9},
+					{AccessoryID: 1, CharacteristicID: 10},
+				},
+				Metadata: true,
+				Type:     true,
+			})
+			require.NoError(t, err)
+			require.Len(t, resps, 2)
+
+			position := newCharacteristicValueOutput(resps[0])
+			require.Equal(t, "CurrentPosition", position.TypeName)
+			require.Equal(t, "uint8", position.Format)
+			require.Equal(t, uint8(40), position.Value)
+
+			humidity := newCharacteristicValueOutput(resps[1])
+			require.Equal(t, "float", humidity.Format)
+			require.Equal(t, 52.5, humidity.Value)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func handoffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "handoff",
+		Short: "Transfer a paired accessory to another controller and unpair this controller",
+	}
+
+	deviceID := cmd.Flags().String("id", "", "Device id of controller to hand off to")
+	markFlagRequired(cmd, "id")
+	key := cmd.Flags().String("key", "", "Controller public key in base64 or hex")
+	markFlagRequired(cmd, "key")
+
+	cmd.RunE = clientCommandRunner(cmd,
+		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+			return handoff(ctx, clientCtx, accClient, *deviceID, *key)
+		},
+	)
+
+	return cmd
+}
+
+func handoff(
+	ctx context.Context,
+	clientCtx *clientContext,
+	accClient *client.AccessoryClient,
+	deviceID string,
+	key string,
+) error {
+	cfg := *clientCtx.Config
+
+	if deviceID == cfg.DeviceID {
+		return fmt.Errorf("%s is this controller", deviceID)
+	}
+
+	publicKey, err := parsePublicKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid key: %v", err)
+	}
+
+	accPairing := findAccessoryPairing(&cfg, clientCtx.AccessoryName)
+
+	if err := accClient.AddPairing(ctx, &client.AddPairingRequest{
+		DeviceID:    deviceID,
+		PublicKey:   publicKey,
+		Permissions: client.PermissionAdmin,
+	}); err != nil {
+		return fmt.Errorf("addPairing: %v", err)
+	}
+
+	// Make sure the new controller is in place as an admin before giving up our own access.
+	added, err := findPairing(ctx, accClient, deviceID)
+	if err != nil {
+		return fmt.Errorf("confirm added pairing: %v", err)
+	}
+	if !added.Admin || !bytes.Equal(added.PublicKey, publicKey) {
+		return fmt.Errorf("added pairing for %s does not match the requested admin pairing", deviceID)
+	}
+
+	// The config is saved before our pairing is removed so a failure to save leaves this
+	// controller with access rather than holding a pairing the accessory no longer trusts.
+	removePairings(&cfg, accPairing)
+	if err := config.SaveControllerConfig(clientCtx.ConfigPath, &cfg, true); err != nil {
+		return fmt.Errorf("save controller config: %v", err)
+	}
+
+	removeErr := accClient.RemovePairing(ctx, cfg.DeviceID)
+
+	// the new controller needs the import settings even if our pairing couldn't be removed
+	heading := "Handoff successful. Import these accessory settings on the new controller:"
+	if removeErr != nil {
+		heading = "Handed off but this controller is still paired. Import these accessory settings on the new controller:"
+	}
+	if err := renderPairingImport(&cfg, accPairing, heading); err != nil {
+		return err
+	}
+
+	if removeErr != nil {
+		return fmt.Errorf("removePairing: %v (the new controller can remove %s with removePairing)", removeErr, cfg.DeviceID)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/cmd/homekit/cli/history"
+	"github.com/spf13/cobra"
+)
+
+func historyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Query recorded characteristic values",
+		Long: "Query characteristic values recorded by the record command.\n\n" +
+			"The min, max and average of numeric values are reported for the period or for each bucket " +
+			"of the period. Use --raw to list the recorded values instead.",
+	}
+
+	name := cmd.Flags().StringP("name", "n", "", "Name of accessory to query")
+	markFlagRequired(cmd, "name")
+	characteristicIDs := cmd.Flags().StringArrayP("c", "c", nil, "Characteristic ID or selector (ex: 1.4, Window/TargetPosition). Defaults to all recorded characteristics.")
+	since := cmd.Flags().Duration("since", 24*time.Hour, "Query values recorded within the duration")
+	bucket := cmd.Flags().Duration("bucket", 0, "Aggregate values into buckets of the duration. The whole period is aggregated if 0.")
+	raw := cmd.Flags().Bool("raw", false, "List recorded values instead of aggregating them")
+	dir := cmd.Flags().String("dir", "", "Directory of the history store. Defaults to a directory in the config path.")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			store := history.NewStore(historyDir(configPath, controllerName, *name, *dir))
+			return queryHistory(store, *characteristicIDs, *since, *bucket, *raw)
+		},
+	)
+
+	return cmd
+}
+
+// historySummaryOutput is the output schema of values aggregated by history.
+type historySummaryOutput struct {
+	AccessoryID      uint64    `json:"aid" yaml:"aid"`
+	CharacteristicID uint64    `json:"iid" yaml:"iid"`
+	TypeName         string    `json:"typeName" yaml:"typeName"`
+	Start            time.Time `json:"start" yaml:"start"`
+	End              time.Time `json:"end" yaml:"end"`
+	Count            int       `json:"count" yaml:"count"`
+	Min              float64   `json:"min" yaml:"min"`
+	Max              float64   `json:"max" yaml:"max"`
+	Avg              float64   `json:"avg" yaml:"avg"`
+}
+
+type historySummariesOutput []historySummaryOutput
+
+func (h historySummariesOutput) csvHeader() []string {
+	return []string{"aid", "iid", "typeName", "start", "end", "count", "min", "max", "avg"}
+}
+
+func (h historySummariesOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(h))
+	for _, s := range h {
+		records = append(records, []string{
+			strconv.FormatUint(s.AccessoryID, 10),
+			strconv.FormatUint(s.CharacteristicID, 10),
+			s.TypeName,
+			s.Start.Format(time.RFC3339),
+			s.End.Format(time.RFC3339),
+			strconv.Itoa(s.Count),
+			strconv.FormatFloat(s.Min, 'f', -1, 64),
+			strconv.FormatFloat(s.Max, 'f', -1, 64),
+			strconv.FormatFloat(s.Avg, 'f', -1, 64),
+		})
+	}
+	return records
+}
+
+// historyReadingOutput is the output schema of a value listed by history --raw.
+type historyReadingOutput struct {
+	Time             time.Time   `json:"time" yaml:"time"`
+	AccessoryID      uint64      `json:"aid" yaml:"aid"`
+	CharacteristicID uint64      `json:"iid" yaml:"iid"`
+	TypeName         string      `json:"typeName" yaml:"typeName"`
+	Value            interface{} `json:"value" yaml:"value"`
+}
+
+type historyReadingsOutput []historyReadingOutput
+
+func (h historyReadingsOutput) csvHeader() []string {
+	return []string{"time", "aid", "iid", "typeName", "value"}
+}
+
+func (h historyReadingsOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(h))
+	for _, r := range h {
+		records = append(records, []string{
+			r.Time.Format(time.RFC3339),
+			strconv.FormatUint(r.AccessoryID, 10),
+			strconv.FormatUint(r.CharacteristicID, 10),
+			r.TypeName,
+			formatCSVValue(r.Value),
+		})
+	}
+	return records
+}
+
+func queryHistory(store *history.Store, characteristicIDs []string, since, bucket time.Duration, raw bool) error {
+	db, err := store.Accessories()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no history recorded")
+		}
+		return fmt.Errorf("read recorded accessories: %v", err)
+	}
+
+	ids, err := resolveHistorySelectors(db, characteristicIDs)
+	if err != nil {
+		return err
+	}
+
+	until := time.Now()
+	start := until.Add(-since)
+	readings, err := store.Query(ids, start, until)
+	if err != nil {
+		return fmt.Errorf("query history: %v", err)
+	}
+
+	chars := make(map[client.CharacteristicReadRequest]*characteristic.RawCharacteristic)
+	all := &client.CharacteristicSelector{Characteristic: "*"}
+	for _, c := range all.Select(db) {
+		chars[client.CharacteristicReadRequest{AccessoryID: c.AccessoryID, CharacteristicID: c.Characteristic.ID}] = c.Characteristic
+	}
+	typeName := func(aid, iid uint64) string {
+		if c := chars[client.CharacteristicReadRequest{AccessoryID: aid, CharacteristicID: iid}]; c != nil {
+			return characteristic.NameForType(c.Type)
+		}
+		return ""
+	}
+
+	if raw {
+		out := make(historyReadingsOutput, 0, len(readings))
+		for _, r := range readings {
+			// values are decoded generically since a recorded value may not match the
+			// characteristic's current format
+			value := outputValue(characteristic.UndefinedValue{}, r.Value)
+			out = append(out, historyReadingOutput{
+				Time:             r.Time.Local(),
+				AccessoryID:      r.AccessoryID,
+				CharacteristicID: r.CharacteristicID,
+				TypeName:         typeName(r.AccessoryID, r.CharacteristicID),
+				Value:            value,
+			})
+		}
+
+		return render(out, func(w io.Writer) {
+			for _, r := range out {
+				fmt.Fprintf(w, "%s %d.%d %s: %v\n", r.Time.Format(time.RFC3339), r.AccessoryID, r.CharacteristicID, r.TypeName, r.Value)
+			}
+		})
+	}
+
+	summaries := history.Summarize(readings, start, until, bucket)
+	out := make(historySummariesOutput, 0, len(summaries))
+	for _, s := range summaries {
+		out = append(out, historySummaryOutput{
+			AccessoryID:      s.AccessoryID,
+			CharacteristicID: s.CharacteristicID,
+			TypeName:         typeName(s.AccessoryID, s.CharacteristicID),
+			Start:            s.Start.Local(),
+			End:              s.End.Local(),
+			Count:            s.Count,
+			Min:              s.Min,
+			Max:              s.Max,
+			Avg:              s.Avg,
+		})
+	}
+
+	return render(out, func(w io.Writer) {
+		if len(out) == 0 {
+			fmt.Fprintln(w, "No values recorded")
+			return
+		}
+		for _, s := range out {
+			if bucket > 0 {
+				fmt.Fprintf(w, "%s ", s.Start.Format(time.RFC3339))
+			}
+			fmt.Fprintf(w, "%d.%d %s: min %v max %v avg %.2f (%d values)\n",
+				s.AccessoryID, s.CharacteristicID, s.TypeName, s.Min, s.Max, s.Avg, s.Count)
+		}
+	})
+}
+
+// resolveHistorySelectors resolves characteristic selectors against the recorded accessory
+// database. nil is returned to query all characteristics if there are no selectors.
+func resolveHistorySelectors(db []*client.RawAccessory, params []string) ([]client.CharacteristicReadRequest, error) {
+	var ids []client.CharacteristicReadRequest
+	for _, param := range params {
+		sel, err := client.ParseCharacteristicSelector(param)
+		if err != nil {
+			return nil, err
+		}
+		if accID, chID, ok := sel.ID(); ok {
+			ids = append(ids, client.CharacteristicReadRequest{AccessoryID: accID, CharacteristicID: chID})
+			continue
+		}
+		selected := sel.Select(db)
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no recorded characteristics match %s", param)
+		}
+		for _, c := range selected {
+			ids = append(ids, client.CharacteristicReadRequest{AccessoryID: c.AccessoryID, CharacteristicID: c.Characteristic.ID})
+		}
+	}
+
+	return ids, nil
+}
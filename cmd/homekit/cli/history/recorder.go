@@ -0,0 +1,174 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+)
+
+// RecordConfig configures Record.
+type RecordConfig struct {
+	// Interval is how often characteristics are read. Defaults to 1 minute.
+	Interval time.Duration
+	// Events records characteristics that support events as they change instead of reading them.
+	Events bool
+	// Retention removes readings older than the duration. Readings are kept forever if 0.
+	Retention time.Duration
+	// ReconnectDelay is how long to wait before retrying a failed event subscription.
+	// Defaults to client.DefaultReconnectDelay.
+	ReconnectDelay time.Duration
+	Logger         *log.Logger
+}
+
+// Record appends readings of the characteristics matching the selectors to the store until ctx is
+// cancelled. Readable numeric and bool characteristics are recorded if no selectors are given.
+func Record(ctx context.Context, acc client.Accessory, store *Store, selectors []*client.CharacteristicSelector, cfg RecordConfig) error {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = client.DefaultReconnectDelay
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(ioutil.Discard, "", 0)
+	}
+	acc = client.NewLockedAccessory(acc)
+
+	db, err := acc.Accessories(ctx)
+	if err != nil {
+		return fmt.Errorf("read accessories: %v", err)
+	}
+	if err := store.SaveAccessories(db); err != nil {
+		return fmt.Errorf("save accessories: %v", err)
+	}
+
+	selected, err := recordedCharacteristics(db, selectors)
+	if err != nil {
+		return err
+	}
+
+	var evented, polled []client.CharacteristicReadRequest
+	for _, c := range selected {
+		id := client.CharacteristicReadRequest{AccessoryID: c.AccessoryID, CharacteristicID: c.Characteristic.ID}
+		if cfg.Events && c.Characteristic.HasPermission(characteristic.PermissionEvents) {
+			evented = append(evented, id)
+		} else {
+			polled = append(polled, id)
+		}
+	}
+
+	if len(evented) > 0 {
+		// record the current values since events are only sent on change
+		if err := read(ctx, acc, store, evented); err != nil {
+			cfg.Logger.Printf("read characteristics: %v", err)
+		}
+
+		done := make(chan struct{})
+		defer func() { <-done }()
+		go func() {
+			defer close(done)
+			subscribe(ctx, acc, store, evented, cfg)
+		}()
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if cfg.Retention > 0 {
+			if err := store.Prune(time.Now().Add(-cfg.Retention)); err != nil {
+				cfg.Logger.Printf("prune: %v", err)
+			}
+		}
+		if len(polled) > 0 {
+			if err := read(ctx, acc, store, polled); err != nil {
+				cfg.Logger.Printf("read characteristics: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordedCharacteristics returns the readable characteristics matching the selectors or the
+// readable numeric and bool characteristics if there are no selectors.
+func recordedCharacteristics(db []*client.RawAccessory, selectors []*client.CharacteristicSelector) ([]*client.SelectedCharacteristic, error) {
+	var selected []*client.SelectedCharacteristic
+	if len(selectors) > 0 {
+		var err error
+		selected, err = client.SelectCharacteristics(db, selectors...)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		all := &client.CharacteristicSelector{Characteristic: "*"}
+		for _, c := range all.Select(db) {
+			if format := c.Characteristic.ValueFormat(); format == "bool" || characteristic.IsNumericFormat(format) {
+				selected = append(selected, c)
+			}
+		}
+	}
+
+	var readable []*client.SelectedCharacteristic
+	for _, c := range selected {
+		if c.Characteristic.HasPermission(characteristic.PermissionPairedRead) {
+			readable = append(readable, c)
+		}
+	}
+
+	if len(readable) == 0 {
+		return nil, errors.New("no readable characteristics to record")
+	}
+
+	return readable, nil
+}
+
+func read(ctx context.Context, acc client.Accessory, store *Store, ids []client.CharacteristicReadRequest) error {
+	resps, err := acc.Characteristics(ctx, &client.CharacteristicsReadRequest{Characteristics: ids})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	readings := make([]*Reading, 0, len(resps))
+	for _, resp := range resps {
+		if resp.Status != nil && *resp.Status != 0 {
+			continue
+		}
+		readings = append(readings, &Reading{
+			Time:             now,
+			AccessoryID:      resp.AccessoryID,
+			CharacteristicID: resp.CharacteristicID,
+			Value:            resp.Value,
+		})
+	}
+
+	return store.Append(readings...)
+}
+
+// subscribe records events until ctx is cancelled.
+func subscribe(ctx context.Context, acc client.Accessory, store *Store, ids []client.CharacteristicReadRequest, cfg RecordConfig) {
+	handler := func(e *client.CharacteristicEvent) {
+		err := store.Append(&Reading{
+			Time:             time.Now(),
+			AccessoryID:      e.AccessoryID,
+			CharacteristicID: e.CharacteristicID,
+			Value:            e.Value,
+		})
+		if err != nil {
+			cfg.Logger.Printf("record event: %v", err)
+		}
+	}
+
+	client.SubscribeWithRetry(ctx, acc, ids, handler, cfg.ReconnectDelay, cfg.Logger, "")
+}
@@ -0,0 +1,341 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+)
+
+const (
+	dayFormat      = "2006-01-02"
+	fileExt        = ".jsonl"
+	accessoriesDB  = "accessories.json"
+	maxReadingSize = 1024 * 1024
+)
+
+// Reading is a characteristic value recorded at a point in time.
+type Reading struct {
+	Time             time.Time            `json:"t"`
+	AccessoryID      uint64               `json:"aid"`
+	CharacteristicID uint64               `json:"iid"`
+	Value            characteristic.Value `json:"value"`
+}
+
+// Store appends readings of a single accessory to JSON lines files in a directory. A file is
+// used per UTC day so old readings can be pruned by removing files.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore returns a store that keeps its files in dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Append writes the readings to the files for their days.
+func (s *Store) Append(readings ...*Reading) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	byDay := make(map[string][]*Reading)
+	var days []string
+	for _, r := range readings {
+		day := r.Time.UTC().Format(dayFormat)
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], r)
+	}
+
+	for _, day := range days {
+		if err := s.appendDay(day, byDay[day]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) appendDay(day string, readings []*Reading) (rErr error) {
+	f, err := os.OpenFile(path.Join(s.dir, day+fileExt), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && rErr == nil {
+			rErr = err
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for _, r := range readings {
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// Query returns the readings of the characteristics recorded in [since, until) ordered by time.
+// All characteristics are returned if ids is empty.
+func (s *Store) Query(ids []client.CharacteristicReadRequest, since, until time.Time) ([]*Reading, error) {
+	include := make(map[client.CharacteristicReadRequest]bool, len(ids))
+	for _, id := range ids {
+		include[id] = true
+	}
+
+	days, err := s.days()
+	if err != nil {
+		return nil, err
+	}
+
+	firstDay := since.UTC().Format(dayFormat)
+	lastDay := until.UTC().Format(dayFormat)
+
+	var readings []*Reading
+	for _, day := range days {
+		if day < firstDay || day > lastDay {
+			continue
+		}
+		err := s.readDay(day, func(r *Reading) {
+			if r.Time.Before(since) || !r.Time.Before(until) {
+				return
+			}
+			if len(include) > 0 && !include[client.CharacteristicReadRequest{AccessoryID: r.AccessoryID, CharacteristicID: r.CharacteristicID}] {
+				return
+			}
+			readings = append(readings, r)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].Time.Before(readings[j].Time)
+	})
+
+	return readings, nil
+}
+
+func (s *Store) readDay(day string, fn func(*Reading)) error {
+	f, err := os.Open(path.Join(s.dir, day+fileExt))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, maxReadingSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Reading
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// a partial line can be left behind if the recorder was killed mid write
+			continue
+		}
+		fn(&r)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read %s: %v", day+fileExt, err)
+	}
+
+	return nil
+}
+
+// Prune removes the files of days entirely before the cutoff.
+func (s *Store) Prune(before time.Time) error {
+	days, err := s.days()
+	if err != nil {
+		return err
+	}
+
+	cutoff := before.UTC().Format(dayFormat)
+	for _, day := range days {
+		if day >= cutoff {
+			continue
+		}
+		if err := os.Remove(path.Join(s.dir, day+fileExt)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// days returns the days that have files in the store in order.
+func (s *Store) days() ([]string, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var days []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != fileExt {
+			continue
+		}
+		day := strings.TrimSuffix(name, fileExt)
+		if _, err := time.Parse(dayFormat, day); err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	return days, nil
+}
+
+// SaveAccessories stores the accessory's attribute database so characteristics can be selected
+// by name when querying without connecting to the accessory.
+func (s *Store) SaveAccessories(accessories []*client.RawAccessory) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(accessories)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path.Join(s.dir, accessoriesDB), data, 0600)
+}
+
+// Accessories returns the attribute database saved by SaveAccessories.
+func (s *Store) Accessories() ([]*client.RawAccessory, error) {
+	data, err := ioutil.ReadFile(path.Join(s.dir, accessoriesDB))
+	if err != nil {
+		return nil, err
+	}
+
+	var accessories []*client.RawAccessory
+	if err := json.Unmarshal(data, &accessories); err != nil {
+		return nil, fmt.Errorf("parse %s: %v", accessoriesDB, err)
+	}
+
+	return accessories, nil
+}
+
+// Summary aggregates the numeric readings of a characteristic over a period.
+type Summary struct {
+	AccessoryID      uint64
+	CharacteristicID uint64
+	Start            time.Time
+	End              time.Time
+	Count            int
+	Min              float64
+	Max              float64
+	Avg              float64
+}
+
+// Summarize aggregates the readings of each characteristic into buckets of the given size
+// aligned to since. A bucket of 0 aggregates all readings of a characteristic together.
+// Readings that aren't numeric or bool are skipped.
+func Summarize(readings []*Reading, since, until time.Time, bucket time.Duration) []*Summary {
+	type key struct {
+		id    client.CharacteristicReadRequest
+		start time.Time
+	}
+
+	summaries := make(map[key]*Summary)
+	sums := make(map[key]float64)
+	var order []key
+
+	for _, r := range readings {
+		v, ok := numericValue(r.Value)
+		if !ok {
+			continue
+		}
+
+		start, end := since, until
+		if bucket > 0 {
+			n := r.Time.Sub(since) / bucket
+			start = since.Add(n * bucket)
+			end = start.Add(bucket)
+			if end.After(until) {
+				end = until
+			}
+		}
+
+		k := key{client.CharacteristicReadRequest{AccessoryID: r.AccessoryID, CharacteristicID: r.CharacteristicID}, start}
+		s := summaries[k]
+		if s == nil {
+			s = &Summary{
+				AccessoryID:      r.AccessoryID,
+				CharacteristicID: r.CharacteristicID,
+				Start:            start,
+				End:              end,
+				Min:              math.Inf(1),
+				Max:              math.Inf(-1),
+			}
+			summaries[k] = s
+			order = append(order, k)
+		}
+		s.Count++
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+		sums[k] += v
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if a.id.AccessoryID != b.id.AccessoryID {
+			return a.id.AccessoryID < b.id.AccessoryID
+		}
+		if a.id.CharacteristicID != b.id.CharacteristicID {
+			return a.id.CharacteristicID < b.id.CharacteristicID
+		}
+		return a.start.Before(b.start)
+	})
+
+	result := make([]*Summary, 0, len(order))
+	for _, k := range order {
+		s := summaries[k]
+		s.Avg = sums[k] / float64(s.Count)
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// numericValue returns the value as a float64. Bools are converted to 0 or 1.
+func numericValue(v characteristic.Value) (float64, bool) {
+	if len(v) == 0 || string(v) == "null" {
+		return 0, false
+	}
+	if f, err := v.Float64(); err == nil {
+		return f, true
+	}
+	if b, err := v.Bool(); err == nil {
+		if b {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
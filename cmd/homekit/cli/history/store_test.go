@@ -0,0 +1,152 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake/hapfaketest"
+	"github.com/stretchr/testify/require"
+)
+
+func reading(t time.Time, aid, iid uint64, value string) *Reading {
+	return &Reading{Time: t, AccessoryID: aid, CharacteristicID: iid, Value: characteristic.Value(value)}
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	day1 := time.Date(2020, 3, 1, 23, 0, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Hour)
+	require.NoError(t, store.Append(
+		reading(day1, 1, 10, "800"),
+		reading(day1, 1, 11, "21.5"),
+		reading(day2, 1, 10, "1200"),
+	))
+	require.NoError(t, store.Append(reading(day2.Add(time.Minute), 1, 10, "1000")))
+
+	require.FileExists(t, path.Join(dir, "2020-03-01.jsonl"))
+	require.FileExists(t, path.Join(dir, "2020-03-02.jsonl"))
+
+	readings, err := store.Query([]client.CharacteristicReadRequest{{AccessoryID: 1, CharacteristicID: 10}}, day1, day2.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, readings, 3)
+	require.Equal(t, characteristic.Value("800"), readings[0].Value)
+	require.True(t, readings[0].Time.Equal(day1))
+	require.Equal(t, characteristic.Value("1000"), readings[2].Value)
+
+	readings, err = store.Query(nil, day2, day2.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+
+	require.NoError(t, store.Prune(day2))
+	require.NoFileExists(t, path.Join(dir, "2020-03-01.jsonl"))
+	readings, err = store.Query(nil, day1, day2.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, readings, 2)
+}
+
+func TestStoreIgnoresPartialLines(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	now := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Append(reading(now, 1, 10, "800")))
+
+	f, err := os.OpenFile(path.Join(dir, "2020-03-01.jsonl"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"t":"2020-03-01T12:01:00Z","aid":1,`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	readings, err := store.Query(nil, now, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+}
+
+func TestSummarize(t *testing.T) {
+	since := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(2 * time.Hour)
+	readings := []*Reading{
+		reading(since.Add(10*time.Minute), 1, 10, "800"),
+		reading(since.Add(20*time.Minute), 1, 10, "1200"),
+		reading(since.Add(70*time.Minute), 1, 10, "1000"),
+		reading(since.Add(30*time.Minute), 1, 11, "true"),
+		reading(since.Add(40*time.Minute), 1, 11, "0"),
+		reading(since.Add(50*time.Minute), 1, 12, `"text"`),
+	}
+
+	summaries := Summarize(readings, since, until, 0)
+	require.Equal(t, []*Summary{
+		{AccessoryID: 1, CharacteristicID: 10, Start: since, End: until, Count: 3, Min: 800, Max: 1200, Avg: 1000},
+		{AccessoryID: 1, CharacteristicID: 11, Start: since, End: until, Count: 2, Min: 0, Max: 1, Avg: 0.5},
+	}, summaries)
+
+	summaries = Summarize(readings[:3], since, until, time.Hour)
+	require.Equal(t, []*Summary{
+		{AccessoryID: 1, CharacteristicID: 10, Start: since, End: since.Add(time.Hour), Count: 2, Min: 800, Max: 1200, Avg: 1000},
+		{AccessoryID: 1, CharacteristicID: 10, Start: since.Add(time.Hour), End: until, Count: 1, Min: 1000, Max: 1000, Avg: 1000},
+	}, summaries)
+}
+
+const testAccessories = `[
+  {
+    "aid": 1,
+    "services": [
+      {"iid": 1, "type": "3E", "characteristics": [
+        {"iid": 2, "type": "23", "format": "string", "value": "Velux", "perms": ["pr"]}
+      ]},
+      {"iid": 8, "type": "97", "characteristics": [
+        {"iid": 9, "type": "23", "format": "string", "value": "Air", "perms": ["pr"]},
+        {"iid": 10, "type": "93", "format": "float", "value": 800, "perms": ["pr", "ev"]},
+        {"iid": 11, "type": "11", "format": "float", "value": 21.5, "perms": ["pr"]}
+      ]}
+    ]
+  }
+]`
+
+func TestRecord(t *testing.T) {
+	acc, accClient := hapfaketest.NewClient(t, testAccessories)
+	expected, err := accClient.Accessories(context.Background())
+	require.NoError(t, err)
+
+	store := NewStore(t.TempDir())
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- Record(ctx, accClient, store, nil, RecordConfig{Interval: time.Hour, Events: true})
+	}()
+
+	require.Eventually(t, func() bool { return acc.Subscriptions(1, 10) > 0 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, acc.SetValue(1, 10, 1250))
+
+	var readings []*Reading
+	require.Eventually(t, func() bool {
+		var err error
+		readings, err = store.Query(nil, start, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		return len(readings) == 3
+	}, time.Second, 5*time.Millisecond)
+
+	values := make(map[uint64][]string)
+	for _, r := range readings {
+		values[r.CharacteristicID] = append(values[r.CharacteristicID], string(r.Value))
+	}
+	// the current value is read before subscribing and names aren't recorded
+	require.Equal(t, map[uint64][]string{10: {"800", "1250"}, 11: {"21.5"}}, values)
+
+	cancel()
+	require.Equal(t, context.Canceled, <-done)
+
+	db, err := store.Accessories()
+	require.NoError(t, err)
+	require.Equal(t, expected, db)
+}
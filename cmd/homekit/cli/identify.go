@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func identifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "identify",
+		Short: "Ask an accessory to identify itself",
+		Long: "Ask an accessory to identify itself, typically by blinking a light or moving briefly.\n\n" +
+			"Paired accessories are selected by --name and --aid selects an accessory behind a bridge. " +
+			"Unpaired accessories are selected by the device id from discovery with --id.",
+	}
+
+	name := cmd.Flags().StringP("name", "n", "", "Name of paired accessory to identify")
+	accessoryID := cmd.Flags().Uint64("aid", 1, "Accessory id of a paired accessory to identify")
+	deviceID := cmd.Flags().String("id", "", "Device id of unpaired accessory to identify (from discovery)")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			switch {
+			case *name != "" && *deviceID != "":
+				return errors.New("only one of --name or --id can be specified")
+			case *name != "":
+				return identifyPaired(ctx, configPath, controllerName, *name, *accessoryID)
+			case *deviceID != "":
+				return identifyUnpaired(ctx, *deviceID)
+			default:
+				return errors.New("one of --name or --id must be specified")
+			}
+		},
+	)
+
+	return cmd
+}
+
+func identifyPaired(ctx context.Context, configPath, controllerName, name string, accessoryID uint64) (rErr error) {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	accClient, err := accessoryClient(cfg, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := accClient.Close(); cErr != nil {
+			rErr = multierror.Append(rErr, cErr)
+		}
+	}()
+
+	if err := accClient.Identify(ctx, accessoryID); err != nil {
+		return fmt.Errorf("identify: %v", err)
+	}
+
+	return renderMessage("Identifying accessory %d of %s", accessoryID, name)
+}
+
+func identifyUnpaired(ctx context.Context, deviceID string) error {
+	device, err := client.DeviceByID(ctx, deviceID, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("deviceByID: %v", err)
+	}
+
+	setupClient := client.NewSetupClient(&http.Client{})
+	err = setupClient.Identify(ctx, client.IPConnectionInfo{
+		IPAddress: device.IPs[0].String(),
+		Port:      device.Port,
+	})
+	if err != nil {
+		if errors.Is(err, client.ErrAccessoryPaired) {
+			return fmt.Errorf("%s is paired, identify it with --name using a paired controller", deviceID)
+		}
+		return fmt.Errorf("identify: %v", err)
+	}
+
+	return renderMessage("Identifying %s", deviceID)
+}
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"time"
 
 	"github.com/mctofu/homekit/client"
@@ -14,17 +17,26 @@ func importPairingCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "importPairing",
 		Short: "Import a known pairing",
+		Long: "Import a known pairing either from a bundle created by exportPairing or from " +
+			"the accessory id and key.\n\n" +
+			"A bundle is only imported if it's signed by one of the controller profiles in the " +
+			"config directory or by a controller public key given with --signer.",
 	}
 
+	bundle := cmd.Flags().String("bundle", "", "Pairing bundle from exportPairing or - to read it from stdin")
 	deviceID := cmd.Flags().String("id", "", "Device id of accessory to import")
-	markFlagRequired(cmd, "id")
 	key := cmd.Flags().String("key", "", "Accessory public key in base64 or hex")
-	markFlagRequired(cmd, "key")
-	name := cmd.Flags().StringP("name", "n", "", "Alias to reference accessory by")
-	markFlagRequired(cmd, "name")
+	name := cmd.Flags().StringP("name", "n", "", "Alias to reference accessory by (defaults to the bundle's alias)")
+	signers := cmd.Flags().StringSlice("signer", nil, "Public key in base64 or hex of a controller trusted to sign bundles")
 
 	cmd.RunE = configCommandRunner(cmd,
 		func(ctx context.Context, configPath, controllerName string) error {
+			if *bundle != "" {
+				return importPairingBundle(configPath, controllerName, *bundle, *name, *signers)
+			}
+			if *deviceID == "" || *key == "" || *name == "" {
+				return errors.New("either --bundle or all of --id, --key and --name are required")
+			}
 			return importPairing(ctx, configPath, controllerName, *deviceID, *key, *name)
 		},
 	)
@@ -38,13 +50,8 @@ func importPairing(ctx context.Context, configPath, controllerName string, devic
 		return fmt.Errorf("read controller config: %v", err)
 	}
 
-	for _, pair := range cfg.AccessoryPairings {
-		if pair.DeviceID == deviceID {
-			return fmt.Errorf("%s is already paired as %s", pair.DeviceID, pair.Name)
-		}
-		if pair.Name == name {
-			return fmt.Errorf("%s is already aliased to %s", pair.Name, pair.DeviceID)
-		}
+	if err := checkPairingConflict(cfg, deviceID, name); err != nil {
+		return err
 	}
 
 	publicKey, err := parsePublicKey(key)
@@ -74,7 +81,92 @@ func importPairing(ctx context.Context, configPath, controllerName string, devic
 		return fmt.Errorf("saveControllerConfig: %v", err)
 	}
 
-	fmt.Println("Pairing imported")
+	return renderMessage("Pairing imported")
+}
+
+func importPairingBundle(configPath, controllerName string, encoded, name string, signers []string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	if encoded == "-" {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("read stdin: %v", err)
+		}
+		encoded = string(data)
+	}
+
+	trustedKeys, err := trustedSignerKeys(configPath, signers)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := config.DecodePairingBundle(encoded, trustedKeys)
+	if err != nil {
+		return fmt.Errorf("invalid bundle: %v", err)
+	}
+
+	pairing := bundle.Pairing
+	if name != "" {
+		pairing.Name = name
+	}
+	if pairing.Name == "" {
+		return errors.New("bundle has no alias, specify one with --name")
+	}
+
+	if err := checkPairingConflict(cfg, pairing.DeviceID, pairing.Name); err != nil {
+		return err
+	}
+
+	cfg.AccessoryPairings = append(cfg.AccessoryPairings, pairing)
+
+	if err := config.SaveControllerConfig(configPath, cfg, true); err != nil {
+		return fmt.Errorf("saveControllerConfig: %v", err)
+	}
+
+	return renderMessage("Pairing for %s (%s) imported from controller %s", pairing.Name, pairing.DeviceID, bundle.SignerID)
+}
+
+// trustedSignerKeys returns the public keys of the controller profiles under configPath and
+// of the signers given as base64 or hex.
+func trustedSignerKeys(configPath string, signers []string) ([][]byte, error) {
+	var keys [][]byte
+	for _, signer := range signers {
+		key, err := parsePublicKey(signer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signer %s: %v", signer, err)
+		}
+		keys = append(keys, key)
+	}
+
+	names, err := config.ListControllerConfigs(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("list controller configs: %v", err)
+	}
+	for _, name := range names {
+		cfg, err := config.ReadControllerConfig(configPath, name)
+		if err != nil {
+			return nil, fmt.Errorf("read controller config %s: %v", name, err)
+		}
+		keys = append(keys, cfg.PublicKey)
+	}
+
+	return keys, nil
+}
+
+// checkPairingConflict returns an error if the accessory is already paired or if
+// the alias is already in use.
+func checkPairingConflict(cfg *config.ControllerConfig, deviceID, name string) error {
+	for _, pair := range cfg.AccessoryPairings {
+		if pair.DeviceID == deviceID {
+			return fmt.Errorf("%s is already paired as %s", pair.DeviceID, pair.Name)
+		}
+		if pair.Name == name {
+			return fmt.Errorf("%s is already aliased to %s", pair.Name, pair.DeviceID)
+		}
+	}
 
 	return nil
 }
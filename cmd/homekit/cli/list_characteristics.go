@@ -2,7 +2,12 @@ package cli
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/mctofu/homekit/client"
 	"github.com/mctofu/homekit/client/characteristic"
@@ -22,23 +27,186 @@ func listCharacteristicsCmd() *cobra.Command {
 	return cmd
 }
 
-func listCharacteristics(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
-	accessories, err := accClient.Accessories(ctx)
-	if err != nil {
-		return err
+// accessoryOutput is the output schema of an accessory listed by listCharacteristics. Values
+// are in the units reported by the accessory, regardless of --units, so the output can be
+// served by the simulate command.
+type accessoryOutput struct {
+	ID           uint64          `json:"aid" yaml:"aid"`
+	Name         string          `json:"name" yaml:"name"`
+	SerialNumber string          `json:"serialNumber" yaml:"serialNumber"`
+	Error        string          `json:"error,omitempty" yaml:"error,omitempty"`
+	Services     []serviceOutput `json:"services" yaml:"services"`
+}
+
+// serviceOutput is the output schema of a service listed by listCharacteristics.
+type serviceOutput struct {
+	ID              uint64                 `json:"iid" yaml:"iid"`
+	Type            string                 `json:"type" yaml:"type"`
+	TypeName        string                 `json:"typeName" yaml:"typeName"`
+	Characteristics []characteristicOutput `json:"characteristics" yaml:"characteristics"`
+}
+
+// characteristicOutput is the output schema of a characteristic listed by listCharacteristics.
+type characteristicOutput struct {
+	ID          uint64      `json:"iid" yaml:"iid"`
+	Type        string      `json:"type" yaml:"type"`
+	TypeName    string      `json:"typeName" yaml:"typeName"`
+	Format      string      `json:"format" yaml:"format"`
+	Unit        string      `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Permissions []string    `json:"perms" yaml:"perms"`
+	Value       interface{} `json:"value" yaml:"value"`
+	Encoding    string      `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	MinValue    interface{} `json:"minValue,omitempty" yaml:"minValue,omitempty"`
+	MaxValue    interface{} `json:"maxValue,omitempty" yaml:"maxValue,omitempty"`
+	StepValue   interface{} `json:"minStep,omitempty" yaml:"minStep,omitempty"`
+	MaxLen      *int        `json:"maxLen,omitempty" yaml:"maxLen,omitempty"`
+}
+
+type accessoriesOutput []accessoryOutput
+
+func (a accessoriesOutput) csvHeader() []string {
+	return []string{"aid", "accessoryName", "serviceIID", "serviceType", "serviceTypeName",
+		"iid", "type", "typeName", "format", "unit", "perms", "value"}
+}
+
+func (a accessoriesOutput) csvRecords() [][]string {
+	var records [][]string
+	for _, acc := range a {
+		for _, svc := range acc.Services {
+			for _, ch := range svc.Characteristics {
+				records = append(records, []string{
+					strconv.FormatUint(acc.ID, 10),
+					acc.Name,
+					strconv.FormatUint(svc.ID, 10),
+					svc.Type,
+					svc.TypeName,
+					strconv.FormatUint(ch.ID, 10),
+					ch.Type,
+					ch.TypeName,
+					ch.Format,
+					ch.Unit,
+					strings.Join(ch.Permissions, " "),
+					formatCSVValue(ch.Value),
+				})
+			}
+		}
 	}
+	return records
+}
 
+func newAccessoriesOutput(accessories []*client.RawAccessory) accessoriesOutput {
+	result := make(accessoriesOutput, 0, len(accessories))
 	for _, acc := range accessories {
-		accInfo := acc.Info()
-		fmt.Printf("Accessory: %d %s (%s)\n", acc.ID, accInfo.Name.Value, accInfo.SerialNumber.Value)
+		accOut := accessoryOutput{
+			ID:       acc.ID,
+			Services: make([]serviceOutput, 0, len(acc.Services)),
+		}
+		accInfo, err := acc.Info()
+		switch {
+		case err != nil:
+			accOut.Error = err.Error()
+		case accInfo != nil:
+			accOut.Name = accInfo.Name.Value
+			accOut.SerialNumber = accInfo.SerialNumber.Value
+		}
 
 		for _, svc := range acc.Services {
-			fmt.Printf("  Service: %d %s (%s)\n", svc.ID, service.NameForType(svc.Type), svc.Type)
+			svcOut := serviceOutput{
+				ID:              svc.ID,
+				Type:            svc.Type,
+				TypeName:        service.NameForType(svc.Type),
+				Characteristics: make([]characteristicOutput, 0, len(svc.Characteristics)),
+			}
 			for _, ch := range svc.Characteristics {
-				fmt.Printf("    %d.%d: %v / %s (%s) %v\n", acc.ID, ch.ID, characteristic.ValueForFormat(ch.Format, ch.Value), characteristic.NameForType(ch.Type), ch.Type, ch.Permissions)
+				permissions := ch.Permissions
+				if permissions == nil {
+					permissions = []string{}
+				}
+				svcOut.Characteristics = append(svcOut.Characteristics, characteristicOutput{
+					ID:          ch.ID,
+					Type:        ch.Type,
+					TypeName:    characteristic.NameForType(ch.Type),
+					Format:      ch.Format,
+					Unit:        ch.Unit,
+					Permissions: permissions,
+					Value:       databaseValue(ch.Format, ch.Value),
+					Encoding:    valueEncoding(ch.Format),
+					MinValue:    metadataValue(ch.MinValue),
+					MaxValue:    metadataValue(ch.MaxValue),
+					StepValue:   metadataValue(ch.StepValue),
+					MaxLen:      ch.MaxLen,
+				})
 			}
+			accOut.Services = append(accOut.Services, svcOut)
 		}
+
+		result = append(result, accOut)
 	}
 
-	return nil
+	return result
+}
+
+// databaseValue decodes a characteristic value like formatOutputValue except binary values
+// keep the base64 encoding reported by accessories and values that don't match the format are
+// kept as reported. This lets the structured output be served by the simulate command.
+func databaseValue(format string, raw characteristic.Value) interface{} {
+	decoded, err := characteristic.ValueForFormat(format, raw)
+	if err != nil {
+		return outputValue(characteristic.UndefinedValue{}, raw)
+	}
+	if b, ok := decoded.([]byte); ok {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return outputValue(decoded, raw)
+}
+
+// valueEncoding returns the encoding of values of the format in the attribute database.
+func valueEncoding(format string) string {
+	if format == "tlv8" || format == "data" {
+		return "base64"
+	}
+	return ""
+}
+
+// metadataValue decodes a numeric limit of a characteristic. It's nil if the characteristic
+// doesn't advertise the limit.
+func metadataValue(raw characteristic.Value) interface{} {
+	if raw == nil {
+		return nil
+	}
+	return outputValue(characteristic.UndefinedValue{}, raw)
+}
+
+func listCharacteristics(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+	accessories, err := accClient.Accessories(ctx)
+	if err != nil {
+		return err
+	}
+
+	return renderAccessories(os.Stdout, accessories)
+}
+
+// renderAccessories writes the services and characteristics of the attribute database to out.
+func renderAccessories(out io.Writer, accessories []*client.RawAccessory) error {
+	return renderTo(out, outputFormat, newAccessoriesOutput(accessories), func(w io.Writer) {
+		for _, acc := range accessories {
+			accInfo, err := acc.Info()
+			switch {
+			case err != nil:
+				fmt.Fprintf(w, "Accessory: %d %s\n", acc.ID, invalidValue(err))
+			case accInfo == nil:
+				fmt.Fprintf(w, "Accessory: %d\n", acc.ID)
+			default:
+				fmt.Fprintf(w, "Accessory: %d %s (%s)\n", acc.ID, accInfo.Name.Value, accInfo.SerialNumber.Value)
+			}
+
+			for _, svc := range acc.Services {
+				fmt.Fprintf(w, "  Service: %d %s (%s)\n", svc.ID, service.NameForType(svc.Type), svc.Type)
+				for _, ch := range svc.Characteristics {
+					value, err := characteristic.ValueForFormat(ch.Format, ch.Value)
+					fmt.Fprintf(w, "    %d.%d: %s / %s (%s) %v\n", acc.ID, ch.ID, formatTextValue(value, err, characteristicUnit(ch.Unit, ch.Type)), characteristic.NameForType(ch.Type), ch.Type, ch.Permissions)
+				}
+			}
+		}
+	})
 }
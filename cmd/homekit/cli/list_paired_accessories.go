@@ -3,6 +3,8 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
+	"strconv"
 
 	"github.com/mctofu/homekit/cmd/homekit/cli/config"
 	"github.com/spf13/cobra"
@@ -19,20 +21,63 @@ func listPairedAccessoriesCmd() *cobra.Command {
 	return cmd
 }
 
+// pairedAccessoryOutput is the output schema of an accessory listed by listPairedAccessories.
+type pairedAccessoryOutput struct {
+	Name       string `json:"name" yaml:"name"`
+	DeviceID   string `json:"deviceId" yaml:"deviceId"`
+	DeviceName string `json:"deviceName" yaml:"deviceName"`
+	Model      string `json:"model" yaml:"model"`
+	IPAddress  string `json:"ipAddress" yaml:"ipAddress"`
+	Port       int    `json:"port" yaml:"port"`
+}
+
+type pairedAccessoriesOutput []pairedAccessoryOutput
+
+func (p pairedAccessoriesOutput) csvHeader() []string {
+	return []string{"name", "deviceId", "deviceName", "model", "ipAddress", "port"}
+}
+
+func (p pairedAccessoriesOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(p))
+	for _, acc := range p {
+		records = append(records, []string{
+			acc.Name,
+			acc.DeviceID,
+			acc.DeviceName,
+			acc.Model,
+			acc.IPAddress,
+			strconv.Itoa(acc.Port),
+		})
+	}
+	return records
+}
+
 func listPairedAccessories(ctx context.Context, configPath, controllerName string) error {
 	cfg, err := config.ReadControllerConfig(configPath, controllerName)
 	if err != nil {
 		return fmt.Errorf("read controller config: %v", err)
 	}
 
-	if len(cfg.AccessoryPairings) == 0 {
-		fmt.Println("No paired accessories")
-		return nil
-	}
-
+	accessories := make(pairedAccessoriesOutput, 0, len(cfg.AccessoryPairings))
 	for _, acc := range cfg.AccessoryPairings {
-		fmt.Printf("%s (%s)\n", acc.Name, acc.DeviceID)
+		accessories = append(accessories, pairedAccessoryOutput{
+			Name:       acc.Name,
+			DeviceID:   acc.DeviceID,
+			DeviceName: acc.DeviceName,
+			Model:      acc.Model,
+			IPAddress:  acc.IPConnectionInfo.IPAddress,
+			Port:       acc.IPConnectionInfo.Port,
+		})
 	}
 
-	return nil
+	return render(accessories, func(w io.Writer) {
+		if len(accessories) == 0 {
+			fmt.Fprintln(w, "No paired accessories")
+			return
+		}
+
+		for _, acc := range accessories {
+			fmt.Fprintf(w, "%s (%s)\n", acc.Name, acc.DeviceID)
+		}
+	})
 }
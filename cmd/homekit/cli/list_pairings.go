@@ -2,7 +2,11 @@ package cli
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 
 	"github.com/mctofu/homekit/client"
 	"github.com/spf13/cobra"
@@ -14,18 +18,70 @@ func listPairingsCmd() *cobra.Command {
 		Short: "List controllers paired to an accessory",
 	}
 
-	cmd.RunE = clientCommandRunner(cmd, listPairings)
+	cmd.RunE = clientCommandRunner(cmd,
+		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+			return listPairings(ctx, os.Stdout, clientCtx, accClient)
+		},
+	)
 
 	return cmd
 }
 
-func listPairings(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+// pairingOutput is the output schema of a controller pairing listed by listPairings.
+type pairingOutput struct {
+	ControllerID string `json:"controllerId" yaml:"controllerId"`
+	PublicKey    string `json:"publicKey" yaml:"publicKey"`
+	Admin        bool   `json:"admin" yaml:"admin"`
+	Self         bool   `json:"self" yaml:"self"`
+}
+
+type pairingsOutput []pairingOutput
+
+func (p pairingsOutput) csvHeader() []string {
+	return []string{"controllerId", "publicKey", "admin", "self"}
+}
+
+func (p pairingsOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(p))
+	for _, pair := range p {
+		records = append(records, []string{
+			pair.ControllerID,
+			pair.PublicKey,
+			strconv.FormatBool(pair.Admin),
+			strconv.FormatBool(pair.Self),
+		})
+	}
+	return records
+}
+
+// listPairings renders the controllers paired with the accessory to out.
+func listPairings(ctx context.Context, out io.Writer, clientCtx *clientContext, accClient *client.AccessoryClient) error {
 	pairs, err := accClient.ListPairings(ctx)
 	if err != nil {
 		return fmt.Errorf("listPairings: %v", err)
 	}
 
-	dump(pairs)
+	pairings := make(pairingsOutput, 0, len(pairs))
+	for _, p := range pairs {
+		pairings = append(pairings, pairingOutput{
+			ControllerID: p.ControllerID,
+			PublicKey:    base64.StdEncoding.EncodeToString(p.PublicKey),
+			Admin:        p.Admin,
+			Self:         p.ControllerID == clientCtx.Config.DeviceID,
+		})
+	}
 
-	return nil
+	return renderTo(out, outputFormat, pairings, func(w io.Writer) {
+		for _, p := range pairings {
+			var notes string
+			if p.Admin {
+				notes += " [admin]"
+			}
+			if p.Self {
+				notes += " (this controller)"
+			}
+			fmt.Fprintf(w, "%s%s\n", p.ControllerID, notes)
+			fmt.Fprintf(w, "  Key: %s\n", p.PublicKey)
+		}
+	})
 }
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/mctofu/homekit/cmd/homekit/cli/mqtt"
+	"github.com/spf13/cobra"
+)
+
+type mqttOptions struct {
+	broker          string
+	username        string
+	password        string
+	clientID        string
+	prefix          string
+	discovery       bool
+	discoveryPrefix string
+	pollInterval    time.Duration
+	names           []string
+}
+
+func mqttCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mqtt",
+		Short: "Bridge paired accessories to an MQTT broker",
+		Long: "Bridge paired accessories to an MQTT broker until interrupted.\n\n" +
+			"Readable characteristics are published as retained messages to " +
+			"<prefix>/<alias>/<aid>/<service>/<characteristic> and values published to the " +
+			"characteristic's topic followed by /set are written to the accessory.",
+	}
+
+	var opts mqttOptions
+	cmd.Flags().StringVar(&opts.broker, "broker", "tcp://localhost:1883", "Address of the broker. Use tls:// to connect with TLS.")
+	cmd.Flags().StringVar(&opts.username, "username", "", "Username to connect to the broker with")
+	cmd.Flags().StringVar(&opts.password, "password", os.Getenv("HOMEKIT_MQTT_PASSWORD"), "Password to connect to the broker with. Defaults to $HOMEKIT_MQTT_PASSWORD.")
+	cmd.Flags().StringVar(&opts.clientID, "client-id", "", "Client id to connect to the broker with. Defaults to homekit-<controller>.")
+	cmd.Flags().StringVar(&opts.prefix, "prefix", "homekit", "Prefix of the published topics")
+	cmd.Flags().BoolVar(&opts.discovery, "discovery", false, "Publish Home Assistant MQTT discovery payloads")
+	cmd.Flags().StringVar(&opts.discoveryPrefix, "discovery-prefix", "homeassistant", "Prefix of Home Assistant discovery topics")
+	cmd.Flags().DurationVar(&opts.pollInterval, "poll", time.Minute, "Interval to read characteristics that don't support events")
+	cmd.Flags().StringArrayVarP(&opts.names, "name", "n", nil, "Name of accessory to bridge. Defaults to all paired accessories.")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			return bridgeMQTT(ctx, configPath, controllerName, &opts)
+		},
+	)
+
+	return cmd
+}
+
+func bridgeMQTT(ctx context.Context, configPath, controllerName string, opts *mqttOptions) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	names := opts.names
+	if len(names) == 0 {
+		for _, pair := range cfg.AccessoryPairings {
+			names = append(names, pair.Name)
+		}
+	}
+
+	accessories := make(map[string]client.Accessory)
+	for _, name := range names {
+		accClient, err := accessoryClient(cfg, name)
+		if err != nil {
+			return err
+		}
+		defer accClient.Close()
+		accessories[name] = accClient
+	}
+
+	clientID := opts.clientID
+	if clientID == "" {
+		clientID = "homekit-" + controllerName
+	}
+
+	logger := log.New(progressWriter(), "", log.LstdFlags)
+	bridge := mqtt.NewBridge(accessories, mqtt.BridgeConfig{
+		Prefix:          opts.prefix,
+		Discovery:       opts.discovery,
+		DiscoveryPrefix: opts.discoveryPrefix,
+		PollInterval:    opts.pollInterval,
+		Logger:          logger,
+	})
+
+	availability := mqtt.AvailabilityTopic(opts.prefix)
+	conn := paho.NewClient(paho.NewClientOptions().
+		AddBroker(opts.broker).
+		SetClientID(clientID).
+		SetUsername(opts.username).
+		SetPassword(opts.password).
+		SetWill(availability, "offline", 1, true).
+		SetConnectRetry(true).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(time.Minute).
+		SetOnConnectHandler(bridge.OnConnect).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			logger.Printf("mqtt: connection to %s lost: %v", opts.broker, err)
+		}))
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// messages are queued until the client connects
+	if token := conn.Connect(); token.WaitTimeout(time.Second) && token.Error() != nil {
+		return fmt.Errorf("connect to %s: %v", opts.broker, token.Error())
+	}
+	defer conn.Disconnect(250)
+
+	err = bridge.Run(ctx, conn)
+
+	// the will isn't published after a clean disconnect
+	conn.Publish(availability, 1, true, "offline").WaitTimeout(5 * time.Second)
+
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,424 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+)
+
+// BridgeConfig configures a Bridge.
+type BridgeConfig struct {
+	// Prefix is the first level of the bridge's topics. Defaults to homekit.
+	Prefix string
+	// Discovery publishes Home Assistant MQTT discovery payloads.
+	Discovery bool
+	// DiscoveryPrefix is the prefix Home Assistant watches for discovery payloads.
+	// Defaults to homeassistant.
+	DiscoveryPrefix string
+	// PollInterval is how often characteristics that don't support events are read.
+	// Defaults to 1 minute.
+	PollInterval time.Duration
+	// ReconnectDelay is how long to wait before retrying a failed accessory connection.
+	// Defaults to client.DefaultReconnectDelay.
+	ReconnectDelay time.Duration
+	Logger         *log.Logger
+}
+
+// Bridge publishes the readable characteristics of accessories to topics named
+// <prefix>/<alias>/<aid>/<service>/<characteristic> and writes values published to the
+// topic followed by /set.
+type Bridge struct {
+	accessories map[string]*client.LockedAccessory
+	writes      map[string]chan paho.Message
+	cfg         BridgeConfig
+}
+
+// qos is the quality of service of published messages and subscriptions. Messages with QoS 1
+// are queued while the client reconnects to the broker.
+const qos = 1
+
+// NewBridge returns a bridge for the accessories keyed by alias.
+func NewBridge(accessories map[string]client.Accessory, cfg BridgeConfig) *Bridge {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "homekit"
+	}
+	if cfg.DiscoveryPrefix == "" {
+		cfg.DiscoveryPrefix = "homeassistant"
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = client.DefaultReconnectDelay
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(ioutil.Discard, "", 0)
+	}
+
+	locked := make(map[string]*client.LockedAccessory, len(accessories))
+	writes := make(map[string]chan paho.Message, len(accessories))
+	for name, acc := range accessories {
+		locked[name] = client.NewLockedAccessory(acc)
+		writes[name] = make(chan paho.Message, 16)
+	}
+
+	return &Bridge{
+		accessories: locked,
+		writes:      writes,
+		cfg:         cfg,
+	}
+}
+
+// AvailabilityTopic is the topic the bridge publishes online to. It should be used as the topic
+// of the client's will with a retained offline payload.
+func AvailabilityTopic(prefix string) string {
+	if prefix == "" {
+		prefix = "homekit"
+	}
+	return prefix + "/status"
+}
+
+// OnConnect publishes the bridge's availability and subscribes to writes. It must be the
+// client's OnConnectHandler since subscriptions aren't kept when the client reconnects.
+func (b *Bridge) OnConnect(conn paho.Client) {
+	b.publishMessage(conn, AvailabilityTopic(b.cfg.Prefix), []byte("online"))
+
+	for alias, writes := range b.writes {
+		alias, writes := alias, writes
+		filter := b.cfg.Prefix + "/" + topicLevel(alias) + "/+/+/+/set"
+		token := conn.Subscribe(filter, qos, func(_ paho.Client, msg paho.Message) {
+			select {
+			case writes <- msg:
+			default:
+				b.cfg.Logger.Printf("%s: dropped write to %s", alias, msg.Topic())
+			}
+		})
+		if token.Wait() && token.Error() != nil {
+			b.cfg.Logger.Printf("%s: subscribe to writes: %v", alias, token.Error())
+		}
+	}
+}
+
+// Run bridges the accessories to the broker until ctx is cancelled. The client reconnects to
+// the broker on its own if the connection is lost.
+func (b *Bridge) Run(ctx context.Context, conn paho.Client) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for alias, acc := range b.accessories {
+		wg.Add(1)
+		go func(alias string, acc *client.LockedAccessory) {
+			defer wg.Done()
+			b.runAccessory(ctx, conn, alias, acc)
+		}(alias, acc)
+	}
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// bridgedCharacteristic is a characteristic of an accessory and the topic it's published to.
+type bridgedCharacteristic struct {
+	topic          string
+	accessoryID    uint64
+	service        *service.RawService
+	characteristic *characteristic.RawCharacteristic
+}
+
+func (c *bridgedCharacteristic) id() client.CharacteristicReadRequest {
+	return client.CharacteristicReadRequest{AccessoryID: c.accessoryID, CharacteristicID: c.characteristic.ID}
+}
+
+func (b *Bridge) runAccessory(ctx context.Context, conn paho.Client, alias string, acc *client.LockedAccessory) {
+	var db []*client.RawAccessory
+	for {
+		var err error
+		db, err = acc.Accessories(ctx)
+		if err == nil {
+			break
+		}
+		b.cfg.Logger.Printf("%s: read accessories: %v", alias, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.cfg.ReconnectDelay):
+		}
+	}
+
+	chars := bridgedCharacteristics(b.cfg.Prefix, alias, db)
+	byTopic := make(map[string]*bridgedCharacteristic, len(chars))
+	byID := make(map[client.CharacteristicReadRequest]*bridgedCharacteristic, len(chars))
+	var evented, polled []client.CharacteristicReadRequest
+	for _, c := range chars {
+		byTopic[c.topic] = c
+		byID[c.id()] = c
+		if !c.characteristic.HasPermission(characteristic.PermissionPairedRead) {
+			continue
+		}
+		if c.characteristic.HasPermission(characteristic.PermissionEvents) {
+			evented = append(evented, c.id())
+		} else {
+			polled = append(polled, c.id())
+		}
+	}
+
+	if b.cfg.Discovery {
+		for _, d := range discoveryConfigs(b.cfg.Prefix, b.cfg.DiscoveryPrefix, alias, db, chars) {
+			b.publishMessage(conn, d.topic, d.payload)
+		}
+	}
+
+	for _, c := range chars {
+		if c.characteristic.HasPermission(characteristic.PermissionPairedRead) {
+			b.publish(conn, c, c.characteristic.Value)
+		}
+	}
+
+	if len(evented) > 0 {
+		done := make(chan struct{})
+		defer func() { <-done }()
+		go func() {
+			defer close(done)
+			b.subscribe(ctx, conn, alias, acc, evented, byID)
+		}()
+	}
+
+	var poll <-chan time.Time
+	if len(polled) > 0 {
+		ticker := time.NewTicker(b.cfg.PollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-b.writes[alias]:
+			c := byTopic[strings.TrimSuffix(msg.Topic(), "/set")]
+			if c == nil {
+				b.cfg.Logger.Printf("%s: write to unknown topic %s", alias, msg.Topic())
+				continue
+			}
+			b.write(ctx, conn, alias, acc, c, msg.Payload())
+		case <-poll:
+			b.poll(ctx, conn, alias, acc, polled, byID)
+		}
+	}
+}
+
+// subscribe keeps an event subscription to the accessory open until ctx is cancelled.
+func (b *Bridge) subscribe(ctx context.Context, conn paho.Client, alias string, acc client.Accessory, ids []client.CharacteristicReadRequest, byID map[client.CharacteristicReadRequest]*bridgedCharacteristic) {
+	handler := func(e *client.CharacteristicEvent) {
+		c := byID[client.CharacteristicReadRequest{AccessoryID: e.AccessoryID, CharacteristicID: e.CharacteristicID}]
+		if c != nil {
+			b.publish(conn, c, e.Value)
+		}
+	}
+
+	client.SubscribeWithRetry(ctx, acc, ids, handler, b.cfg.ReconnectDelay, b.cfg.Logger, alias)
+}
+
+func (b *Bridge) poll(ctx context.Context, conn paho.Client, alias string, acc client.Accessory, ids []client.CharacteristicReadRequest, byID map[client.CharacteristicReadRequest]*bridgedCharacteristic) {
+	resps, err := acc.Characteristics(ctx, &client.CharacteristicsReadRequest{Characteristics: ids})
+	if err != nil {
+		b.cfg.Logger.Printf("%s: read characteristics: %v", alias, err)
+		return
+	}
+
+	for _, resp := range resps {
+		if resp.Status != nil && *resp.Status != 0 {
+			continue
+		}
+		c := byID[client.CharacteristicReadRequest{AccessoryID: resp.AccessoryID, CharacteristicID: resp.CharacteristicID}]
+		if c != nil {
+			b.publish(conn, c, resp.Value)
+		}
+	}
+}
+
+func (b *Bridge) write(ctx context.Context, conn paho.Client, alias string, acc client.Accessory, c *bridgedCharacteristic, payload []byte) {
+	if !c.characteristic.HasPermission(characteristic.PermissionPairedWrite) {
+		b.cfg.Logger.Printf("%s: %s is not writable", alias, c.topic)
+		return
+	}
+
+	value, err := parsePayload(c.characteristic.ValueFormat(), payload)
+	if err != nil {
+		b.cfg.Logger.Printf("%s: invalid value for %s: %v", alias, c.topic, err)
+		return
+	}
+
+	resps, err := acc.SetCharacteristics(ctx, &client.CharacteristicsWriteRequest{
+		Characteristics: []client.CharacteristicWriteRequest{
+			{AccessoryID: c.accessoryID, CharacteristicID: c.characteristic.ID, Value: value},
+		},
+	})
+	if err != nil {
+		b.cfg.Logger.Printf("%s: write %s: %v", alias, c.topic, err)
+		return
+	}
+	for _, resp := range resps {
+		if resp.Status != nil && *resp.Status != 0 {
+			b.cfg.Logger.Printf("%s: write %s: status %d", alias, c.topic, *resp.Status)
+			return
+		}
+	}
+
+	// evented characteristics are published when the subscription receives the new value
+	if c.characteristic.HasPermission(characteristic.PermissionPairedRead) &&
+		!c.characteristic.HasPermission(characteristic.PermissionEvents) {
+		encoded, err := json.Marshal(value)
+		if err == nil {
+			b.publish(conn, c, encoded)
+		}
+	}
+}
+
+// publish publishes the value of the characteristic. Null values aren't published as an empty
+// retained message would clear the last known value on the broker.
+func (b *Bridge) publish(conn paho.Client, c *bridgedCharacteristic, v characteristic.Value) {
+	p := payload(c.characteristic.ValueFormat(), v)
+	if p == nil {
+		return
+	}
+	b.publishMessage(conn, c.topic, p)
+}
+
+// publishMessage publishes a retained message without waiting for the broker to receive it.
+func (b *Bridge) publishMessage(conn paho.Client, topic string, payload []byte) {
+	token := conn.Publish(topic, qos, true, payload)
+	go func() {
+		<-token.Done()
+		if err := token.Error(); err != nil {
+			b.cfg.Logger.Printf("publish %s: %v", topic, err)
+		}
+	}()
+}
+
+// bridgedCharacteristics returns the readable or writable characteristics of the accessories
+// with their topics. Services and characteristics are named by their name or type and
+// suffixed by their instance id if the name isn't unique.
+func bridgedCharacteristics(prefix, alias string, db []*client.RawAccessory) []*bridgedCharacteristic {
+	var chars []*bridgedCharacteristic
+	for _, acc := range db {
+		svcNames := make(map[string]bool)
+		for _, svc := range acc.Services {
+			svcName := svc.Name()
+			if svcName == "" {
+				svcName = service.NameForType(svc.Type)
+			}
+			svcName = uniqueLevel(svcNames, topicLevel(svcName), svc.ID)
+
+			charNames := make(map[string]bool)
+			for _, ch := range svc.Characteristics {
+				if !ch.HasPermission(characteristic.PermissionPairedRead) && !ch.HasPermission(characteristic.PermissionPairedWrite) {
+					continue
+				}
+				charName := characteristic.NameForType(ch.Type)
+				if charName == "<Unknown>" {
+					charName = ch.Type
+				}
+				charName = uniqueLevel(charNames, topicLevel(charName), ch.ID)
+
+				chars = append(chars, &bridgedCharacteristic{
+					topic:          strings.Join([]string{prefix, topicLevel(alias), strconv.FormatUint(acc.ID, 10), svcName, charName}, "/"),
+					accessoryID:    acc.ID,
+					service:        svc,
+					characteristic: ch,
+				})
+			}
+		}
+	}
+
+	return chars
+}
+
+func uniqueLevel(used map[string]bool, level string, id uint64) string {
+	if used[level] {
+		level = fmt.Sprintf("%s-%d", level, id)
+	}
+	used[level] = true
+	return level
+}
+
+// topicLevel replaces characters that can't be used in a topic level.
+func topicLevel(s string) string {
+	return strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(s)
+}
+
+// payload returns the message payload for a value. Strings are unquoted, bools are published
+// as true or false even if the accessory reports them as 1 or 0 and other values are published
+// as json. Null values return nil.
+func payload(format string, v characteristic.Value) []byte {
+	if len(v) == 0 || string(v) == "null" {
+		return nil
+	}
+	switch format {
+	case "bool":
+		switch string(v) {
+		case "1":
+			return []byte("true")
+		case "0":
+			return []byte("false")
+		}
+	case "string", "tlv8", "data":
+		if s, err := v.String(); err == nil {
+			return []byte(s)
+		}
+	}
+	return v
+}
+
+// parsePayload parses a message payload to a value of the characteristic format.
+func parsePayload(format string, payload []byte) (interface{}, error) {
+	s := strings.TrimSpace(string(payload))
+
+	switch format {
+	case "bool":
+		switch strings.ToLower(s) {
+		case "true", "1", "on":
+			return true, nil
+		case "false", "0", "off":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%q is not a bool", s)
+	case "uint8", "uint16", "uint32", "uint64", "int":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		if f != math.Trunc(f) {
+			return nil, fmt.Errorf("%q is not an integer", s)
+		}
+		return int64(f), nil
+	case "float":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return f, nil
+	case "string", "tlv8", "data":
+		return string(payload), nil
+	default:
+		if json.Valid(payload) {
+			return json.RawMessage(payload), nil
+		}
+		return string(payload), nil
+	}
+}
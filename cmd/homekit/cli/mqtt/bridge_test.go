@@ -0,0 +1,252 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/client/hapfake/hapfaketest"
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccessories = `[
+  {
+    "aid": 1,
+    "services": [
+      {"iid": 1, "type": "3E", "characteristics": [
+        {"iid": 2, "type": "23", "format": "string", "value": "Velux", "perms": ["pr"]},
+        {"iid": 3, "type": "20", "format": "string", "value": "VELUX", "perms": ["pr"]},
+        {"iid": 4, "type": "30", "format": "string", "value": null, "perms": ["pr"]}
+      ]},
+      {"iid": 8, "type": "97", "characteristics": [
+        {"iid": 9, "type": "23", "format": "string", "value": "Air", "perms": ["pr"]},
+        {"iid": 10, "type": "93", "format": "float", "value": 800, "perms": ["pr", "ev"]}
+      ]},
+      {"iid": 12, "type": "8B", "characteristics": [
+        {"iid": 13, "type": "23", "format": "string", "value": "Roof Window", "perms": ["pr"]},
+        {"iid": 14, "type": "7C", "format": "uint8", "unit": "percentage", "value": 0, "minValue": 0, "maxValue": 100, "minStep": 1, "perms": ["pr", "pw", "ev"]}
+      ]},
+      {"iid": 16, "type": "49", "characteristics": [
+        {"iid": 17, "type": "25", "format": "bool", "value": 0, "perms": ["pr", "pw"]}
+      ]}
+    ]
+  }
+]`
+
+// testBroker is an in-process broker.
+type testBroker struct {
+	server *mochi.Server
+	addr   string
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	server := mochi.New(&mochi.Options{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, server.AddHook(new(auth.AllowHook), nil))
+
+	listener := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	require.NoError(t, server.AddListener(listener))
+	require.NoError(t, server.Serve())
+	t.Cleanup(func() { server.Close() })
+
+	return &testBroker{server: server, addr: "tcp://" + listener.Address()}
+}
+
+func (b *testBroker) retainedValue(topic string) (string, bool) {
+	pk, ok := b.server.Topics.Retained.Get(topic)
+	return string(pk.Payload), ok
+}
+
+// connect returns a client connected to the broker.
+func (b *testBroker) connect(t *testing.T, opts *paho.ClientOptions) paho.Client {
+	conn := paho.NewClient(opts.AddBroker(b.addr))
+	token := conn.Connect()
+	require.True(t, token.WaitTimeout(5*time.Second))
+	require.NoError(t, token.Error())
+	t.Cleanup(func() { conn.Disconnect(0) })
+
+	return conn
+}
+
+func runBridge(t *testing.T, cfg BridgeConfig) (*testBroker, *hapfake.Server, func()) {
+	return runBridgeWithBroker(t, newTestBroker(t), cfg)
+}
+
+func runBridgeWithBroker(t *testing.T, broker *testBroker, cfg BridgeConfig) (*testBroker, *hapfake.Server, func()) {
+	acc, accClient := hapfaketest.NewClient(t, testAccessories)
+
+	bridge := NewBridge(map[string]client.Accessory{"velux": accClient}, cfg)
+	conn := broker.connect(t, paho.NewClientOptions().
+		SetClientID("bridge").
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(10*time.Millisecond).
+		SetOnConnectHandler(bridge.OnConnect))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	done := make(chan error)
+	go func() {
+		done <- bridge.Run(ctx, conn)
+	}()
+
+	return broker, acc, func() {
+		cancel()
+		require.Equal(t, context.Canceled, <-done)
+	}
+}
+
+func requireRetained(t *testing.T, broker *testBroker, topic, expected string) {
+	require.Eventually(t, func() bool {
+		v, _ := broker.retainedValue(topic)
+		return v == expected
+	}, time.Second, 5*time.Millisecond, topic)
+}
+
+func TestBridgePublishesValues(t *testing.T) {
+	broker := newTestBroker(t)
+	publisher := broker.connect(t, paho.NewClientOptions().SetClientID("publisher"))
+	token := publisher.Publish("homekit/velux/1/Velux/SerialNumber", 1, true, "ABC123")
+	require.True(t, token.WaitTimeout(time.Second))
+	require.NoError(t, token.Error())
+
+	broker, acc, stop := runBridgeWithBroker(t, broker, BridgeConfig{PollInterval: 10 * time.Millisecond})
+	defer stop()
+
+	requireRetained(t, broker, "homekit/status", "online")
+	requireRetained(t, broker, "homekit/velux/1/Velux/Name", "Velux")
+	requireRetained(t, broker, "homekit/velux/1/Velux/Manufacturer", "VELUX")
+	requireRetained(t, broker, "homekit/velux/1/Air/CarbonDioxideLevel", "800")
+	requireRetained(t, broker, "homekit/velux/1/Roof Window/TargetPosition", "0")
+
+	// null values don't clear the retained value
+	v, _ := broker.retainedValue("homekit/velux/1/Velux/SerialNumber")
+	require.Equal(t, "ABC123", v)
+
+	// events
+	require.Eventually(t, func() bool { return acc.Subscriptions(1, 10) > 0 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, acc.SetValue(1, 10, 1250))
+	requireRetained(t, broker, "homekit/velux/1/Air/CarbonDioxideLevel", "1250")
+
+	// polled characteristics without event support, bools are normalized
+	require.NoError(t, acc.SetValue(1, 17, 1))
+	requireRetained(t, broker, "homekit/velux/1/Switch/On", "true")
+}
+
+func TestBridgeWrites(t *testing.T) {
+	broker, acc, stop := runBridge(t, BridgeConfig{})
+	defer stop()
+
+	requireRetained(t, broker, "homekit/velux/1/Switch/On", "false")
+
+	publisher := broker.connect(t, paho.NewClientOptions().SetClientID("publisher"))
+	publish(t, publisher, "homekit/velux/1/Roof Window/TargetPosition/set", "100")
+	publish(t, publisher, "homekit/velux/1/Switch/On/set", "ON")
+	// not writable
+	publish(t, publisher, "homekit/velux/1/Air/CarbonDioxideLevel/set", "0")
+
+	require.Eventually(t, func() bool { return len(acc.Writes()) == 2 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []hapfake.Write{
+		{AccessoryID: 1, CharacteristicID: 14, Value: characteristic.Value("100")},
+		{AccessoryID: 1, CharacteristicID: 17, Value: characteristic.Value("true")},
+	}, acc.Writes())
+
+	requireRetained(t, broker, "homekit/velux/1/Roof Window/TargetPosition", "100")
+	requireRetained(t, broker, "homekit/velux/1/Switch/On", "true")
+}
+
+func publish(t *testing.T, conn paho.Client, topic, payload string) {
+	token := conn.Publish(topic, 1, false, payload)
+	require.True(t, token.WaitTimeout(time.Second))
+	require.NoError(t, token.Error())
+}
+
+func TestBridgeReconnect(t *testing.T) {
+	broker, acc, stop := runBridge(t, BridgeConfig{})
+	defer stop()
+
+	requireRetained(t, broker, "homekit/status", "online")
+
+	// the broker forgets the bridge's subscriptions when it disconnects
+	cl, ok := broker.server.Clients.Get("bridge")
+	require.True(t, ok)
+	cl.Stop(packets.ErrServerShuttingDown)
+	require.Eventually(t, func() bool {
+		cl, ok := broker.server.Clients.Get("bridge")
+		return ok && !cl.Closed()
+	}, time.Second, 5*time.Millisecond)
+
+	publisher := broker.connect(t, paho.NewClientOptions().SetClientID("publisher"))
+	require.Eventually(t, func() bool {
+		publish(t, publisher, "homekit/velux/1/Roof Window/TargetPosition/set", "100")
+		return len(acc.Writes()) > 0
+	}, time.Second, 50*time.Millisecond)
+}
+
+func TestBridgeDiscovery(t *testing.T) {
+	broker, _, stop := runBridge(t, BridgeConfig{Discovery: true})
+	defer stop()
+
+	requireRetained(t, broker, "homekit/velux/1/Velux/Name", "Velux")
+
+	var number map[string]interface{}
+	require.Eventually(t, func() bool {
+		v, ok := broker.retainedValue("homeassistant/number/homekit_velux/1_14/config")
+		return ok && json.Unmarshal([]byte(v), &number) == nil
+	}, time.Second, 5*time.Millisecond)
+
+	require.Equal(t, "Roof Window TargetPosition", number["name"])
+	require.Equal(t, "homekit/velux/1/Roof Window/TargetPosition", number["state_topic"])
+	require.Equal(t, "homekit/velux/1/Roof Window/TargetPosition/set", number["command_topic"])
+	require.Equal(t, "homekit/status", number["availability_topic"])
+	require.Equal(t, "%", number["unit_of_measurement"])
+	require.Equal(t, 100.0, number["max"])
+	require.Equal(t, map[string]interface{}{
+		"identifiers":  []interface{}{"homekit_velux_1"},
+		"name":         "Velux",
+		"manufacturer": "VELUX",
+	}, number["device"])
+
+	v, ok := broker.retainedValue("homeassistant/sensor/homekit_velux/1_10/config")
+	require.True(t, ok)
+	require.Contains(t, v, `"device_class":"carbon_dioxide"`)
+
+	_, ok = broker.retainedValue("homeassistant/switch/homekit_velux/1_17/config")
+	require.True(t, ok)
+
+	// accessory information isn't discovered
+	_, ok = broker.retainedValue("homeassistant/sensor/homekit_velux/1_3/config")
+	require.False(t, ok)
+}
+
+func TestParsePayload(t *testing.T) {
+	for _, tc := range []struct {
+		format, payload string
+		expected        interface{}
+	}{
+		{"bool", "off", false},
+		{"bool", "1", true},
+		{"uint8", "50.0", int64(50)},
+		{"float", "21.5", 21.5},
+		{"string", "Kitchen", "Kitchen"},
+	} {
+		v, err := parsePayload(tc.format, []byte(tc.payload))
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, v)
+	}
+
+	_, err := parsePayload("uint8", []byte("50.5"))
+	require.Error(t, err)
+	_, err = parsePayload("bool", []byte("maybe"))
+	require.Error(t, err)
+}
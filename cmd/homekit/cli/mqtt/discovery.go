@@ -0,0 +1,210 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+	"github.com/mctofu/homekit/client/units"
+)
+
+// discoveryConfig is a Home Assistant MQTT discovery payload and the topic to publish it to.
+type discoveryConfig struct {
+	topic   string
+	payload []byte
+}
+
+// discoveryEntity is the subset of Home Assistant's MQTT entity configuration used by the bridge.
+type discoveryEntity struct {
+	Name              string           `json:"name"`
+	UniqueID          string           `json:"unique_id"`
+	StateTopic        string           `json:"state_topic,omitempty"`
+	CommandTopic      string           `json:"command_topic,omitempty"`
+	AvailabilityTopic string           `json:"availability_topic"`
+	PayloadOn         string           `json:"payload_on,omitempty"`
+	PayloadOff        string           `json:"payload_off,omitempty"`
+	Unit              string           `json:"unit_of_measurement,omitempty"`
+	DeviceClass       string           `json:"device_class,omitempty"`
+	Min               *float64         `json:"min,omitempty"`
+	Max               *float64         `json:"max,omitempty"`
+	Step              *float64         `json:"step,omitempty"`
+	Device            *discoveryDevice `json:"device"`
+}
+
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer,omitempty"`
+	Model        string   `json:"model,omitempty"`
+	SWVersion    string   `json:"sw_version,omitempty"`
+}
+
+// sensorClasses maps numeric characteristic types to Home Assistant sensor device classes.
+var sensorClasses = map[string]string{
+	characteristic.TypeCurrentTemperature:       "temperature",
+	characteristic.TypeCurrentRelativeHumidity:  "humidity",
+	characteristic.TypeCurrentAmbientLightLevel: "illuminance",
+	characteristic.TypeCarbonDioxideLevel:       "carbon_dioxide",
+	characteristic.TypeCarbonMonoxideLevel:      "carbon_monoxide",
+	characteristic.TypeBatteryLevel:             "battery",
+	characteristic.TypeAirParticulateDensity:    "pm25",
+	characteristic.TypePM10Density:              "pm10",
+	characteristic.TypeVOCDensity:               "volatile_organic_compounds",
+	characteristic.TypeNitrogenDioxideDensity:   "nitrogen_dioxide",
+	characteristic.TypeOzoneDensity:             "ozone",
+	characteristic.TypeSulphurDioxideDensity:    "sulphur_dioxide",
+}
+
+// binarySensorClasses maps bool characteristic types to Home Assistant binary sensor device classes.
+var binarySensorClasses = map[string]string{
+	characteristic.TypeMotionDetected: "motion",
+}
+
+var nonIDChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// discoveryConfigs returns Home Assistant discovery payloads for the bridged characteristics.
+// The accessory information service and name characteristics are skipped.
+func discoveryConfigs(prefix, discoveryPrefix, alias string, db []*client.RawAccessory, chars []*bridgedCharacteristic) []*discoveryConfig {
+	nodeID := nonIDChars.ReplaceAllString(prefix+"_"+alias, "_")
+
+	devices := make(map[uint64]*discoveryDevice, len(db))
+	for _, acc := range db {
+		devices[acc.ID] = accessoryDevice(nodeID, alias, acc)
+	}
+
+	var configs []*discoveryConfig
+	for _, c := range chars {
+		if c.service.Type == service.TypeAccessoryInformation || c.characteristic.Type == characteristic.TypeName {
+			continue
+		}
+
+		component, entity := discoveryEntityFor(c)
+		if component == "" {
+			continue
+		}
+
+		objectID := fmt.Sprintf("%d_%d", c.accessoryID, c.characteristic.ID)
+		entity.UniqueID = nodeID + "_" + objectID
+		entity.AvailabilityTopic = AvailabilityTopic(prefix)
+		entity.Device = devices[c.accessoryID]
+
+		svcName := c.service.Name()
+		if svcName == "" {
+			svcName = service.NameForType(c.service.Type)
+		}
+		entity.Name = svcName + " " + characteristic.NameForType(c.characteristic.Type)
+
+		payload, err := json.Marshal(entity)
+		if err != nil {
+			continue
+		}
+
+		configs = append(configs, &discoveryConfig{
+			topic:   strings.Join([]string{discoveryPrefix, component, nodeID, objectID, "config"}, "/"),
+			payload: payload,
+		})
+	}
+
+	return configs
+}
+
+// discoveryEntityFor returns the Home Assistant component and entity for the characteristic
+// based on its format and permissions. An empty component is returned if the characteristic
+// isn't supported.
+func discoveryEntityFor(c *bridgedCharacteristic) (string, *discoveryEntity) {
+	ch := c.characteristic
+	readable := ch.HasPermission(characteristic.PermissionPairedRead)
+	writable := ch.HasPermission(characteristic.PermissionPairedWrite)
+
+	entity := &discoveryEntity{}
+	if readable {
+		entity.StateTopic = c.topic
+	}
+	if writable {
+		entity.CommandTopic = c.topic + "/set"
+	}
+
+	switch c.characteristic.ValueFormat() {
+	case "bool":
+		entity.PayloadOn = "true"
+		entity.PayloadOff = "false"
+		if writable {
+			return "switch", entity
+		}
+		entity.DeviceClass = binarySensorClasses[ch.Type]
+		return "binary_sensor", entity
+	case "uint8", "uint16", "uint32", "uint64", "int", "float":
+		// Home Assistant uses the same unit symbols
+		entity.Unit = units.Unit(ch.Unit).Symbol()
+		if writable {
+			entity.Min = metadataFloat(ch.MinValue)
+			entity.Max = metadataFloat(ch.MaxValue)
+			entity.Step = metadataFloat(ch.StepValue)
+			return "number", entity
+		}
+		if !readable {
+			return "", nil
+		}
+		entity.DeviceClass = sensorClasses[ch.Type]
+		return "sensor", entity
+	case "string":
+		if writable {
+			return "text", entity
+		}
+		if !readable {
+			return "", nil
+		}
+		return "sensor", entity
+	default:
+		return "", nil
+	}
+}
+
+func accessoryDevice(nodeID, alias string, acc *client.RawAccessory) *discoveryDevice {
+	device := &discoveryDevice{
+		Identifiers: []string{nodeID + "_" + strconv.FormatUint(acc.ID, 10)},
+		Name:        alias,
+	}
+
+	infoSvc := acc.ServiceByType(service.TypeAccessoryInformation)
+	if infoSvc == nil {
+		return device
+	}
+
+	if name := infoSvc.Name(); name != "" {
+		device.Name = name
+	}
+	device.Manufacturer = stringValue(infoSvc, characteristic.TypeManufacturer)
+	device.Model = stringValue(infoSvc, characteristic.TypeModel)
+	device.SWVersion = stringValue(infoSvc, characteristic.TypeFirmwareRevision)
+
+	return device
+}
+
+func stringValue(svc *service.RawService, t string) string {
+	c := svc.CharacteristicByType(t)
+	if c == nil {
+		return ""
+	}
+	s, err := c.Value.String()
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+func metadataFloat(v characteristic.Value) *float64 {
+	if len(v) == 0 {
+		return nil
+	}
+	f, err := v.Float64()
+	if err != nil {
+		return nil
+	}
+	return &f
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats supported by the --output flag.
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+	outputCSV  = "csv"
+)
+
+var outputFormats = []string{outputText, outputJSON, outputYAML, outputCSV}
+
+// outputFormat is set by the global --output flag.
+var outputFormat string
+
+// csvTable is implemented by command results that can be rendered as csv.
+type csvTable interface {
+	csvHeader() []string
+	csvRecords() [][]string
+}
+
+func validateOutputFormat() error {
+	for _, f := range outputFormats {
+		if outputFormat == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %q, expected one of %s", outputFormat, strings.Join(outputFormats, ", "))
+}
+
+// structuredOutput returns true if a machine-readable output format was requested.
+func structuredOutput() bool {
+	return outputFormat != outputText
+}
+
+// progressWriter returns where progress messages should be written. They're redirected to
+// stderr when using a machine-readable format so stdout only contains the result.
+func progressWriter() io.Writer {
+	if structuredOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// render writes v to stdout in the requested output format. The text function is used
+// to render the human readable format.
+func render(v csvTable, text func(w io.Writer)) error {
+	return renderTo(os.Stdout, outputFormat, v, text)
+}
+
+func renderTo(w io.Writer, format string, v csvTable, text func(w io.Writer)) error {
+	switch format {
+	case outputText:
+		text(w)
+		return nil
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case outputYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case outputCSV:
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write(v.csvHeader()); err != nil {
+			return err
+		}
+		if err := csvWriter.WriteAll(v.csvRecords()); err != nil {
+			return err
+		}
+		return csvWriter.Error()
+	default:
+		return fmt.Errorf("unhandled output format: %s", format)
+	}
+}
+
+// outputValue converts a characteristic value to a representation that can be encoded
+// by all output formats. Binary values are hex encoded to match the input format of
+// setCharacteristics and values of an unknown format are decoded generically.
+func outputValue(decoded interface{}, raw characteristic.Value) interface{} {
+	switch v := decoded.(type) {
+	case []byte:
+		return hex.EncodeToString(v)
+	case characteristic.UndefinedValue, characteristic.UnknownType:
+		var generic interface{}
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return nil
+		}
+		return generic
+	default:
+		return v
+	}
+}
+
+// formatOutputValue decodes a characteristic value of the format with outputValue. Values that
+// don't match the format are reported as invalid rather than failing the command.
+func formatOutputValue(format string, raw characteristic.Value) interface{} {
+	decoded, err := characteristic.ValueForFormat(format, raw)
+	if err != nil {
+		return invalidValue(err)
+	}
+	return outputValue(decoded, raw)
+}
+
+func invalidValue(err error) string {
+	return fmt.Sprintf("<invalid: %v>", err)
+}
+
+// formatCSVValue formats a value for a csv cell.
+func formatCSVValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// messageOutput is the output schema of commands that only report a result message.
+type messageOutput struct {
+	Message string `json:"message" yaml:"message"`
+}
+
+func (m messageOutput) csvHeader() []string {
+	return []string{"message"}
+}
+
+func (m messageOutput) csvRecords() [][]string {
+	return [][]string{{m.Message}}
+}
+
+// renderMessage prints a result message in the requested output format.
+func renderMessage(format string, a ...interface{}) error {
+	return renderMessageTo(os.Stdout, format, a...)
+}
+
+// renderMessageTo writes a result message to w in the requested output format.
+func renderMessageTo(w io.Writer, format string, a ...interface{}) error {
+	msg := messageOutput{Message: fmt.Sprintf(format, a...)}
+	return renderTo(w, outputFormat, msg, func(w io.Writer) {
+		fmt.Fprintln(w, msg.Message)
+	})
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccessoriesJSON = `[
+  {
+    "aid": 1,
+    "services": [
+      {
+        "iid": 1,
+        "type": "3E",
+        "characteristics": [
+          {"iid": 2, "type": "23", "format": "string", "value": "Sensor", "perms": ["pr"]},
+          {"iid": 3, "type": "30", "format": "string", "value": "abc123", "perms": ["pr"]}
+        ]
+      },
+      {
+        "iid": 8,
+        "type": "8A",
+        "characteristics": [
+          {"iid": 10, "type": "11", "format": "float", "unit": "celsius", "value": 28.5, "perms": ["pr", "ev"]}
+        ]
+      }
+    ]
+  }
+]`
+
+func TestRenderAccessories(t *testing.T) {
+	var accessories []*client.RawAccessory
+	require.NoError(t, json.Unmarshal([]byte(testAccessoriesJSON), &accessories))
+	out := newAccessoriesOutput(accessories)
+
+	noText := func(w io.Writer) {}
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, renderTo(&jsonOut, outputJSON, out, noText))
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &decoded))
+	require.Equal(t, "Sensor", decoded[0]["name"])
+	require.Equal(t, "abc123", decoded[0]["serialNumber"])
+
+	var yamlOut bytes.Buffer
+	require.NoError(t, renderTo(&yamlOut, outputYAML, out, noText))
+	require.Contains(t, yamlOut.String(), "typeName: CurrentTemperature")
+
+	var csvOut bytes.Buffer
+	require.NoError(t, renderTo(&csvOut, outputCSV, out, noText))
+	require.Equal(t,
+		"aid,accessoryName,serviceIID,serviceType,serviceTypeName,iid,type,typeName,format,unit,perms,value\n"+
+			"1,Sensor,1,3E,AccessoryInformation,2,23,Name,string,,pr,Sensor\n"+
+			"1,Sensor,1,3E,AccessoryInformation,3,30,SerialNumber,string,,pr,abc123\n"+
+			"1,Sensor,8,8A,TemperatureSensor,10,11,CurrentTemperature,float,celsius,pr ev,28.5\n",
+		csvOut.String(),
+	)
+
+	var textOut bytes.Buffer
+	require.NoError(t, renderTo(&textOut, outputText, out, func(w io.Writer) {
+		_, _ = w.Write([]byte("text"))
+	}))
+	require.Equal(t, "text", textOut.String())
+}
+
+func TestAccessoriesOutputSimulated(t *testing.T) {
+	var accessories []*client.RawAccessory
+	require.NoError(t, json.Unmarshal([]byte(`[{"aid": 1, "services": [{"iid": 8, "type": "8C", "characteristics": [
+	  {"iid": 9, "type": "7C", "format": "uint8", "value": 20, "perms": ["pr", "pw"], "minValue": 0, "maxValue": 100, "minStep": 1},
+	  {"iid": 10, "type": "24", "format": "tlv8", "value": "AQE=", "perms": ["pr"]},
+	  {"iid": 11, "type": "24", "format": "data", "value": "ABCD", "perms": ["pr"]}
+	]}]}]`), &accessories))
+
+	out := newAccessoriesOutput(accessories)
+	chs := out[0].Services[0].Characteristics
+	require.Equal(t, "", chs[0].Encoding)
+	require.Equal(t, "base64", chs[1].Encoding)
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, renderTo(&jsonOut, outputJSON, out, func(w io.Writer) {}))
+
+	// the json output can be served by the simulate command
+	simulated, err := hapfake.ReadAccessories(&jsonOut)
+	require.NoError(t, err)
+
+	target := simulated[0].Services[0].Characteristics[0]
+	require.Equal(t, "20", string(target.Value))
+	require.Equal(t, "0", string(target.MinValue))
+	require.Equal(t, "100", string(target.MaxValue))
+	require.Equal(t, "1", string(target.StepValue))
+	require.Equal(t, `"AQE="`, string(simulated[0].Services[0].Characteristics[1].Value))
+	// base64 values that are also valid hex are served unchanged
+	require.Equal(t, `"ABCD"`, string(simulated[0].Services[0].Characteristics[2].Value))
+}
+
+func TestAccessoriesOutputInvalidValue(t *testing.T) {
+	var accessories []*client.RawAccessory
+	require.NoError(t, json.Unmarshal([]byte(`[{"aid": 1, "services": [{"iid": 1, "type": "3E", "characteristics": [
+	  {"iid": 2, "type": "23", "format": "string", "value": 5, "perms": ["pr"]},
+	  {"iid": 3, "type": "6D", "format": "uint8", "value": 40.5, "perms": ["pr"]},
+	  {"iid": 4, "type": "6D", "format": "uint8", "value": 40.0, "perms": ["pr"]}
+	]}]}]`), &accessories))
+
+	out := newAccessoriesOutput(accessories)
+	chs := out[0].Services[0].Characteristics
+	require.Equal(t, "", out[0].Name)
+	require.NotEmpty(t, out[0].Error)
+	// values that don't match the format are kept as reported
+	require.Equal(t, 5.0, chs[0].Value)
+	require.Equal(t, 40.5, chs[1].Value)
+	require.Equal(t, uint8(40), chs[2].Value)
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, renderTo(&jsonOut, outputJSON, out, func(w io.Writer) {}))
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &decoded))
+	require.Equal(t, out[0].Error, decoded[0]["error"])
+}
@@ -47,13 +47,8 @@ func pair(
 		return err
 	}
 
-	for _, pair := range cfg.AccessoryPairings {
-		if pair.DeviceID == deviceID {
-			return fmt.Errorf("%s is already paired as %s", pair.DeviceID, pair.Name)
-		}
-		if pair.Name == name {
-			return fmt.Errorf("%s is already aliased to %s", pair.Name, pair.DeviceID)
-		}
+	if err := checkPairingConflict(cfg, deviceID, name); err != nil {
+		return err
 	}
 
 	pairDevice, err := client.DeviceByID(ctx, deviceID, 10*time.Second)
@@ -103,7 +98,5 @@ func pair(
 		return fmt.Errorf("could not save pairing - review manual pairing info: %v", err)
 	}
 
-	fmt.Println("Accessory paired successfully!")
-
-	return nil
+	return renderMessage("Accessory paired successfully!")
 }
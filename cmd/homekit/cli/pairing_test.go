@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/stretchr/testify/require"
+)
+
+// pairTestController pairs a new controller with each of the fake accessories and saves its
+// config. The accessories are named fake1, fake2...
+func pairTestController(t *testing.T, servers ...*hapfake.Server) (string, *config.ControllerConfig) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	identity, err := client.NewRandomControllerConfig()
+	require.NoError(t, err)
+
+	cfg := &config.ControllerConfig{
+		Name:       "test",
+		DeviceID:   identity.DeviceID,
+		PublicKey:  identity.PublicKey,
+		PrivateKey: identity.PrivateKey,
+	}
+	for i, server := range servers {
+		connCfg, err := client.NewSetupClient(&http.Client{}).Pair(ctx,
+			&client.AccessoryPairingConfig{
+				IPConnectionInfo: client.IPConnectionInfo{IPAddress: server.Host(), Port: server.Port()},
+				PIN:              server.PIN(),
+				DeviceID:         server.DeviceID(),
+			},
+			identity,
+		)
+		require.NoError(t, err)
+		cfg.AccessoryPairings = append(cfg.AccessoryPairings, &config.AccessoryPairing{
+			Name:             fmt.Sprintf("fake%d", i+1),
+			DeviceID:         connCfg.DeviceID,
+			PublicKey:        connCfg.PublicKey,
+			IPConnectionInfo: connCfg.IPConnectionInfo,
+		})
+	}
+
+	configPath := t.TempDir()
+	require.NoError(t, config.SaveControllerConfig(configPath, cfg, false))
+
+	return configPath, cfg
+}
+
+// testClientContext returns the context of a client command for the accessory and a client
+// connected to it.
+func testClientContext(t *testing.T, configPath string, cfg *config.ControllerConfig, name string) (*clientContext, *client.AccessoryClient) {
+	t.Helper()
+
+	accClient := pairingClient(controllerIdentity(cfg), findAccessoryPairing(cfg, name))
+	t.Cleanup(func() { accClient.Close() })
+
+	return &clientContext{AccessoryName: name, Config: cfg, ConfigPath: configPath}, accClient
+}
+
+// addTestController adds a new controller to the accessory with the permissions.
+func addTestController(t *testing.T, ctx context.Context, accClient *client.AccessoryClient, permissions byte) *client.ControllerIdentity {
+	t.Helper()
+
+	identity, err := client.NewRandomControllerConfig()
+	require.NoError(t, err)
+	require.NoError(t, accClient.AddPairing(ctx, &client.AddPairingRequest{
+		DeviceID:    identity.DeviceID,
+		PublicKey:   identity.PublicKey,
+		Permissions: permissions,
+	}))
+
+	return identity
+}
+
+func serverPairings(server *hapfake.Server) map[string]bool {
+	admins := make(map[string]bool)
+	for _, p := range server.Pairings() {
+		admins[p.ControllerID] = p.Admin
+	}
+	return admins
+}
+
+func TestHandoff(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	newController, err := client.NewRandomControllerConfig()
+	require.NoError(t, err)
+
+	// check the state when this controller's pairing is removed
+	var (
+		server       *hapfake.Server
+		configPath   string
+		cfg          *config.ControllerConfig
+		removed      bool
+		newAdmin     bool
+		savedPairing *config.AccessoryPairing
+		readErr      error
+	)
+	onPairingChange := func() {
+		if cfg == nil || removed {
+			return
+		}
+		pairings := serverPairings(server)
+		if _, ok := pairings[cfg.DeviceID]; ok {
+			return
+		}
+		removed = true
+		newAdmin = pairings[newController.DeviceID]
+		var saved *config.ControllerConfig
+		if saved, readErr = config.ReadControllerConfig(configPath, cfg.Name); readErr == nil {
+			savedPairing = findAccessoryPairing(saved, "fake1")
+		}
+	}
+	server, err = hapfake.NewServer(hapfake.Config{OnPairingChange: func() { onPairingChange() }})
+	require.NoError(t, err)
+	defer server.Close()
+
+	configPath, cfg = pairTestController(t, server)
+	clientCtx, accClient := testClientContext(t, configPath, cfg, "fake1")
+
+	require.NoError(t, handoff(ctx, clientCtx, accClient, newController.DeviceID,
+		base64.StdEncoding.EncodeToString(newController.PublicKey)))
+
+	require.True(t, removed)
+	require.NoError(t, readErr)
+	require.True(t, newAdmin, "new controller is an admin before the old pairing is removed")
+	require.Nil(t, savedPairing, "config is saved before the old pairing is removed")
+	require.Equal(t, map[string]bool{newController.DeviceID: true}, serverPairings(server))
+}
+
+func TestHandoffToThisController(t *testing.T) {
+	server, err := hapfake.NewServer(hapfake.Config{})
+	require.NoError(t, err)
+	defer server.Close()
+
+	configPath, cfg := pairTestController(t, server)
+	clientCtx, accClient := testClientContext(t, configPath, cfg, "fake1")
+
+	err = handoff(context.Background(), clientCtx, accClient, cfg.DeviceID, base64.StdEncoding.EncodeToString(cfg.PublicKey))
+	require.EqualError(t, err, cfg.DeviceID+" is this controller")
+	require.Equal(t, map[string]bool{cfg.DeviceID: true}, serverPairings(server))
+}
+
+func TestRemovePairing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server, err := hapfake.NewServer(hapfake.Config{})
+	require.NoError(t, err)
+	defer server.Close()
+
+	configPath, cfg := pairTestController(t, server)
+	clientCtx, accClient := testClientContext(t, configPath, cfg, "fake1")
+	other := addTestController(t, ctx, accClient, client.PermissionUser)
+
+	// this controller's own pairing can't be removed
+	err = removePairing(ctx, clientCtx, accClient, cfg.DeviceID)
+	require.EqualError(t, err, cfg.DeviceID+" is this controller, use unpair to remove it")
+	require.Len(t, server.Pairings(), 2)
+
+	require.NoError(t, removePairing(ctx, clientCtx, accClient, other.DeviceID))
+	require.Equal(t, map[string]bool{cfg.DeviceID: true}, serverPairings(server))
+
+	err = removePairing(ctx, clientCtx, accClient, other.DeviceID)
+	require.EqualError(t, err, "controller "+other.DeviceID+" is not paired with the accessory")
+}
+
+func TestUpdatePairing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server, err := hapfake.NewServer(hapfake.Config{})
+	require.NoError(t, err)
+	defer server.Close()
+
+	configPath, cfg := pairTestController(t, server)
+	clientCtx, accClient := testClientContext(t, configPath, cfg, "fake1")
+	other := addTestController(t, ctx, accClient, client.PermissionUser)
+
+	// this controller can't demote itself
+	err = updatePairing(ctx, clientCtx, accClient, cfg.DeviceID, false)
+	require.EqualError(t, err, cfg.DeviceID+" is this controller, refusing to remove its admin access")
+	require.Equal(t, map[string]bool{cfg.DeviceID: true, other.DeviceID: false}, serverPairings(server))
+
+	require.NoError(t, updatePairing(ctx, clientCtx, accClient, other.DeviceID, true))
+	require.Equal(t, map[string]bool{cfg.DeviceID: true, other.DeviceID: true}, serverPairings(server))
+
+	require.NoError(t, updatePairing(ctx, clientCtx, accClient, other.DeviceID, false))
+	require.Equal(t, map[string]bool{cfg.DeviceID: true, other.DeviceID: false}, serverPairings(server))
+}
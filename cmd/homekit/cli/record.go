@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/cmd/homekit/cli/history"
+	"github.com/spf13/cobra"
+)
+
+func recordCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "record",
+		Short: "Record characteristic values of an accessory",
+		Long: "Record characteristic values of an accessory to a local history store until interrupted.\n\n" +
+			"Readable numeric and bool characteristics are recorded unless characteristics are selected. " +
+			"Recorded values can be queried with the history command.",
+	}
+
+	characteristicIDs := cmd.Flags().StringArrayP("c", "c", nil, "Characteristic ID or selector to record (ex: 1.4, Window/TargetPosition)")
+	interval := cmd.Flags().Duration("interval", time.Minute, "Interval to read characteristics")
+	events := cmd.Flags().Bool("events", false, "Record characteristics that support events as they change instead of reading them")
+	retention := cmd.Flags().Duration("retention", 0, "Remove values older than the duration. Values are kept forever if 0.")
+	dir := cmd.Flags().String("dir", "", "Directory of the history store. Defaults to a directory in the config path.")
+
+	cmd.RunE = clientCommandRunner(cmd,
+		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+			storeDir := historyDir(clientCtx.ConfigPath, clientCtx.Config.Name, clientCtx.AccessoryName, *dir)
+			return record(ctx, accClient, storeDir, *characteristicIDs, history.RecordConfig{
+				Interval:  *interval,
+				Events:    *events,
+				Retention: *retention,
+			})
+		},
+	)
+
+	return cmd
+}
+
+// historyDir returns the directory of the accessory's history store. dir is used if set.
+func historyDir(configPath, controllerName, name, dir string) string {
+	if dir != "" {
+		return dir
+	}
+	return path.Join(configPath, "history", controllerName, name)
+}
+
+func record(ctx context.Context, accClient *client.AccessoryClient, dir string, characteristicIDs []string, cfg history.RecordConfig) error {
+	selectors := make([]*client.CharacteristicSelector, 0, len(characteristicIDs))
+	for _, param := range characteristicIDs {
+		sel, err := client.ParseCharacteristicSelector(param)
+		if err != nil {
+			return err
+		}
+		selectors = append(selectors, sel)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg.Logger = log.New(progressWriter(), "", log.LstdFlags)
+	cfg.Logger.Printf("recording to %s", dir)
+
+	err := history.Record(ctx, accClient, history.NewStore(dir), selectors, cfg)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("record: %v", err)
+	}
+
+	return nil
+}
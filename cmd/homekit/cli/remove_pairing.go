@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/spf13/cobra"
+)
+
+func removePairingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "removePairing",
+		Short: "Remove another controller's access to a paired accessory",
+	}
+
+	deviceID := cmd.Flags().String("id", "", "Device id of controller to remove")
+	markFlagRequired(cmd, "id")
+
+	cmd.RunE = clientCommandRunner(cmd,
+		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+			return removePairing(ctx, clientCtx, accClient, *deviceID)
+		},
+	)
+
+	return cmd
+}
+
+func removePairing(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient, deviceID string) error {
+	if deviceID == clientCtx.Config.DeviceID {
+		return fmt.Errorf("%s is this controller, use unpair to remove it", deviceID)
+	}
+
+	if _, err := findPairing(ctx, accClient, deviceID); err != nil {
+		return err
+	}
+
+	if err := accClient.RemovePairing(ctx, deviceID); err != nil {
+		return fmt.Errorf("removePairing: %v", err)
+	}
+
+	return renderMessage("Removed pairing for %s", deviceID)
+}
+
+// findPairing returns the pairing for the controller with deviceID or an error if the
+// controller isn't paired with the accessory.
+func findPairing(ctx context.Context, accClient *client.AccessoryClient, deviceID string) (*client.ListPairingResponse, error) {
+	pairs, err := accClient.ListPairings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listPairings: %v", err)
+	}
+
+	for _, p := range pairs {
+		if p.ControllerID == deviceID {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("controller %s is not paired with the accessory", deviceID)
+}
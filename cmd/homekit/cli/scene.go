@@ -0,0 +1,539 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func sceneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scene",
+		Short: "Save and apply snapshots of characteristic values",
+	}
+
+	cmd.AddCommand(sceneSaveCmd())
+	cmd.AddCommand(sceneApplyCmd())
+	cmd.AddCommand(sceneListCmd())
+	cmd.AddCommand(sceneShowCmd())
+	cmd.AddCommand(sceneDeleteCmd())
+
+	return cmd
+}
+
+type sceneCommand func(ctx context.Context, configPath, controllerName, sceneName string) error
+
+// sceneCommandRunner runs a command that acts on the scene named by its only argument.
+func sceneCommandRunner(cmd *cobra.Command, sceneCmd sceneCommand) runner {
+	cmd.Args = cobra.ExactArgs(1)
+
+	var sceneName string
+	run := configCommandRunner(cmd, func(ctx context.Context, configPath, controllerName string) error {
+		return sceneCmd(ctx, configPath, controllerName, sceneName)
+	})
+
+	return func(cmd *cobra.Command, args []string) error {
+		sceneName = args[0]
+		return run(cmd, args)
+	}
+}
+
+func sceneSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save <scene>",
+		Short: "Save the current values of writable characteristics to a scene",
+		Long: "Save the current values of writable characteristics to a scene.\n\n" +
+			"Each selector is resolved against every accessory named with --name. A selector " +
+			"only needs to match characteristics of one of the accessories.",
+	}
+
+	names := cmd.Flags().StringArrayP("name", "n", nil, "Name of accessory to capture values from")
+	markFlagRequired(cmd, "name")
+	selectors := cmd.Flags().StringArrayP("c", "c", nil, "Characteristic ID or selector (ex: 1.4, Window/TargetPosition)")
+	markFlagRequired(cmd, "c")
+	merge := cmd.Flags().Bool("merge", false, "Add the values to an existing scene instead of replacing it")
+
+	cmd.RunE = sceneCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName, sceneName string) error {
+			return sceneSave(ctx, configPath, controllerName, sceneName, *names, *selectors, *merge)
+		},
+	)
+
+	return cmd
+}
+
+func sceneSave(
+	ctx context.Context,
+	configPath, controllerName, sceneName string,
+	names, selectorParams []string,
+	merge bool,
+) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	selectors := make([]*client.CharacteristicSelector, 0, len(selectorParams))
+	for _, param := range selectorParams {
+		sel, err := client.ParseCharacteristicSelector(param)
+		if err != nil {
+			return err
+		}
+		selectors = append(selectors, sel)
+	}
+
+	matched := make([]bool, len(selectors))
+	var values []*config.SceneValue
+	for _, name := range names {
+		accValues, err := captureSceneValues(ctx, cfg, name, selectors, matched)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		values = append(values, accValues...)
+	}
+
+	for i, ok := range matched {
+		if !ok {
+			return fmt.Errorf("no writable characteristics match %s", selectors[i])
+		}
+	}
+
+	scene := cfg.FindScene(sceneName)
+	if scene == nil || !merge {
+		scene = &config.Scene{Name: sceneName}
+	}
+	scene.Merge(values...)
+	cfg.SaveScene(scene)
+
+	if err := config.SaveControllerConfig(configPath, cfg, true); err != nil {
+		return err
+	}
+
+	return renderMessage("Saved %d values to scene %s", len(scene.Values), sceneName)
+}
+
+// captureSceneValues reads the current values of the readable and writable characteristics
+// of the accessory matching the selectors. matched is updated with the selectors that matched
+// a characteristic.
+func captureSceneValues(
+	ctx context.Context,
+	cfg *config.ControllerConfig,
+	name string,
+	selectors []*client.CharacteristicSelector,
+	matched []bool,
+) (values []*config.SceneValue, rErr error) {
+	accClient, err := accessoryClient(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cErr := accClient.Close(); cErr != nil {
+			rErr = multierror.Append(rErr, cErr)
+		}
+	}()
+
+	accessories, err := accClient.Accessories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reads []client.CharacteristicReadRequest
+	seen := make(map[client.CharacteristicReadRequest]bool)
+	for i, sel := range selectors {
+		for _, match := range sel.Select(accessories) {
+			ch := match.Characteristic
+			if !ch.HasPermission(characteristic.PermissionPairedRead) ||
+				!ch.HasPermission(characteristic.PermissionPairedWrite) {
+				continue
+			}
+			matched[i] = true
+
+			req := client.CharacteristicReadRequest{
+				AccessoryID:      match.AccessoryID,
+				CharacteristicID: ch.ID,
+			}
+			if seen[req] {
+				continue
+			}
+			seen[req] = true
+			reads = append(reads, req)
+		}
+	}
+
+	if len(reads) == 0 {
+		return nil, nil
+	}
+
+	resps, err := accClient.Characteristics(ctx, &client.CharacteristicsReadRequest{
+		Characteristics: reads,
+		Type:            true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resp := range resps {
+		if resp.Status != nil && *resp.Status != 0 {
+			return nil, fmt.Errorf("read %d.%d failed with status %d", resp.AccessoryID, resp.CharacteristicID, *resp.Status)
+		}
+		value := &config.SceneValue{
+			Accessory:        name,
+			AccessoryID:      resp.AccessoryID,
+			CharacteristicID: resp.CharacteristicID,
+			Value:            resp.Value,
+		}
+		if resp.Type != nil {
+			value.Type = *resp.Type
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func sceneApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <scene>",
+		Short: "Write the values saved in a scene",
+		Long: "Write the values saved in a scene.\n\n" +
+			"Values are written with one request per accessory and accessories are updated in parallel.",
+	}
+
+	cmd.RunE = sceneCommandRunner(cmd, sceneApply)
+
+	return cmd
+}
+
+// sceneResultOutput is the output schema of a characteristic written by scene apply.
+type sceneResultOutput struct {
+	Accessory        string      `json:"accessory" yaml:"accessory"`
+	AccessoryID      uint64      `json:"aid" yaml:"aid"`
+	CharacteristicID uint64      `json:"iid" yaml:"iid"`
+	TypeName         string      `json:"typeName" yaml:"typeName"`
+	Value            interface{} `json:"value" yaml:"value"`
+	Status           int         `json:"status" yaml:"status"`
+	Error            string      `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type sceneResultsOutput []sceneResultOutput
+
+func (s sceneResultsOutput) csvHeader() []string {
+	return []string{"accessory", "aid", "iid", "typeName", "value", "status", "error"}
+}
+
+func (s sceneResultsOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(s))
+	for _, r := range s {
+		records = append(records, []string{
+			r.Accessory,
+			strconv.FormatUint(r.AccessoryID, 10),
+			strconv.FormatUint(r.CharacteristicID, 10),
+			r.TypeName,
+			formatCSVValue(r.Value),
+			strconv.Itoa(r.Status),
+			r.Error,
+		})
+	}
+	return records
+}
+
+func (s sceneResultOutput) failed() bool {
+	return s.Status != 0 || s.Error != ""
+}
+
+func sceneApply(ctx context.Context, configPath, controllerName, sceneName string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	scene := cfg.FindScene(sceneName)
+	if scene == nil {
+		return fmt.Errorf("scene %s not found", sceneName)
+	}
+
+	var accessoryNames []string
+	valuesByAccessory := make(map[string][]*config.SceneValue)
+	for _, v := range scene.Values {
+		if _, ok := valuesByAccessory[v.Accessory]; !ok {
+			accessoryNames = append(accessoryNames, v.Accessory)
+		}
+		valuesByAccessory[v.Accessory] = append(valuesByAccessory[v.Accessory], v)
+	}
+
+	accResults := make([]sceneResultsOutput, len(accessoryNames))
+	var wg sync.WaitGroup
+	for i, name := range accessoryNames {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			accResults[i] = applySceneValues(ctx, cfg, name, valuesByAccessory[name])
+		}(i, name)
+	}
+	wg.Wait()
+
+	var results sceneResultsOutput
+	failed := 0
+	for _, accResult := range accResults {
+		for _, r := range accResult {
+			if r.failed() {
+				failed++
+			}
+			results = append(results, r)
+		}
+	}
+
+	if err := render(results, func(w io.Writer) {
+		for _, r := range results {
+			switch {
+			case r.Error != "":
+				fmt.Fprintf(w, "%s %d.%d %s: failed: %s\n", r.Accessory, r.AccessoryID, r.CharacteristicID, r.TypeName, r.Error)
+			case r.Status != 0:
+				fmt.Fprintf(w, "%s %d.%d %s: write failed with status %d\n", r.Accessory, r.AccessoryID, r.CharacteristicID, r.TypeName, r.Status)
+			default:
+				fmt.Fprintf(w, "%s %d.%d %s: %v\n", r.Accessory, r.AccessoryID, r.CharacteristicID, r.TypeName, r.Value)
+			}
+		}
+	}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d values could not be written", failed, len(results))
+	}
+
+	return nil
+}
+
+// applySceneValues writes the values to the accessory in a single request and returns the
+// result of each write.
+func applySceneValues(ctx context.Context, cfg *config.ControllerConfig, name string, values []*config.SceneValue) sceneResultsOutput {
+	results := make(sceneResultsOutput, 0, len(values))
+	writes := make([]client.CharacteristicWriteRequest, 0, len(values))
+	for _, v := range values {
+		results = append(results, sceneResultOutput{
+			Accessory:        name,
+			AccessoryID:      v.AccessoryID,
+			CharacteristicID: v.CharacteristicID,
+			TypeName:         characteristic.NameForType(v.Type),
+			Value:            outputValue(characteristic.UndefinedValue{}, v.Value),
+		})
+		writes = append(writes, client.CharacteristicWriteRequest{
+			AccessoryID:      v.AccessoryID,
+			CharacteristicID: v.CharacteristicID,
+			Value:            v.Value,
+		})
+	}
+
+	failAll := func(err error) sceneResultsOutput {
+		for i := range results {
+			results[i].Error = err.Error()
+		}
+		return results
+	}
+
+	accClient, err := accessoryClient(cfg, name)
+	if err != nil {
+		return failAll(err)
+	}
+	defer accClient.Close()
+
+	resps, err := accClient.SetCharacteristics(ctx, &client.CharacteristicsWriteRequest{
+		Characteristics: writes,
+	})
+	if err != nil {
+		return failAll(err)
+	}
+
+	for _, resp := range resps {
+		if resp.Status == nil {
+			continue
+		}
+		for i := range results {
+			if results[i].AccessoryID == resp.AccessoryID && results[i].CharacteristicID == resp.CharacteristicID {
+				results[i].Status = *resp.Status
+			}
+		}
+	}
+
+	return results
+}
+
+func sceneListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved scenes",
+	}
+
+	cmd.RunE = configCommandRunner(cmd, sceneList)
+
+	return cmd
+}
+
+// sceneOutput is the output schema of a scene listed by scene list.
+type sceneOutput struct {
+	Name        string   `json:"name" yaml:"name"`
+	Accessories []string `json:"accessories" yaml:"accessories"`
+	Values      int      `json:"values" yaml:"values"`
+}
+
+type scenesOutput []sceneOutput
+
+func (s scenesOutput) csvHeader() []string {
+	return []string{"name", "accessories", "values"}
+}
+
+func (s scenesOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(s))
+	for _, scene := range s {
+		records = append(records, []string{
+			scene.Name,
+			formatCSVValue(scene.Accessories),
+			strconv.Itoa(scene.Values),
+		})
+	}
+	return records
+}
+
+func sceneList(ctx context.Context, configPath, controllerName string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	scenes := make(scenesOutput, 0, len(cfg.Scenes))
+	for _, scene := range cfg.Scenes {
+		out := sceneOutput{
+			Name:        scene.Name,
+			Accessories: []string{},
+			Values:      len(scene.Values),
+		}
+		seen := make(map[string]bool)
+		for _, v := range scene.Values {
+			if !seen[v.Accessory] {
+				seen[v.Accessory] = true
+				out.Accessories = append(out.Accessories, v.Accessory)
+			}
+		}
+		scenes = append(scenes, out)
+	}
+
+	return render(scenes, func(w io.Writer) {
+		if len(scenes) == 0 {
+			fmt.Fprintln(w, "No scenes")
+			return
+		}
+		for _, s := range scenes {
+			fmt.Fprintf(w, "%s: %d values %v\n", s.Name, s.Values, s.Accessories)
+		}
+	})
+}
+
+func sceneShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <scene>",
+		Short: "Show the values saved in a scene",
+	}
+
+	cmd.RunE = sceneCommandRunner(cmd, sceneShow)
+
+	return cmd
+}
+
+// sceneValueOutput is the output schema of a value shown by scene show.
+type sceneValueOutput struct {
+	Accessory        string      `json:"accessory" yaml:"accessory"`
+	AccessoryID      uint64      `json:"aid" yaml:"aid"`
+	CharacteristicID uint64      `json:"iid" yaml:"iid"`
+	Type             string      `json:"type" yaml:"type"`
+	TypeName         string      `json:"typeName" yaml:"typeName"`
+	Value            interface{} `json:"value" yaml:"value"`
+}
+
+type sceneValuesOutput []sceneValueOutput
+
+func (s sceneValuesOutput) csvHeader() []string {
+	return []string{"accessory", "aid", "iid", "type", "typeName", "value"}
+}
+
+func (s sceneValuesOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(s))
+	for _, v := range s {
+		records = append(records, []string{
+			v.Accessory,
+			strconv.FormatUint(v.AccessoryID, 10),
+			strconv.FormatUint(v.CharacteristicID, 10),
+			v.Type,
+			v.TypeName,
+			formatCSVValue(v.Value),
+		})
+	}
+	return records
+}
+
+func sceneShow(ctx context.Context, configPath, controllerName, sceneName string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	scene := cfg.FindScene(sceneName)
+	if scene == nil {
+		return fmt.Errorf("scene %s not found", sceneName)
+	}
+
+	values := make(sceneValuesOutput, 0, len(scene.Values))
+	for _, v := range scene.Values {
+		values = append(values, sceneValueOutput{
+			Accessory:        v.Accessory,
+			AccessoryID:      v.AccessoryID,
+			CharacteristicID: v.CharacteristicID,
+			Type:             v.Type,
+			TypeName:         characteristic.NameForType(v.Type),
+			Value:            outputValue(characteristic.UndefinedValue{}, v.Value),
+		})
+	}
+
+	return render(values, func(w io.Writer) {
+		fmt.Fprintf(w, "Scene: %s\n", scene.Name)
+		for _, v := range values {
+			fmt.Fprintf(w, "  %s %d.%d %s: %v\n", v.Accessory, v.AccessoryID, v.CharacteristicID, v.TypeName, v.Value)
+		}
+	})
+}
+
+func sceneDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <scene>",
+		Short: "Delete a saved scene",
+	}
+
+	cmd.RunE = sceneCommandRunner(cmd, sceneDelete)
+
+	return cmd
+}
+
+func sceneDelete(ctx context.Context, configPath, controllerName, sceneName string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	if !cfg.RemoveScene(sceneName) {
+		return fmt.Errorf("scene %s not found", sceneName)
+	}
+
+	if err := config.SaveControllerConfig(configPath, cfg, true); err != nil {
+		return err
+	}
+
+	return renderMessage("Scene %s deleted", sceneName)
+}
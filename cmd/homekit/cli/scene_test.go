@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/client/hapfake/hapfaketest"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/stretchr/testify/require"
+)
+
+const sceneAccessories = `{"accessories": [
+  {"aid": 1, "services": [
+    {"iid": 1, "type": "3E", "characteristics": [
+      {"iid": 2, "type": "23", "format": "string", "value": "Window", "perms": ["pr"]},
+      {"iid": 3, "type": "14", "format": "bool", "perms": ["pw"]}
+    ]},
+    {"iid": 8, "type": "8B", "characteristics": [
+      {"iid": 9, "type": "6D", "format": "uint8", "unit": "percentage", "value": 0, "perms": ["pr", "ev"],
+       "minValue": 0, "maxValue": 100, "minStep": 1},
+      {"iid": 10, "type": "7C", "format": "uint8", "unit": "percentage", "value": 0, "perms": ["pr", "pw", "ev"],
+       "minValue": 0, "maxValue": 100, "minStep": 1},
+      {"iid": 11, "type": "7B", "format": "int", "unit": "arcdegrees", "value": 0, "perms": ["pr", "pw", "ev"],
+       "minValue": -90, "maxValue": 90, "minStep": 1},
+      {"iid": 12, "type": "6F", "format": "bool", "perms": ["pw"]}
+    ]}
+  ]}
+]}`
+
+func TestScene(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// the first write to each accessory waits for a write to the other so the test only
+	// passes if the accessories are updated in parallel
+	var (
+		once       [2]sync.Once
+		arrived    = [2]chan struct{}{make(chan struct{}), make(chan struct{})}
+		overlapped = make(chan bool, 2)
+	)
+	onWrite := func(i int) func(hapfake.Write) {
+		return func(hapfake.Write) {
+			once[i].Do(func() {
+				close(arrived[i])
+				select {
+				case <-arrived[1-i]:
+					overlapped <- true
+				case <-time.After(2 * time.Second):
+					overlapped <- false
+				}
+			})
+		}
+	}
+	servers := []*hapfake.Server{
+		hapfaketest.NewServer(t, sceneAccessories, hapfake.Config{OnWrite: onWrite(0)}),
+		hapfaketest.NewServer(t, sceneAccessories, hapfake.Config{OnWrite: onWrite(1)}),
+	}
+	configPath, cfg := pairTestController(t, servers...)
+
+	require.NoError(t, servers[0].SetValue(1, 10, 30))
+	require.NoError(t, servers[0].SetValue(1, 11, 10))
+	require.NoError(t, servers[1].SetValue(1, 10, 70))
+	require.NoError(t, servers[1].SetValue(1, 11, -20))
+
+	require.NoError(t, sceneSave(ctx, configPath, cfg.Name, "evening", []string{"fake1", "fake2"}, []string{"Window/*"}, false))
+
+	// only the readable and writable characteristics are captured
+	saved, err := config.ReadControllerConfig(configPath, cfg.Name)
+	require.NoError(t, err)
+	scene := saved.FindScene("evening")
+	require.NotNil(t, scene)
+	require.Equal(t, []*config.SceneValue{
+		{Accessory: "fake1", AccessoryID: 1, CharacteristicID: 10, Type: "7C", Value: characteristic.Value("30")},
+		{Accessory: "fake1", AccessoryID: 1, CharacteristicID: 11, Type: "7B", Value: characteristic.Value("10")},
+		{Accessory: "fake2", AccessoryID: 1, CharacteristicID: 10, Type: "7C", Value: characteristic.Value("70")},
+		{Accessory: "fake2", AccessoryID: 1, CharacteristicID: 11, Type: "7B", Value: characteristic.Value("-20")},
+	}, scene.Values)
+
+	for _, server := range servers {
+		require.NoError(t, server.SetValue(1, 10, 0))
+		require.NoError(t, server.SetValue(1, 11, 0))
+	}
+
+	require.NoError(t, sceneApply(ctx, configPath, cfg.Name, "evening"))
+	require.True(t, <-overlapped, "accessories are updated in parallel")
+	require.True(t, <-overlapped, "accessories are updated in parallel")
+
+	require.Equal(t, []hapfake.Write{
+		{AccessoryID: 1, CharacteristicID: 10, Value: characteristic.Value("30")},
+		{AccessoryID: 1, CharacteristicID: 11, Value: characteristic.Value("10")},
+	}, servers[0].Writes())
+	require.Equal(t, []hapfake.Write{
+		{AccessoryID: 1, CharacteristicID: 10, Value: characteristic.Value("70")},
+		{AccessoryID: 1, CharacteristicID: 11, Value: characteristic.Value("-20")},
+	}, servers[1].Writes())
+}
+
+func TestSceneApplyFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	servers := []*hapfake.Server{
+		hapfaketest.NewServer(t, sceneAccessories, hapfake.Config{}),
+		hapfaketest.NewServer(t, sceneAccessories, hapfake.Config{}),
+	}
+	configPath, cfg := pairTestController(t, servers...)
+
+	cfg.SaveScene(&config.Scene{
+		Name: "evening",
+		Values: []*config.SceneValue{
+			{Accessory: "fake1", AccessoryID: 1, CharacteristicID: 10, Type: "7C", Value: characteristic.Value("30")},
+			{Accessory: "fake1", AccessoryID: 1, CharacteristicID: 11, Type: "7B", Value: characteristic.Value("10")},
+			{Accessory: "fake2", AccessoryID: 1, CharacteristicID: 10, Type: "7C", Value: characteristic.Value("200")},
+			{Accessory: "fake2", AccessoryID: 1, CharacteristicID: 11, Type: "7B", Value: characteristic.Value("-20")},
+		},
+	})
+	require.NoError(t, config.SaveControllerConfig(configPath, cfg, true))
+
+	// the values are written in a single request so a failed request fails all of them
+	servers[0].Fail(hapfake.Failure{Method: http.MethodPut, Path: "/characteristics", Status: hapfake.StatusResourceBusy, Count: 1})
+
+	err := sceneApply(ctx, configPath, cfg.Name, "evening")
+	require.EqualError(t, err, "3 of 4 values could not be written")
+
+	// the failing accessory doesn't stop the other from being updated
+	require.Empty(t, servers[0].Writes())
+	require.Equal(t, []hapfake.Write{
+		{AccessoryID: 1, CharacteristicID: 11, Value: characteristic.Value("-20")},
+	}, servers[1].Writes())
+
+	// each value reports its own status
+	scene := cfg.FindScene("evening")
+	servers[0].Fail(hapfake.Failure{Method: http.MethodPut, Path: "/characteristics", Status: hapfake.StatusResourceBusy, Count: 1})
+	results := applySceneValues(ctx, cfg, "fake1", scene.Values[:2])
+	require.Len(t, results, 2)
+	for _, r := range results {
+		require.Equal(t, hapfake.StatusResourceBusy, r.Status)
+	}
+
+	results = applySceneValues(ctx, cfg, "fake2", scene.Values[2:])
+	require.Len(t, results, 2)
+	require.Equal(t, hapfake.StatusInvalidValue, results[0].Status)
+	require.Equal(t, 0, results[1].Status)
+
+	require.NoError(t, servers[1].Close())
+	results = applySceneValues(ctx, cfg, "fake2", scene.Values[2:])
+	require.Len(t, results, 2)
+	for _, r := range results {
+		require.NotEmpty(t, r.Error)
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/mctofu/homekit/cmd/homekit/cli/gateway"
+	"github.com/spf13/cobra"
+)
+
+func serveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Expose paired accessories over HTTP",
+		Long: "Expose the controller's paired accessories over HTTP until interrupted.\n\n" +
+			"Requests must include the controller's gateway token as a bearer token. " +
+			"A token is generated and saved to the controller config the first time the gateway is served.",
+	}
+
+	listen := cmd.Flags().String("listen", ":8080", "Address to listen on")
+	names := cmd.Flags().StringArrayP("name", "n", nil, "Name of accessory to expose. Defaults to all paired accessories.")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			return serve(ctx, configPath, controllerName, *listen, *names)
+		},
+	)
+
+	return cmd
+}
+
+func serve(ctx context.Context, configPath, controllerName, listen string, names []string) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	if cfg.GatewayToken == "" {
+		token, err := generateToken()
+		if err != nil {
+			return fmt.Errorf("generate token: %v", err)
+		}
+		cfg.GatewayToken = token
+		if err := config.SaveControllerConfig(configPath, cfg, true); err != nil {
+			return fmt.Errorf("save controller config: %v", err)
+		}
+		fmt.Fprintf(progressWriter(), "Generated gateway token: %s\n", token)
+	}
+
+	if len(names) == 0 {
+		for _, pair := range cfg.AccessoryPairings {
+			names = append(names, pair.Name)
+		}
+	}
+
+	accessories := make(map[string]client.Accessory)
+	for _, name := range names {
+		accClient, err := accessoryClient(cfg, name)
+		if err != nil {
+			return err
+		}
+		defer accClient.Close()
+		accessories[name] = accClient
+	}
+
+	logger := log.New(progressWriter(), "", log.LstdFlags)
+	gw := gateway.NewServer(accessories, cfg.GatewayToken, logger)
+	defer gw.Close()
+
+	server := &http.Server{
+		Addr:     listen,
+		Handler:  gw.Handler(),
+		ErrorLog: logger,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	logger.Printf("serving %d accessories on %s", len(accessories), listen)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	// event streams stay open until their clients disconnect so stop them before shutting down
+	gw.Close()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("shutdown: %v", err)
+	}
+
+	return nil
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
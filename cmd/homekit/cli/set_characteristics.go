@@ -3,6 +3,9 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 
 	"github.com/mctofu/homekit/client"
 	"github.com/mctofu/homekit/client/characteristic"
@@ -16,33 +19,76 @@ func setCharacteristicsCmd() *cobra.Command {
 		Short:   "Set values of accessory characteristics",
 	}
 
-	characteristicParams := cmd.Flags().StringToStringP("c", "c", nil, "Characteristic ID value pair (ex: 1.4=10)")
+	characteristicParams := cmd.Flags().StringArrayP("c", "c", nil,
+		"Characteristic ID or selector and value pair (ex: 1.4=10, */TargetPosition=0)")
 	markFlagRequired(cmd, "c")
 
 	cmd.RunE = clientCommandRunner(cmd,
 		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
-			return setCharacteristics(ctx, accClient, *characteristicParams)
+			return setCharacteristics(ctx, os.Stdout, accClient, *characteristicParams)
 		},
 	)
 
 	return cmd
 }
 
-func setCharacteristics(ctx context.Context, accClient *client.AccessoryClient, characteristicParams map[string]string) error {
-	var reads []client.CharacteristicReadRequest
+// writeResultOutput is the output schema of a characteristic written by setCharacteristics.
+type writeResultOutput struct {
+	AccessoryID      uint64 `json:"aid" yaml:"aid"`
+	CharacteristicID uint64 `json:"iid" yaml:"iid"`
+	Status           int    `json:"status" yaml:"status"`
+}
+
+type writeResultsOutput []writeResultOutput
+
+func (w writeResultsOutput) csvHeader() []string {
+	return []string{"aid", "iid", "status"}
+}
+
+func (w writeResultsOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(w))
+	for _, r := range w {
+		records = append(records, []string{
+			strconv.FormatUint(r.AccessoryID, 10),
+			strconv.FormatUint(r.CharacteristicID, 10),
+			strconv.Itoa(r.Status),
+		})
+	}
+	return records
+}
 
-	for k := range characteristicParams {
-		accID, chID, err := parseCharacteristicIDs(k)
+// setCharacteristics writes the characteristic values and renders the results to out.
+func setCharacteristics(ctx context.Context, out io.Writer, accClient characteristicClient, characteristicParams []string) error {
+	selectors := make([]string, 0, len(characteristicParams))
+	values := make([]string, 0, len(characteristicParams))
+	for _, param := range characteristicParams {
+		selector, value, err := splitCharacteristicValue(param)
 		if err != nil {
 			return err
 		}
+		selectors = append(selectors, selector)
+		values = append(values, value)
+	}
 
-		reads = append(reads,
-			client.CharacteristicReadRequest{
-				AccessoryID:      accID,
-				CharacteristicID: chID,
-			},
-		)
+	resolved, err := resolveSelectors(ctx, accClient, selectors, true)
+	if err != nil {
+		return err
+	}
+
+	var reads []client.CharacteristicReadRequest
+	valuesByID := make(map[string]string)
+	for i, reqs := range resolved {
+		for _, req := range reqs {
+			cKey := fmt.Sprintf("%d.%d", req.AccessoryID, req.CharacteristicID)
+			if prev, ok := valuesByID[cKey]; ok {
+				if prev != values[i] {
+					return fmt.Errorf("conflicting values for characteristic %s: %s, %s", cKey, prev, values[i])
+				}
+				continue
+			}
+			valuesByID[cKey] = values[i]
+			reads = append(reads, req)
+		}
 	}
 
 	req := &client.CharacteristicsReadRequest{
@@ -60,10 +106,14 @@ func setCharacteristics(ctx context.Context, accClient *client.AccessoryClient,
 
 	for _, resp := range resps {
 		cKey := fmt.Sprintf("%d.%d", resp.AccessoryID, resp.CharacteristicID)
-		val, ok := characteristicParams[cKey]
+		val, ok := valuesByID[cKey]
 		if !ok {
 			return fmt.Errorf("unexpected characteristic returned: %s", cKey)
 		}
+		val, err := convertInput(val, responseUnit(resp), resp.StepValue)
+		if err != nil {
+			return err
+		}
 		writeVal, err := characteristic.ParseValueForType(*resp.Type, val)
 		if err != nil {
 			return err
@@ -81,10 +131,42 @@ func setCharacteristics(ctx context.Context, accClient *client.AccessoryClient,
 		Characteristics: writes,
 	}
 
-	_, err = accClient.SetCharacteristics(ctx, writeReq)
+	writeResps, err := accClient.SetCharacteristics(ctx, writeReq)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	results := make(writeResultsOutput, 0, len(writeResps))
+	for _, resp := range writeResps {
+		result := writeResultOutput{
+			AccessoryID:      resp.AccessoryID,
+			CharacteristicID: resp.CharacteristicID,
+		}
+		if resp.Status != nil {
+			result.Status = *resp.Status
+		}
+		results = append(results, result)
+	}
+
+	return renderTo(out, outputFormat, results, func(w io.Writer) {
+		for _, r := range results {
+			if r.Status != 0 {
+				fmt.Fprintf(w, "%d.%d: write failed with status %d\n", r.AccessoryID, r.CharacteristicID, r.Status)
+			}
+		}
+	})
+}
+
+// splitCharacteristicValue splits a selector=value pair. Both selectors and values may
+// contain '=' so the pair is split at the first '=' that ends a valid selector.
+func splitCharacteristicValue(param string) (selector, value string, err error) {
+	for i := 0; i < len(param); i++ {
+		if param[i] != '=' {
+			continue
+		}
+		if _, err := client.ParseCharacteristicSelector(param[:i]); err == nil {
+			return param[:i], param[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("invalid characteristic value pair %s, expect selector=value", param)
 }
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCharacteristicValue(t *testing.T) {
+	tests := []struct {
+		param           string
+		selector, value string
+	}{
+		{"1.10=50", "1.10", "50"},
+		{"1.10=a=b", "1.10", "a=b"},
+		{"1.10=", "1.10", ""},
+		{"*/TargetPosition=0", "*/TargetPosition", "0"},
+		{"Lamp/ConfiguredName=x=y", "Lamp/ConfiguredName", "x=y"},
+		{"service=Window,char=TargetPosition=0", "service=Window,char=TargetPosition", "0"},
+		{"aid=1,char=ConfiguredName,service=Lamp==", "aid=1,char=ConfiguredName,service=Lamp", "="},
+	}
+	for _, test := range tests {
+		selector, value, err := splitCharacteristicValue(test.param)
+		require.NoError(t, err, test.param)
+		require.Equal(t, test.selector, selector, test.param)
+		require.Equal(t, test.value, value, test.param)
+	}
+
+	_, _, err := splitCharacteristicValue("1.10")
+	require.EqualError(t, err, "invalid characteristic value pair 1.10, expect selector=value")
+	_, _, err = splitCharacteristicValue("TargetPosition=0")
+	require.Error(t, err)
+}
@@ -0,0 +1,392 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/service"
+	"github.com/mctofu/homekit/cmd/homekit/cli/shell"
+	"github.com/spf13/cobra"
+)
+
+const shellHistorySize = 1000
+
+func shellCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Interactive session with an accessory",
+		Long: "Open an interactive session with an accessory that keeps a single verified connection.\n\n" +
+			"Tab completes commands, characteristic ids and names from the accessory's attribute " +
+			"database. Type help to list the available commands.",
+	}
+
+	cmd.RunE = clientCommandRunner(cmd,
+		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+			return runShell(ctx, clientCtx, accClient, os.Stdin, os.Stdout)
+		},
+	)
+
+	return cmd
+}
+
+// cachedAccessoryClient reuses the attribute database fetched from the accessory until it's
+// refreshed.
+type cachedAccessoryClient struct {
+	*client.AccessoryClient
+	accessories []*client.RawAccessory
+}
+
+func (c *cachedAccessoryClient) Accessories(ctx context.Context) ([]*client.RawAccessory, error) {
+	if c.accessories != nil {
+		return c.accessories, nil
+	}
+
+	accessories, err := c.AccessoryClient.Accessories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.accessories = accessories
+
+	return accessories, nil
+}
+
+func (c *cachedAccessoryClient) refresh(ctx context.Context) ([]*client.RawAccessory, error) {
+	c.accessories = nil
+	return c.Accessories(ctx)
+}
+
+type shellSession struct {
+	clientCtx *clientContext
+	client    *cachedAccessoryClient
+	out       io.Writer
+}
+
+type shellCommand struct {
+	name  string
+	usage string
+	help  string
+	run   func(ctx context.Context, s *shellSession, args []string) error
+	// complete returns the words that can complete the command's next argument.
+	complete func(s *shellSession, args []string) []string
+}
+
+var shellCommands []*shellCommand
+
+func init() {
+	shellCommands = []*shellCommand{
+		{
+			name:     "ls",
+			usage:    "ls [aid...]",
+			help:     "List services and characteristics",
+			run:      shellList,
+			complete: (*shellSession).accessoryIDs,
+		},
+		{
+			name:  "get",
+			usage: "get <selector>...",
+			help:  "Read characteristic values",
+			run: func(ctx context.Context, s *shellSession, args []string) error {
+				if len(args) == 0 {
+					return errors.New("expected a characteristic id or selector")
+				}
+				return getCharacteristics(ctx, s.out, s.client, args)
+			},
+			complete: func(s *shellSession, args []string) []string {
+				return s.selectors(characteristic.PermissionPairedRead, "")
+			},
+		},
+		{
+			name:  "set",
+			usage: "set <selector=value>...",
+			help:  "Write characteristic values",
+			run: func(ctx context.Context, s *shellSession, args []string) error {
+				if len(args) == 0 {
+					return errors.New("expected a characteristic id or selector and value pair")
+				}
+				return setCharacteristics(ctx, s.out, s.client, args)
+			},
+			complete: func(s *shellSession, args []string) []string {
+				return s.selectors(characteristic.PermissionPairedWrite, "=")
+			},
+		},
+		{
+			name:  "watch",
+			usage: "watch <selector>...",
+			help:  "Print characteristic changes until interrupted",
+			run:   shellWatch,
+			complete: func(s *shellSession, args []string) []string {
+				return s.selectors(characteristic.PermissionEvents, "")
+			},
+		},
+		{
+			name:  "pairings",
+			usage: "pairings",
+			help:  "List controllers paired with the accessory",
+			run: func(ctx context.Context, s *shellSession, args []string) error {
+				return listPairings(ctx, s.out, s.clientCtx, s.client.AccessoryClient)
+			},
+		},
+		{
+			name:     "identify",
+			usage:    "identify [aid]",
+			help:     "Ask the accessory to identify itself",
+			run:      shellIdentify,
+			complete: (*shellSession).accessoryIDs,
+		},
+		{
+			name:  "refresh",
+			usage: "refresh",
+			help:  "Fetch the attribute database again",
+			run: func(ctx context.Context, s *shellSession, args []string) error {
+				accessories, err := s.client.refresh(ctx)
+				if err != nil {
+					return err
+				}
+				return renderMessageTo(s.out, "Loaded %d accessories", len(accessories))
+			},
+		},
+		{
+			name:  "help",
+			usage: "help",
+			help:  "List commands",
+			run: func(ctx context.Context, s *shellSession, args []string) error {
+				for _, c := range shellCommands {
+					fmt.Fprintf(s.out, "  %-26s %s\n", c.usage, c.help)
+				}
+				fmt.Fprintf(s.out, "  %-26s %s\n", "exit", "End the session")
+				return nil
+			},
+		},
+	}
+}
+
+func findShellCommand(name string) *shellCommand {
+	for _, c := range shellCommands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func runShell(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient, in io.Reader, out io.Writer) error {
+	s := &shellSession{
+		clientCtx: clientCtx,
+		client:    &cachedAccessoryClient{AccessoryClient: accClient},
+		out:       out,
+	}
+
+	accessories, err := s.client.Accessories(ctx)
+	if err != nil {
+		return fmt.Errorf("read accessories: %v", err)
+	}
+
+	reader := shell.NewReader(in, out)
+	reader.Complete = shell.WordCompleter(s.complete)
+
+	historyPath := path.Join(clientCtx.ConfigPath, "shell_history")
+	if reader.Interactive() {
+		history, err := shell.ReadHistory(historyPath, shellHistorySize)
+		if err != nil {
+			return fmt.Errorf("read history: %v", err)
+		}
+		reader.History = history
+		defer func() {
+			if err := history.Save(historyPath); err != nil {
+				fmt.Fprintf(os.Stderr, "save history: %v\n", err)
+			}
+		}()
+
+		fmt.Fprintf(out, "Connected to %s with %d accessories. Type help to list commands.\n", clientCtx.AccessoryName, len(accessories))
+	}
+
+	prompt := clientCtx.AccessoryName + "> "
+	for {
+		line, err := reader.ReadLine(prompt)
+		if err != nil {
+			if errors.Is(err, shell.ErrInterrupted) {
+				continue
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		args, err := shell.SplitArgs(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "exit" || args[0] == "quit" {
+			return nil
+		}
+
+		cmd := findShellCommand(args[0])
+		if cmd == nil {
+			fmt.Fprintf(os.Stderr, "unknown command %s, type help to list commands\n", args[0])
+			continue
+		}
+
+		if err := s.run(ctx, cmd, args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+	}
+}
+
+// run executes the command until it completes or is interrupted by ctrl-c.
+func (s *shellSession) run(ctx context.Context, cmd *shellCommand, args []string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	err := cmd.run(ctx, s, args)
+	if err != nil && ctx.Err() != nil {
+		// the command was interrupted
+		return nil
+	}
+
+	return err
+}
+
+func (s *shellSession) complete(args []string) []string {
+	if len(args) == 0 {
+		names := make([]string, 0, len(shellCommands)+1)
+		for _, c := range shellCommands {
+			names = append(names, c.name)
+		}
+		return append(names, "exit")
+	}
+
+	cmd := findShellCommand(args[0])
+	if cmd == nil || cmd.complete == nil {
+		return nil
+	}
+
+	return cmd.complete(s, args[1:])
+}
+
+// accessoryIDs returns the ids of the accessories in the cached attribute database.
+func (s *shellSession) accessoryIDs(args []string) []string {
+	var ids []string
+	for _, acc := range s.client.accessories {
+		ids = append(ids, strconv.FormatUint(acc.ID, 10))
+	}
+	return ids
+}
+
+// selectors returns the ids and service/characteristic names of the characteristics in the
+// cached attribute database with the permission. Each is followed by the suffix.
+func (s *shellSession) selectors(perm string, suffix string) []string {
+	var ids, names []string
+	seen := make(map[string]bool)
+	for _, acc := range s.client.accessories {
+		for _, svc := range acc.Services {
+			svcName := svc.Name()
+			if svcName == "" {
+				svcName = service.NameForType(svc.Type)
+			}
+			for _, ch := range svc.Characteristics {
+				if !ch.HasPermission(perm) {
+					continue
+				}
+				ids = append(ids, fmt.Sprintf("%d.%d%s", acc.ID, ch.ID, suffix))
+
+				name := svcName + "/" + characteristic.NameForType(ch.Type) + suffix
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return append(ids, names...)
+}
+
+func shellList(ctx context.Context, s *shellSession, args []string) error {
+	accessories, err := s.client.Accessories(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		include := make(map[uint64]bool)
+		for _, arg := range args {
+			id, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid accessory id %s", arg)
+			}
+			include[id] = true
+		}
+
+		var filtered []*client.RawAccessory
+		for _, acc := range accessories {
+			if include[acc.ID] {
+				filtered = append(filtered, acc)
+			}
+		}
+		accessories = filtered
+	}
+
+	return renderAccessories(s.out, accessories)
+}
+
+func shellWatch(ctx context.Context, s *shellSession, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected a characteristic id or selector")
+	}
+
+	resolved, err := resolveSelectors(ctx, s.client, args, false)
+	if err != nil {
+		return err
+	}
+
+	var ids []client.CharacteristicReadRequest
+	for _, reqs := range resolved {
+		ids = append(ids, reqs...)
+	}
+
+	typeNames := make(map[client.CharacteristicReadRequest]string)
+	all := &client.CharacteristicSelector{Characteristic: "*"}
+	for _, c := range all.Select(s.client.accessories) {
+		typeNames[client.CharacteristicReadRequest{AccessoryID: c.AccessoryID, CharacteristicID: c.Characteristic.ID}] = characteristic.NameForType(c.Characteristic.Type)
+	}
+
+	fmt.Fprintf(progressWriter(), "Watching %d characteristics, press ctrl-c to stop\n", len(ids))
+
+	return s.client.Subscribe(ctx, ids, func(e *client.CharacteristicEvent) {
+		typeName := typeNames[client.CharacteristicReadRequest{AccessoryID: e.AccessoryID, CharacteristicID: e.CharacteristicID}]
+		fmt.Fprintf(s.out, "%d.%d %s: %v\n", e.AccessoryID, e.CharacteristicID, typeName,
+			outputValue(characteristic.UndefinedValue{}, e.Value))
+	})
+}
+
+func shellIdentify(ctx context.Context, s *shellSession, args []string) error {
+	accID := uint64(1)
+	if len(args) > 0 {
+		var err error
+		accID, err = strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid accessory id %s", args[0])
+		}
+	}
+
+	if err := s.client.AccessoryClient.Identify(ctx, accID); err != nil {
+		return err
+	}
+
+	return renderMessageTo(s.out, "Identifying accessory %d", accID)
+}
@@ -0,0 +1,98 @@
+package shell
+
+import (
+	"errors"
+	"strings"
+)
+
+// SplitArgs splits a line into whitespace separated arguments. Single or double quotes group
+// words containing spaces and a backslash escapes the next character outside of single quotes.
+func SplitArgs(line string) ([]string, error) {
+	args, _, _, err := splitArgs(line)
+	return args, err
+}
+
+// splitArgs returns the arguments of line along with the offset where the last argument
+// started and whether it's still open at the end of the line.
+func splitArgs(line string) (args []string, lastStart int, open bool, err error) {
+	var (
+		arg     strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for i, c := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(c)
+			}
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+			continue
+		case c == '"' || c == '\'':
+			quote = c
+		default:
+			arg.WriteRune(c)
+		}
+		if !inArg {
+			inArg = true
+			lastStart = i
+		}
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if quote != 0 || escaped {
+		return args, lastStart, inArg, errors.New("unterminated quote or escape")
+	}
+
+	return args, lastStart, inArg, nil
+}
+
+// WordCompleter returns a Completer that completes the word before the cursor from the words
+// returned by fn. fn is called with the arguments preceding the word being completed.
+// Words are matched by case insensitive prefix and quoted if they contain spaces.
+func WordCompleter(fn func(args []string) []string) Completer {
+	return func(line string) (int, []string) {
+		args, lastStart, open, _ := splitArgs(line)
+
+		start := len(line)
+		var partial string
+		if open {
+			start = lastStart
+			partial = args[len(args)-1]
+			args = args[:len(args)-1]
+		}
+
+		var candidates []string
+		for _, w := range fn(args) {
+			if !strings.HasPrefix(strings.ToLower(w), strings.ToLower(partial)) {
+				continue
+			}
+			if strings.ContainsAny(w, " \t") {
+				// keep a trailing = outside the quotes so a value can follow it
+				if strings.HasSuffix(w, "=") {
+					w = `"` + w[:len(w)-1] + `"=`
+				} else {
+					w = `"` + w + `"`
+				}
+			}
+			candidates = append(candidates, w)
+		}
+
+		return start, candidates
+	}
+}
@@ -0,0 +1,31 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitArgs(t *testing.T) {
+	args, err := SplitArgs(`set  "Roof Window/TargetPosition"=0 'a\b' c\ d ""`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"set", "Roof Window/TargetPosition=0", `a\b`, "c d", ""}, args)
+
+	_, err = SplitArgs(`get "Roof Window`)
+	require.Error(t, err)
+}
+
+func TestWordCompleter(t *testing.T) {
+	complete := WordCompleter(func(args []string) []string {
+		require.Equal(t, []string{"set"}, args)
+		return []string{"1.10=", "Roof Window/TargetPosition="}
+	})
+
+	start, candidates := complete("set ")
+	require.Equal(t, 4, start)
+	require.Equal(t, []string{"1.10=", `"Roof Window/TargetPosition"=`}, candidates)
+
+	start, candidates = complete(`set "roof`)
+	require.Equal(t, 4, start)
+	require.Equal(t, []string{`"Roof Window/TargetPosition"=`}, candidates)
+}
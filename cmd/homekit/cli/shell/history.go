@@ -0,0 +1,90 @@
+package shell
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// History is the list of lines entered at the prompt, oldest first. It implements
+// term.History.
+type History struct {
+	lines []string
+	max   int
+}
+
+// NewHistory returns an empty history that keeps up to max lines.
+func NewHistory(max int) *History {
+	return &History{max: max}
+}
+
+// ReadHistory loads the history saved at path. An empty history is returned if the file
+// doesn't exist.
+func ReadHistory(filePath string, max int) (*History, error) {
+	h := NewHistory(max)
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.Add(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Add appends a line to the history. Blank lines and repeats of the last line are skipped.
+func (h *History) Add(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if len(h.lines) > 0 && h.lines[len(h.lines)-1] == line {
+		return
+	}
+	h.lines = append(h.lines, line)
+	if h.max > 0 && len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+}
+
+// Lines returns the lines of the history, oldest first.
+func (h *History) Lines() []string {
+	return h.lines
+}
+
+// Len returns the number of lines in the history.
+func (h *History) Len() int {
+	return len(h.lines)
+}
+
+// At returns a line of the history where 0 is the most recent line.
+func (h *History) At(idx int) string {
+	return h.lines[len(h.lines)-1-idx]
+}
+
+// Save writes the history to path so it can be loaded by ReadHistory.
+func (h *History) Save(filePath string) error {
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, line := range h.lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	return ioutil.WriteFile(filePath, []byte(b.String()), 0600)
+}
@@ -0,0 +1,214 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupted is returned by ReadLine when ctrl-c is pressed.
+var ErrInterrupted = errors.New("interrupted")
+
+// ctrlC is the byte read when ctrl-c is pressed in raw mode.
+const ctrlC = 3
+
+// Completer returns the candidates that can replace line[start:] to complete the text
+// before the cursor.
+type Completer func(line string) (start int, candidates []string)
+
+// Reader reads lines entered at a prompt. When reading from a terminal the line is edited
+// with term.Terminal: the arrow keys and common emacs bindings move the cursor, previous
+// lines are recalled from the history with up and down and tab completes the current word.
+type Reader struct {
+	in  *bufio.Reader
+	out io.Writer
+	fd  int
+
+	// raw is set when reading from a terminal that needs to be put into raw mode.
+	raw bool
+	// input is the input of the terminal. It's nil when lines aren't edited.
+	input *interruptReader
+	term  *term.Terminal
+	// tabs counts consecutive presses of tab to list the candidates on the second one.
+	tabs int
+
+	// Complete is used to complete the current word when tab is pressed. Optional.
+	Complete Completer
+	// History is where entered lines are saved. Optional.
+	History *History
+}
+
+// NewReader returns a reader of lines from in that echoes edits to out.
+func NewReader(in io.Reader, out io.Writer) *Reader {
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		r := newEditingReader(in, out)
+		r.fd = int(f.Fd())
+		r.raw = true
+		return r
+	}
+
+	return &Reader{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// newEditingReader returns a reader that edits lines read from in without changing the mode
+// of the terminal.
+func newEditingReader(in io.Reader, out io.Writer) *Reader {
+	r := &Reader{
+		out:   out,
+		input: &interruptReader{r: in},
+	}
+	r.resetTerminal()
+	return r
+}
+
+// resetTerminal starts editing a new line, discarding the state of the previous one.
+func (r *Reader) resetTerminal() {
+	r.term = term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{r.input, r.out}, "")
+	r.term.AutoCompleteCallback = r.complete
+}
+
+// Interactive returns true if lines are being entered at a terminal.
+func (r *Reader) Interactive() bool {
+	return r.term != nil
+}
+
+// ReadLine displays the prompt and returns the line entered without the trailing newline.
+// io.EOF is returned at the end of the input or when ctrl-d is pressed on an empty line.
+func (r *Reader) ReadLine(prompt string) (string, error) {
+	if r.term == nil {
+		return r.readPlainLine()
+	}
+
+	if r.raw {
+		state, err := term.MakeRaw(r.fd)
+		if err != nil {
+			return "", fmt.Errorf("enable raw mode: %v", err)
+		}
+		defer term.Restore(r.fd, state)
+
+		if width, height, err := term.GetSize(r.fd); err == nil {
+			_ = r.term.SetSize(width, height)
+		}
+	}
+
+	if r.History != nil {
+		r.term.History = r.History
+	}
+	r.term.SetPrompt(prompt)
+	r.tabs = 0
+
+	line, err := r.term.ReadLine()
+	if err == io.EOF && r.input.interrupted {
+		// term.Terminal ends the line with io.EOF for ctrl-c as well as ctrl-d and keeps
+		// the partial line, so editing starts over with a new terminal.
+		r.input.interrupted = false
+		fmt.Fprint(r.out, "^C\r\n")
+		r.resetTerminal()
+		return "", ErrInterrupted
+	}
+	if err == io.EOF {
+		fmt.Fprint(r.out, "\r\n")
+	}
+
+	return line, err
+}
+
+func (r *Reader) readPlainLine() (string, error) {
+	line, err := r.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// complete is the term.Terminal AutoCompleteCallback completing the word before the cursor
+// when tab is pressed. The candidates are listed if tab is pressed again and they don't
+// share a longer prefix.
+func (r *Reader) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		r.tabs = 0
+		return "", 0, false
+	}
+	r.tabs++
+	if r.Complete == nil {
+		return "", 0, false
+	}
+
+	head := line[:pos]
+	start, candidates := r.Complete(head)
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+	word := head[start:]
+
+	replacement := commonPrefix(candidates)
+	if len(candidates) == 1 && !strings.HasSuffix(candidates[0], "=") && !strings.HasSuffix(candidates[0], "/") {
+		replacement += " "
+	}
+
+	if len(candidates) == 1 || len(replacement) > len(word) {
+		return head[:start] + replacement + line[pos:], start + len(replacement), true
+	}
+
+	if r.tabs > 1 {
+		fmt.Fprintln(r.term, strings.Join(candidates, "  "))
+	}
+	return "", 0, false
+}
+
+func commonPrefix(words []string) string {
+	prefix := []rune(words[0])
+	for _, w := range words[1:] {
+		runes := []rune(w)
+		n := 0
+		for n < len(prefix) && n < len(runes) && prefix[n] == runes[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// interruptReader notes when ctrl-c is read so ReadLine can tell it from the end of the
+// input. Reads end after ctrl-c so the terminal doesn't hold input following it.
+type interruptReader struct {
+	r           io.Reader
+	pending     []byte
+	buf         [256]byte
+	interrupted bool
+}
+
+func (i *interruptReader) Read(p []byte) (int, error) {
+	if len(i.pending) == 0 {
+		n, err := i.r.Read(i.buf[:])
+		if n == 0 {
+			return 0, err
+		}
+		i.pending = i.buf[:n]
+	}
+
+	data := i.pending
+	if idx := bytes.IndexByte(data, ctrlC); idx >= 0 {
+		data = data[:idx+1]
+	}
+	n := copy(p, data)
+	if data[n-1] == ctrlC {
+		i.interrupted = true
+	}
+	i.pending = i.pending[n:]
+
+	return n, nil
+}
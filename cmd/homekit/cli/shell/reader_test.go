@@ -0,0 +1,131 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func editingReader(input string) *Reader {
+	return newEditingReader(strings.NewReader(input), &bytes.Buffer{})
+}
+
+func TestReaderEditing(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "get 1.10\r", "get 1.10"},
+		{"backspace", "gte\x7f\x7fet\r", "get"},
+		{"cursor keys", "gt\x1b[De\x1b[C 1\r", "get 1"},
+		{"home and end", "et\x01g\x05 1\r", "get 1"},
+		{"kill to end", "get 1.10\x01\x06\x06\x06\x0b\r", "get"},
+		{"delete word", "get 1.10\x17\r", "get "},
+		{"delete forward", "xget\x01\x1b[3~\r", "get"},
+		{"unicode", "set Küche\x7f\x7f\x7fche\r", "set Küche"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			line, err := editingReader(tc.input).ReadLine("> ")
+			require.NoError(t, err)
+			require.Equal(t, tc.want, line)
+		})
+	}
+}
+
+func TestReaderHistory(t *testing.T) {
+	r := editingReader("ls\rget 1.10\r\x1b[A\x1b[A\r\x1b[A\x1b[A\x1b[A\x1b[B\x7f1\r")
+	r.History = NewHistory(10)
+
+	var lines []string
+	for {
+		line, err := r.ReadLine("> ")
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		lines = append(lines, line)
+	}
+
+	require.Equal(t, []string{"ls", "get 1.10", "ls", "get 1.11"}, lines)
+	require.Equal(t, []string{"ls", "get 1.10", "ls", "get 1.11"}, r.History.Lines())
+}
+
+func TestReaderControlKeys(t *testing.T) {
+	_, err := editingReader("get\x03").ReadLine("> ")
+	require.Equal(t, ErrInterrupted, err)
+
+	_, err = editingReader("\x04").ReadLine("> ")
+	require.Equal(t, io.EOF, err)
+
+	// the interrupted line is discarded
+	r := editingReader("get\x03ls\r")
+	_, err = r.ReadLine("> ")
+	require.Equal(t, ErrInterrupted, err)
+	line, err := r.ReadLine("> ")
+	require.NoError(t, err)
+	require.Equal(t, "ls", line)
+}
+
+func TestReaderCompletion(t *testing.T) {
+	complete := WordCompleter(func(args []string) []string {
+		if len(args) == 0 {
+			return []string{"get", "set", "identify"}
+		}
+		return []string{"1.10", "1.11", "Roof Window/TargetPosition", "Roof Window/CurrentPosition"}
+	})
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"command", "g\t1.10\r", "get 1.10"},
+		{"common prefix", "get 1\t1\r", "get 1.11"},
+		{"quoted", "get ro\tT\t\r", `get "Roof Window/TargetPosition" `},
+		{"open quote", "get \"roof window/c\t\r", `get "Roof Window/CurrentPosition" `},
+		{"no match", "get x\t\r", "get x"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := editingReader(tc.input)
+			r.Complete = complete
+			line, err := r.ReadLine("> ")
+			require.NoError(t, err)
+			require.Equal(t, tc.want, line)
+		})
+	}
+}
+
+func TestReaderListsCompletions(t *testing.T) {
+	out := &bytes.Buffer{}
+	r := newEditingReader(strings.NewReader("get 1.1\t\t0\r"), out)
+	r.Complete = WordCompleter(func(args []string) []string {
+		return []string{"1.10", "1.11"}
+	})
+
+	line, err := r.ReadLine("> ")
+	require.NoError(t, err)
+	require.Equal(t, "get 1.10", line)
+	require.Equal(t, 1, strings.Count(out.String(), "1.10  1.11"))
+}
+
+func TestReaderPlain(t *testing.T) {
+	r := NewReader(strings.NewReader("ls\r\nget 1.10"), &bytes.Buffer{})
+	require.False(t, r.Interactive())
+
+	line, err := r.ReadLine("> ")
+	require.NoError(t, err)
+	require.Equal(t, "ls", line)
+
+	line, err = r.ReadLine("> ")
+	require.NoError(t, err)
+	require.Equal(t, "get 1.10", line)
+
+	_, err = r.ReadLine("> ")
+	require.Equal(t, io.EOF, err)
+}
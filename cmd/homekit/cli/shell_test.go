@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server, err := hapfake.NewServer(hapfake.Config{})
+	require.NoError(t, err)
+	defer server.Close()
+
+	configPath, cfg := pairTestController(t, server)
+	clientCtx, accClient := testClientContext(t, configPath, cfg, "fake1")
+
+	script := strings.Join([]string{
+		"get 1.9",
+		"set Switch/On=true",
+		"get 1.9",
+		"refresh",
+		"exit",
+		"get 1.9",
+	}, "\n")
+	var out bytes.Buffer
+	require.NoError(t, runShell(ctx, clientCtx, accClient, strings.NewReader(script), &out))
+
+	require.Equal(t, "1.9: On\nValue: false\n"+
+		"1.9: On\nValue: true\n"+
+		"Loaded 1 accessories\n", out.String())
+	require.Equal(t, []hapfake.Write{
+		{AccessoryID: 1, CharacteristicID: 9, Value: characteristic.Value("true")},
+	}, server.Writes())
+}
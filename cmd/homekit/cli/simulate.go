@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/brutella/hc/db"
+	"github.com/grandcat/zeroconf"
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/client/service"
+	"github.com/mctofu/homekit/cmd/homekit/cli/simulate"
+	"github.com/spf13/cobra"
+)
+
+func simulateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Advertise a simulated accessory over mDNS",
+		Long: "Serve an attribute database as a HAP accessory on the local network until interrupted.\n\n" +
+			"The database can be the output of listCharacteristics -o json. Controllers can pair with the " +
+			"accessory and write its characteristics. Writing a TargetPosition gradually moves the " +
+			"CurrentPosition and sensor values drift randomly.",
+	}
+
+	dbPath := cmd.Flags().String("db", "", "Attribute database to serve. Defaults to a switch.")
+	pin := cmd.Flags().String("pin", hapfake.DefaultPIN, "Accessory PIN for pairing (XXX-XX-XXX)")
+	deviceID := cmd.Flags().String("id", hapfake.DefaultDeviceID, "Pairing identifier of the accessory")
+	name := cmd.Flags().String("name", "", "Name to advertise. Defaults to the name of the first accessory.")
+	listen := cmd.Flags().String("listen", ":0", "Address to listen on")
+	statePath := cmd.Flags().String("state", "", "Directory to store the accessory's keys and pairings. "+
+		"Defaults to a directory for the id in the config path.")
+	moveInterval := cmd.Flags().Duration("move", 100*time.Millisecond, "Time to move a position one step. Positions move immediately if 0.")
+	driftInterval := cmd.Flags().Duration("drift", 30*time.Second, "Interval sensor values drift. Sensors don't drift if 0.")
+	category := cmd.Flags().Int("category", 0, "HAP category to advertise. Defaults to a bridge if there are multiple accessories.")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			if *statePath == "" {
+				*statePath = path.Join(configPath, "simulator", *deviceID)
+			}
+			return simulateAccessory(ctx, simulateOptions{
+				DBPath:        *dbPath,
+				PIN:           *pin,
+				DeviceID:      *deviceID,
+				Name:          *name,
+				Listen:        *listen,
+				StatePath:     *statePath,
+				MoveInterval:  *moveInterval,
+				DriftInterval: *driftInterval,
+				Category:      *category,
+			})
+		},
+	)
+
+	return cmd
+}
+
+type simulateOptions struct {
+	DBPath        string
+	PIN           string
+	DeviceID      string
+	Name          string
+	Listen        string
+	StatePath     string
+	MoveInterval  time.Duration
+	DriftInterval time.Duration
+	Category      int
+}
+
+func simulateAccessory(ctx context.Context, opts simulateOptions) error {
+	accessories := hapfake.DefaultAccessories()
+	if opts.DBPath != "" {
+		var err error
+		accessories, err = hapfake.LoadAccessories(opts.DBPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Name == "" {
+		opts.Name = accessoryName(accessories)
+	}
+	if opts.Category == 0 {
+		opts.Category = simulate.CategoryOther
+		if len(accessories) > 1 {
+			opts.Category = simulate.CategoryBridge
+		}
+	}
+
+	database, err := db.NewDatabase(opts.StatePath)
+	if err != nil {
+		return fmt.Errorf("open state: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	writes := make(chan hapfake.Write)
+	pairingChanges := make(chan struct{}, 1)
+	server, err := hapfake.NewServer(hapfake.Config{
+		DeviceID:    opts.DeviceID,
+		PIN:         opts.PIN,
+		Accessories: accessories,
+		Addr:        opts.Listen,
+		Database:    database,
+		OnWrite: func(w hapfake.Write) {
+			select {
+			case writes <- w:
+			case <-ctx.Done():
+			}
+		},
+		OnPairingChange: func() {
+			select {
+			case pairingChanges <- struct{}{}:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("serve accessory: %v", err)
+	}
+	defer server.Close()
+
+	advertiser, err := zeroconf.Register(opts.Name, "_hap._tcp", "local.", server.Port(),
+		simulate.TXTRecords(opts.DeviceID, opts.Name, opts.Category, server.Paired()), nil)
+	if err != nil {
+		return fmt.Errorf("advertise: %v", err)
+	}
+	defer advertiser.Shutdown()
+
+	logger := log.New(progressWriter(), "", log.LstdFlags)
+	sim := simulate.New(server, simulate.Config{
+		MoveInterval:  opts.MoveInterval,
+		DriftInterval: opts.DriftInterval,
+		Logger:        logger,
+	})
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- sim.Run(ctx, writes)
+	}()
+	logger.Printf("simulating %s (%s) on port %d with PIN %s", opts.Name, opts.DeviceID, server.Port(), opts.PIN)
+
+	for {
+		select {
+		case <-pairingChanges:
+			paired := server.Paired()
+			advertiser.SetText(simulate.TXTRecords(opts.DeviceID, opts.Name, opts.Category, paired))
+			logger.Printf("paired: %t", paired)
+		case err := <-errs:
+			if !errors.Is(err, context.Canceled) {
+				return err
+			}
+			return nil
+		}
+	}
+}
+
+// accessoryName returns the name of the first accessory in the database.
+func accessoryName(accessories []*hapfake.Accessory) string {
+	for _, acc := range accessories {
+		for _, svc := range acc.Services {
+			if svc.Type != service.TypeAccessoryInformation {
+				continue
+			}
+			if ch := svc.CharacteristicByType(characteristic.TypeName); ch != nil {
+				if name, err := ch.Value.String(); err == nil && name != "" {
+					return name
+				}
+			}
+		}
+	}
+	return "Simulator"
+}
@@ -0,0 +1,29 @@
+package simulate
+
+import "fmt"
+
+// HAP categories advertised when simulating accessories.
+const (
+	CategoryOther  = 1
+	CategoryBridge = 2
+)
+
+// TXTRecords returns the mDNS TXT records advertising a HAP accessory. Controllers only
+// offer to pair with accessories that aren't paired.
+func TXTRecords(deviceID, model string, category int, paired bool) []string {
+	statusFlags := 1
+	if paired {
+		statusFlags = 0
+	}
+
+	return []string{
+		"c#=1",
+		"ff=0",
+		"id=" + deviceID,
+		"md=" + model,
+		"pv=1.1",
+		"s#=1",
+		fmt.Sprintf("sf=%d", statusFlags),
+		fmt.Sprintf("ci=%d", category),
+	}
+}
@@ -0,0 +1,288 @@
+package simulate
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+)
+
+// Config configures a Simulator.
+type Config struct {
+	// MoveInterval is how long a position takes to move one step toward its target. Positions
+	// move to their target immediately if 0.
+	MoveInterval time.Duration
+	// DriftInterval is how often sensor values drift. Sensors don't drift if 0.
+	DriftInterval time.Duration
+	// Rand is the source of sensor drift. Defaults to a source seeded with the current time.
+	Rand   *rand.Rand
+	Logger *log.Logger
+}
+
+// Position states reported while a position moves.
+const (
+	positionDecreasing = 0
+	positionIncreasing = 1
+	positionStopped    = 2
+)
+
+// movers are target characteristics that gradually move a current characteristic of the same
+// service with an optional state characteristic.
+var movers = map[string]struct{ current, state string }{
+	characteristic.TypeTargetPosition:            {characteristic.TypeCurrentPosition, characteristic.TypePositionState},
+	characteristic.TypeTargetHorizontalTiltAngle: {characteristic.TypeCurrentHorizontalTiltAngle, ""},
+	characteristic.TypeTargetVerticalTiltAngle:   {characteristic.TypeCurrentVerticalTiltAngle, ""},
+}
+
+// mirrors are target characteristics whose value is copied to a current characteristic of the
+// same service.
+var mirrors = map[string]string{
+	characteristic.TypeTargetDoorState: characteristic.TypeCurrentDoorState,
+	characteristic.TypeLockTargetState: characteristic.TypeLockCurrentState,
+}
+
+// driftTypes are the sensor characteristics that drift and the most they change per interval.
+var driftTypes = map[string]float64{
+	characteristic.TypeCurrentTemperature:       0.2,
+	characteristic.TypeCurrentRelativeHumidity:  1,
+	characteristic.TypeCarbonDioxideLevel:       25,
+	characteristic.TypeCarbonMonoxideLevel:      0.5,
+	characteristic.TypeCurrentAmbientLightLevel: 10,
+	characteristic.TypePM25Density:              2,
+	characteristic.TypePM10Density:              2,
+	characteristic.TypeVOCDensity:               2,
+	characteristic.TypeAirParticulateDensity:    2,
+}
+
+type characteristicID struct {
+	aid, iid uint64
+}
+
+// limits describe the values a numeric characteristic accepts.
+type limits struct {
+	format   string
+	min, max float64
+	step     float64
+}
+
+func newLimits(ch *characteristic.RawCharacteristic) limits {
+	l := limits{format: ch.Format, min: math.Inf(-1), max: math.Inf(1)}
+	if v, err := ch.MinValue.Float64(); err == nil && ch.MinValue != nil {
+		l.min = v
+	}
+	if v, err := ch.MaxValue.Float64(); err == nil && ch.MaxValue != nil {
+		l.max = v
+	}
+	if v, err := ch.StepValue.Float64(); err == nil && v > 0 {
+		l.step = v
+	}
+	return l
+}
+
+// fit clamps v to the limits and rounds it to the step and format.
+func (l limits) fit(v float64) float64 {
+	v = math.Max(l.min, math.Min(l.max, v))
+	if l.step > 0 {
+		v = math.Round(v/l.step) * l.step
+		// round off the error introduced by fractional steps
+		decimals := math.Max(0, -math.Floor(math.Log10(l.step)))
+		scale := math.Pow(10, decimals)
+		v = math.Round(v*scale) / scale
+	}
+	if l.format != "float" {
+		v = math.Round(v)
+	}
+	return v
+}
+
+type mover struct {
+	current characteristicID
+	state   *characteristicID
+	limits  limits
+}
+
+type sensor struct {
+	id        characteristicID
+	magnitude float64
+	limits    limits
+}
+
+// Simulator applies behaviors to a simulated accessory. Writes to a TargetPosition gradually
+// move the CurrentPosition of the same service, door and lock states follow their targets and
+// sensor values drift randomly.
+type Simulator struct {
+	acc     *hapfake.Server
+	cfg     Config
+	movers  map[characteristicID]*mover
+	mirrors map[characteristicID]characteristicID
+	sensors []*sensor
+
+	// moving holds the target of each current characteristic that's moving
+	moving map[*mover]float64
+}
+
+// New returns a Simulator for the accessories served by acc.
+func New(acc *hapfake.Server, cfg Config) *Simulator {
+	if cfg.Rand == nil {
+		cfg.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(log.Writer(), "", log.LstdFlags)
+	}
+
+	s := &Simulator{
+		acc:     acc,
+		cfg:     cfg,
+		movers:  make(map[characteristicID]*mover),
+		mirrors: make(map[characteristicID]characteristicID),
+		moving:  make(map[*mover]float64),
+	}
+
+	for _, a := range acc.Accessories() {
+		for _, svc := range a.Services {
+			for _, ch := range svc.Characteristics {
+				id := characteristicID{a.ID, ch.ID}
+
+				if m, ok := movers[ch.Type]; ok {
+					current := svc.CharacteristicByType(m.current)
+					if current == nil {
+						continue
+					}
+					mv := &mover{
+						current: characteristicID{a.ID, current.ID},
+						limits:  newLimits(current),
+					}
+					if mv.limits.step == 0 {
+						mv.limits.step = 1
+					}
+					if state := svc.CharacteristicByType(m.state); m.state != "" && state != nil {
+						mv.state = &characteristicID{a.ID, state.ID}
+					}
+					s.movers[id] = mv
+				}
+
+				if currentType, ok := mirrors[ch.Type]; ok {
+					if current := svc.CharacteristicByType(currentType); current != nil {
+						s.mirrors[id] = characteristicID{a.ID, current.ID}
+					}
+				}
+
+				if magnitude, ok := driftTypes[ch.Type]; ok && ch.HasPermission(characteristic.PermissionPairedRead) {
+					s.sensors = append(s.sensors, &sensor{id: id, magnitude: magnitude, limits: newLimits(ch)})
+				}
+			}
+		}
+	}
+
+	return s
+}
+
+// Run applies the behaviors for the writes received until ctx is cancelled.
+func (s *Simulator) Run(ctx context.Context, writes <-chan hapfake.Write) error {
+	var moveTick, driftTick <-chan time.Time
+	if s.cfg.MoveInterval > 0 {
+		ticker := time.NewTicker(s.cfg.MoveInterval)
+		defer ticker.Stop()
+		moveTick = ticker.C
+	}
+	if s.cfg.DriftInterval > 0 && len(s.sensors) > 0 {
+		ticker := time.NewTicker(s.cfg.DriftInterval)
+		defer ticker.Stop()
+		driftTick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case w := <-writes:
+			s.write(w)
+		case <-moveTick:
+			s.move()
+		case <-driftTick:
+			s.drift()
+		}
+	}
+}
+
+func (s *Simulator) write(w hapfake.Write) {
+	id := characteristicID{w.AccessoryID, w.CharacteristicID}
+
+	if current, ok := s.mirrors[id]; ok {
+		s.set(current, json.RawMessage(w.Value))
+	}
+
+	mv, ok := s.movers[id]
+	if !ok {
+		return
+	}
+	target, err := w.Value.Float64()
+	if err != nil {
+		return
+	}
+	target = mv.limits.fit(target)
+
+	if s.cfg.MoveInterval == 0 {
+		s.set(mv.current, target)
+		s.setState(mv, positionStopped)
+		return
+	}
+
+	s.moving[mv] = target
+	current, err := s.acc.Value(mv.current.aid, mv.current.iid).Float64()
+	if err != nil {
+		return
+	}
+	switch {
+	case target > current:
+		s.setState(mv, positionIncreasing)
+	case target < current:
+		s.setState(mv, positionDecreasing)
+	}
+}
+
+// move moves each moving characteristic one step toward its target.
+func (s *Simulator) move() {
+	for mv, target := range s.moving {
+		current, err := s.acc.Value(mv.current.aid, mv.current.iid).Float64()
+		if err != nil {
+			delete(s.moving, mv)
+			continue
+		}
+
+		next := current + math.Copysign(math.Min(mv.limits.step, math.Abs(target-current)), target-current)
+		s.set(mv.current, mv.limits.fit(next))
+		if math.Abs(target-next) < mv.limits.step/2 {
+			s.setState(mv, positionStopped)
+			delete(s.moving, mv)
+		}
+	}
+}
+
+// drift changes each sensor value by a random amount up to its magnitude.
+func (s *Simulator) drift() {
+	for _, sn := range s.sensors {
+		v, err := s.acc.Value(sn.id.aid, sn.id.iid).Float64()
+		if err != nil {
+			continue
+		}
+		s.set(sn.id, sn.limits.fit(v+(s.cfg.Rand.Float64()*2-1)*sn.magnitude))
+	}
+}
+
+func (s *Simulator) setState(mv *mover, state int) {
+	if mv.state != nil {
+		s.set(*mv.state, state)
+	}
+}
+
+func (s *Simulator) set(id characteristicID, v interface{}) {
+	if err := s.acc.SetValue(id.aid, id.iid, v); err != nil {
+		s.cfg.Logger.Printf("set %d.%d: %v", id.aid, id.iid, err)
+	}
+}
@@ -0,0 +1,110 @@
+package simulate
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/client/hapfake/hapfaketest"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccessories = `[
+  {"aid": 1, "services": [
+    {"iid": 8, "type": "8C", "characteristics": [
+      {"iid": 9, "type": "6D", "format": "uint8", "value": 0, "perms": ["pr", "ev"],
+       "minValue": 0, "maxValue": 100, "minStep": 10},
+      {"iid": 10, "type": "7C", "format": "uint8", "value": 0, "perms": ["pr", "pw", "ev"],
+       "minValue": 0, "maxValue": 100, "minStep": 1},
+      {"iid": 11, "type": "72", "format": "uint8", "value": 2, "perms": ["pr", "ev"]}
+    ]},
+    {"iid": 12, "type": "41", "characteristics": [
+      {"iid": 13, "type": "E", "format": "uint8", "value": 1, "perms": ["pr", "ev"]},
+      {"iid": 14, "type": "32", "format": "uint8", "value": 1, "perms": ["pr", "pw", "ev"]}
+    ]},
+    {"iid": 15, "type": "8A", "characteristics": [
+      {"iid": 16, "type": "11", "format": "float", "value": 20, "perms": ["pr", "ev"],
+       "minValue": 19.9, "maxValue": 20.1, "minStep": 0.1}
+    ]}
+  ]}
+]`
+
+func newTestSimulator(t *testing.T, cfg Config) (*hapfake.Server, chan hapfake.Write) {
+	acc := hapfaketest.NewServer(t, testAccessories, hapfake.Config{})
+
+	cfg.Logger = log.New(io.Discard, "", 0)
+	sim := New(acc, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	writes := make(chan hapfake.Write)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = sim.Run(ctx, writes)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return acc, writes
+}
+
+func TestSimulatorMove(t *testing.T) {
+	acc, writes := newTestSimulator(t, Config{MoveInterval: time.Millisecond})
+
+	writes <- hapfake.Write{AccessoryID: 1, CharacteristicID: 10, Value: characteristic.Value("35")}
+	require.Eventually(t, func() bool {
+		return string(acc.Value(1, 11)) == "2"
+	}, 5*time.Second, time.Millisecond)
+	// the current position moves in steps of 10
+	require.Equal(t, "40", string(acc.Value(1, 9)))
+
+	writes <- hapfake.Write{AccessoryID: 1, CharacteristicID: 10, Value: characteristic.Value("0")}
+	require.Eventually(t, func() bool {
+		return string(acc.Value(1, 9)) == "0"
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestSimulatorMoveImmediate(t *testing.T) {
+	acc, writes := newTestSimulator(t, Config{})
+
+	writes <- hapfake.Write{AccessoryID: 1, CharacteristicID: 10, Value: characteristic.Value("70")}
+	writes <- hapfake.Write{AccessoryID: 1, CharacteristicID: 14, Value: characteristic.Value("0")}
+	// the unbuffered channel ensures the previous writes were applied
+	writes <- hapfake.Write{AccessoryID: 1, CharacteristicID: 99, Value: characteristic.Value("0")}
+
+	require.Equal(t, "70", string(acc.Value(1, 9)))
+	require.Equal(t, "2", string(acc.Value(1, 11)))
+	require.Equal(t, "0", string(acc.Value(1, 13)))
+}
+
+func TestSimulatorDrift(t *testing.T) {
+	acc, _ := newTestSimulator(t, Config{
+		DriftInterval: time.Millisecond,
+		Rand:          rand.New(rand.NewSource(1)),
+	})
+
+	seen := make(map[string]bool)
+	require.Eventually(t, func() bool {
+		seen[string(acc.Value(1, 16))] = true
+		return len(seen) > 1
+	}, 5*time.Second, time.Millisecond)
+
+	for v := range seen {
+		require.Contains(t, []string{"19.9", "20", "20.1"}, v)
+	}
+}
+
+func TestTXTRecords(t *testing.T) {
+	require.Equal(t,
+		[]string{"c#=1", "ff=0", "id=5F-7A-CA-6A-83-92", "md=Fake", "pv=1.1", "s#=1", "sf=1", "ci=2"},
+		TXTRecords("5F-7A-CA-6A-83-92", "Fake", 2, false),
+	)
+	require.Contains(t, TXTRecords("5F-7A-CA-6A-83-92", "Fake", 2, true), "sf=0")
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mctofu/homekit/client/characteristic"
+	"github.com/mctofu/homekit/client/units"
+)
+
+// unitSystemName is set by the global --units flag.
+var unitSystemName string
+
+// unitSystem values are presented in. It's set from unitSystemName before commands run.
+var unitSystem = units.Metric
+
+func unitSystemNames() []string {
+	names := make([]string, 0, len(units.Systems))
+	for _, system := range units.Systems {
+		names = append(names, string(system))
+	}
+	return names
+}
+
+func validateUnitSystem() error {
+	system, err := units.ParseSystem(unitSystemName)
+	if err != nil {
+		return err
+	}
+	unitSystem = system
+	return nil
+}
+
+// characteristicUnit returns the unit reported for a characteristic or the unit of its type if
+// the accessory doesn't report one.
+func characteristicUnit(unit, typ string) units.Unit {
+	if unit != "" {
+		return units.Unit(unit)
+	}
+	return characteristic.UnitForType(typ)
+}
+
+// convertValue converts a decoded value of unit to the unit system selected by --units and
+// returns the converted value and its unit. Converted values are rounded to hundredths to hide
+// floating point noise.
+func convertValue(v interface{}, unit units.Unit) (interface{}, units.Unit) {
+	f, ok := v.(float64)
+	if !ok || unitSystem.Unit(unit) == unit {
+		return v, unit
+	}
+	return math.Round(unitSystem.Convert(unit, f)*100) / 100, unitSystem.Unit(unit)
+}
+
+// formatTextValue formats a decoded value for text output with the symbol of its unit, like
+// 28.5 °C.
+func formatTextValue(decoded interface{}, err error, unit units.Unit) string {
+	if err != nil {
+		return invalidValue(err)
+	}
+	decoded, unit = convertValue(decoded, unit)
+	return unit.Format(decoded)
+}
+
+// convertInput converts a value entered in the unit system selected by --units back to the unit
+// of the characteristic. The converted value is rounded to the minimum step of the
+// characteristic if it's known.
+func convertInput(val string, unit units.Unit, step characteristic.Value) (string, error) {
+	if unitSystem.Unit(unit) == unit {
+		return val, nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s value %s", unitSystem.Unit(unit), val)
+	}
+	converted := unitSystem.ConvertBack(unit, f)
+	if s, err := step.Float64(); err == nil && s > 0 {
+		converted = math.Round(converted/s) * s
+	}
+	return strconv.FormatFloat(converted, 'g', 10, 64), nil
+}
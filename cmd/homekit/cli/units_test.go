@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/units"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImperialUnits(t *testing.T) {
+	unitSystem = units.Imperial
+	defer func() { unitSystem = units.Metric }()
+
+	ctx := context.Background()
+	exchanges, err := client.LoadExchanges("testdata/imperial.json")
+	require.NoError(t, err)
+	replay := client.NewReplayTransport(exchanges)
+	accClient := client.NewAccessoryClientWithTransport(replay, client.IPConnectionInfo{})
+
+	resps, err := accClient.Characteristics(ctx, &client.CharacteristicsReadRequest{
+		Characteristics: []client.CharacteristicReadRequest{{AccessoryID: 1, CharacteristicID: 11}},
+		Metadata:        true,
+		Type:            true,
+	})
+	require.NoError(t, err)
+	out := newCharacteristicValueOutput(resps[0])
+	require.Equal(t, 69.8, out.Value)
+	require.Equal(t, "fahrenheit", out.Unit)
+	require.Equal(t, "69.8 °F", formatTextValue(21.0, nil, units.Celsius))
+	require.Equal(t, "40%", formatTextValue(uint8(40), nil, units.Percentage))
+
+	// 73 °F is 22.8 °C which is rounded to the 0.5 °C step of the characteristic
+	require.NoError(t, setCharacteristics(ctx, io.Discard, accClient, []string{"1.11=73"}))
+	require.Empty(t, replay.Unused())
+}
+
+func TestImperialUnitsAccessoriesOutput(t *testing.T) {
+	unitSystem = units.Imperial
+	defer func() { unitSystem = units.Metric }()
+
+	var accessories []*client.RawAccessory
+	require.NoError(t, json.Unmarshal([]byte(`[{"aid": 1, "services": [{"iid": 8, "type": "8A", "characteristics": [
+	  {"iid": 10, "type": "11", "format": "float", "unit": "celsius", "value": 21.5, "perms": ["pr"], "minValue": 0, "maxValue": 100, "minStep": 0.1},
+	  {"iid": 11, "type": "11", "format": "float", "value": 22, "perms": ["pr"]}
+	]}]}]`), &accessories))
+
+	// the attribute database keeps the native values and units
+	chs := newAccessoriesOutput(accessories)[0].Services[0].Characteristics
+	require.Equal(t, 21.5, chs[0].Value)
+	require.Equal(t, "celsius", chs[0].Unit)
+	require.Equal(t, 100.0, chs[0].MaxValue)
+	require.Equal(t, 0.1, chs[0].StepValue)
+	require.Equal(t, 22.0, chs[1].Value)
+	require.Equal(t, "", chs[1].Unit)
+}
@@ -2,8 +2,12 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/mctofu/homekit/client"
 	"github.com/mctofu/homekit/cmd/homekit/cli/config"
 	"github.com/spf13/cobra"
@@ -13,33 +17,183 @@ func unpairCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "unpair",
 		Short: "Unpair an accessory from the controller",
+		Long: "Unpair an accessory from the controller.\n\n" +
+			"With --all every accessory paired with the controller is unpaired. Accessories that " +
+			"could not be unpaired are kept in the config and reported.",
 	}
 
-	cmd.RunE = clientCommandRunner(cmd, unpair)
+	name := cmd.Flags().StringP("name", "n", "", "Name of accessory to act on")
+	all := cmd.Flags().Bool("all", false, "Unpair all accessories paired with the controller")
+	everyone := cmd.Flags().Bool("everyone", false, "Remove the pairings of all controllers, not just this one")
+
+	cmd.RunE = configCommandRunner(cmd,
+		func(ctx context.Context, configPath, controllerName string) error {
+			switch {
+			case *all && *name != "":
+				return errors.New("--name and --all can't be used together")
+			case *all:
+				return unpairAll(ctx, configPath, controllerName, *everyone)
+			case *name != "":
+				return unpair(ctx, configPath, controllerName, *name, *everyone)
+			default:
+				return errors.New("either --name or --all is required")
+			}
+		},
+	)
 
 	return cmd
 }
 
-func unpair(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
-	cfg := *clientCtx.Config
+func unpair(ctx context.Context, configPath, controllerName, name string, everyone bool) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
 
-	if err := accClient.RemovePairing(ctx, cfg.DeviceID); err != nil {
-		return fmt.Errorf("removePairing: %v", err)
+	accPairing := findAccessoryPairing(cfg, name)
+	if accPairing == nil {
+		return fmt.Errorf("accessory %s not found", name)
 	}
 
-	newPairings := make([]*config.AccessoryPairing, 0, len(cfg.AccessoryPairings)-1)
-	for _, pairing := range cfg.AccessoryPairings {
-		if pairing.Name != clientCtx.AccessoryName {
-			newPairings = append(newPairings, pairing)
+	if err := unpairAccessory(ctx, controllerIdentity(cfg), accPairing, everyone); err != nil {
+		return err
+	}
+
+	removePairings(cfg, accPairing)
+
+	if err := config.SaveControllerConfig(configPath, cfg, true); err != nil {
+		return err
+	}
+
+	return renderMessage("Pairing removed")
+}
+
+// unpairResultOutput is the output schema of an accessory unpaired by unpair --all.
+type unpairResultOutput struct {
+	Name     string `json:"name" yaml:"name"`
+	DeviceID string `json:"deviceId" yaml:"deviceId"`
+	Unpaired bool   `json:"unpaired" yaml:"unpaired"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type unpairResultsOutput []unpairResultOutput
+
+func (u unpairResultsOutput) csvHeader() []string {
+	return []string{"name", "deviceId", "unpaired", "error"}
+}
+
+func (u unpairResultsOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(u))
+	for _, r := range u {
+		records = append(records, []string{r.Name, r.DeviceID, strconv.FormatBool(r.Unpaired), r.Error})
+	}
+	return records
+}
+
+func unpairAll(ctx context.Context, configPath, controllerName string, everyone bool) error {
+	cfg, err := config.ReadControllerConfig(configPath, controllerName)
+	if err != nil {
+		return fmt.Errorf("read controller config: %v", err)
+	}
+
+	var unpaired []*config.AccessoryPairing
+	results := make(unpairResultsOutput, 0, len(cfg.AccessoryPairings))
+	for _, accPairing := range cfg.AccessoryPairings {
+		result := unpairResultOutput{
+			Name:     accPairing.Name,
+			DeviceID: accPairing.DeviceID,
 		}
+		if err := unpairAccessory(ctx, controllerIdentity(cfg), accPairing, everyone); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Unpaired = true
+			unpaired = append(unpaired, accPairing)
+		}
+		results = append(results, result)
 	}
-	cfg.AccessoryPairings = newPairings
 
-	if err := config.SaveControllerConfig(clientCtx.ConfigPath, &cfg, true); err != nil {
+	removePairings(cfg, unpaired...)
+
+	if err := config.SaveControllerConfig(configPath, cfg, true); err != nil {
 		return err
 	}
 
-	fmt.Println("Pairing removed")
+	if err := render(results, func(w io.Writer) {
+		if len(results) == 0 {
+			fmt.Fprintln(w, "No paired accessories")
+			return
+		}
+		for _, r := range results {
+			if r.Unpaired {
+				fmt.Fprintf(w, "%s (%s): unpaired\n", r.Name, r.DeviceID)
+			} else {
+				fmt.Fprintf(w, "%s (%s): failed: %s\n", r.Name, r.DeviceID, r.Error)
+			}
+		}
+		fmt.Fprintf(w, "Unpaired %d of %d accessories\n", len(unpaired), len(results))
+	}); err != nil {
+		return err
+	}
+
+	if len(cfg.AccessoryPairings) > 0 {
+		return fmt.Errorf("%d accessories could not be unpaired and remain in the config", len(cfg.AccessoryPairings))
+	}
+
+	return nil
+}
+
+// unpairAccessory removes this controller's pairing from the accessory. If everyone is set
+// the pairings of all other controllers are removed first.
+func unpairAccessory(
+	ctx context.Context,
+	identity *client.ControllerIdentity,
+	accPairing *config.AccessoryPairing,
+	everyone bool,
+) (rErr error) {
+	accClient := pairingClient(identity, accPairing)
+	defer func() {
+		// the accessory is unpaired once RemovePairing succeeds so failing to close the
+		// session afterwards isn't an error
+		if cErr := accClient.Close(); cErr != nil && rErr != nil {
+			rErr = multierror.Append(rErr, cErr)
+		}
+	}()
+
+	if everyone {
+		pairs, err := accClient.ListPairings(ctx)
+		if err != nil {
+			return fmt.Errorf("listPairings: %v", err)
+		}
+
+		for _, p := range pairs {
+			if p.ControllerID == identity.DeviceID {
+				continue
+			}
+			if err := accClient.RemovePairing(ctx, p.ControllerID); err != nil {
+				return fmt.Errorf("removePairing %s: %v", p.ControllerID, err)
+			}
+		}
+	}
+
+	if err := accClient.RemovePairing(ctx, identity.DeviceID); err != nil {
+		return fmt.Errorf("removePairing: %v", err)
+	}
 
 	return nil
 }
+
+// removePairings removes the pairings from the controller config.
+func removePairings(cfg *config.ControllerConfig, pairings ...*config.AccessoryPairing) {
+	removed := make(map[*config.AccessoryPairing]bool, len(pairings))
+	for _, p := range pairings {
+		removed[p] = true
+	}
+
+	kept := make([]*config.AccessoryPairing, 0, len(cfg.AccessoryPairings))
+	for _, p := range cfg.AccessoryPairings {
+		if !removed[p] {
+			kept = append(kept, p)
+		}
+	}
+	cfg.AccessoryPairings = kept
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/mctofu/homekit/client/hapfake"
+	"github.com/mctofu/homekit/cmd/homekit/cli/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpairAllPartialFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	failing, err := hapfake.NewServer(hapfake.Config{})
+	require.NoError(t, err)
+	defer failing.Close()
+	server, err := hapfake.NewServer(hapfake.Config{})
+	require.NoError(t, err)
+	defer server.Close()
+
+	configPath, cfg := pairTestController(t, failing, server)
+	failing.Fail(hapfake.Failure{Method: http.MethodPost, Path: "/pairings", StatusCode: http.StatusInternalServerError})
+
+	err = unpairAll(ctx, configPath, cfg.Name, false)
+	require.EqualError(t, err, "1 accessories could not be unpaired and remain in the config")
+
+	// the failure doesn't stop the other accessory from being unpaired
+	require.Equal(t, map[string]bool{cfg.DeviceID: true}, serverPairings(failing))
+	require.Empty(t, server.Pairings())
+
+	saved, err := config.ReadControllerConfig(configPath, cfg.Name)
+	require.NoError(t, err)
+	require.Len(t, saved.AccessoryPairings, 1)
+	require.Equal(t, "fake1", saved.AccessoryPairings[0].Name)
+
+	// retrying unpairs the remaining accessory
+	failing.ClearFailures()
+	require.NoError(t, unpairAll(ctx, configPath, cfg.Name, false))
+	require.Empty(t, failing.Pairings())
+
+	saved, err = config.ReadControllerConfig(configPath, cfg.Name)
+	require.NoError(t, err)
+	require.Empty(t, saved.AccessoryPairings)
+}
+
+func TestUnpairEveryone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// record the pairings after each change
+	var (
+		server    *hapfake.Server
+		recording bool
+		changes   []map[string]bool
+	)
+	server, err := hapfake.NewServer(hapfake.Config{OnPairingChange: func() {
+		if recording {
+			changes = append(changes, serverPairings(server))
+		}
+	}})
+	require.NoError(t, err)
+	defer server.Close()
+
+	configPath, cfg := pairTestController(t, server)
+	_, accClient := testClientContext(t, configPath, cfg, "fake1")
+	admin := addTestController(t, ctx, accClient, client.PermissionAdmin)
+	user := addTestController(t, ctx, accClient, client.PermissionUser)
+	require.NoError(t, accClient.Close())
+
+	recording = true
+	require.NoError(t, unpair(ctx, configPath, cfg.Name, "fake1", true))
+
+	// this controller stays paired until the other controllers are removed
+	require.Len(t, changes, 3)
+	require.Len(t, changes[0], 2)
+	require.Contains(t, changes[0], cfg.DeviceID)
+	_, hasAdmin := changes[0][admin.DeviceID]
+	_, hasUser := changes[0][user.DeviceID]
+	require.NotEqual(t, hasAdmin, hasUser, "one other controller is removed at a time")
+	require.Equal(t, map[string]bool{cfg.DeviceID: true}, changes[1])
+	require.Empty(t, changes[2])
+
+	saved, err := config.ReadControllerConfig(configPath, cfg.Name)
+	require.NoError(t, err)
+	require.Empty(t, saved.AccessoryPairings)
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/spf13/cobra"
+)
+
+func updatePairingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "updatePairing",
+		Short: "Grant or revoke admin access for a controller paired to an accessory",
+	}
+
+	deviceID := cmd.Flags().String("id", "", "Device id of controller to update")
+	markFlagRequired(cmd, "id")
+	admin := cmd.Flags().Bool("admin", false, "Allow admin access")
+
+	cmd.RunE = clientCommandRunner(cmd,
+		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+			return updatePairing(ctx, clientCtx, accClient, *deviceID, *admin)
+		},
+	)
+
+	return cmd
+}
+
+func updatePairing(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient, deviceID string, admin bool) error {
+	if deviceID == clientCtx.Config.DeviceID && !admin {
+		return fmt.Errorf("%s is this controller, refusing to remove its admin access", deviceID)
+	}
+
+	existing, err := findPairing(ctx, accClient, deviceID)
+	if err != nil {
+		return err
+	}
+
+	// Adding a pairing for a controller that is already paired updates its permissions.
+	addReq := &client.AddPairingRequest{
+		DeviceID:    existing.ControllerID,
+		PublicKey:   existing.PublicKey,
+		Permissions: client.PermissionUser,
+	}
+	if admin {
+		addReq.Permissions = client.PermissionAdmin
+	}
+
+	if err := accClient.AddPairing(ctx, addReq); err != nil {
+		return fmt.Errorf("addPairing: %v", err)
+	}
+
+	return renderMessage("Updated pairing for %s (admin: %t)", deviceID, admin)
+}
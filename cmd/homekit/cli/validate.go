@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mctofu/homekit/client"
+	"github.com/spf13/cobra"
+)
+
+func validateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check an accessory's attributes against the HAP service and characteristic definitions",
+	}
+
+	cmd.RunE = clientCommandRunner(cmd,
+		func(ctx context.Context, clientCtx *clientContext, accClient *client.AccessoryClient) error {
+			accessories, err := accClient.Accessories(ctx)
+			if err != nil {
+				return err
+			}
+
+			return renderValidationIssues(client.ValidateAccessories(accessories))
+		},
+	)
+
+	return cmd
+}
+
+// validationIssueOutput is the output schema of an issue found by validate.
+type validationIssueOutput struct {
+	Severity         string `json:"severity" yaml:"severity"`
+	AccessoryID      uint64 `json:"aid" yaml:"aid"`
+	ServiceID        uint64 `json:"serviceIid,omitempty" yaml:"serviceIid,omitempty"`
+	CharacteristicID uint64 `json:"iid,omitempty" yaml:"iid,omitempty"`
+	Message          string `json:"message" yaml:"message"`
+}
+
+type validationIssuesOutput []validationIssueOutput
+
+func (v validationIssuesOutput) csvHeader() []string {
+	return []string{"severity", "aid", "serviceIid", "iid", "message"}
+}
+
+func (v validationIssuesOutput) csvRecords() [][]string {
+	records := make([][]string, 0, len(v))
+	for _, issue := range v {
+		records = append(records, []string{
+			issue.Severity,
+			strconv.FormatUint(issue.AccessoryID, 10),
+			formatOptionalID(issue.ServiceID),
+			formatOptionalID(issue.CharacteristicID),
+			issue.Message,
+		})
+	}
+	return records
+}
+
+// formatOptionalID formats an instance id for a csv cell. It's empty if the id is 0.
+func formatOptionalID(id uint64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(id, 10)
+}
+
+func renderValidationIssues(issues []*client.ValidationIssue) error {
+	out := make(validationIssuesOutput, 0, len(issues))
+	var errs, warnings int
+	for _, issue := range issues {
+		out = append(out, validationIssueOutput{
+			Severity:         string(issue.Severity),
+			AccessoryID:      issue.AccessoryID,
+			ServiceID:        issue.ServiceID,
+			CharacteristicID: issue.CharacteristicID,
+			Message:          issue.Message,
+		})
+		if issue.Severity == client.SeverityError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+
+	return render(out, func(w io.Writer) {
+		for _, issue := range issues {
+			fmt.Fprintln(w, issue)
+		}
+		if len(issues) == 0 {
+			fmt.Fprintln(w, "No issues found")
+			return
+		}
+		fmt.Fprintf(w, "%s, %s\n", plural(errs, "error"), plural(warnings, "warning"))
+	})
+}
+
+// plural formats a count of noun.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -6,91 +6,84 @@ import (
 	"go/format"
 	"io"
 	"io/ioutil"
-	"strings"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 
-	"gopkg.in/yaml.v3"
+	"github.com/mctofu/homekit/client/definitions"
 )
 
-const (
-	appleBaseUUID = "-0000-1000-8000-0026BB765291"
-)
-
-type characteristicConfig struct {
-	Name   string
-	UUID   string
-	Format string
-}
-
-func (c *characteristicConfig) TypeConstant() string {
-	return "Type" + c.TypeName()
-}
-
-func (c *characteristicConfig) TypeName() string {
-	return strings.ReplaceAll(pascalCase(c.Name), ".", "")
-}
-
-func (c *characteristicConfig) JSONUUID() string {
-	if strings.HasSuffix(c.UUID, appleBaseUUID) {
-		return strings.TrimLeft(c.UUID[:len(c.UUID)-len(appleBaseUUID)], "0")
-	}
-	return c.UUID
+// typeConstant returns the name of the constant of a type.
+func typeConstant(name string) string {
+	return "Type" + identifier(name)
 }
 
 // GenerateCharacteristics generates go source files that implement the characteristics
 // defined in characteristics.yaml
 func GenerateCharacteristics() error {
-	cfgData, err := ioutil.ReadFile("characteristics.yaml")
+	srcs, err := characteristicSources(".")
 	if err != nil {
 		return err
 	}
 
-	var cfgs []characteristicConfig
-	if err := yaml.Unmarshal(cfgData, &cfgs); err != nil {
-		return fmt.Errorf("unmarshal config: %v", err)
+	for name, src := range srcs {
+		if err := ioutil.WriteFile(name, src, 0755); err != nil {
+			return err
+		}
 	}
 
-	if err := generateCharacteristicTypes(cfgs); err != nil {
-		return fmt.Errorf("generateCharacteristicTypes: %v", err)
+	return nil
+}
+
+// characteristicSources returns the source of each file generated from the characteristics.yaml
+// in dir by file name.
+func characteristicSources(dir string) (map[string][]byte, error) {
+	f, err := os.Open(filepath.Join(dir, "characteristics.yaml"))
+	if err != nil {
+		return nil, err
 	}
+	defer f.Close()
 
-	if err := generateCharacteristicReaders(cfgs); err != nil {
-		return fmt.Errorf("generateCharacteristicReaders: %v", err)
+	cfgs, err := definitions.ReadCharacteristics(f)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	types, err := generateCharacteristicTypes(cfgs)
+	if err != nil {
+		return nil, fmt.Errorf("generateCharacteristicTypes: %v", err)
+	}
+
+	readers, err := generateCharacteristicReaders(cfgs)
+	if err != nil {
+		return nil, fmt.Errorf("generateCharacteristicReaders: %v", err)
+	}
+
+	return map[string][]byte{
+		"characteristic_types.go":   types,
+		"characteristic_readers.go": readers,
+	}, nil
 }
 
-func generateCharacteristicTypes(cfgs []characteristicConfig) error {
+func generateCharacteristicTypes(cfgs []*definitions.Characteristic) ([]byte, error) {
 	var w bytes.Buffer
 	cGen := characteristicGenerator{w: &w}
 	if err := cGen.writeTypes(cfgs); err != nil {
-		return err
-	}
-
-	if err := ioutil.WriteFile("characteristic_types.go", w.Bytes(), 0755); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return format.Source(w.Bytes())
 }
 
-func generateCharacteristicReaders(cfgs []characteristicConfig) error {
+func generateCharacteristicReaders(cfgs []*definitions.Characteristic) ([]byte, error) {
 	var w bytes.Buffer
 	cGen := characteristicGenerator{w: &w}
 	if err := cGen.writeReaders(cfgs); err != nil {
-		return err
+		return nil, err
 	}
 
-	formattedSrc, err := format.Source(w.Bytes())
-	if err != nil {
-		return err
-	}
-
-	if err := ioutil.WriteFile("characteristic_readers.go", formattedSrc, 0755); err != nil {
-		return err
-	}
-
-	return nil
+	return format.Source(w.Bytes())
 }
 
 type characteristicGenerator struct {
@@ -98,19 +91,47 @@ type characteristicGenerator struct {
 	wErr error
 }
 
-func (c *characteristicGenerator) writeTypes(cfgs []characteristicConfig) error {
+func (c *characteristicGenerator) writeTypes(cfgs []*definitions.Characteristic) error {
 	c.printf("// Code generated by cmd/gen. DO NOT EDIT.\n\n")
 	c.printf("package characteristic\n\n")
 
 	c.printf("const (\n")
 	for _, cfg := range cfgs {
-		c.printf("\t%s = %q\n", cfg.TypeConstant(), cfg.JSONUUID())
+		c.printf("\t%s = %q\n", typeConstant(cfg.Name), cfg.Type)
 	}
 	c.printf(")\n\n")
 
 	c.printf("var typeMetadatas = []*TypeMetadata{\n")
 	for _, cfg := range cfgs {
-		c.printf("\t{%s, %q, %q},\n", cfg.TypeConstant(), pascalCase(cfg.Name), cfg.Format)
+		c.printf("\t{\n")
+		c.printf("\t\tType: %s,\n", typeConstant(cfg.Name))
+		c.printf("\t\tName: %q,\n", cfg.Name)
+		c.printf("\t\tDescription: %q,\n", cfg.Description)
+		c.printf("\t\tFormat: %q,\n", cfg.Format)
+		if cfg.Unit != "" {
+			c.printf("\t\tUnit: %q,\n", cfg.Unit)
+		}
+		c.printf("\t\tPermissions: %#v,\n", cfg.Permissions)
+		if cfg.MinValue != nil {
+			c.printf("\t\tMinValue: floatPtr(%v),\n", *cfg.MinValue)
+		}
+		if cfg.MaxValue != nil {
+			c.printf("\t\tMaxValue: floatPtr(%v),\n", *cfg.MaxValue)
+		}
+		if cfg.StepValue != nil {
+			c.printf("\t\tStepValue: floatPtr(%v),\n", *cfg.StepValue)
+		}
+		if cfg.MaxLength != nil {
+			c.printf("\t\tMaxLen: intPtr(%d),\n", *cfg.MaxLength)
+		}
+		if len(cfg.ValidValues) > 0 {
+			c.printf("\t\tValidValues: map[string]string{\n")
+			for _, v := range sortedValidValues(cfg.ValidValues) {
+				c.printf("\t\t\t%q: %q,\n", v, cfg.ValidValues[v])
+			}
+			c.printf("\t\t},\n")
+		}
+		c.printf("\t},\n")
 	}
 	c.printf("}\n")
 
@@ -121,12 +142,16 @@ func (c *characteristicGenerator) writeTypes(cfgs []characteristicConfig) error
 	return nil
 }
 
-func (c *characteristicGenerator) writeReaders(cfgs []characteristicConfig) error {
+func (c *characteristicGenerator) writeReaders(cfgs []*definitions.Characteristic) error {
 	c.printf("// Code generated by cmd/gen. DO NOT EDIT.\n\n")
 	c.printf("package characteristic\n\n")
+	c.printf("import (\n")
+	c.printf("\t\"fmt\"\n\n")
+	c.printf("\t\"github.com/mctofu/homekit/client/units\"\n")
+	c.printf(")\n\n")
 
 	for i, cfg := range cfgs {
-		typeName := cfg.TypeName()
+		typeName := identifier(cfg.Name)
 
 		if i > 0 {
 			c.printf("\n")
@@ -135,14 +160,31 @@ func (c *characteristicGenerator) writeReaders(cfgs []characteristicConfig) erro
 		c.printf("type %s struct {\n", typeName)
 		c.printf("\tID uint64\n")
 		c.printf("\tValue %s\n", goType(cfg.Format))
+		if cfg.Unit != "" {
+			c.printf("\tUnit units.Unit\n")
+		}
 		c.printf("}\n\n")
 
-		c.printf("func Read%s(ch *RawCharacteristic) *%s {\n", typeName, typeName)
+		c.printf("func Read%s(ch *RawCharacteristic) (*%s, error) {\n", typeName, typeName)
+		c.printf("\tv, err := ch.Value.%s()\n", goTypeConverterFunc(cfg.Format))
+		c.printf("\tif err != nil {\n")
+		c.printf("\t\treturn nil, fmt.Errorf(\"read %s: %%v\", err)\n", cfg.Name)
+		c.printf("\t}\n")
 		c.printf("\treturn &%s{\n", typeName)
 		c.printf("\t\tID: ch.ID,\n")
-		c.printf("\t\tValue: ch.Value.%s(),\n", goTypeConverterFunc(cfg.Format))
-		c.printf("\t}\n")
+		c.printf("\t\tValue: v,\n")
+		if cfg.Unit != "" {
+			c.printf("\t\tUnit: readUnit(ch, %q),\n", cfg.Unit)
+		}
+		c.printf("\t}, nil\n")
 		c.printf("}\n")
+
+		if cfg.Unit == "celsius" {
+			c.printf("\n// Fahrenheit returns the temperature in degrees Fahrenheit\n")
+			c.printf("func (c *%s) Fahrenheit() float64 {\n", typeName)
+			c.printf("\treturn units.CelsiusToFahrenheit(c.Value)\n")
+			c.printf("}\n")
+		}
 	}
 
 	if c.wErr != nil {
@@ -152,6 +194,23 @@ func (c *characteristicGenerator) writeReaders(cfgs []characteristicConfig) erro
 	return nil
 }
 
+// sortedValidValues returns the valid values in numeric order.
+func sortedValidValues(validValues map[string]string) []string {
+	values := make([]string, 0, len(validValues))
+	for v := range validValues {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		a, aErr := strconv.Atoi(values[i])
+		b, bErr := strconv.Atoi(values[j])
+		if aErr != nil || bErr != nil {
+			return values[i] < values[j]
+		}
+		return a < b
+	})
+	return values
+}
+
 func (c *characteristicGenerator) printf(format string, a ...interface{}) {
 	if c.wErr != nil {
 		return
@@ -187,23 +246,23 @@ func goType(format string) string {
 func goTypeConverterFunc(format string) string {
 	switch format {
 	case "bool":
-		return "MustBool"
+		return "Bool"
 	case "uint8":
-		return "MustByte"
+		return "Byte"
 	case "uint16":
-		return "MustUint16"
+		return "Uint16"
 	case "uint32":
-		return "MustUint32"
+		return "Uint32"
 	case "uint64":
-		return "MustUint64"
+		return "Uint64"
 	case "int":
-		return "MustInt32"
+		return "Int32"
 	case "float":
-		return "MustFloat64"
+		return "Float64"
 	case "string":
-		return "MustString"
+		return "String"
 	case "tlv8", "data": // TODO: look at tlv8/data examples
-		return "MustBytes"
+		return "Bytes"
 	default:
 		panic("unhandled format: " + format)
 	}
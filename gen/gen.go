@@ -2,6 +2,8 @@ package gen
 
 import "strings"
 
-func pascalCase(name string) string {
-	return strings.ReplaceAll(name, " ", "")
+// identifier returns the go identifier for the name of a type. Names of types are already
+// pascal cased but may contain punctuation like PM2.5Density.
+func identifier(name string) string {
+	return strings.ReplaceAll(name, ".", "")
 }
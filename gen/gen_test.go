@@ -0,0 +1,32 @@
+package gen
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCharacteristicsUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "client", "characteristic")
+	srcs, err := characteristicSources(dir)
+	require.NoError(t, err)
+	require.Len(t, srcs, 2)
+
+	for name, src := range srcs {
+		existing, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, string(src), string(existing), "%s is stale, run go generate ./client/characteristic", name)
+	}
+}
+
+func TestServicesUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "client", "service")
+	src, err := serviceSource(dir)
+	require.NoError(t, err)
+
+	existing, err := ioutil.ReadFile(filepath.Join(dir, "service_types.go"))
+	require.NoError(t, err)
+	require.Equal(t, string(src), string(existing), "service_types.go is stale, run go generate ./client/service")
+}
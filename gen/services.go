@@ -6,56 +6,48 @@ import (
 	"go/format"
 	"io"
 	"io/ioutil"
-	"strings"
+	"os"
+	"path/filepath"
 
-	"gopkg.in/yaml.v3"
+	"github.com/mctofu/homekit/client/definitions"
 )
 
-type serviceConfig struct {
-	Name string
-	UUID string
-}
-
-func (s *serviceConfig) TypeConstant() string {
-	return "Type" + pascalCase(s.Name)
-}
-
-func (s *serviceConfig) JSONUUID() string {
-	if strings.HasSuffix(s.UUID, appleBaseUUID) {
-		return strings.TrimLeft(s.UUID[:len(s.UUID)-len(appleBaseUUID)], "0")
-	}
-	return s.UUID
-}
-
 // GenerateServices generates go source files that implement the services
 // defined in services.yaml
 func GenerateServices() error {
-	cfgData, err := ioutil.ReadFile("services.yaml")
+	src, err := serviceSource(".")
 	if err != nil {
 		return err
 	}
 
-	var cfgs []serviceConfig
-	if err := yaml.Unmarshal(cfgData, &cfgs); err != nil {
-		return fmt.Errorf("unmarshal config: %v", err)
+	if err := ioutil.WriteFile("service_types.go", src, 0755); err != nil {
+		return err
 	}
 
-	var w bytes.Buffer
-	sGen := serviceGenerator{w: &w}
-	if err := sGen.writeTypes(cfgs); err != nil {
-		return err
+	return nil
+}
+
+// serviceSource returns the source of service_types.go generated from the services.yaml in
+// dir.
+func serviceSource(dir string) ([]byte, error) {
+	f, err := os.Open(filepath.Join(dir, "services.yaml"))
+	if err != nil {
+		return nil, err
 	}
+	defer f.Close()
 
-	formattedSrc, err := format.Source(w.Bytes())
+	cfgs, err := definitions.ReadServices(f)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := ioutil.WriteFile("service_types.go", formattedSrc, 0755); err != nil {
-		return err
+	var w bytes.Buffer
+	sGen := serviceGenerator{w: &w}
+	if err := sGen.writeTypes(cfgs); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return format.Source(w.Bytes())
 }
 
 type serviceGenerator struct {
@@ -63,19 +55,26 @@ type serviceGenerator struct {
 	wErr error
 }
 
-func (s *serviceGenerator) writeTypes(cfgs []serviceConfig) error {
+func (s *serviceGenerator) writeTypes(cfgs []*definitions.Service) error {
 	s.printf("// generated by cmd/gen; DO NOT EDIT\n\n")
 	s.printf("package service\n\n")
 
 	s.printf("const (\n")
 	for _, cfg := range cfgs {
-		s.printf("\t%s = %q\n", cfg.TypeConstant(), cfg.JSONUUID())
+		s.printf("\t%s = %q\n", typeConstant(cfg.Name), cfg.Type)
 	}
 	s.printf(")\n\n")
 
 	s.printf("var typeMetadatas = []*TypeMetadata{\n")
 	for _, cfg := range cfgs {
-		s.printf("\t{%s, %q},\n", cfg.TypeConstant(), pascalCase(cfg.Name))
+		s.printf("\t{\n")
+		s.printf("\t\tType: %s,\n", typeConstant(cfg.Name))
+		s.printf("\t\tName: %q,\n", cfg.Name)
+		s.printf("\t\tRequiredCharacteristics: %#v,\n", cfg.RequiredCharacteristics)
+		if len(cfg.OptionalCharacteristics) > 0 {
+			s.printf("\t\tOptionalCharacteristics: %#v,\n", cfg.OptionalCharacteristics)
+		}
+		s.printf("\t},\n")
 	}
 	s.printf("}\n")
 